@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/devops-mission-control/pkg/approval"
+	"github.com/yourusername/devops-mission-control/pkg/audit"
+	"github.com/yourusername/devops-mission-control/pkg/config"
+	"github.com/yourusername/devops-mission-control/pkg/slack"
+)
+
+// approvalsDir is the approvals_dir key, else the default location.
+func approvalsDir() string {
+	if cliProfile.ApprovalsDir != "" {
+		return cliProfile.ApprovalsDir
+	}
+	return config.DefaultApprovalsDir()
+}
+
+// approvalGate returns the Slack approval gate set up by slack_bot_token
+// and slack_approval_channel, or nil when approvals are off.
+func approvalGate(prof config.Profile) (*approval.Gate, error) {
+	if prof.SlackBotToken == "" || prof.SlackApprovalChannel == "" {
+		return nil, nil
+	}
+	g := approval.NewGate(approval.NewStore(approvalsDir()), slack.NewClient("", prof.SlackApprovalChannel, slack.WithBotToken(prof.SlackBotToken)), prof.SlackApprovalChannel)
+	g.Users = userStore
+	for _, a := range strings.Split(prof.SlackApprovers, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			g.Approvers = append(g.Approvers, a)
+		}
+	}
+	if prof.SlackApprovalTimeout != "" {
+		d, err := audit.ParseDuration(prof.SlackApprovalTimeout)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("config slack_approval_timeout: invalid duration %q", prof.SlackApprovalTimeout)
+		}
+		g.Timeout = d
+	}
+	return g, nil
+}
+
+// requireApproval holds a dangerous command until someone approves it in
+// the Slack approval channel. It must follow requireMinRole. Without a
+// configured gate it returns a nil approval and no error; otherwise the
+// caller reports how the command ended with Finish.
+func requireApproval(cmd *cobra.Command, command string) (*approval.Approval, error) {
+	g, err := approvalGate(cliProfile)
+	if g == nil || err != nil {
+		return nil, err
+	}
+	actor, err := resolveActor(cmd)
+	if err != nil {
+		return nil, err
+	}
+	a, err := g.Open(command, actor, cliTenant)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "⏳ Waiting up to %s for approval of %s in Slack (request %s)...\n", g.Timeout, command, a.ID)
+	if err := a.Wait(); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "✅ Approved by %s\n", a.DecidedBy)
+	return a, nil
+}
@@ -2,10 +2,15 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/yourusername/devops-mission-control/pkg/audit"
+	"github.com/yourusername/devops-mission-control/pkg/printer"
 )
 
 var auditCmd = &cobra.Command{
@@ -14,44 +19,290 @@ var auditCmd = &cobra.Command{
 }
 
 var auditListCmd = &cobra.Command{
-	Use:   "list",
+	Use:   "list [field=pattern | field!=pattern]...",
 	Short: "List audit entries",
+	Long: `List audit entries, oldest first, from the audit log and its rotated
+segments. Entries are streamed, so only the matches are held in memory;
+with --limit the cursor of the next page is printed to stderr for --after.
+Like /api/audit it is for admins, and tenant admins see their tenant's
+entries only.
+
+` + auditQueryHelp,
+	Example: `  missionctl audit list --action auth.login --limit 100
+  missionctl audit list --action auth.login --limit 100 --after 4711
+  missionctl audit list --since 24h 'target=/api/*' details.allowed=false
+  missionctl audit list --since 7d --action 'tenant.*' --group-by actor
+  missionctl audit list --since 1h 'actor=ci-*' --actor root`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		actor, _ := cmd.Flags().GetString("actor")
-		action, _ := cmd.Flags().GetString("action")
-		sinceStr, _ := cmd.Flags().GetString("since")
-		var since time.Time
-		if sinceStr != "" {
-			t, err := time.Parse(time.RFC3339, sinceStr)
+		if err := requireAdmin(cmd); err != nil {
+			return err
+		}
+		q, err := auditQuery(cmd, args)
+		if err != nil {
+			return err
+		}
+		if groupBy, _ := cmd.Flags().GetStringSlice("group-by"); len(groupBy) > 0 {
+			agg, err := audit.NewAggregator(groupBy)
 			if err != nil {
 				return err
 			}
-			since = t
+			// keep nothing, count everything: the log is streamed once
+			if _, _, err := audit.Page("", "", 0, func(e audit.Entry) bool {
+				if q.Match(e) {
+					agg.Add(e)
+				}
+				return false
+			}); err != nil {
+				return err
+			}
+			return printResult(cmd, agg.Groups(), auditGroupTable(agg))
+		}
+		limit, _ := cmd.Flags().GetInt("limit")
+		after, _ := cmd.Flags().GetString("after")
+		matched, next, err := audit.Page("", after, limit, q.Match)
+		if err != nil {
+			return err
+		}
+		if next != "" {
+			fmt.Fprintf(os.Stderr, "more entries: use --after %s\n", next)
+		}
+		return printResult(cmd, matched, auditTable(matched))
+	},
+}
+
+var auditExportCmd = &cobra.Command{
+	Use:   "export [field=pattern | field!=pattern]...",
+	Short: "Export audit entries for a SIEM or spreadsheet",
+	Long: `Stream the audit entries matching a query as CSV, a JSON array, NDJSON
+(one entry per line), CEF or RFC 5424 syslog lines. Entries are written as
+they are read, so any size of log can be exported. Like audit list it is
+for admins, and tenant admins export their tenant's entries only.
+
+` + auditQueryHelp,
+	Example: `  missionctl audit export --format ndjson --since 24h > audit.ndjson
+  missionctl audit export --format cef details.allowed=false --output-file denied.cef`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireAdmin(cmd); err != nil {
+			return err
 		}
-		entries, err := audit.ReadEntries("")
+		q, err := auditQuery(cmd, args)
 		if err != nil {
 			return err
 		}
-		for _, e := range entries {
-			if actor != "" && e.Actor != actor {
-				continue
+		format, _ := cmd.Flags().GetString("format")
+		out := cmd.OutOrStdout()
+		if path, _ := cmd.Flags().GetString("output-file"); path != "" {
+			f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			out = f
+		}
+		exp, err := audit.NewExporter(out, format)
+		if err != nil {
+			return err
+		}
+		r, err := audit.Open("")
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		for {
+			e, err := r.Next()
+			if err == io.EOF {
+				break
 			}
-			if action != "" && e.Action != action {
-				continue
+			if err != nil {
+				return err
+			}
+			if q.Match(e) {
+				if err := exp.Write(e); err != nil {
+					return err
+				}
+			}
+		}
+		return exp.Close()
+	},
+}
+
+const auditQueryHelp = `A query is any number of field=pattern and field!=pattern terms, all of
+which must match. Fields are seq, tenant, action, actor, target and
+details.<key> (dotted for nested details); patterns are globs where *
+matches anything, so actor=ann or details.allowed=false. --action,
+--target and --tenant are shorthands for the same terms (--actor is who
+runs the command, as everywhere else), and
+--since/--until take RFC 3339 times or durations back from now (24h, 7d).`
+
+// auditQuery builds the query of list and export from their flags and
+// args. It must follow the command's authorization: callers in a tenant
+// are confined to it, only global callers see every tenant.
+func auditQuery(cmd *cobra.Command, args []string) (audit.Query, error) {
+	var q audit.Query
+	now := time.Now()
+	for flag, field := range map[string]*time.Time{"since": &q.Since, "until": &q.Until} {
+		if v, _ := cmd.Flags().GetString(flag); v != "" {
+			t, err := audit.ParseTime(v, now)
+			if err != nil {
+				return q, fmt.Errorf("--%s: %w", flag, err)
 			}
-			if !since.IsZero() && e.Timestamp.Before(since) {
-				continue
+			*field = t
+		}
+	}
+	tenant, _ := cmd.Flags().GetString("tenant")
+	if cliTenant != "" {
+		if tenant != "" && tenant != cliTenant {
+			return q, fmt.Errorf("forbidden: tenant %s is not your tenant %s", tenant, cliTenant)
+		}
+		tenant = cliTenant
+	}
+	if tenant != "" {
+		q.Terms = append(q.Terms, audit.Term{Field: "tenant", Pattern: tenant})
+	}
+	for _, field := range []string{"action", "target"} {
+		if v, _ := cmd.Flags().GetString(field); v != "" {
+			q.Terms = append(q.Terms, audit.Term{Field: field, Pattern: v})
+		}
+	}
+	for _, arg := range args {
+		t, err := audit.ParseTerm(arg)
+		if err != nil {
+			return q, err
+		}
+		if t.Field == "tenant" && tenant != "" && !t.Match(audit.Entry{TenantID: tenant}) {
+			return q, fmt.Errorf("forbidden: query %s leaves tenant %s", arg, tenant)
+		}
+		q.Terms = append(q.Terms, t)
+	}
+	return q, nil
+}
+
+var auditVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check the audit log for tampering",
+	Long: `Walk the hash chain of the audit log and report the first entry that was
+edited, deleted, inserted or reordered. With --key (default: the
+audit_signing_key config key) signatures are checked too; an ed25519 log
+can be verified with just the public key. Exits non-zero when the chain
+is broken.
+
+Entries removed from the end of the log leave no gap; keep the reported
+head seq and hash somewhere else and compare them on the next run.`,
+	Example: `  missionctl audit verify
+  missionctl audit verify --file /var/log/missionctl/audit.log.jsonl --key audit.pub`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file, _ := cmd.Flags().GetString("file")
+		keyPath, _ := cmd.Flags().GetString("key")
+		if keyPath == "" {
+			keyPath = cliProfile.AuditSigningKey
+		}
+		var key audit.Key
+		if keyPath != "" {
+			k, err := audit.LoadKey(keyPath)
+			if err != nil {
+				return err
 			}
-			fmt.Printf("%s %s actor=%s target=%s details=%v\n", e.Timestamp.Format(time.RFC3339), e.Action, e.Actor, e.Target, e.Details)
+			key = k
+		}
+		rep, err := audit.Verify(file, key)
+		if err != nil {
+			return fmt.Errorf("audit verify error: %s", err)
+		}
+		status := "ok"
+		if rep.Break != nil {
+			status = "broken: " + rep.Break.Error()
+		}
+		signed := strconv.Itoa(rep.Signed)
+		if rep.Signed > 0 && !rep.SignaturesChecked {
+			signed += " (not checked)"
+		}
+		t := printer.Table{Headers: []string{"FILE", "ENTRIES", "SIGNED", "HEAD SEQ", "HEAD HASH", "STATUS", "SEGMENTS", "FIRST SEQ", "UNCHAINED"}, WideFrom: 6}
+		t.AddRow(rep.File, strconv.Itoa(rep.Entries), signed, strconv.FormatUint(rep.HeadSeq, 10), printer.Cell(rep.HeadHash), status,
+			strconv.Itoa(rep.Segments), strconv.FormatUint(rep.FirstSeq, 10), strconv.Itoa(rep.Unchained))
+		if err := printResult(cmd, rep, t); err != nil {
+			return err
+		}
+		if rep.Break != nil {
+			return fmt.Errorf("audit log is broken: %s", rep.Break)
 		}
 		return nil
 	},
 }
 
+var auditRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Rotate the audit log and apply retention",
+	Long: `Move the audit log to a gzipped segment and delete segments older than
+audit_retention. Writes rotate on their own once audit_max_size or
+audit_max_age is reached; run this from cron to also rotate and prune an
+idle log.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireAdmin(cmd); err != nil {
+			return err
+		}
+		if err := audit.Rotate(); err != nil {
+			return err
+		}
+		segs, err := audit.Segments(audit.DefaultFile)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ Audit log rotated (%d segments kept)\n", len(segs))
+		return nil
+	},
+}
+
+func auditTable(entries []audit.Entry) printer.Table {
+	t := printer.Table{Headers: []string{"TIMESTAMP", "ACTION", "ACTOR", "TARGET", "DETAILS", "TENANT", "SEQ"}, WideFrom: 5, Empty: "No audit entries found"}
+	for _, e := range entries {
+		t.AddRow(e.Timestamp.Format(time.RFC3339), e.Action, e.Actor, e.Target, auditDetails(e), printer.Cell(e.TenantID), printer.Cell(e.Field("seq")))
+	}
+	return t
+}
+
+// auditDetails renders details as sorted key=value pairs, nested values
+// as JSON.
+func auditDetails(e audit.Entry) string {
+	pairs := make(map[string]string, len(e.Details))
+	for k := range e.Details {
+		pairs[k] = e.Field("details." + k)
+	}
+	return printer.Pairs(pairs)
+}
+
+func auditGroupTable(agg *audit.Aggregator) printer.Table {
+	var t printer.Table
+	for _, f := range agg.Fields() {
+		t.Headers = append(t.Headers, strings.ToUpper(f))
+	}
+	t.Headers = append(t.Headers, "COUNT")
+	t.Empty = "No audit entries found"
+	for _, g := range agg.Groups() {
+		row := make([]string, 0, len(t.Headers))
+		for _, f := range agg.Fields() {
+			row = append(row, printer.Cell(g.Values[f]))
+		}
+		t.AddRow(append(row, strconv.Itoa(g.Count))...)
+	}
+	return t
+}
+
 func init() {
 	rootCmd.AddCommand(auditCmd)
-	auditCmd.AddCommand(auditListCmd)
-	auditListCmd.Flags().String("actor", "", "filter by actor")
-	auditListCmd.Flags().String("action", "", "filter by action")
-	auditListCmd.Flags().String("since", "", "filter entries since RFC3339 timestamp")
+	auditCmd.AddCommand(auditListCmd, auditExportCmd, auditVerifyCmd, auditRotateCmd)
+	for _, c := range []*cobra.Command{auditListCmd, auditExportCmd} {
+		c.Flags().String("action", "", "filter by action (glob)")
+		c.Flags().String("target", "", "filter by target (glob)")
+		c.Flags().String("since", "", "entries at or after this RFC3339 time or duration ago (24h, 7d)")
+		c.Flags().String("until", "", "entries before this RFC3339 time or duration ago")
+		c.Flags().String("tenant", "", "filter by tenant (callers in a tenant always get their own)")
+	}
+	auditListCmd.Flags().Int("limit", 0, "return at most this many entries (0 = all)")
+	auditListCmd.Flags().String("after", "", "cursor printed by the previous page")
+	auditListCmd.Flags().StringSlice("group-by", nil, "count entries per value of these fields instead of listing them (e.g. actor or action,details.allowed)")
+	auditExportCmd.Flags().String("format", audit.ExportNDJSON, "export format: "+audit.ExportFormats)
+	auditExportCmd.Flags().String("output-file", "", "write to this file instead of stdout")
+	auditVerifyCmd.Flags().String("file", "", "audit log to verify (default: the audit_file config key)")
+	auditVerifyCmd.Flags().String("key", "", "HMAC secret or ed25519 key to check signatures with")
 }
@@ -1,120 +1,153 @@
 package cmd
 
 import (
-    "fmt"
-    "os"
+	"fmt"
+	"sort"
 
-    "github.com/spf13/cobra"
-    "github.com/yourusername/devops-mission-control/pkg/auth"
+	"github.com/spf13/cobra"
+	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+	"github.com/yourusername/devops-mission-control/pkg/printer"
 )
 
+// The auth commands predate `user` and `token`. They now work on the same
+// users and tokens and are kept so existing scripts keep working.
+
 var authCmd = &cobra.Command{
-    Use:   "auth",
-    Short: "Authentication and user/role management",
+	Use:   "auth",
+	Short: "Authentication and user/role management",
+	Long:  "Shortcuts over the same users and tokens as `missionctl user` and `missionctl token`.",
 }
 
 var authUserCmd = &cobra.Command{
-    Use:   "user",
-    Short: "User management",
+	Use:   "user",
+	Short: "User management",
 }
 
 var authUserAddCmd = &cobra.Command{
-    Use:   "add [username] [role]",
-    Short: "Add a user with role",
-    Args:  cobra.ExactArgs(2),
-    RunE: func(cmd *cobra.Command, args []string) error {
-        username := args[0]
-        role := args[1]
-        token, err := auth.AddUser(username, role)
-        if err != nil {
-            return err
-        }
-        if token == "" {
-            fmt.Printf("user %s already exists\n", username)
-            return nil
-        }
-        fmt.Printf("created user %s with role %s\n", username, role)
-        fmt.Printf("token: %s\n", token)
-        return nil
-    },
+	Use:   "add [username] [role]",
+	Short: "Add a user with role and issue them an API token",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		username := args[0]
+		role := args[1]
+		if err := requireAdmin(cmd); err != nil {
+			return err
+		}
+		if _, err := userStore.GetUser(username); err == nil {
+			fmt.Printf("user %s already exists\n", username)
+			return nil
+		}
+		if err := userStore.AddUser(username, "", authpkg.Role(role)); err != nil {
+			return err
+		}
+		if cliTenant != "" {
+			if err := userStore.SetUserTenant(username, cliTenant); err != nil {
+				return err
+			}
+		}
+		tok, err := tokenStore.GenerateToken(username, "default", 0)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("created user %s with role %s\n", username, role)
+		fmt.Printf("token: %s\n", tok.Token)
+		return nil
+	},
 }
 
 var authUserListCmd = &cobra.Command{
-    Use:   "list",
-    Short: "List users",
-    RunE: func(cmd *cobra.Command, args []string) error {
-        users, err := auth.LoadUsers()
-        if err != nil {
-            return err
-        }
-        for _, u := range users {
-            fmt.Printf("%s\t%s\n", u.Username, u.Role)
-        }
-        return nil
-    },
+	Use:   "list",
+	Short: "List users",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireMinRole(cmd, authpkg.RoleViewer); err != nil {
+			return err
+		}
+		users, err := visibleUsers(cmd)
+		if err != nil {
+			return err
+		}
+		sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+		// structured output must not include password hashes
+		type userInfo struct {
+			Username string       `json:"username"`
+			Role     authpkg.Role `json:"role"`
+			Tenant   string       `json:"tenant,omitempty"`
+		}
+		infos := make([]userInfo, len(users))
+		t := printer.Table{Headers: []string{"USERNAME", "ROLE", "TENANT"}, Empty: "No users found"}
+		for i, u := range users {
+			infos[i] = userInfo{Username: u.Username, Role: u.Role, Tenant: u.Tenant}
+			t.AddRow(u.Username, string(u.Role), printer.Cell(u.Tenant))
+		}
+		return printResult(cmd, infos, t)
+	},
 }
 
 var authUserRemoveCmd = &cobra.Command{
-    Use:   "remove [username]",
-    Short: "Remove a user",
-    Args:  cobra.ExactArgs(1),
-    RunE: func(cmd *cobra.Command, args []string) error {
-        username := args[0]
-        if err := auth.RemoveUser(username); err != nil {
-            return err
-        }
-        fmt.Printf("removed user %s\n", username)
-        return nil
-    },
+	Use:   "remove [username]",
+	Short: "Remove a user",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		username := args[0]
+		if err := requireAdmin(cmd); err != nil {
+			return err
+		}
+		if err := requireSameTenant(cmd, username); err != nil {
+			return err
+		}
+		if err := userStore.DeleteUser(username); err != nil {
+			return err
+		}
+		fmt.Printf("removed user %s\n", username)
+		return nil
+	},
+}
+
+var authRoleCmd = &cobra.Command{
+	Use:   "role",
+	Short: "Role management",
 }
 
 var authRoleSetCmd = &cobra.Command{
-    Use:   "role set [username] [role]",
-    Short: "Set role for a user",
-    Args:  cobra.ExactArgs(2),
-    RunE: func(cmd *cobra.Command, args []string) error {
-        username := args[0]
-        role := args[1]
-        if err := auth.SetRole(username, role); err != nil {
-            return err
-        }
-        fmt.Printf("set role %s for %s\n", role, username)
-        return nil
-    },
+	Use:   "set [username] [role]",
+	Short: "Set role for a user",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		username := args[0]
+		role := args[1]
+		if err := requireAdmin(cmd); err != nil {
+			return err
+		}
+		if err := requireSameTenant(cmd, username); err != nil {
+			return err
+		}
+		if err := userStore.SetUserRole(username, authpkg.Role(role)); err != nil {
+			return err
+		}
+		fmt.Printf("set role %s for %s\n", role, username)
+		return nil
+	},
 }
 
 var authTokenCmd = &cobra.Command{
-    Use:   "token",
-    Short: "Token management",
+	Use:   "token",
+	Short: "Token management",
 }
 
 var authTokenListCmd = &cobra.Command{
-    Use:   "list",
-    Short: "List tokens (username:token)",
-    RunE: func(cmd *cobra.Command, args []string) error {
-        users, err := auth.LoadUsers()
-        if err != nil {
-            return err
-        }
-        for _, u := range users {
-            fmt.Printf("%s\t%s\n", u.Username, u.Token)
-        }
-        return nil
-    },
+	Use:   "list",
+	Short: "List tokens (same as `missionctl token list`)",
+	RunE:  tokenListCmd.RunE,
 }
 
 func init() {
-    rootCmd.AddCommand(authCmd)
-    authCmd.AddCommand(authUserCmd)
-    authUserCmd.AddCommand(authUserAddCmd)
-    authUserCmd.AddCommand(authUserListCmd)
-    authUserCmd.AddCommand(authUserRemoveCmd)
-    authCmd.AddCommand(authRoleSetCmd)
-    authCmd.AddCommand(authTokenCmd)
-    authTokenCmd.AddCommand(authTokenListCmd)
-
-    // ensure users.json exists
-    if _, err := os.Stat("users.json"); os.IsNotExist(err) {
-        _ = auth.SaveUsers([]auth.User{})
-    }
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authUserCmd)
+	authUserCmd.AddCommand(authUserAddCmd)
+	authUserCmd.AddCommand(authUserListCmd)
+	authUserCmd.AddCommand(authUserRemoveCmd)
+	authCmd.AddCommand(authRoleCmd)
+	authRoleCmd.AddCommand(authRoleSetCmd)
+	authCmd.AddCommand(authTokenCmd)
+	authTokenCmd.AddCommand(authTokenListCmd)
 }
@@ -3,11 +3,22 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+	"github.com/yourusername/devops-mission-control/pkg/authz"
+	"github.com/yourusername/devops-mission-control/pkg/printer"
 )
 
+// authzEngine decides every RBAC check of the CLI. loadConfig loads the
+// policy file into it; without one it enforces the built-in roles.
+var authzEngine = authz.NewEngine(authz.Policy{})
+
+// cliTenant is the tenant of the actor, set once a command has authorized
+// it. Audit records of the command go to that tenant.
+var cliTenant string
+
 // resolveActor determines the actor username from flags: token takes precedence
 // over the explicit --actor flag. Returns empty string if none provided.
 func resolveActor(cmd *cobra.Command) (string, error) {
@@ -26,23 +37,9 @@ func resolveActor(cmd *cobra.Command) (string, error) {
 	return "", nil
 }
 
-// requireAdmin ensures the resolved actor is an admin user.
+// requireAdmin ensures the resolved actor may run cmd as an admin action.
 func requireAdmin(cmd *cobra.Command) error {
-	actor, err := resolveActor(cmd)
-	if err != nil {
-		return err
-	}
-	if actor == "" {
-		return errors.New("no actor provided; use --actor or --token")
-	}
-	u, err := userStore.GetUser(actor)
-	if err != nil {
-		return fmt.Errorf("actor lookup failed: %w", err)
-	}
-	if u.Role != authpkg.RoleAdmin {
-		return errors.New("forbidden: admin role required")
-	}
-	return nil
+	return requireMinRole(cmd, authpkg.RoleAdmin)
 }
 
 // requireAdminOrSelf allows action if actor is admin or actor equals targetUsername
@@ -60,28 +57,281 @@ func requireAdminOrSelf(cmd *cobra.Command, targetUsername string) error {
 	return requireAdmin(cmd)
 }
 
-// role level mapping used by CLI RBAC checks
-var cliRoleLevel = map[authpkg.Role]int{
-	authpkg.RoleViewer:   10,
-	authpkg.RoleOperator: 20,
-	authpkg.RoleAdmin:    30,
+// requireMinRole authorizes the resolved actor to run cmd. min is the role
+// the command needs unless the policy file says otherwise.
+func requireMinRole(cmd *cobra.Command, min authpkg.Role) error {
+	verb, resource := commandAction(cmd)
+	return authorize(cmd, verb, resource, min)
 }
 
-// requireMinRole enforces that the resolved actor has at least the provided role.
-func requireMinRole(cmd *cobra.Command, min authpkg.Role) error {
-	actor, err := resolveActor(cmd)
+// authorize checks that the resolved actor may perform verb on resource in
+// the scope of cmd's flags and the active profile.
+func authorize(cmd *cobra.Command, verb, resource string, min authpkg.Role) error {
+	u, err := actorUser(cmd)
 	if err != nil {
 		return err
 	}
+	cliTenant = u.Tenant
+	req := actionRequest(cmd, verb, resource, min)
+	req.User, req.Role, req.Tenant = u.Username, u.Role, u.Tenant
+	if d := authzEngine.Authorize(req); !d.Allowed {
+		return fmt.Errorf("forbidden: %s", d.Reason)
+	}
+	return nil
+}
+
+// actorUser resolves the actor and looks the user up.
+func actorUser(cmd *cobra.Command) (*authpkg.User, error) {
+	actor, err := resolveActor(cmd)
+	if err != nil {
+		return nil, err
+	}
 	if actor == "" {
-		return errors.New("no actor provided; use --actor or --token")
+		return nil, errors.New("no actor provided; use --actor or --token")
 	}
 	u, err := userStore.GetUser(actor)
 	if err != nil {
-		return fmt.Errorf("actor lookup failed: %w", err)
+		return nil, fmt.Errorf("actor lookup failed: %w", err)
+	}
+	return u, nil
+}
+
+// requireSameTenant keeps actors in a tenant to the users of that tenant.
+// Global actors may manage any user. Unknown users pass, so commands that
+// create them can run; they are created in the actor's tenant.
+func requireSameTenant(cmd *cobra.Command, username string) error {
+	if name, _ := resolveActor(cmd); name == username {
+		return nil
+	}
+	actor, err := actorUser(cmd)
+	if err != nil {
+		return err
+	}
+	if actor.Tenant == "" {
+		return nil
 	}
-	if cliRoleLevel[u.Role] < cliRoleLevel[min] {
-		return errors.New("forbidden: insufficient role")
+	if u, err := userStore.GetUser(username); err == nil && u.Tenant != actor.Tenant {
+		return fmt.Errorf("forbidden: user %s is not in tenant %s", username, actor.Tenant)
 	}
 	return nil
 }
+
+// visibleUsers returns the users the actor may see: everyone for global
+// actors, their tenant's users otherwise.
+func visibleUsers(cmd *cobra.Command) ([]*authpkg.User, error) {
+	actor, err := actorUser(cmd)
+	if err != nil {
+		return nil, err
+	}
+	users := userStore.ListUsers()
+	if actor.Tenant == "" {
+		return users, nil
+	}
+	var out []*authpkg.User
+	for _, u := range users {
+		if u.Tenant == actor.Tenant {
+			out = append(out, u)
+		}
+	}
+	return out, nil
+}
+
+// commandAction maps a command to the verb and resource policies match on.
+// The last word of its path is the verb and the words before it the
+// resource, so `k8s deployments scale` is scale on k8s/deployments and
+// `helm uninstall` is uninstall on helm. Top-level commands are run on
+// themselves and plugins run on plugins/<name>.
+func commandAction(cmd *cobra.Command) (verb, resource string) {
+	if cmd.GroupID == pluginGroup {
+		return "run", "plugins/" + cmd.Name()
+	}
+	words := strings.Fields(cmd.CommandPath())
+	if len(words) > 0 {
+		words = words[1:]
+	}
+	switch len(words) {
+	case 0:
+		return "run", ""
+	case 1:
+		return "run", words[0]
+	}
+	return words[len(words)-1], strings.Join(words[:len(words)-1], "/")
+}
+
+// actionRequest builds the request for verb on resource. The provider is
+// the first word of the resource; the namespace is the one cmd runs in
+// and the account comes from cmd's flags when it has them, else the
+// active profile; the environment is the environment key, else the name
+// of the active profile.
+func actionRequest(cmd *cobra.Command, verb, resource string, min authpkg.Role) authz.Request {
+	provider, _, _ := strings.Cut(resource, "/")
+	req := authz.Request{
+		Verb:        verb,
+		Resource:    resource,
+		Provider:    provider,
+		Namespace:   commandNamespace(cmd),
+		Environment: activeEnvironment(),
+		MinRole:     min,
+	}
+	switch provider {
+	case "k8s", "helm":
+		req.Account = flagOr(cmd, "context", cliProfile.KubeContext)
+	case "aws":
+		req.Account = flagOr(cmd, "profile", cliProfile.AWSProfile)
+	case "gcp":
+		req.Account = flagOr(cmd, "project", cliProfile.GCPProject)
+	case "azure":
+		req.Account = flagOr(cmd, "subscription", cliProfile.AzureSubscription)
+	}
+	return req
+}
+
+// activeEnvironment is the environment key, else the active profile's name.
+func activeEnvironment() string {
+	if cliProfile.Environment != "" {
+		return cliProfile.Environment
+	}
+	return cliConfig.ActiveProfile()
+}
+
+// commandNamespace is the namespace cmd runs in: its [namespace] argument
+// when given, else the --namespace flag when set, else the profile's
+// namespace, else the flag's default.
+func commandNamespace(cmd *cobra.Command) string {
+	args := cmd.Flags().Args()
+	for i, p := range strings.Fields(cmd.Use) {
+		if p == "[namespace]" && i > 0 && i <= len(args) {
+			return args[i-1]
+		}
+	}
+	if f := cmd.Flags().Lookup("namespace"); f != nil && f.Changed && f.Value.String() != "" {
+		return f.Value.String()
+	}
+	if cliProfile.Namespace != "" {
+		return cliProfile.Namespace
+	}
+	return flagOr(cmd, "namespace", "")
+}
+
+// flagOr returns the value of cmd's flag name, or def when the command has
+// no such flag or it is empty.
+func flagOr(cmd *cobra.Command, name, def string) string {
+	if f := cmd.Flags().Lookup(name); f != nil && f.Value.String() != "" {
+		return f.Value.String()
+	}
+	return def
+}
+
+var authzCmd = &cobra.Command{
+	Use:   "authz",
+	Short: "Inspect authorization policy decisions",
+}
+
+var authzCanICmd = &cobra.Command{
+	Use:   "can-i <verb> <resource>",
+	Short: "Check whether an action is allowed",
+	Long: `Ask the policy engine whether you (or --as another user) may perform an
+action. Verbs and resources are named after commands: the last word of a
+command is the verb and the words before it the resource, e.g.
+
+  missionctl authz can-i scale k8s/deployments --namespace staging
+  missionctl authz can-i terminate aws/ec2 --account prod --environment prod
+
+When no policy rule matches, the answer is what the built-in roles allow;
+--min-role sets the role the action needs (default viewer for read verbs
+such as list, get or describe, operator otherwise). Exits non-zero on no.`,
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		verb, resource := args[0], args[1]
+		actor, err := resolveActor(cmd)
+		if err != nil {
+			return err
+		}
+		as, _ := cmd.Flags().GetString("as")
+		if as != "" && as != actor {
+			if err := requireAdmin(cmd); err != nil {
+				return err
+			}
+			actor = as
+		}
+		if actor == "" {
+			return errors.New("no actor provided; use --actor or --token")
+		}
+		u, err := userStore.GetUser(actor)
+		if err != nil {
+			return fmt.Errorf("actor lookup failed: %w", err)
+		}
+		if err := requireSameTenant(cmd, u.Username); err != nil {
+			return err
+		}
+
+		min := authpkg.Role(stringFlag(cmd, "min-role"))
+		if min == "" {
+			min = defaultMinRole(verb)
+		}
+		if !min.Valid() {
+			return fmt.Errorf("unknown role %q", min)
+		}
+		req := actionRequest(cmd, verb, resource, min)
+		req.User, req.Role, req.Tenant = u.Username, u.Role, u.Tenant
+		for flag, field := range map[string]*string{
+			"provider":    &req.Provider,
+			"namespace":   &req.Namespace,
+			"account":     &req.Account,
+			"environment": &req.Environment,
+		} {
+			if cmd.Flags().Changed(flag) {
+				*field = stringFlag(cmd, flag)
+			}
+		}
+
+		d := authzEngine.Authorize(req)
+		answer := "no"
+		if d.Allowed {
+			answer = "yes"
+		}
+		t := printer.Table{Headers: []string{"ALLOWED", "REASON"}}
+		t.AddRow(answer, d.Reason)
+		if err := printResult(cmd, d, t); err != nil {
+			return err
+		}
+		if !d.Allowed {
+			return fmt.Errorf("forbidden: %s", d.Reason)
+		}
+		return nil
+	},
+}
+
+// readVerbs are the verbs can-i treats as needing only viewer by default.
+var readVerbs = map[string]bool{
+	"list": true, "get": true, "describe": true, "logs": true, "status": true,
+	"show": true, "view": true, "history": true, "info": true, "values": true,
+	"search": true, "top": true, "health": true, "who": true, "cost": true,
+	"validate": true, "inspect": true, "query": true,
+}
+
+// defaultMinRole is the role can-i assumes verb needs without --min-role.
+func defaultMinRole(verb string) authpkg.Role {
+	if readVerbs[verb] {
+		return authpkg.RoleViewer
+	}
+	return authpkg.RoleOperator
+}
+
+// stringFlag returns a string flag, or "" when cmd does not have it.
+func stringFlag(cmd *cobra.Command, name string) string {
+	v, _ := cmd.Flags().GetString(name)
+	return v
+}
+
+func init() {
+	rootCmd.AddCommand(authzCmd)
+	authzCmd.AddCommand(authzCanICmd)
+	authzCanICmd.Flags().String("as", "", "check for another user (admin only)")
+	authzCanICmd.Flags().String("min-role", "", "role the action needs when no rule matches")
+	authzCanICmd.Flags().String("provider", "", "provider (default: first part of the resource)")
+	authzCanICmd.Flags().StringP("namespace", "n", "", "namespace")
+	authzCanICmd.Flags().String("account", "", "kube context, AWS profile, GCP project or Azure subscription")
+	authzCanICmd.Flags().String("environment", "", "environment (default: environment key or active profile)")
+}
@@ -1,12 +1,19 @@
 package cmd
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/yourusername/devops-mission-control/pkg/audit"
 	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+	"github.com/yourusername/devops-mission-control/pkg/authz"
+	"github.com/yourusername/devops-mission-control/pkg/config"
+	"github.com/yourusername/devops-mission-control/pkg/tui"
 )
 
 func TestRequireMinRoleAndResolveActor(t *testing.T) {
@@ -175,3 +182,273 @@ func TestRequireAdmin_InvalidToken(t *testing.T) {
 		t.Fatalf("expected error resolving invalid token")
 	}
 }
+
+func TestRequireMinRoleAppliesPolicy(t *testing.T) {
+	tmp := t.TempDir()
+	userStore = authpkg.NewUserStore(tmp)
+	tokenStore = authpkg.NewTokenStore(tmp, filepath.Join(tmp, "tokens.json"))
+	if err := userStore.AddUser("op", "", authpkg.RoleOperator); err != nil {
+		t.Fatal(err)
+	}
+	authzEngine.SetPolicy(authz.Policy{Rules: []authz.Rule{{
+		Name: "prod-read-only", Effect: authz.Deny, Verbs: []string{authz.VerbWrite}, Namespaces: []string{"prod"},
+	}}})
+	t.Cleanup(func() { authzEngine.SetPolicy(authz.Policy{}) })
+	cliProfile = config.Profile{}
+	t.Cleanup(func() { cliProfile = config.Profile{} })
+
+	root := &cobra.Command{Use: "missionctl"}
+	root.PersistentFlags().String("actor", "", "actor")
+	root.PersistentFlags().String("token", "", "token")
+	k8s := &cobra.Command{Use: "k8s"}
+	k8s.PersistentFlags().String("namespace", "", "namespace")
+	deployments := &cobra.Command{Use: "deployments"}
+	scale := &cobra.Command{Use: "scale <deployment-name> <replicas> [namespace]"}
+	list := &cobra.Command{Use: "list [namespace]"}
+	root.AddCommand(k8s)
+	k8s.AddCommand(deployments)
+	deployments.AddCommand(scale, list)
+	for _, c := range []*cobra.Command{scale, list} {
+		c.Flags().AddFlagSet(c.InheritedFlags())
+		if err := c.Flags().Set("actor", "op"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if verb, resource := commandAction(scale); verb != "scale" || resource != "k8s/deployments" {
+		t.Fatalf("commandAction = %s %s", verb, resource)
+	}
+	if err := scale.Flags().Set("namespace", "staging"); err != nil {
+		t.Fatal(err)
+	}
+	if err := requireMinRole(scale, authpkg.RoleOperator); err != nil {
+		t.Fatalf("operator should scale in staging: %v", err)
+	}
+	// the namespace falls back to the profile when the flag is empty
+	if err := scale.Flags().Set("namespace", ""); err != nil {
+		t.Fatal(err)
+	}
+	cliProfile.Namespace = "prod"
+	err := requireMinRole(scale, authpkg.RoleOperator)
+	if err == nil || !strings.Contains(err.Error(), "prod-read-only") {
+		t.Fatalf("operator scaled in prod: %v", err)
+	}
+	if err := requireMinRole(list, authpkg.RoleViewer); err != nil {
+		t.Fatalf("operator should still list in prod: %v", err)
+	}
+
+	// the [namespace] argument is where the command runs, whatever the
+	// flag and the profile say
+	cliProfile.Namespace = "staging"
+	if err := scale.ParseFlags([]string{"web", "3", "prod", "--namespace=staging"}); err != nil {
+		t.Fatal(err)
+	}
+	err = requireMinRole(scale, authpkg.RoleOperator)
+	if err == nil || !strings.Contains(err.Error(), "prod-read-only") {
+		t.Fatalf("operator scaled in the prod argument: %v", err)
+	}
+	if err := scale.ParseFlags([]string{"web", "3", "staging"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := requireMinRole(scale, authpkg.RoleOperator); err != nil {
+		t.Fatalf("operator should scale in the staging argument: %v", err)
+	}
+	if err := list.ParseFlags([]string{"prod"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := requireMinRole(list, authpkg.RoleViewer); err != nil {
+		t.Fatalf("operator should list in the prod argument: %v", err)
+	}
+}
+
+func TestTenantIsolation(t *testing.T) {
+	store := authpkg.NewMemoryStore()
+	userStore = authpkg.OpenUserStore(store)
+	tokenStore = authpkg.OpenTokenStore(store)
+	tenantStore = authpkg.OpenTenantStore(store)
+	t.Cleanup(func() { cliTenant = "" })
+	for _, tn := range []*authpkg.Tenant{{Name: "acme", KubeContexts: []string{"acme-*"}}, {Name: "beta"}} {
+		if err := tenantStore.CreateTenant(tn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, u := range []struct {
+		name, tenant string
+		role         authpkg.Role
+	}{{"root", "", authpkg.RoleAdmin}, {"ann", "acme", authpkg.RoleAdmin}, {"amy", "acme", authpkg.RoleViewer}, {"bob", "beta", authpkg.RoleAdmin}} {
+		if err := userStore.AddUser(u.name, "", u.role); err != nil {
+			t.Fatal(err)
+		}
+		if err := userStore.SetUserTenant(u.name, u.tenant); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	root := &cobra.Command{Use: "missionctl"}
+	root.PersistentFlags().String("actor", "", "actor")
+	root.PersistentFlags().String("token", "", "token")
+	k8s := &cobra.Command{Use: "k8s"}
+	k8s.PersistentFlags().String("context", "", "kube context")
+	pods := &cobra.Command{Use: "pods"}
+	list := &cobra.Command{Use: "list"}
+	root.AddCommand(k8s)
+	k8s.AddCommand(pods)
+	pods.AddCommand(list)
+	list.Flags().AddFlagSet(list.InheritedFlags())
+	set := func(flag, value string) {
+		if err := list.Flags().Set(flag, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	set("actor", "ann")
+	set("context", "acme-prod")
+	if err := requireMinRole(list, authpkg.RoleViewer); err != nil || cliTenant != "acme" {
+		t.Fatalf("ann in her tenant's context: %v (tenant %q)", err, cliTenant)
+	}
+	set("context", "beta-prod")
+	if err := requireMinRole(list, authpkg.RoleViewer); err == nil {
+		t.Fatal("ann used another tenant's kube context")
+	}
+	if err := requireSameTenant(list, "amy"); err != nil {
+		t.Fatalf("ann manages amy: %v", err)
+	}
+	if err := requireSameTenant(list, "bob"); err == nil {
+		t.Fatal("ann managed a user of another tenant")
+	}
+	users, err := visibleUsers(list)
+	if err != nil || len(users) != 2 {
+		t.Fatalf("ann sees %d users, %v", len(users), err)
+	}
+
+	set("actor", "root")
+	if err := requireMinRole(list, authpkg.RoleViewer); err != nil {
+		t.Fatalf("global admin restricted: %v", err)
+	}
+	if err := requireSameTenant(list, "bob"); err != nil {
+		t.Fatalf("global admin restricted: %v", err)
+	}
+
+	// the UI views are listed in the same scope as `k8s pods list`
+	ui := &cobra.Command{Use: "ui"}
+	ui.Flags().String("context", "", "kube context")
+	root.AddCommand(ui)
+	ui.Flags().AddFlagSet(ui.InheritedFlags())
+	loads := 0
+	views := []*tui.View{{Name: "pods", Load: func() ([]tui.Row, error) {
+		loads++
+		return []tui.Row{{ID: "web-1"}}, nil
+	}}}
+	authorizeLoads(ui, views)
+	for flag, value := range map[string]string{"actor": "ann", "context": "beta-prod"} {
+		if err := ui.Flags().Set(flag, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if rows, err := views[0].Load(); err == nil || !strings.HasPrefix(err.Error(), "forbidden") || len(rows) != 0 || loads != 0 {
+		t.Fatalf("ann loaded pods of another tenant's kube context: %v, %v", rows, err)
+	}
+	if err := ui.Flags().Set("context", "acme-prod"); err != nil {
+		t.Fatal(err)
+	}
+	if rows, err := views[0].Load(); err != nil || len(rows) != 1 {
+		t.Fatalf("ann in her tenant's context: %v, %v", rows, err)
+	}
+}
+
+func TestAuditExportTenants(t *testing.T) {
+	origWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() {
+		if cerr := os.Chdir(origWd); cerr != nil {
+			t.Fatalf("failed to chdir back: %v", cerr)
+		}
+	})
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	store := authpkg.NewMemoryStore()
+	userStore = authpkg.OpenUserStore(store)
+	tokenStore = authpkg.OpenTokenStore(store)
+	tenantStore = authpkg.OpenTenantStore(store)
+	t.Cleanup(func() { cliTenant = "" })
+	for _, tn := range []string{"acme", "beta"} {
+		if err := tenantStore.CreateTenant(&authpkg.Tenant{Name: tn}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, u := range []struct {
+		name, tenant string
+		role         authpkg.Role
+	}{{"root", "", authpkg.RoleAdmin}, {"ann", "acme", authpkg.RoleAdmin}, {"amy", "acme", authpkg.RoleViewer}, {"bob", "beta", authpkg.RoleAdmin}} {
+		if err := userStore.AddUser(u.name, "", u.role); err != nil {
+			t.Fatal(err)
+		}
+		if err := userStore.SetUserTenant(u.name, u.tenant); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, tenant := range []string{"acme", "beta", ""} {
+		if err := audit.Record(tenant, "test.export", "someone", tenant, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the commands are shared: start and end with their flags unset
+	reset := func() {
+		rootCmd.SetArgs(nil)
+		for _, fl := range []*pflag.Flag{rootCmd.PersistentFlags().Lookup("actor"), auditExportCmd.Flags().Lookup("tenant"), auditExportCmd.Flags().Lookup("output-file")} {
+			_ = fl.Value.Set("")
+			fl.Changed = false
+		}
+	}
+	reset()
+	t.Cleanup(reset)
+	export := func(args ...string) ([]audit.Entry, error) {
+		t.Helper()
+		out := filepath.Join(t.TempDir(), "export.ndjson")
+		rootCmd.SetArgs(append([]string{"audit", "export", "--format", "ndjson", "--output-file", out}, args...))
+		if _, err := rootCmd.ExecuteC(); err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var entries []audit.Entry
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			var e audit.Entry
+			if err := json.Unmarshal([]byte(line), &e); err != nil {
+				t.Fatal(err)
+			}
+			if e.Action == "test.export" {
+				entries = append(entries, e)
+			}
+		}
+		return entries, nil
+	}
+
+	if _, err := export(); err == nil || !strings.Contains(err.Error(), "no actor") {
+		t.Fatalf("unauthenticated export: %v", err)
+	}
+	if _, err := export("--actor", "amy"); err == nil || !strings.Contains(err.Error(), "forbidden") {
+		t.Fatalf("viewer exported: %v", err)
+	}
+	if _, err := export("--actor", "bob", "--tenant", "acme"); err == nil || !strings.Contains(err.Error(), "forbidden") {
+		t.Fatalf("bob exported acme: %v", err)
+	}
+	if _, err := export("--actor", "bob", "tenant=a*"); err == nil || !strings.Contains(err.Error(), "forbidden") {
+		t.Fatalf("bob queried acme: %v", err)
+	}
+	entries, err := export("--actor", "ann", "--tenant", "")
+	if err != nil || len(entries) != 1 || entries[0].TenantID != "acme" {
+		t.Fatalf("ann exported %+v: %v", entries, err)
+	}
+	entries, err = export("--actor", "root")
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("global admin exported %+v: %v", entries, err)
+	}
+}
@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 
 	"github.com/spf13/cobra"
 	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
 	awspkg "github.com/yourusername/devops-mission-control/pkg/aws"
+	"github.com/yourusername/devops-mission-control/pkg/printer"
 )
 
 var awsRegion string
@@ -35,8 +38,7 @@ var awsWhoCmd = &cobra.Command{
 			return fmt.Errorf("failed to get AWS identity: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -61,13 +63,12 @@ var awsEC2ListCmd = &cobra.Command{
 		}
 		running, _ := cmd.Flags().GetBool("running")
 		client := awspkg.NewClient(awsRegion, awsProfile)
-		output, err := client.ListEC2Instances(running)
+		instances, err := client.ListEC2Instances(running)
 		if err != nil {
 			return fmt.Errorf("failed to list EC2 instances: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, instances, instanceTable(instances))
 	},
 }
 
@@ -85,8 +86,7 @@ var awsEC2StartCmd = &cobra.Command{
 			return fmt.Errorf("failed to start instance: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -104,8 +104,7 @@ var awsEC2StopCmd = &cobra.Command{
 			return fmt.Errorf("failed to stop instance: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -117,14 +116,18 @@ var awsEC2TerminateCmd = &cobra.Command{
 		if err := requireMinRole(cmd, authpkg.RoleAdmin); err != nil {
 			return err
 		}
+		approved, err := requireApproval(cmd, "aws ec2 terminate "+args[0])
+		if err != nil {
+			return err
+		}
 		client := awspkg.NewClient(awsRegion, awsProfile)
 		output, err := client.TerminateEC2Instance(args[0])
+		approved.Finish(err)
 		if err != nil {
 			return fmt.Errorf("failed to terminate instance: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -142,8 +145,7 @@ var awsEC2DescribeCmd = &cobra.Command{
 			return fmt.Errorf("failed to describe instance: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -170,12 +172,11 @@ var awsS3ListCmd = &cobra.Command{
 		client := awspkg.NewClient(awsRegion, awsProfile)
 
 		if len(args) == 0 {
-			output, err := client.ListS3Buckets()
+			buckets, err := client.ListS3Buckets()
 			if err != nil {
 				return fmt.Errorf("failed to list buckets: %w", err)
 			}
-			fmt.Println(output)
-			return nil
+			return printResult(cmd, buckets, bucketTable(buckets))
 		}
 
 		recursive, _ := cmd.Flags().GetBool("recursive")
@@ -184,8 +185,7 @@ var awsS3ListCmd = &cobra.Command{
 			return fmt.Errorf("failed to list bucket contents: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -203,8 +203,7 @@ var awsS3SizeCmd = &cobra.Command{
 			return fmt.Errorf("failed to get bucket size: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -228,13 +227,12 @@ var awsRDSListCmd = &cobra.Command{
 			return err
 		}
 		client := awspkg.NewClient(awsRegion, awsProfile)
-		output, err := client.ListRDSInstances()
+		dbs, err := client.ListRDSInstances()
 		if err != nil {
 			return fmt.Errorf("failed to list RDS instances: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, dbs, dbInstanceTable(dbs))
 	},
 }
 
@@ -252,8 +250,7 @@ var awsRDSDescribeCmd = &cobra.Command{
 			return fmt.Errorf("failed to describe RDS instance: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -271,8 +268,7 @@ var awsRDSStartCmd = &cobra.Command{
 			return fmt.Errorf("failed to start RDS instance: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -290,8 +286,7 @@ var awsRDSStopCmd = &cobra.Command{
 			return fmt.Errorf("failed to stop RDS instance: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -320,19 +315,7 @@ var awsCostOptimizeCmd = &cobra.Command{
 			return fmt.Errorf("failed to analyze resources: %w", err)
 		}
 
-		fmt.Println("🔍 Cost Optimization Opportunities:")
-		fmt.Println("===================================")
-		if len(resources) == 0 {
-			fmt.Println("✅ No unused resources found!")
-		} else {
-			for resource, items := range resources {
-				fmt.Printf("\n%s (%d):\n", resource, len(items))
-				for _, item := range items {
-					fmt.Printf("  - %s\n", item)
-				}
-			}
-		}
-		return nil
+		return printResult(cmd, resources, unusedTable(resources))
 	},
 }
 
@@ -361,8 +344,7 @@ var awsAccountInfoCmd = &cobra.Command{
 			return fmt.Errorf("failed to get account info: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -386,13 +368,12 @@ var awsSecurityGroupsCmd = &cobra.Command{
 			return err
 		}
 		client := awspkg.NewClient(awsRegion, awsProfile)
-		output, err := client.ListSecurityGroups()
+		groups, err := client.ListSecurityGroups()
 		if err != nil {
 			return fmt.Errorf("failed to list security groups: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, groups, securityGroupTable(groups))
 	},
 }
 
@@ -404,16 +385,71 @@ var awsSecurityVpcsCmd = &cobra.Command{
 			return err
 		}
 		client := awspkg.NewClient(awsRegion, awsProfile)
-		output, err := client.ListVPCs()
+		vpcs, err := client.ListVPCs()
 		if err != nil {
 			return fmt.Errorf("failed to list VPCs: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, vpcs, vpcTable(vpcs))
 	},
 }
 
+func instanceTable(instances []awspkg.Instance) printer.Table {
+	t := printer.Table{Headers: []string{"ID", "NAME", "TYPE", "STATE", "PRIVATE-IP", "PUBLIC-IP", "ZONE", "LAUNCHED"}, WideFrom: 6, Empty: "No EC2 instances found"}
+	for _, i := range instances {
+		t.AddRow(i.ID, printer.Cell(i.Name), i.Type, i.State, printer.Cell(i.PrivateIP), printer.Cell(i.PublicIP), i.AvailabilityZone, printer.Age(i.LaunchTime))
+	}
+	return t
+}
+
+func bucketTable(buckets []awspkg.Bucket) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "CREATED"}, Empty: "No buckets found"}
+	for _, b := range buckets {
+		t.AddRow(b.Name, b.CreatedAt.Format("2006-01-02 15:04:05"))
+	}
+	return t
+}
+
+func dbInstanceTable(dbs []awspkg.DBInstance) printer.Table {
+	t := printer.Table{Headers: []string{"ID", "CLASS", "ENGINE", "STATUS", "VERSION", "ENDPOINT"}, WideFrom: 4, Empty: "No RDS instances found"}
+	for _, db := range dbs {
+		t.AddRow(db.ID, db.Class, db.Engine, db.Status, db.EngineVersion, printer.Cell(db.Endpoint))
+	}
+	return t
+}
+
+func securityGroupTable(groups []awspkg.SecurityGroup) printer.Table {
+	t := printer.Table{Headers: []string{"ID", "NAME", "VPC", "DESCRIPTION"}, WideFrom: 3, Empty: "No security groups found"}
+	for _, g := range groups {
+		t.AddRow(g.ID, g.Name, printer.Cell(g.VpcID), g.Description)
+	}
+	return t
+}
+
+func vpcTable(vpcs []awspkg.VPC) printer.Table {
+	t := printer.Table{Headers: []string{"ID", "CIDR", "STATE", "DEFAULT"}, Empty: "No VPCs found"}
+	for _, v := range vpcs {
+		t.AddRow(v.ID, v.CIDR, v.State, strconv.FormatBool(v.IsDefault))
+	}
+	return t
+}
+
+// unusedTable flattens FindUnusedResources into one row per resource.
+func unusedTable(resources map[string][]string) printer.Table {
+	kinds := make([]string, 0, len(resources))
+	for kind := range resources {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	t := printer.Table{Headers: []string{"KIND", "RESOURCE"}, Empty: "✅ No unused resources found!"}
+	for _, kind := range kinds {
+		for _, item := range resources[kind] {
+			t.AddRow(kind, item)
+		}
+	}
+	return t
+}
+
 func init() {
 	// Global AWS flags
 	awsCmd.PersistentFlags().StringVarP(&awsRegion, "region", "r", "", "AWS region")
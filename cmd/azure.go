@@ -6,6 +6,7 @@ import (
 	"github.com/spf13/cobra"
 	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
 	azurepkg "github.com/yourusername/devops-mission-control/pkg/azure"
+	"github.com/yourusername/devops-mission-control/pkg/printer"
 )
 
 var azureSubscription string
@@ -40,12 +41,11 @@ var azureVMListCmd = &cobra.Command{
 			return err
 		}
 		client := azurepkg.NewClient(azureSubscription, azureResourceGroup)
-		output, err := client.ListVMs()
+		vms, err := client.ListVMs()
 		if err != nil {
 			return fmt.Errorf("failed to list VMs: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, vms, azureVMTable(vms))
 	},
 }
 
@@ -103,12 +103,11 @@ var azureStorageListCmd = &cobra.Command{
 			return err
 		}
 		client := azurepkg.NewClient(azureSubscription, azureResourceGroup)
-		output, err := client.ListStorageAccounts()
+		accounts, err := client.ListStorageAccounts()
 		if err != nil {
 			return fmt.Errorf("failed to list storage accounts: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, accounts, azureStorageTable(accounts))
 	},
 }
 
@@ -130,12 +129,11 @@ var azureDatabaseListCmd = &cobra.Command{
 			return err
 		}
 		client := azurepkg.NewClient(azureSubscription, azureResourceGroup)
-		output, err := client.ListDatabases()
+		dbs, err := client.ListDatabases()
 		if err != nil {
 			return fmt.Errorf("failed to list databases: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, dbs, azureDatabaseTable(dbs))
 	},
 }
 
@@ -157,12 +155,11 @@ var azureAppServiceListCmd = &cobra.Command{
 			return err
 		}
 		client := azurepkg.NewClient(azureSubscription, azureResourceGroup)
-		output, err := client.ListAppServices()
+		apps, err := client.ListAppServices()
 		if err != nil {
 			return fmt.Errorf("failed to list app services: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, apps, azureAppTable(apps))
 	},
 }
 
@@ -184,12 +181,11 @@ var azureResourceGroupListCmd = &cobra.Command{
 			return err
 		}
 		client := azurepkg.NewClient(azureSubscription, azureResourceGroup)
-		output, err := client.ListResourceGroups()
+		groups, err := client.ListResourceGroups()
 		if err != nil {
 			return fmt.Errorf("failed to list resource groups: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, groups, azureGroupTable(groups))
 	},
 }
 
@@ -211,15 +207,62 @@ var azureNetworkListCmd = &cobra.Command{
 			return err
 		}
 		client := azurepkg.NewClient(azureSubscription, azureResourceGroup)
-		output, err := client.ListNetworks()
+		vnets, err := client.ListNetworks()
 		if err != nil {
 			return fmt.Errorf("failed to list networks: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, vnets, azureVNetTable(vnets))
 	},
 }
 
+func azureVMTable(vms []azurepkg.VM) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "RESOURCE GROUP", "LOCATION", "POWER STATE", "SIZE", "PRIVATE IPS", "PUBLIC IPS"}, WideFrom: 4, Empty: "No VMs found"}
+	for _, vm := range vms {
+		t.AddRow(vm.Name, vm.ResourceGroup, vm.Location, printer.Cell(vm.PowerState), vm.Size, printer.Cell(vm.PrivateIPs), printer.Cell(vm.PublicIPs))
+	}
+	return t
+}
+
+func azureStorageTable(accounts []azurepkg.StorageAccount) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "RESOURCE GROUP", "LOCATION", "KIND", "SKU"}, Empty: "No storage accounts found"}
+	for _, a := range accounts {
+		t.AddRow(a.Name, a.ResourceGroup, a.Location, a.Kind, a.SKU)
+	}
+	return t
+}
+
+func azureDatabaseTable(dbs []azurepkg.Database) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "RESOURCE GROUP", "STATUS", "SKU", "LOCATION"}, WideFrom: 4, Empty: "No databases found"}
+	for _, db := range dbs {
+		t.AddRow(db.Name, db.ResourceGroup, db.Status, printer.Cell(db.SKU), db.Location)
+	}
+	return t
+}
+
+func azureAppTable(apps []azurepkg.AppService) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "RESOURCE GROUP", "STATE", "HOST", "LOCATION"}, WideFrom: 4, Empty: "No app services found"}
+	for _, app := range apps {
+		t.AddRow(app.Name, app.ResourceGroup, app.State, printer.Cell(app.Host), app.Location)
+	}
+	return t
+}
+
+func azureGroupTable(groups []azurepkg.ResourceGroup) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "LOCATION", "STATE"}, Empty: "No resource groups found"}
+	for _, g := range groups {
+		t.AddRow(g.Name, g.Location, g.State)
+	}
+	return t
+}
+
+func azureVNetTable(vnets []azurepkg.VNet) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "RESOURCE GROUP", "LOCATION", "ADDRESS PREFIXES"}, Empty: "No virtual networks found"}
+	for _, v := range vnets {
+		t.AddRow(v.Name, v.ResourceGroup, v.Location, printer.Join(v.AddressPrefixes))
+	}
+	return t
+}
+
 func init() {
 	rootCmd.AddCommand(azureCmd)
 
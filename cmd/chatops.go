@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/yourusername/devops-mission-control/pkg/chatops"
+	"github.com/yourusername/devops-mission-control/pkg/config"
+	"github.com/yourusername/devops-mission-control/pkg/slack"
+)
+
+// chatopsBot returns the slash command bot of the dashboard, set up once
+// slack_signing_secret is. It runs this binary, with the config file of
+// cmd, for the commands of slack_commands (chatops.DefaultCommands when
+// unset).
+func chatopsBot(cmd *cobra.Command, prof config.Profile) (*chatops.Bot, error) {
+	if prof.SlackSigningSecret == "" {
+		return nil, nil
+	}
+	bin, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("chatops error: %s", err)
+	}
+	b := chatops.NewBot(userStore, bin, commandPath, slack.NewClient("", "", slack.WithBotToken(prof.SlackBotToken)))
+	b.Flags = commandFlags
+	if prof.SlackCommands != "" {
+		b.Allowed = nil
+		for _, c := range strings.Split(prof.SlackCommands, ",") {
+			if c = strings.TrimSpace(c); c != "" {
+				b.Allowed = append(b.Allowed, c)
+			}
+		}
+	}
+	if path, _ := cmd.Flags().GetString("config"); path != "" {
+		b.BaseArgs = []string{"--config", path}
+	}
+	return b, nil
+}
+
+// commandPath names the command args run, without the binary name, e.g.
+// "k8s pods list" for [k8s pods list prod].
+func commandPath(args []string) (string, error) {
+	c, _, err := rootCmd.Find(args)
+	if err != nil || c == rootCmd {
+		return "", fmt.Errorf("unknown command %q", strings.Join(args, " "))
+	}
+	return strings.TrimPrefix(c.CommandPath(), rootCmd.Name()+" "), nil
+}
+
+// commandFlags returns the flags of the command args run, the persistent
+// ones of its parents included, or nil for an unknown command.
+func commandFlags(args []string) *pflag.FlagSet {
+	c, _, err := rootCmd.Find(args)
+	if err != nil {
+		return nil
+	}
+	c.InheritedFlags() // merges the parents' persistent flags into Flags
+	return c.Flags()
+}
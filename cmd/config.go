@@ -0,0 +1,389 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/devops-mission-control/pkg/audit"
+	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+	"github.com/yourusername/devops-mission-control/pkg/authz"
+	"github.com/yourusername/devops-mission-control/pkg/config"
+	metricspkg "github.com/yourusername/devops-mission-control/pkg/metrics"
+	"github.com/yourusername/devops-mission-control/pkg/printer"
+)
+
+// Loaded by the root PersistentPreRunE before any command runs.
+var (
+	cliConfigPath string
+	cliConfig     = &config.Config{}
+	cliProfile    config.Profile
+)
+
+// configBinding ties a persistent flag of a command group to a config key.
+type configBinding struct {
+	group *cobra.Command
+	flag  string
+	key   string
+}
+
+func configBindings() []configBinding {
+	return []configBinding{
+		{k8sCmd, "namespace", "namespace"},
+		{k8sCmd, "context", "kube_context"},
+		{helmCmd, "namespace", "namespace"},
+		{awsCmd, "region", "aws_region"},
+		{awsCmd, "profile", "aws_profile"},
+		{gcpCmd, "project", "gcp_project"},
+		{gcpCmd, "region", "gcp_region"},
+		{azureCmd, "subscription", "azure_subscription"},
+		{azureCmd, "resource-group", "azure_resource_group"},
+		{terraformCmd, "workdir", "terraform_workdir"},
+		{dashboardStartCmd, "addr", "dashboard_addr"},
+	}
+}
+
+// loadConfig reads the config file, resolves the active profile and fills in
+// every bound flag the user did not set explicitly. Precedence, highest
+// first: command-line flag, MISSIONCTL_<KEY> env var, profile, defaults,
+// flag default.
+func loadConfig(cmd *cobra.Command, args []string) error {
+	path, _ := cmd.Flags().GetString("config")
+	if path == "" {
+		path = config.DefaultPath()
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+	cliConfigPath, cliConfig = path, cfg
+	// The config commands must keep working when the active profile is
+	// missing, otherwise use-profile could not repair it.
+	if inGroup(cmd, configCmd) {
+		return nil
+	}
+	prof, err := cfg.Resolve(cfg.ActiveProfile())
+	if err != nil {
+		return err
+	}
+	cliProfile = prof
+
+	for _, b := range configBindings() {
+		value := prof.Get(b.key)
+		if value == "" || !inGroup(cmd, b.group) {
+			continue
+		}
+		f := cmd.Flags().Lookup(b.flag)
+		if f == nil || f.Changed {
+			continue
+		}
+		if err := f.Value.Set(value); err != nil {
+			return fmt.Errorf("config %s: %w", b.key, err)
+		}
+	}
+	if prof.AuthStore != "" || prof.UsersFile != "" || prof.TokensFile != "" {
+		store, err := authpkg.OpenStore(prof.AuthStore, prof.UsersFile, prof.TokensFile)
+		if err != nil {
+			return err
+		}
+		authStore = store
+		userStore = authpkg.OpenUserStore(store)
+		tokenStore = authpkg.OpenTokenStore(store)
+		tenantStore = authpkg.OpenTenantStore(store)
+	}
+	policy, err := authz.LoadPolicyIfExists(config.DefaultPolicyPath())
+	if prof.PolicyFile != "" {
+		policy, err = authz.LoadPolicy(prof.PolicyFile)
+	}
+	if err != nil {
+		return err
+	}
+	authzEngine.SetPolicy(policy)
+	if prof.AuditFile != "" {
+		audit.DefaultFile = prof.AuditFile
+	}
+	if prof.AuditSigningKey != "" {
+		key, err := audit.LoadSigningKey(prof.AuditSigningKey)
+		if err != nil {
+			return err
+		}
+		audit.SigningKey = key
+	}
+	if err := loadAuditRotation(prof); err != nil {
+		return err
+	}
+	return loadAuditSinks(cmd, prof)
+}
+
+// metricsStoreSize is how many metrics, events and alerts the store keeps.
+const metricsStoreSize = 10000
+
+// openMetricsStore opens the metrics_store backend, which the dashboard
+// shares with every CLI run, the first time a command needs it; commands
+// that do not touch metrics never open it.
+func openMetricsStore() error {
+	if metricsStore != nil {
+		return nil
+	}
+	store, err := metricspkg.OpenStore(cliProfile.MetricsStore, metricsStoreSize)
+	if err != nil {
+		return fmt.Errorf("config metrics_store: %w", err)
+	}
+	metricsStore = store
+	return nil
+}
+
+// metricsRemoteWriter returns the Prometheus remote-write output set by
+// metrics_remote_write and metrics_remote_write_interval, or nil.
+func metricsRemoteWriter(prof config.Profile, store *metricspkg.MetricsStore) (*metricspkg.RemoteWriter, error) {
+	if prof.MetricsRemoteWrite == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(prof.MetricsRemoteWrite)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("config metrics_remote_write: %q is not an http(s) URL", prof.MetricsRemoteWrite)
+	}
+	rw := metricspkg.NewRemoteWriter(prof.MetricsRemoteWrite, store)
+	if prof.MetricsRemoteWriteInterval != "" {
+		d, err := audit.ParseDuration(prof.MetricsRemoteWriteInterval)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("config metrics_remote_write_interval: invalid duration %q", prof.MetricsRemoteWriteInterval)
+		}
+		rw.Interval = d
+	}
+	return rw, nil
+}
+
+// alertRulesPath is the alert_rules_file key, else the default location.
+func alertRulesPath() string {
+	if cliProfile.AlertRulesFile != "" {
+		return cliProfile.AlertRulesFile
+	}
+	return config.DefaultAlertRulesPath()
+}
+
+// notifyRoutesPath is the notify_routes_file key, else the default location.
+func notifyRoutesPath() string {
+	if cliProfile.NotifyRoutesFile != "" {
+		return cliProfile.NotifyRoutesFile
+	}
+	return config.DefaultNotifyRoutesPath()
+}
+
+// silencesPath is the silences_file key, else the default location.
+func silencesPath() string {
+	if cliProfile.SilencesFile != "" {
+		return cliProfile.SilencesFile
+	}
+	return config.DefaultSilencesPath()
+}
+
+// slackTemplatesDir is the slack_templates_dir key, else the default
+// location.
+func slackTemplatesDir() string {
+	if cliProfile.SlackTemplatesDir != "" {
+		return cliProfile.SlackTemplatesDir
+	}
+	return config.DefaultSlackTemplatesDir()
+}
+
+// dashboardAuditBuffer is the audit queue of the dashboard when audit_buffer
+// is not set: it records a check on every request and must not wait on the
+// sinks to answer.
+const dashboardAuditBuffer = 4096
+
+// loadAuditSinks applies the audit_sinks and audit_buffer keys. Without
+// either, entries go straight to audit_file as before.
+func loadAuditSinks(cmd *cobra.Command, prof config.Profile) error {
+	buffer := 0
+	if prof.AuditBuffer != "" {
+		n, err := strconv.Atoi(prof.AuditBuffer)
+		if err != nil || n < 0 {
+			return fmt.Errorf("config audit_buffer: invalid entry count %q", prof.AuditBuffer)
+		}
+		buffer = n
+	} else if cmd == dashboardStartCmd {
+		buffer = dashboardAuditBuffer
+	}
+	if prof.AuditSinks == "" && buffer == 0 {
+		return nil
+	}
+	specs := []string{"file"}
+	if prof.AuditSinks != "" {
+		specs = strings.Split(prof.AuditSinks, ",")
+	}
+	var sinks []audit.Sink
+	for _, spec := range specs {
+		s, err := audit.ParseSink(spec)
+		if err != nil {
+			return fmt.Errorf("config audit_sinks: %w", err)
+		}
+		sinks = append(sinks, s)
+	}
+	return audit.UseSinks(buffer, sinks...)
+}
+
+// loadAuditRotation applies the audit_max_size, audit_max_age and
+// audit_retention keys.
+func loadAuditRotation(prof config.Profile) error {
+	var rot audit.Rotation
+	var err error
+	if prof.AuditMaxSize != "" {
+		if rot.MaxSize, err = audit.ParseSize(prof.AuditMaxSize); err != nil {
+			return fmt.Errorf("config audit_max_size: %w", err)
+		}
+	}
+	if prof.AuditMaxAge != "" {
+		if rot.MaxAge, err = audit.ParseDuration(prof.AuditMaxAge); err != nil {
+			return fmt.Errorf("config audit_max_age: %w", err)
+		}
+	}
+	if prof.AuditRetention != "" {
+		if rot.Retention, err = audit.ParseDuration(prof.AuditRetention); err != nil {
+			return fmt.Errorf("config audit_retention: %w", err)
+		}
+	}
+	audit.DefaultRotation = rot
+	return nil
+}
+
+// inGroup reports whether cmd is group or one of its subcommands.
+func inGroup(cmd, group *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == group {
+			return true
+		}
+	}
+	return false
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and edit the missionctl config file",
+	Long: `Manage the missionctl config file ($XDG_CONFIG_HOME/missionctl/config.yaml
+unless --config or MISSIONCTL_CONFIG say otherwise).
+
+The file holds defaults and named profiles. The active profile is
+MISSIONCTL_PROFILE or current_profile. Every key can be overridden with a
+MISSIONCTL_<KEY> environment variable, and command-line flags win over both.
+
+Keys: ` + strings.Join(config.Keys(), ", "),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			_ = cmd.Help()
+		}
+	},
+}
+
+// configView is what `config view` prints for the structured output formats.
+type configView struct {
+	Path     string           `json:"path"`
+	Profile  string           `json:"profile,omitempty"`
+	Settings []config.Setting `json:"settings"`
+}
+
+var configViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Show the resolved settings of the active profile",
+	Long: `Show every key of the active profile, or the one named by --profile,
+with its resolved value and where it came from. The values of secret keys
+such as slack_bot_token are shown as ***.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile, _ := cmd.Flags().GetString("profile")
+		if profile == "" {
+			profile = cliConfig.ActiveProfile()
+		}
+		settings, err := cliConfig.Settings(profile)
+		if err != nil {
+			return err
+		}
+		for i, s := range settings {
+			if s.Value != "" && secretName.MatchString(s.Key) {
+				settings[i].Value = redacted
+			}
+		}
+		view := configView{Path: cliConfigPath, Profile: profile, Settings: settings}
+
+		t := printer.Table{Headers: []string{"KEY", "VALUE", "SOURCE"}}
+		for _, s := range settings {
+			t.AddRow(s.Key, printer.Cell(s.Value), printer.Cell(s.Source))
+		}
+		p, err := newPrinter(cmd)
+		if err != nil {
+			return err
+		}
+		if p.Format == printer.FormatTable || p.Format == printer.FormatWide {
+			fmt.Fprintf(cmd.OutOrStdout(), "Config file: %s\nProfile: %s\n\n", cliConfigPath, printer.Cell(profile))
+		}
+		return p.Print(view, t)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a key in the defaults or in a profile",
+	Long: `Set a key in the defaults, or in the profile named by --profile (created
+if needed). An empty value unsets the key.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, value := args[0], args[1]
+		profile, _ := cmd.Flags().GetString("profile")
+		if profile == "" {
+			if err := cliConfig.Defaults.Set(key, value); err != nil {
+				return err
+			}
+		} else {
+			if cliConfig.Profiles == nil {
+				cliConfig.Profiles = map[string]config.Profile{}
+			}
+			p := cliConfig.Profiles[profile]
+			if err := p.Set(key, value); err != nil {
+				return err
+			}
+			cliConfig.Profiles[profile] = p
+		}
+		if err := cliConfig.Save(cliConfigPath); err != nil {
+			return err
+		}
+		scope := "defaults"
+		if profile != "" {
+			scope = "profile " + profile
+		}
+		shown := strconv.Quote(value)
+		if value != "" && secretName.MatchString(key) {
+			shown = redacted
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "✅ Set %s=%s in %s (%s)\n", key, shown, scope, cliConfigPath)
+		return nil
+	},
+}
+
+var configUseProfileCmd = &cobra.Command{
+	Use:   "use-profile <name>",
+	Short: "Make a profile the current one",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if _, ok := cliConfig.Profiles[name]; !ok {
+			return fmt.Errorf("profile %q not found in %s", name, cliConfigPath)
+		}
+		cliConfig.CurrentProfile = name
+		if err := cliConfig.Save(cliConfigPath); err != nil {
+			return err
+		}
+		fmt.Printf("✅ Switched to profile %s\n", name)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configViewCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUseProfileCmd)
+
+	configViewCmd.Flags().String("profile", "", "Show this profile instead of the active one")
+	configSetCmd.Flags().String("profile", "", "Profile to modify (defaults when empty)")
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yourusername/devops-mission-control/pkg/config"
+)
+
+// configTestEnv points the config file and the audit log of the config
+// commands at temporary directories.
+func configTestEnv(t *testing.T) {
+	origWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() {
+		if cerr := os.Chdir(origWd); cerr != nil {
+			t.Fatalf("failed to chdir back: %v", cerr)
+		}
+	})
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv(config.EnvConfig, filepath.Join(dir, "config.yaml"))
+}
+
+func TestConfigViewMasksSecrets(t *testing.T) {
+	configTestEnv(t)
+	t.Setenv("MISSIONCTL_SLACK_BOT_TOKEN", "xoxb-secret")
+	t.Setenv("MISSIONCTL_SLACK_SIGNING_SECRET", "shh")
+	t.Setenv("MISSIONCTL_NAMESPACE", "prod")
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"config", "view", "-o", "json"})
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+		fl := rootCmd.PersistentFlags().Lookup("output")
+		_ = fl.Value.Set("table")
+		fl.Changed = false
+	})
+	if _, err := rootCmd.ExecuteC(); err != nil {
+		t.Fatal(err)
+	}
+	var view configView
+	if err := json.Unmarshal(out.Bytes(), &view); err != nil {
+		t.Fatalf("%v: %s", err, out.String())
+	}
+	want := map[string]string{"slack_bot_token": "***", "slack_signing_secret": "***", "namespace": "prod", "audit_signing_key": ""}
+	for _, s := range view.Settings {
+		if v, ok := want[s.Key]; ok && s.Value != v {
+			t.Errorf("%s = %q, want %q", s.Key, s.Value, v)
+		}
+	}
+}
+
+func TestConfigSetMasksSecrets(t *testing.T) {
+	configTestEnv(t)
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+	})
+	for _, tc := range []struct{ key, value, want string }{
+		{"slack_bot_token", "xoxb-secret", "slack_bot_token=***"},
+		{"slack_signing_secret", "shh", "slack_signing_secret=***"},
+		{"namespace", "prod", `namespace="prod"`},
+	} {
+		out.Reset()
+		rootCmd.SetArgs([]string{"config", "set", tc.key, tc.value})
+		if _, err := rootCmd.ExecuteC(); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out.String(), tc.want) || tc.value != "prod" && strings.Contains(out.String(), tc.value) {
+			t.Errorf("config set %s: %q", tc.key, out.String())
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/spf13/cobra"
 	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
 	"github.com/yourusername/devops-mission-control/pkg/docker"
+	"github.com/yourusername/devops-mission-control/pkg/printer"
 )
 
 var dockerCmd = &cobra.Command{
@@ -39,13 +40,12 @@ var dockerContainersListCmd = &cobra.Command{
 		}
 		all, _ := cmd.Flags().GetBool("all")
 		client := docker.NewClient()
-		output, err := client.ListContainers(!all)
+		containers, err := client.ListContainers(!all)
 		if err != nil {
 			return fmt.Errorf("failed to list containers: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, containers, containerTable(containers))
 	},
 }
 
@@ -63,8 +63,7 @@ var dockerContainersStopCmd = &cobra.Command{
 			return fmt.Errorf("failed to stop container: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -83,8 +82,7 @@ var dockerContainersRemoveCmd = &cobra.Command{
 			return fmt.Errorf("failed to remove container: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -110,13 +108,12 @@ var dockerContainersStatsCmd = &cobra.Command{
 			return err
 		}
 		client := docker.NewClient()
-		output, err := client.GetContainerStats()
+		stats, err := client.GetContainerStats()
 		if err != nil {
 			return fmt.Errorf("failed to get stats: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, stats, statsTable(stats))
 	},
 }
 
@@ -139,13 +136,12 @@ var dockerImagesListCmd = &cobra.Command{
 			return err
 		}
 		client := docker.NewClient()
-		output, err := client.ListImages()
+		images, err := client.ListImages()
 		if err != nil {
 			return fmt.Errorf("failed to list images: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, images, imageTable(images))
 	},
 }
 
@@ -177,8 +173,7 @@ var dockerImagesRemoveCmd = &cobra.Command{
 			return fmt.Errorf("failed to remove image: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -243,8 +238,7 @@ var dockerComposeStatusCmd = &cobra.Command{
 			return fmt.Errorf("failed to get status: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -271,8 +265,7 @@ var dockerSystemInfoCmd = &cobra.Command{
 			return fmt.Errorf("failed to get system info: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -290,11 +283,34 @@ var dockerSystemPruneCmd = &cobra.Command{
 			return fmt.Errorf("failed to prune: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
+func containerTable(containers []docker.Container) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "IMAGE", "STATUS", "PORTS", "CONTAINER ID", "CREATED"}, WideFrom: 4, Empty: "No containers found"}
+	for _, c := range containers {
+		t.AddRow(c.Name, c.Image, c.Status, c.Ports, c.ID, c.Created)
+	}
+	return t
+}
+
+func imageTable(images []docker.Image) printer.Table {
+	t := printer.Table{Headers: []string{"REPOSITORY", "TAG", "SIZE", "CREATED", "IMAGE ID"}, WideFrom: 4, Empty: "No images found"}
+	for _, img := range images {
+		t.AddRow(img.Repository, img.Tag, img.Size, img.Created, img.ID)
+	}
+	return t
+}
+
+func statsTable(stats []docker.ContainerStats) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "CPU %", "MEM USAGE / LIMIT", "MEM %", "NET I/O", "BLOCK I/O"}, WideFrom: 4, Empty: "No running containers"}
+	for _, st := range stats {
+		t.AddRow(st.Name, st.CPUPerc, st.MemUsage, st.MemPerc, st.NetIO, st.BlockIO)
+	}
+	return t
+}
+
 func init() {
 	// Containers subcommands
 	dockerContainersListCmd.Flags().BoolP("all", "a", false, "Show all containers")
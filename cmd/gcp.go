@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/spf13/cobra"
 	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
 	gcppkg "github.com/yourusername/devops-mission-control/pkg/gcp"
+	"github.com/yourusername/devops-mission-control/pkg/printer"
 )
 
 var gcpProject string
@@ -40,12 +42,11 @@ var gcpComputeListCmd = &cobra.Command{
 			return err
 		}
 		client := gcppkg.NewClient(gcpProject, gcpRegion)
-		output, err := client.ListInstances()
+		instances, err := client.ListInstances()
 		if err != nil {
 			return fmt.Errorf("failed to list instances: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, instances, gcpInstanceTable(instances))
 	},
 }
 
@@ -103,12 +104,11 @@ var gcpStorageListCmd = &cobra.Command{
 			return err
 		}
 		client := gcppkg.NewClient(gcpProject, gcpRegion)
-		output, err := client.ListBuckets()
+		buckets, err := client.ListBuckets()
 		if err != nil {
 			return fmt.Errorf("failed to list buckets: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, buckets, gcpBucketTable(buckets))
 	},
 }
 
@@ -130,12 +130,11 @@ var gcpSQLListCmd = &cobra.Command{
 			return err
 		}
 		client := gcppkg.NewClient(gcpProject, gcpRegion)
-		output, err := client.ListSQLInstances()
+		instances, err := client.ListSQLInstances()
 		if err != nil {
 			return fmt.Errorf("failed to list SQL instances: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, instances, gcpSQLTable(instances))
 	},
 }
 
@@ -157,12 +156,11 @@ var gcpRunListCmd = &cobra.Command{
 			return err
 		}
 		client := gcppkg.NewClient(gcpProject, gcpRegion)
-		output, err := client.ListCloudRunServices()
+		services, err := client.ListCloudRunServices()
 		if err != nil {
 			return fmt.Errorf("failed to list Cloud Run services: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, services, gcpRunTable(services))
 	},
 }
 
@@ -184,12 +182,11 @@ var gcpIAMAccountsCmd = &cobra.Command{
 			return err
 		}
 		client := gcppkg.NewClient(gcpProject, gcpRegion)
-		output, err := client.ListServiceAccounts()
+		accounts, err := client.ListServiceAccounts()
 		if err != nil {
 			return fmt.Errorf("failed to list service accounts: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, accounts, gcpServiceAccountTable(accounts))
 	},
 }
 
@@ -205,11 +202,50 @@ var gcpInfoCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to get project info: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
+func gcpInstanceTable(instances []gcppkg.Instance) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "ZONE", "MACHINE_TYPE", "STATUS", "INTERNAL_IP", "EXTERNAL_IP", "AGE"}, WideFrom: 4, Empty: "No instances found"}
+	for _, i := range instances {
+		t.AddRow(i.Name, i.Zone, i.MachineType, i.Status, printer.Cell(i.InternalIP), printer.Cell(i.ExternalIP), printer.Age(i.CreatedAt))
+	}
+	return t
+}
+
+func gcpBucketTable(buckets []gcppkg.Bucket) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "LOCATION", "STORAGE_CLASS", "AGE"}, Empty: "No buckets found"}
+	for _, b := range buckets {
+		t.AddRow(b.Name, b.Location, b.StorageClass, printer.Age(b.CreatedAt))
+	}
+	return t
+}
+
+func gcpSQLTable(instances []gcppkg.SQLInstance) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "DATABASE_VERSION", "STATE", "IP", "REGION", "TIER"}, WideFrom: 4, Empty: "No Cloud SQL instances found"}
+	for _, i := range instances {
+		t.AddRow(i.Name, i.DatabaseVersion, i.State, printer.Cell(i.IP), i.Region, i.Tier)
+	}
+	return t
+}
+
+func gcpRunTable(services []gcppkg.RunService) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "READY", "URL", "AGE"}, Empty: "No Cloud Run services found"}
+	for _, svc := range services {
+		t.AddRow(svc.Name, svc.Ready, svc.URL, printer.Age(svc.CreatedAt))
+	}
+	return t
+}
+
+func gcpServiceAccountTable(accounts []gcppkg.ServiceAccount) printer.Table {
+	t := printer.Table{Headers: []string{"EMAIL", "DISPLAY_NAME", "DISABLED"}, Empty: "No service accounts found"}
+	for _, sa := range accounts {
+		t.AddRow(sa.Email, sa.DisplayName, strconv.FormatBool(sa.Disabled))
+	}
+	return t
+}
+
 func init() {
 	rootCmd.AddCommand(gcpCmd)
 
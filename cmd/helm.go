@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"strconv"
 
 	"github.com/spf13/cobra"
 	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
 	helmpkg "github.com/yourusername/devops-mission-control/pkg/helm"
+	"github.com/yourusername/devops-mission-control/pkg/printer"
 )
 
 var helmNamespace string
@@ -29,12 +31,11 @@ var helmListCmd = &cobra.Command{
 			return err
 		}
 		client := helmpkg.NewClient(helmNamespace)
-		output, err := client.ListReleases()
+		releases, err := client.ListReleases()
 		if err != nil {
 			return fmt.Errorf("failed to list releases: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, releases, releaseTable(releases))
 	},
 }
 
@@ -56,12 +57,11 @@ var helmRepoListCmd = &cobra.Command{
 			return err
 		}
 		client := helmpkg.NewClient(helmNamespace)
-		output, err := client.RepoList()
+		repos, err := client.RepoList()
 		if err != nil {
 			return fmt.Errorf("failed to list repos: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, repos, repoTable(repos))
 	},
 }
 
@@ -77,8 +77,7 @@ var helmRepoUpdateCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to update repos: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -91,12 +90,11 @@ var helmSearchCmd = &cobra.Command{
 			return err
 		}
 		client := helmpkg.NewClient(helmNamespace)
-		output, err := client.SearchChart(args[0])
+		charts, err := client.SearchChart(args[0])
 		if err != nil {
 			return fmt.Errorf("failed to search charts: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, charts, chartTable(charts))
 	},
 }
 
@@ -113,8 +111,7 @@ var helmStatusCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to get status: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -124,12 +121,11 @@ var helmHistoryCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		client := helmpkg.NewClient(helmNamespace)
-		output, err := client.GetReleaseHistory(args[0])
+		history, err := client.GetReleaseHistory(args[0])
 		if err != nil {
 			return fmt.Errorf("failed to get history: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, history, historyTable(history))
 	},
 }
 
@@ -143,8 +139,7 @@ var helmValuesCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to get values: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -166,6 +161,38 @@ var helmUninstallCmd = &cobra.Command{
 	},
 }
 
+func releaseTable(releases []helmpkg.Release) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "NAMESPACE", "REVISION", "STATUS", "CHART", "APP VERSION", "UPDATED"}, WideFrom: 6, Empty: "No releases found"}
+	for _, r := range releases {
+		t.AddRow(r.Name, r.Namespace, r.Revision, r.Status, r.Chart, r.AppVersion, r.Updated)
+	}
+	return t
+}
+
+func repoTable(repos []helmpkg.Repo) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "URL"}, Empty: "No repositories configured"}
+	for _, r := range repos {
+		t.AddRow(r.Name, r.URL)
+	}
+	return t
+}
+
+func chartTable(charts []helmpkg.Chart) printer.Table {
+	t := printer.Table{Headers: []string{"URL", "CHART VERSION", "APP VERSION", "DESCRIPTION"}, Empty: "No charts found"}
+	for _, c := range charts {
+		t.AddRow(c.URL, c.Version, c.AppVersion, c.Description)
+	}
+	return t
+}
+
+func historyTable(history []helmpkg.Revision) printer.Table {
+	t := printer.Table{Headers: []string{"REVISION", "UPDATED", "STATUS", "CHART", "APP VERSION", "DESCRIPTION"}}
+	for _, r := range history {
+		t.AddRow(strconv.Itoa(r.Revision), r.Updated, r.Status, r.Chart, r.AppVersion, r.Description)
+	}
+	return t
+}
+
 func init() {
 	rootCmd.AddCommand(helmCmd)
 	helmCmd.AddCommand(helmListCmd, helmSearchCmd, helmStatusCmd, helmHistoryCmd, helmValuesCmd, helmUninstallCmd)
@@ -2,12 +2,14 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
 	"github.com/yourusername/devops-mission-control/pkg/k8s"
+	"github.com/yourusername/devops-mission-control/pkg/printer"
 )
 
 var k8sNamespace string
@@ -17,9 +19,6 @@ var k8sCmd = &cobra.Command{
 	Use:   "k8s",
 	Short: "Kubernetes operations",
 	Long:  "Manage Kubernetes clusters, pods, deployments, and services",
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		// Initialize any k8s-specific setup here
-	},
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
 			_ = cmd.Help()
@@ -51,13 +50,14 @@ var k8sPodsListCmd = &cobra.Command{
 		}
 
 		client := k8s.NewClient(ns, k8sContext)
-		output, err := client.ListPods(ns)
+		pods, err := client.ListPods(ns)
 		if err != nil {
 			return fmt.Errorf("failed to list pods: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		t := podTable(pods)
+		t.Empty = fmt.Sprintf("No pods found in namespace %s", client.Namespace)
+		return printResult(cmd, pods, t)
 	},
 }
 
@@ -101,8 +101,7 @@ var k8sPodsDescribeCmd = &cobra.Command{
 			return fmt.Errorf("failed to describe pod: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -126,8 +125,7 @@ var k8sPodsDeleteCmd = &cobra.Command{
 			return fmt.Errorf("failed to delete pod: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -155,13 +153,14 @@ var k8sDeploymentsListCmd = &cobra.Command{
 		}
 
 		client := k8s.NewClient(ns, k8sContext)
-		output, err := client.ListDeployments(ns)
+		deployments, err := client.ListDeployments(ns)
 		if err != nil {
 			return fmt.Errorf("failed to list deployments: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		t := deploymentTable(deployments)
+		t.Empty = fmt.Sprintf("No deployments found in namespace %s", client.Namespace)
+		return printResult(cmd, deployments, t)
 	},
 }
 
@@ -190,8 +189,7 @@ var k8sDeploymentsScaleCmd = &cobra.Command{
 			return fmt.Errorf("failed to scale deployment: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -219,13 +217,14 @@ var k8sServicesListCmd = &cobra.Command{
 		}
 
 		client := k8s.NewClient(ns, k8sContext)
-		output, err := client.ListServices(ns)
+		services, err := client.ListServices(ns)
 		if err != nil {
 			return fmt.Errorf("failed to list services: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		t := serviceTable(services)
+		t.Empty = fmt.Sprintf("No services found in namespace %s", client.Namespace)
+		return printResult(cmd, services, t)
 	},
 }
 
@@ -247,13 +246,14 @@ var k8sNodesListCmd = &cobra.Command{
 			return err
 		}
 		client := k8s.NewClient("", k8sContext)
-		output, err := client.ListNodes()
+		nodes, err := client.ListNodes()
 		if err != nil {
 			return fmt.Errorf("failed to list nodes: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		t := nodeTable(nodes)
+		t.Empty = "No nodes found"
+		return printResult(cmd, nodes, t)
 	},
 }
 
@@ -280,8 +280,7 @@ var k8sContextListCmd = &cobra.Command{
 			return fmt.Errorf("failed to list contexts: %w", err)
 		}
 
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -333,19 +332,63 @@ var k8sHealthCmd = &cobra.Command{
 			return fmt.Errorf("failed to check health: %w", err)
 		}
 
-		fmt.Println("🏥 Cluster Health Status:")
-		fmt.Println("================================")
-		for service, status := range health {
-			fmt.Printf("%s: %s\n", strings.ToUpper(service[:1])+service[1:], status)
+		components := make([]string, 0, len(health))
+		for service := range health {
+			components = append(components, service)
 		}
-		return nil
+		sort.Strings(components)
+		t := printer.Table{Headers: []string{"COMPONENT", "STATUS"}}
+		for _, service := range components {
+			t.AddRow(strings.ToUpper(service[:1])+service[1:], health[service])
+		}
+		return printResult(cmd, health, t)
 	},
 }
 
+// podTable lays pods out like `kubectl get pods -o wide`.
+func podTable(pods []k8s.Pod) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "READY", "STATUS", "RESTARTS", "AGE", "IP", "NODE", "OWNER"}, WideFrom: 5}
+	for _, p := range pods {
+		t.AddRow(p.Name, p.Ready, p.Status, strconv.Itoa(p.Restarts), printer.Age(p.CreatedAt),
+			printer.Cell(p.IP), printer.Cell(p.Node), printer.Cell(p.Owner))
+	}
+	return t
+}
+
+// deploymentTable lays deployments out like `kubectl get deployments -o wide`.
+func deploymentTable(deployments []k8s.Deployment) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "READY", "UP-TO-DATE", "AVAILABLE", "AGE", "IMAGES", "SELECTOR"}, WideFrom: 5}
+	for _, d := range deployments {
+		t.AddRow(d.Name, fmt.Sprintf("%d/%d", d.Ready, d.Replicas), strconv.Itoa(d.UpToDate),
+			strconv.Itoa(d.Available), printer.Age(d.CreatedAt), printer.Join(d.Images), printer.Pairs(d.Selector))
+	}
+	return t
+}
+
+// serviceTable lays services out like `kubectl get services -o wide`.
+func serviceTable(services []k8s.Service) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "TYPE", "CLUSTER-IP", "EXTERNAL-IP", "PORT(S)", "AGE", "SELECTOR"}, WideFrom: 6}
+	for _, svc := range services {
+		t.AddRow(svc.Name, svc.Type, printer.Cell(svc.ClusterIP), printer.Join(svc.ExternalIPs),
+			printer.Join(svc.Ports), printer.Age(svc.CreatedAt), printer.Pairs(svc.Selector))
+	}
+	return t
+}
+
+// nodeTable lays nodes out like `kubectl get nodes -o wide`.
+func nodeTable(nodes []k8s.Node) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "STATUS", "ROLES", "AGE", "VERSION", "INTERNAL-IP", "OS-IMAGE", "KERNEL-VERSION", "CONTAINER-RUNTIME"}, WideFrom: 5}
+	for _, n := range nodes {
+		t.AddRow(n.Name, n.Status, printer.Join(n.Roles), printer.Age(n.CreatedAt), n.Version,
+			printer.Cell(n.InternalIP), printer.Cell(n.OSImage), printer.Cell(n.KernelVersion), printer.Cell(n.ContainerRuntime))
+	}
+	return t
+}
+
 func init() {
 	// Global k8s flags
 	k8sCmd.PersistentFlags().StringVarP(&k8sNamespace, "namespace", "n", "default", "Kubernetes namespace")
-	k8sCmd.PersistentFlags().StringVarP(&k8sContext, "context", "c", "", "Kubernetes context")
+	k8sCmd.PersistentFlags().StringVar(&k8sContext, "context", "", "Kubernetes context")
 
 	// Pods subcommands
 	k8sPodsCmd.AddCommand(k8sPodsListCmd)
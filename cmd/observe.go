@@ -1,12 +1,25 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/yourusername/devops-mission-control/pkg/alerting"
+	"github.com/yourusername/devops-mission-control/pkg/audit"
 	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
 	dashboardpkg "github.com/yourusername/devops-mission-control/pkg/dashboard"
 	metricspkg "github.com/yourusername/devops-mission-control/pkg/metrics"
+	"github.com/yourusername/devops-mission-control/pkg/notify"
+	"github.com/yourusername/devops-mission-control/pkg/printer"
 	slackpkg "github.com/yourusername/devops-mission-control/pkg/slack"
 )
 
@@ -35,13 +48,62 @@ var dashboardStartCmd = &cobra.Command{
 		if err := requireMinRole(cmd, authpkg.RoleOperator); err != nil {
 			return err
 		}
-		if metricsStore == nil {
-			metricsStore = metricspkg.NewMetricsStore(10000)
+		if err := openMetricsStore(); err != nil {
+			return err
 		}
 
+		if authStore != nil {
+			dashboardpkg.UseAuthStore(authStore)
+		}
+		dashboardpkg.UsePolicy(authzEngine, activeEnvironment())
 		dashboardInst = dashboardpkg.NewDashboard(dashboardAddr, metricsStore)
+		rw, err := metricsRemoteWriter(cliProfile, metricsStore)
+		if err != nil {
+			return err
+		}
+		if rw != nil {
+			dashboardInst.UseRemoteWrite(rw)
+		}
+		rules := alerting.NewEngine(metricsStore, alertRulesPath())
+		if _, err := rules.Reload(); err != nil {
+			return err
+		}
+		dashboardInst.UseAlertEngine(rules)
+		notifier := notify.NewDispatcher(metricsStore, notifyRoutesPath(), notify.NewSilenceStore(silencesPath()))
+		if notifier.SlackOptions, err = slackMessageOptions(); err != nil {
+			return err
+		}
+		if _, err := notifier.Reload(); err != nil {
+			return err
+		}
+		dashboardInst.UseNotifier(notifier)
+		gate, err := approvalGate(cliProfile)
+		if err != nil {
+			return err
+		}
+		if gate != nil {
+			if cliProfile.SlackSigningSecret == "" {
+				return fmt.Errorf("config slack_signing_secret is required to take Slack approvals")
+			}
+			dashboardInst.UseApprovals(gate, cliProfile.SlackSigningSecret)
+		}
+		bot, err := chatopsBot(cmd, cliProfile)
+		if err != nil {
+			return err
+		}
+		if bot != nil {
+			dashboardInst.UseChatOps(bot, cliProfile.SlackSigningSecret)
+		}
 		// Run dashboard in foreground so startup errors surface to stdout/logs
 		fmt.Printf("Starting dashboard at http://%s (foreground)\n", dashboardAddr)
+		// Shut down cleanly on Ctrl-C so queued audit entries are flushed.
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(sig)
+		go func() {
+			<-sig
+			dashboardInst.Stop()
+		}()
 		if err := dashboardInst.Start(); err != nil {
 			return fmt.Errorf("failed to start dashboard: %w", err)
 		}
@@ -67,33 +129,24 @@ var dashboardStopCmd = &cobra.Command{
 }
 
 var metricsListCmd = &cobra.Command{
-	Use:   "metrics list",
+	Use:   "list",
 	Short: "List all metrics",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// viewer role is sufficient to list metrics
 		if err := requireMinRole(cmd, authpkg.RoleViewer); err != nil {
 			return err
 		}
-		if metricsStore == nil {
-			metricsStore = metricspkg.NewMetricsStore(10000)
+		if err := openMetricsStore(); err != nil {
+			return err
 		}
 
 		metrics := metricsStore.GetMetrics()
-		if len(metrics) == 0 {
-			fmt.Println("No metrics recorded")
-			return nil
-		}
-
-		fmt.Println("Metrics:")
-		for _, m := range metrics {
-			fmt.Printf("  %s: %.2f %s (tags: %v)\n", m.Name, m.Value, m.Unit, m.Tags)
-		}
-		return nil
+		return printResult(cmd, metrics, metricTable(metrics))
 	},
 }
 
 var metricsRecordCmd = &cobra.Command{
-	Use:   "metrics record <name> <value> <unit>",
+	Use:   "record <name> <value> <unit>",
 	Short: "Record a metric",
 	Args:  cobra.ExactArgs(3),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -101,8 +154,8 @@ var metricsRecordCmd = &cobra.Command{
 		if err := requireMinRole(cmd, authpkg.RoleOperator); err != nil {
 			return err
 		}
-		if metricsStore == nil {
-			metricsStore = metricspkg.NewMetricsStore(10000)
+		if err := openMetricsStore(); err != nil {
+			return err
 		}
 
 		var value float64
@@ -111,131 +164,349 @@ var metricsRecordCmd = &cobra.Command{
 			return fmt.Errorf("invalid metric value: %w", err)
 		}
 
-		metricsStore.RecordMetric(args[0], value, args[2], nil)
+		if err := metricsStore.RecordMetric(args[0], value, args[2], nil); err != nil {
+			return err
+		}
 		fmt.Printf("✅ Recorded metric: %s = %.2f %s\n", args[0], value, args[2])
 		return nil
 	},
 }
 
 var metricsStatsCmd = &cobra.Command{
-	Use:   "metrics stats",
+	Use:   "stats",
 	Short: "Show metrics statistics",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := requireMinRole(cmd, authpkg.RoleViewer); err != nil {
 			return err
 		}
-		if metricsStore == nil {
-			metricsStore = metricspkg.NewMetricsStore(10000)
+		if err := openMetricsStore(); err != nil {
+			return err
 		}
 
 		stats := metricsStore.GetStats()
-		fmt.Println("Metrics Statistics:")
-		for key, value := range stats {
-			fmt.Printf("  %s: %v\n", key, value)
+		keys := make([]string, 0, len(stats))
+		for key := range stats {
+			keys = append(keys, key)
 		}
-		return nil
+		sort.Strings(keys)
+		t := printer.Table{Headers: []string{"STAT", "VALUE"}}
+		for _, key := range keys {
+			t.AddRow(key, fmt.Sprint(stats[key]))
+		}
+		return printResult(cmd, stats, t)
 	},
 }
 
-var alertsListCmd = &cobra.Command{
-	Use:   "alerts list",
-	Short: "List all alerts",
+var metricsQueryCmd = &cobra.Command{
+	Use:   "query <name>",
+	Short: "Query a metric over time",
+	Long: `Query one metric over a time range. Without --agg the points are listed
+as stored; points older than an hour have been rolled up and come back as
+the averages of their minute (or, after a day, hour) buckets. --agg
+aggregates the whole range, or every --step window of it.
+
+--from and --to take RFC 3339 times or durations back from now (24h, 7d).
+--tag filters on tags and labels with key=glob or key!=glob, and --by
+returns one series per value of the given tags.`,
+	Example: `  missionctl observe metrics query cpu --from 6h --step 5m --agg p95
+  missionctl observe metrics query requests --from 1h --step 1m --agg rate --by host
+  missionctl observe metrics query cpu --tag env=prod --tag 'host!=db-*' --agg max`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := requireMinRole(cmd, authpkg.RoleViewer); err != nil {
 			return err
 		}
-		if metricsStore == nil {
-			metricsStore = metricspkg.NewMetricsStore(10000)
+		if err := openMetricsStore(); err != nil {
+			return err
 		}
 
-		alerts := metricsStore.GetAlerts()
-		if len(alerts) == 0 {
-			fmt.Println("No alerts")
-			return nil
+		q := metricspkg.Query{Name: args[0]}
+		now := time.Now()
+		for flag, field := range map[string]*time.Time{"from": &q.From, "to": &q.To} {
+			if v, _ := cmd.Flags().GetString(flag); v != "" {
+				t, err := audit.ParseTime(v, now)
+				if err != nil {
+					return fmt.Errorf("--%s: %w", flag, err)
+				}
+				*field = t
+			}
+		}
+		if v, _ := cmd.Flags().GetString("step"); v != "" {
+			step, err := audit.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("--step: %w", err)
+			}
+			q.Step = step
+		}
+		q.Agg, _ = cmd.Flags().GetString("agg")
+		tags, _ := cmd.Flags().GetStringArray("tag")
+		for _, v := range tags {
+			f, err := metricspkg.ParseTagFilter(v)
+			if err != nil {
+				return err
+			}
+			q.Filters = append(q.Filters, f)
 		}
+		q.GroupBy, _ = cmd.Flags().GetStringSlice("by")
 
-		fmt.Println("Alerts:")
-		for _, a := range alerts {
-			status := "Active"
-			if a.Resolved {
-				status = "Resolved"
+		series, err := metricsStore.Query(q)
+		if err != nil {
+			return err
+		}
+		t := printer.Table{Headers: []string{"SERIES", "TIME", "VALUE"}, Empty: "No data points"}
+		for _, s := range series {
+			name := s.Name
+			if len(s.Tags) > 0 {
+				name += "{" + printer.Pairs(s.Tags) + "}"
+			}
+			for _, p := range s.Points {
+				t.AddRow(name, p.Time.Format(time.RFC3339), strconv.FormatFloat(p.Value, 'f', -1, 64))
 			}
-			fmt.Printf("  [%s] %s: %s (%s)\n", a.Severity, a.Name, a.Message, status)
 		}
-		return nil
+		return printResult(cmd, series, t)
+	},
+}
+
+var alertsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all alerts",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireMinRole(cmd, authpkg.RoleViewer); err != nil {
+			return err
+		}
+		if err := openMetricsStore(); err != nil {
+			return err
+		}
+
+		alerts := metricsStore.GetAlerts()
+		t := alertTable(alerts)
+		t.Empty = "No alerts"
+		return printResult(cmd, alerts, t)
 	},
 }
 
 var alertsActiveCmd = &cobra.Command{
-	Use:   "alerts active",
+	Use:   "active",
 	Short: "Show active alerts",
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := requireMinRole(cmd, authpkg.RoleViewer); err != nil {
 			return err
 		}
-		if metricsStore == nil {
-			metricsStore = metricspkg.NewMetricsStore(10000)
+		if err := openMetricsStore(); err != nil {
+			return err
 		}
 
 		alerts := metricsStore.GetActiveAlerts()
-		if len(alerts) == 0 {
-			fmt.Println("✅ No active alerts")
-			return nil
-		}
-
-		fmt.Println("Active Alerts:")
-		for _, a := range alerts {
-			fmt.Printf("  [%s] %s: %s\n", a.Severity, a.Name, a.Message)
-		}
-		return nil
+		t := alertTable(alerts)
+		t.Empty = "✅ No active alerts"
+		return printResult(cmd, alerts, t)
 	},
 }
 
 var alertsCreateCmd = &cobra.Command{
-	Use:   "alerts create <name> <severity> <message>",
+	Use:   "create <name> <severity> <message>",
 	Short: "Create an alert",
 	Args:  cobra.ExactArgs(3),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := requireMinRole(cmd, authpkg.RoleOperator); err != nil {
 			return err
 		}
-		if metricsStore == nil {
-			metricsStore = metricspkg.NewMetricsStore(10000)
+		if err := openMetricsStore(); err != nil {
+			return err
 		}
 
-		metricsStore.CreateAlert(args[0], args[1], args[2], nil)
+		if err := metricsStore.CreateAlert(args[0], args[1], args[2], nil); err != nil {
+			return err
+		}
 		fmt.Printf("✅ Alert created: %s (%s)\n", args[0], args[1])
 		return nil
 	},
 }
 
-var eventsListCmd = &cobra.Command{
-	Use:   "events list",
-	Short: "List all events",
+var alertsRulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "List alert rules",
+	Long: `List the alert rules of alert_rules_file (default alerts.yaml in the
+config directory) and how many alerts each has firing. The running dashboard
+evaluates them every interval and picks up changes to the file without a
+restart.
+
+  interval: 30s
+  rules:
+    - name: high-cpu
+      metric: cpu
+      tags: [env=prod]
+      by: [host]
+      agg: avg          # ` + metricspkg.Aggregations + `
+      window: 5m
+      op: gt            # > >= < <= == != (quoted) or gt ge lt le eq ne
+      threshold: 90
+      for: 2m
+      severity: critical
+      message: "CPU at {{.Value}}% on {{.Labels.host}}"
+      labels: {team: infra}`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := requireMinRole(cmd, authpkg.RoleViewer); err != nil {
 			return err
 		}
-		if metricsStore == nil {
-			metricsStore = metricspkg.NewMetricsStore(10000)
+		if err := openMetricsStore(); err != nil {
+			return err
 		}
 
-		events := metricsStore.GetEvents()
-		if len(events) == 0 {
-			fmt.Println("No events")
-			return nil
+		rs, err := alerting.LoadRulesIfExists(alertRulesPath())
+		if err != nil {
+			return err
 		}
+		firing := map[string]int{}
+		for _, a := range metricsStore.GetActiveAlerts() {
+			if rule := a.Metadata[alerting.MetaRule]; rule != "" {
+				firing[rule]++
+			}
+		}
+		t := printer.Table{Headers: []string{"NAME", "CONDITION", "FOR", "SEVERITY", "FIRING"}, Empty: "No alert rules in " + alertRulesPath()}
+		for _, r := range rs.Rules {
+			cond := fmt.Sprintf("%s(%s) over %s %s %s", r.Agg, r.Metric, time.Duration(r.Window), r.Op, strconv.FormatFloat(r.Threshold, 'g', -1, 64))
+			t.AddRow(r.Name, cond, time.Duration(r.For).String(), r.Severity, strconv.Itoa(firing[r.Name]))
+		}
+		return printResult(cmd, rs, t)
+	},
+}
 
-		fmt.Println("Events:")
-		for _, e := range events {
-			fmt.Printf("  [%s] %s: %s (%s)\n", e.Type, e.Message, e.Status, e.Duration)
+var silenceAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Silence alert notifications",
+	Long: `Stop notifying the alerts that match every --match (key=glob or
+key!=glob over alertname, severity, tenant and the alert's metadata) for
+--for. The alerts still fire and show on the dashboard. Actors in a tenant
+only silence their tenant's alerts.`,
+	Example: "  missionctl observe silence add --match severity=warning --for 2h --comment 'maintenance'",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireMinRole(cmd, authpkg.RoleOperator); err != nil {
+			return err
+		}
+		match, _ := cmd.Flags().GetStringArray("match")
+		forFlag, _ := cmd.Flags().GetString("for")
+		comment, _ := cmd.Flags().GetString("comment")
+		d, err := audit.ParseDuration(forFlag)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid --for %q", forFlag)
+		}
+		if cliTenant != "" {
+			match = append(match, metricspkg.TenantKey+"="+cliTenant)
+		}
+		actor, _ := resolveActor(cmd)
+		now := time.Now()
+		sil, err := notify.NewSilenceStore(silencesPath()).Add(notify.Silence{
+			Match:     match,
+			StartsAt:  now,
+			EndsAt:    now.Add(d),
+			CreatedBy: actor,
+			Comment:   comment,
+		})
+		if err != nil {
+			return err
 		}
+		muted := 0
+		if openMetricsStore() == nil {
+			for _, a := range metricsStore.GetActiveAlerts() {
+				if sil.Matches(notify.Labels(a)) {
+					muted++
+				}
+			}
+		}
+		fmt.Printf("✅ Silence %s added until %s (%d active alerts muted)\n", sil.ID, sil.EndsAt.Format(time.RFC3339), muted)
 		return nil
 	},
 }
 
+var silenceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List silences",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireMinRole(cmd, authpkg.RoleViewer); err != nil {
+			return err
+		}
+		silences, err := tenantSilences()
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		t := printer.Table{Headers: []string{"ID", "MATCH", "STATUS", "ENDS", "CREATED BY", "COMMENT"}, WideFrom: 4, Empty: "No silences"}
+		for _, sil := range silences {
+			status := "active"
+			if now.Before(sil.StartsAt) {
+				status = "pending"
+			} else if !sil.Active(now) {
+				status = "ended"
+			}
+			t.AddRow(sil.ID, strings.Join(sil.Match, ","), status, sil.EndsAt.Format(time.RFC3339), sil.CreatedBy, sil.Comment)
+		}
+		return printResult(cmd, silences, t)
+	},
+}
+
+var silenceExpireCmd = &cobra.Command{
+	Use:   "expire <id>",
+	Short: "End a silence",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireMinRole(cmd, authpkg.RoleOperator); err != nil {
+			return err
+		}
+		silences, err := tenantSilences()
+		if err != nil {
+			return err
+		}
+		for _, sil := range silences {
+			if sil.ID == args[0] {
+				if err := notify.NewSilenceStore(silencesPath()).Expire(sil.ID); err != nil {
+					return err
+				}
+				fmt.Printf("✅ Silence %s expired\n", sil.ID)
+				return nil
+			}
+		}
+		return fmt.Errorf("silence %s not found", args[0])
+	},
+}
+
+// tenantSilences returns the silences the actor may see: all of them for
+// global actors, those limited to their tenant otherwise.
+func tenantSilences() ([]notify.Silence, error) {
+	all, err := notify.NewSilenceStore(silencesPath()).List()
+	if err != nil || cliTenant == "" {
+		return all, err
+	}
+	var out []notify.Silence
+	for _, sil := range all {
+		for _, m := range sil.Match {
+			if m == metricspkg.TenantKey+"="+cliTenant {
+				out = append(out, sil)
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+var eventsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all events",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireMinRole(cmd, authpkg.RoleViewer); err != nil {
+			return err
+		}
+		if err := openMetricsStore(); err != nil {
+			return err
+		}
+
+		events := metricsStore.GetEvents()
+		return printResult(cmd, events, eventTable(events))
+	},
+}
+
 var slackSendCmd = &cobra.Command{
-	Use:   "slack send <webhook-url> <channel> <message>",
+	Use:   "send <webhook-url> <channel> <message>",
 	Short: "Send message to Slack",
 	Args:  cobra.ExactArgs(3),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -253,15 +524,19 @@ var slackSendCmd = &cobra.Command{
 }
 
 var slackAlertCmd = &cobra.Command{
-	Use:   "slack alert <webhook-url> <channel> <alert-name> <severity> <message>",
+	Use:   "alert <webhook-url> <channel> <alert-name> <severity> <message>",
 	Short: "Send alert to Slack",
 	Args:  cobra.ExactArgs(5),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := requireMinRole(cmd, authpkg.RoleOperator); err != nil {
 			return err
 		}
-		client := slackpkg.NewClient(args[0], args[1])
-		err := client.SendAlert(args[2], args[3], args[4], nil)
+		opts, err := slackMessageOptions()
+		if err != nil {
+			return err
+		}
+		client := slackpkg.NewClient(args[0], args[1], opts...)
+		err = client.SendAlert(args[2], args[3], args[4], nil)
 		if err != nil {
 			return fmt.Errorf("failed to send alert: %w", err)
 		}
@@ -271,15 +546,19 @@ var slackAlertCmd = &cobra.Command{
 }
 
 var slackDeployCmd = &cobra.Command{
-	Use:   "slack deploy <webhook-url> <channel> <app> <status> <version>",
+	Use:   "deploy <webhook-url> <channel> <app> <status> <version>",
 	Short: "Send deployment notification to Slack",
 	Args:  cobra.ExactArgs(5),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := requireMinRole(cmd, authpkg.RoleOperator); err != nil {
 			return err
 		}
-		client := slackpkg.NewClient(args[0], args[1])
-		err := client.SendDeployment(args[2], args[3], args[4], nil)
+		opts, err := slackMessageOptions()
+		if err != nil {
+			return err
+		}
+		client := slackpkg.NewClient(args[0], args[1], opts...)
+		err = client.SendDeployment(args[2], args[3], args[4], nil)
 		if err != nil {
 			return fmt.Errorf("failed to send deployment: %w", err)
 		}
@@ -288,6 +567,89 @@ var slackDeployCmd = &cobra.Command{
 	},
 }
 
+var slackPreviewCmd = &cobra.Command{
+	Use:   "preview <alert|deployment|operation|template-file>",
+	Short: "Render a Slack message template with sample data",
+	Long: `Render the message template of a type from slack_templates_dir, or a
+<type>.tmpl or <type>.json file, with sample data and print the message
+payload. Types without a template show the built-in message.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireMinRole(cmd, authpkg.RoleViewer); err != nil {
+			return err
+		}
+		typ := args[0]
+		var templates *slackpkg.Templates
+		var err error
+		if ext := filepath.Ext(typ); ext == ".tmpl" || ext == ".json" {
+			text, err := os.ReadFile(typ)
+			if err != nil {
+				return fmt.Errorf("slack template error: %s", err)
+			}
+			templates = &slackpkg.Templates{}
+			if err := templates.Add(filepath.Base(typ), string(text)); err != nil {
+				return err
+			}
+			typ = strings.TrimSuffix(filepath.Base(typ), ext)
+		} else if templates, err = slackpkg.LoadTemplates(slackTemplatesDir()); err != nil {
+			return err
+		}
+		data, err := slackpkg.SampleData(typ)
+		if err != nil {
+			return err
+		}
+		channel, _ := cmd.Flags().GetString("channel")
+		msg, err := slackpkg.NewClient("", channel, slackpkg.WithTemplates(templates), slackpkg.WithDashboardURL(cliProfile.DashboardURL)).Compose(data)
+		if err != nil {
+			return err
+		}
+		out, err := json.MarshalIndent(msg, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	},
+}
+
+// slackMessageOptions gives Slack clients the message templates of
+// slack_templates_dir and the dashboard_url they link to.
+func slackMessageOptions() ([]slackpkg.Option, error) {
+	templates, err := slackpkg.LoadTemplates(slackTemplatesDir())
+	if err != nil {
+		return nil, err
+	}
+	return []slackpkg.Option{slackpkg.WithTemplates(templates), slackpkg.WithDashboardURL(cliProfile.DashboardURL)}, nil
+}
+
+func metricTable(metrics []metricspkg.Metric) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "VALUE", "UNIT", "TIMESTAMP", "TAGS"}, WideFrom: 3, Empty: "No metrics recorded"}
+	for _, m := range metrics {
+		t.AddRow(m.Name, strconv.FormatFloat(m.Value, 'f', 2, 64), m.Unit, m.Timestamp.Format(time.RFC3339), printer.Pairs(m.Tags))
+	}
+	return t
+}
+
+func alertTable(alerts []metricspkg.Alert) printer.Table {
+	t := printer.Table{Headers: []string{"SEVERITY", "NAME", "MESSAGE", "STATUS", "ID", "TIMESTAMP"}, WideFrom: 4}
+	for _, a := range alerts {
+		status := "Active"
+		if a.Resolved {
+			status = "Resolved"
+		}
+		t.AddRow(a.Severity, a.Name, a.Message, status, a.ID, a.Timestamp.Format(time.RFC3339))
+	}
+	return t
+}
+
+func eventTable(events []metricspkg.Event) printer.Table {
+	t := printer.Table{Headers: []string{"TYPE", "MESSAGE", "STATUS", "DURATION", "USER", "TIMESTAMP"}, WideFrom: 4, Empty: "No events"}
+	for _, e := range events {
+		t.AddRow(e.Type, e.Message, e.Status, e.Duration.String(), e.User, e.Timestamp.Format(time.RFC3339))
+	}
+	return t
+}
+
 func init() {
 	rootCmd.AddCommand(observabilityCmd)
 
@@ -316,7 +678,13 @@ func init() {
 		},
 	}
 	observabilityCmd.AddCommand(metricsCmd)
-	metricsCmd.AddCommand(metricsListCmd, metricsRecordCmd, metricsStatsCmd)
+	metricsCmd.AddCommand(metricsListCmd, metricsRecordCmd, metricsStatsCmd, metricsQueryCmd)
+	metricsQueryCmd.Flags().String("from", "", "start of the range: RFC3339 time or duration ago (default 1h)")
+	metricsQueryCmd.Flags().String("to", "", "end of the range: RFC3339 time or duration ago (default now)")
+	metricsQueryCmd.Flags().String("step", "", "aggregate over windows of this length (e.g. 1m, 1h)")
+	metricsQueryCmd.Flags().String("agg", "", "aggregation: "+metricspkg.Aggregations+" (default avg with --step)")
+	metricsQueryCmd.Flags().StringArray("tag", nil, "tag filter key=glob or key!=glob (repeatable)")
+	metricsQueryCmd.Flags().StringSlice("by", nil, "group into one series per value of these tags")
 
 	// Alerts commands
 	alertsCmd := &cobra.Command{
@@ -329,7 +697,24 @@ func init() {
 		},
 	}
 	observabilityCmd.AddCommand(alertsCmd)
-	alertsCmd.AddCommand(alertsListCmd, alertsActiveCmd, alertsCreateCmd)
+	alertsCmd.AddCommand(alertsListCmd, alertsActiveCmd, alertsCreateCmd, alertsRulesCmd)
+
+	// Silence commands
+	silenceCmd := &cobra.Command{
+		Use:   "silence",
+		Short: "Silence alert notifications",
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 0 {
+				_ = cmd.Help()
+			}
+		},
+	}
+	observabilityCmd.AddCommand(silenceCmd)
+	silenceCmd.AddCommand(silenceAddCmd, silenceListCmd, silenceExpireCmd)
+	silenceAddCmd.Flags().StringArray("match", nil, "alert label matcher key=glob or key!=glob (repeatable, all must match)")
+	silenceAddCmd.Flags().String("for", "1h", "how long the silence lasts (e.g. 30m, 2h, 1d)")
+	silenceAddCmd.Flags().String("comment", "", "why the alerts are silenced")
+	_ = silenceAddCmd.MarkFlagRequired("match")
 
 	// Events commands
 	eventsCmd := &cobra.Command{
@@ -355,5 +740,6 @@ func init() {
 		},
 	}
 	observabilityCmd.AddCommand(slackCmd)
-	slackCmd.AddCommand(slackSendCmd, slackAlertCmd, slackDeployCmd)
+	slackCmd.AddCommand(slackSendCmd, slackAlertCmd, slackDeployCmd, slackPreviewCmd)
+	slackPreviewCmd.Flags().String("channel", "#alerts", "channel the sample message is addressed to")
 }
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/yourusername/devops-mission-control/pkg/printer"
+)
+
+// newPrinter returns a printer for the --output format selected on cmd.
+func newPrinter(cmd *cobra.Command) (*printer.Printer, error) {
+	output, _ := cmd.Flags().GetString("output")
+	return printer.ForOutput(cmd.OutOrStdout(), output)
+}
+
+// printResult renders structured data: t for table, wide and csv output,
+// data itself for json, yaml, jsonpath and go-template.
+func printResult(cmd *cobra.Command, data any, t printer.Table) error {
+	p, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	return p.Print(data, t)
+}
+
+// printText renders the raw output of an underlying CLI through --output.
+func printText(cmd *cobra.Command, text string) error {
+	p, err := newPrinter(cmd)
+	if err != nil {
+		return err
+	}
+	return p.PrintText(text)
+}
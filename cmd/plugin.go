@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/devops-mission-control/pkg/audit"
+	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+	"github.com/yourusername/devops-mission-control/pkg/config"
+	"github.com/yourusername/devops-mission-control/pkg/plugins"
+	"github.com/yourusername/devops-mission-control/pkg/printer"
+)
+
+const pluginGroup = "plugins"
+
+// pluginManager returns a manager for the configured plugins dir.
+func pluginManager() *plugins.Manager {
+	dir := cliProfile.PluginsDir
+	if dir == "" {
+		dir = config.DefaultPluginsDir()
+	}
+	return plugins.NewManager(dir)
+}
+
+// registerPlugins adds a command for every discovered plugin so plugins show
+// up in help and can be invoked as `missionctl <name>`. It runs before cobra
+// parses flags, so the plugins dir is resolved from a quick scan of args.
+// Built-in commands always win over plugins of the same name.
+func registerPlugins(args []string) {
+	path := config.DefaultPath()
+	if p := configFlagValue(args); p != "" {
+		path = p
+	}
+	dir := config.DefaultPluginsDir()
+	if cfg, err := config.Load(path); err == nil {
+		if prof, err := cfg.Resolve(cfg.ActiveProfile()); err == nil && prof.PluginsDir != "" {
+			dir = prof.PluginsDir
+		}
+	}
+	list, err := plugins.NewManager(dir).Discover()
+	if err != nil {
+		return
+	}
+	builtin := map[string]bool{}
+	for _, c := range rootCmd.Commands() {
+		builtin[c.Name()] = true
+		for _, a := range c.Aliases {
+			builtin[a] = true
+		}
+	}
+	for _, p := range list {
+		if p.Shadowed || builtin[p.Name] {
+			continue
+		}
+		if !rootCmd.ContainsGroup(pluginGroup) {
+			rootCmd.AddGroup(&cobra.Group{ID: pluginGroup, Title: "Plugin Commands:"})
+		}
+		rootCmd.AddCommand(newPluginCmd(p))
+		builtin[p.Name] = true
+	}
+}
+
+// configFlagValue returns the value of --config/-c in args, if any.
+func configFlagValue(args []string) string {
+	for i, a := range args {
+		switch {
+		case a == "--":
+			return ""
+		case a == "--config" || a == "-c":
+			if i+1 < len(args) {
+				return args[i+1]
+			}
+		case strings.HasPrefix(a, "--config="):
+			return strings.TrimPrefix(a, "--config=")
+		}
+	}
+	return ""
+}
+
+// newPluginCmd wraps a plugin executable. Flag parsing is disabled so every
+// argument reaches the plugin, except --actor, --token and --config which
+// missionctl consumes itself; put them after `--` to forward them instead.
+func newPluginCmd(p plugins.Plugin) *cobra.Command {
+	short := p.Description
+	if short == "" {
+		short = fmt.Sprintf("Plugin %s (%s)", p.Name, p.Path)
+	}
+	return &cobra.Command{
+		Use:                p.Name,
+		Short:              short,
+		GroupID:            pluginGroup,
+		DisableFlagParsing: true,
+		SilenceUsage:       true,
+		// Globals are only known once RunE has pulled them out of args.
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rest, err := extractGlobalFlags(cmd, args)
+			if err != nil {
+				return err
+			}
+			if err := loadConfig(cmd, rest); err != nil {
+				return err
+			}
+			if err := requireMinRole(cmd, p.Role); err != nil {
+				return err
+			}
+			actor, _ := resolveActor(cmd)
+			token, _ := cmd.Flags().GetString("token")
+
+			env := []string{
+				"MISSIONCTL_ACTOR=" + actor,
+				"MISSIONCTL_TOKEN=" + token,
+				"MISSIONCTL_TENANT=" + cliTenant,
+				config.EnvProfile + "=" + cliConfig.ActiveProfile(),
+				config.EnvConfig + "=" + cliConfigPath,
+			}
+			for _, key := range config.Keys() {
+				if v := cliProfile.Get(key); v != "" {
+					env = append(env, "MISSIONCTL_"+strings.ToUpper(key)+"="+v)
+				}
+			}
+
+			code, runErr := plugins.NewManager("").Run(context.Background(), p, rest, env)
+			details := map[string]any{"args": rest, "exit_code": code, "source": p.Source}
+			if rerr := audit.Record(cliTenant, "plugin.run", actor, p.Name, details); rerr != nil {
+				fmt.Fprintf(os.Stderr, "audit record error: %v\n", rerr)
+			}
+			if runErr != nil {
+				if code > 0 {
+					return fmt.Errorf("plugin %s exited with status %d", p.Name, code)
+				}
+				return fmt.Errorf("plugin %s failed: %w", p.Name, runErr)
+			}
+			return nil
+		},
+	}
+}
+
+// extractGlobalFlags removes --actor, --token and --config from args (up to
+// a `--` separator), sets them on cmd and returns the remaining arguments.
+func extractGlobalFlags(cmd *cobra.Command, args []string) ([]string, error) {
+	rest := []string{}
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			rest = append(rest, args[i+1:]...)
+			break
+		}
+		name, value, hasValue := "", "", false
+		switch {
+		case a == "--actor" || a == "--token" || a == "--config" || a == "-c":
+			name = strings.TrimLeft(a, "-")
+		case strings.HasPrefix(a, "--actor=") || strings.HasPrefix(a, "--token=") || strings.HasPrefix(a, "--config="):
+			name, value, _ = strings.Cut(a[2:], "=")
+			hasValue = true
+		default:
+			rest = append(rest, a)
+			continue
+		}
+		if name == "c" {
+			name = "config"
+		}
+		if !hasValue {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("flag needs an argument: %s", a)
+			}
+			i++
+			value = args[i]
+		}
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return nil, err
+		}
+	}
+	return rest, nil
+}
+
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Manage missionctl plugins",
+	Long: `Plugins are executables named missionctl-<name>, found in the plugins dir
+(plugins_dir in the config file, default $XDG_CONFIG_HOME/missionctl/plugins)
+and on PATH. Run one with ` + "`missionctl <name> [args...]`" + `.
+
+Plugins require the operator role unless installed with --role. They receive
+the caller in MISSIONCTL_ACTOR, MISSIONCTL_TOKEN and MISSIONCTL_TENANT, the
+active profile in MISSIONCTL_PROFILE and every resolved config key as
+MISSIONCTL_<KEY>.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			_ = cmd.Help()
+		}
+	},
+}
+
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List discovered plugins",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireMinRole(cmd, authpkg.RoleViewer); err != nil {
+			return err
+		}
+		list, err := pluginManager().Discover()
+		if err != nil {
+			return err
+		}
+		return printResult(cmd, list, pluginTable(list))
+	},
+}
+
+func pluginTable(list []plugins.Plugin) printer.Table {
+	t := printer.Table{Headers: []string{"NAME", "ROLE", "SOURCE", "STATUS", "PATH", "DESCRIPTION"}, WideFrom: 5, Empty: "No plugins found"}
+	builtin := map[string]bool{}
+	for _, c := range rootCmd.Commands() {
+		if c.GroupID != pluginGroup {
+			builtin[c.Name()] = true
+		}
+	}
+	for _, p := range list {
+		status := "active"
+		switch {
+		case builtin[p.Name]:
+			status = "shadowed by built-in"
+		case p.Shadowed:
+			status = "shadowed"
+		}
+		t.AddRow(p.Name, string(p.Role), p.Source, status, p.Path, printer.Cell(p.Description))
+	}
+	return t
+}
+
+var pluginInstallCmd = &cobra.Command{
+	Use:   "install <file>",
+	Short: "Install an executable into the plugins dir",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireMinRole(cmd, authpkg.RoleAdmin); err != nil {
+			return err
+		}
+		name, _ := cmd.Flags().GetString("name")
+		role, _ := cmd.Flags().GetString("role")
+		desc, _ := cmd.Flags().GetString("description")
+		p, err := pluginManager().Install(args[0], name, plugins.ManifestEntry{Role: authpkg.Role(role), Description: desc})
+		if err != nil {
+			return err
+		}
+		actor, _ := resolveActor(cmd)
+		if rerr := audit.Record(cliTenant, "plugin.install", actor, p.Name, map[string]any{"path": p.Path, "role": p.Role}); rerr != nil {
+			fmt.Fprintf(os.Stderr, "audit record error: %v\n", rerr)
+		}
+		fmt.Printf("✅ Plugin '%s' installed to %s (role: %s)\n", p.Name, p.Path, p.Role)
+		return nil
+	},
+}
+
+var pluginRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a plugin from the plugins dir",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireMinRole(cmd, authpkg.RoleAdmin); err != nil {
+			return err
+		}
+		if err := pluginManager().Remove(args[0]); err != nil {
+			return err
+		}
+		actor, _ := resolveActor(cmd)
+		if rerr := audit.Record(cliTenant, "plugin.remove", actor, args[0], nil); rerr != nil {
+			fmt.Fprintf(os.Stderr, "audit record error: %v\n", rerr)
+		}
+		fmt.Printf("✅ Plugin '%s' removed\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pluginCmd)
+	pluginCmd.AddCommand(pluginListCmd)
+	pluginCmd.AddCommand(pluginInstallCmd)
+	pluginCmd.AddCommand(pluginRemoveCmd)
+
+	pluginInstallCmd.Flags().String("name", "", "Plugin name (default: file name without the missionctl- prefix)")
+	pluginInstallCmd.Flags().String("role", "", "Minimum role required to run the plugin (default operator)")
+	pluginInstallCmd.Flags().String("description", "", "Short description shown in help")
+}
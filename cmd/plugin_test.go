@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestExtractGlobalFlagsForPlugins(t *testing.T) {
+	cmd := &cobra.Command{Use: "hello"}
+	cmd.Flags().String("actor", "", "actor")
+	cmd.Flags().String("token", "", "token")
+	cmd.Flags().String("config", "", "config")
+
+	rest, err := extractGlobalFlags(cmd, []string{"deploy", "--actor", "alice", "-v", "--token=abc", "-c", "/tmp/c.yaml", "--", "--actor", "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"deploy", "-v", "--actor", "x"}; !reflect.DeepEqual(rest, want) {
+		t.Fatalf("expected %v, got %v", want, rest)
+	}
+	actor, _ := cmd.Flags().GetString("actor")
+	token, _ := cmd.Flags().GetString("token")
+	cfg, _ := cmd.Flags().GetString("config")
+	if actor != "alice" || token != "abc" || cfg != "/tmp/c.yaml" {
+		t.Fatalf("unexpected flags actor=%q token=%q config=%q", actor, token, cfg)
+	}
+	if _, err := extractGlobalFlags(cmd, []string{"--actor"}); err == nil {
+		t.Fatal("expected error for missing flag value")
+	}
+	if got := configFlagValue([]string{"hello", "--config=/etc/m.yaml"}); got != "/etc/m.yaml" {
+		t.Fatalf("unexpected config path %q", got)
+	}
+}
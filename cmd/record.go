@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/yourusername/devops-mission-control/pkg/audit"
+	metricspkg "github.com/yourusername/devops-mission-control/pkg/metrics"
+)
+
+// redacted replaces secrets in recorded command lines.
+const redacted = "***"
+
+// secretName matches flags, positional arguments and key=value pairs whose
+// value must not be recorded.
+var secretName = regexp.MustCompile(`(?i)pass|secret|token|credential|(api|access|private|signing)[-_]?key`)
+
+// recordCommand audits a finished command: who ran it and for which
+// tenant, the command line with secrets redacted, the resolved target, the
+// exit status and how long it took. The same facts go to the metrics store
+// as a command event. args is the command line without the program name.
+func recordCommand(cmd *cobra.Command, args []string, err error, start time.Time) {
+	if !auditedCommand(cmd) {
+		return
+	}
+	duration := time.Since(start)
+	verb, resource := commandAction(cmd)
+	req := actionRequest(cmd, verb, resource, "")
+	actor, aerr := resolveActor(cmd)
+	if aerr != nil {
+		actor = stringFlag(cmd, "actor")
+	}
+	line := append([]string{rootCmd.Name()}, redactArgs(cmd, args)...)
+	target := commandTarget(cmd, resource)
+	code, status := 0, "success"
+	details := map[string]any{
+		"command":     strings.Join(line, " "),
+		"verb":        verb,
+		"duration_ms": duration.Milliseconds(),
+	}
+	if req.Environment != "" {
+		details["environment"] = req.Environment
+	}
+	if req.Namespace != "" {
+		details["namespace"] = req.Namespace
+	}
+	if req.Account != "" {
+		details["account"] = req.Account
+	}
+	if err != nil {
+		code, status = 1, "failure"
+		details["error"] = err.Error()
+	}
+	details["exit_code"] = code
+
+	action := "cli." + verb
+	if resource != "" {
+		action = "cli." + strings.ReplaceAll(resource, "/", ".") + "." + verb
+	}
+	if rerr := audit.Record(cliTenant, action, actor, target, details); rerr != nil {
+		fmt.Fprintf(os.Stderr, "audit record error: %v\n", rerr)
+	}
+
+	if merr := openMetricsStore(); merr != nil {
+		fmt.Fprintf(os.Stderr, "metrics record error: %v\n", merr)
+		return
+	}
+	metadata := map[string]string{"verb": verb, "exit_code": strconv.Itoa(code)}
+	if cliTenant != "" {
+		metadata["tenant"] = cliTenant
+	}
+	if err != nil {
+		metadata["error"] = err.Error()
+	}
+	if merr := metricsStore.AddEvent(metricspkg.Event{
+		Type:     "command",
+		Message:  strings.Join(line, " "),
+		Status:   status,
+		Duration: duration,
+		Metadata: metadata,
+		User:     actor,
+		Resource: target,
+	}); merr != nil {
+		fmt.Fprintf(os.Stderr, "metrics record error: %v\n", merr)
+	}
+}
+
+// auditedCommand reports whether cmd did something worth recording: help
+// output, shell completion and bare command groups are not.
+func auditedCommand(cmd *cobra.Command) bool {
+	if cmd == nil || cmd == rootCmd || !cmd.Runnable() {
+		return false
+	}
+	switch cmd.Name() {
+	case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return false
+	}
+	if cmd.HasParent() && cmd.Parent().Name() == "completion" {
+		return false
+	}
+	if f := cmd.Flags().Lookup("help"); f != nil && f.Changed {
+		return false
+	}
+	return true
+}
+
+// commandTarget is the resource the command acted on, followed by its first
+// argument when it has one, e.g. k8s/pods/web-1.
+func commandTarget(cmd *cobra.Command, resource string) string {
+	if resource == "" {
+		resource = cmd.Name()
+	}
+	if cmd.DisableFlagParsing {
+		return resource
+	}
+	args := cmd.Flags().Args()
+	names := argNames(cmd.Use)
+	if len(args) == 0 || (len(names) > 0 && secretName.MatchString(names[0])) {
+		return resource
+	}
+	return resource + "/" + args[0]
+}
+
+// redactArgs returns args with the values of secret flags, secret
+// positional arguments (by their name in cmd.Use, or following a <key>
+// argument naming a secret, as in config set) and secret key=value pairs
+// replaced.
+func redactArgs(cmd *cobra.Command, args []string) []string {
+	out := append([]string(nil), args...)
+	depth := len(strings.Fields(cmd.CommandPath())) - 1
+	names := argNames(cmd.Use)
+	words, flagsDone, secretKey := 0, false, false
+	for i := 0; i < len(out); i++ {
+		a := out[i]
+		if !flagsDone && a == "--" {
+			flagsDone = true
+			continue
+		}
+		if !flagsDone && len(a) > 1 && strings.HasPrefix(a, "-") {
+			name, value, hasValue := strings.Cut(strings.TrimLeft(a, "-"), "=")
+			f := lookupFlag(cmd, name, !strings.HasPrefix(a, "--"))
+			secret := secretName.MatchString(name) || (f != nil && secretName.MatchString(f.Name))
+			if hasValue {
+				if secret && value != "" {
+					out[i] = strings.TrimSuffix(a, value) + redacted
+				} else {
+					out[i] = strings.TrimSuffix(a, value) + redactPair(value)
+				}
+				continue
+			}
+			if f != nil && f.NoOptDefVal == "" && i+1 < len(out) {
+				i++
+				if secret {
+					out[i] = redacted
+				} else {
+					out[i] = redactPair(out[i])
+				}
+			}
+			continue
+		}
+		words++
+		if words <= depth {
+			continue
+		}
+		n := words - depth - 1
+		if n >= len(names) && len(names) > 0 && strings.HasSuffix(cmd.Use, "...") {
+			n = len(names) - 1
+		}
+		if secretKey || (n < len(names) && secretName.MatchString(names[n])) {
+			out[i] = redacted
+		} else {
+			out[i] = redactPair(a)
+		}
+		secretKey = n < len(names) && names[n] == "key" && secretName.MatchString(a)
+	}
+	return out
+}
+
+// redactPair hides the value of a key=value argument with a secret key.
+func redactPair(s string) string {
+	if k, _, ok := strings.Cut(s, "="); ok && secretName.MatchString(k) {
+		return k + "=" + redacted
+	}
+	return s
+}
+
+// lookupFlag finds a flag of cmd by long name or shorthand.
+func lookupFlag(cmd *cobra.Command, name string, short bool) *pflag.Flag {
+	if short && len(name) == 1 {
+		return cmd.Flags().ShorthandLookup(name)
+	}
+	return cmd.Flags().Lookup(name)
+}
+
+// argNames lists the positional argument names of a Use line, e.g.
+// "passwd <username> [password]" gives username, password.
+func argNames(use string) []string {
+	fields := strings.Fields(use)
+	if len(fields) > 0 {
+		fields = fields[1:]
+	}
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		names = append(names, strings.Trim(f, "<>[]."))
+	}
+	return names
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/devops-mission-control/pkg/audit"
+)
+
+func TestRedactArgs(t *testing.T) {
+	group := &cobra.Command{Use: "user"}
+	passwd := &cobra.Command{Use: "passwd <username> [password]", Run: func(*cobra.Command, []string) {}}
+	passwd.Flags().String("token", "", "")
+	passwd.Flags().StringP("namespace", "n", "", "")
+	passwd.Flags().Bool("force", false, "")
+	passwd.Flags().String("set", "", "")
+	group.AddCommand(passwd)
+	rootCmd.AddCommand(group)
+	t.Cleanup(func() { rootCmd.RemoveCommand(group) })
+
+	for _, tc := range []struct{ in, want string }{
+		{"user passwd bob s3cret", "user passwd bob ***"},
+		{"user --token abc passwd bob", "user --token *** passwd bob"},
+		{"user passwd --token=abc -n prod bob", "user passwd --token=*** -n prod bob"},
+		{"user passwd --force bob hunter2", "user passwd --force bob ***"},
+		{"user passwd bob --set db_password=x", "user passwd bob --set db_password=***"},
+		{"user passwd -- bob api_key=x", "user passwd -- bob ***"},
+	} {
+		if got := strings.Join(redactArgs(passwd, strings.Fields(tc.in)), " "); got != tc.want {
+			t.Errorf("redactArgs(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+
+	// the value of a secret config key
+	for _, tc := range []struct{ in, want string }{
+		{"config set slack_bot_token xoxb-1", "config set slack_bot_token ***"},
+		{"config set slack_signing_secret abc", "config set slack_signing_secret ***"},
+		{"config set --config=other.yaml audit_signing_key /etc/k", "config set --config=other.yaml audit_signing_key ***"},
+		{"config set namespace prod", "config set namespace prod"},
+	} {
+		if got := strings.Join(redactArgs(configSetCmd, strings.Fields(tc.in)), " "); got != tc.want {
+			t.Errorf("redactArgs(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestRecordCommand(t *testing.T) {
+	origWd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() {
+		if cerr := os.Chdir(origWd); cerr != nil {
+			t.Fatalf("failed to chdir back: %v", cerr)
+		}
+	})
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	group := &cobra.Command{Use: "widgets"}
+	del := &cobra.Command{Use: "delete <name>", Run: func(*cobra.Command, []string) {}}
+	del.Flags().StringP("namespace", "n", "", "")
+	group.AddCommand(del)
+	rootCmd.AddCommand(group)
+	t.Cleanup(func() { rootCmd.RemoveCommand(group) })
+	args := []string{"widgets", "delete", "web-1", "-n", "staging", "--actor", "alice"}
+	rootCmd.SetArgs(args)
+	t.Cleanup(func() { rootCmd.SetArgs(nil) })
+	cmd, err := rootCmd.ExecuteC()
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now().Add(-1500 * time.Millisecond)
+	recordCommand(cmd, args, errors.New("boom"), start)
+	// groups and help are not recorded
+	recordCommand(group, []string{"widgets"}, nil, start)
+
+	entries, err := audit.ReadEntries("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("want 1 entry, got %+v", entries)
+	}
+	e := entries[0]
+	if e.Action != "cli.widgets.delete" || e.Actor != "alice" || e.Target != "widgets/web-1" {
+		t.Fatalf("unexpected entry: %+v", e)
+	}
+	for field, want := range map[string]string{
+		"details.command":   "missionctl widgets delete web-1 -n staging --actor alice",
+		"details.namespace": "staging",
+		"details.exit_code": "1",
+		"details.error":     "boom",
+	} {
+		if got := e.Field(field); got != want {
+			t.Errorf("%s = %q, want %q", field, got, want)
+		}
+	}
+	if ms, _ := strconv.Atoi(e.Field("details.duration_ms")); ms < 1500 {
+		t.Errorf("duration_ms = %d", ms)
+	}
+	events := metricsStore.GetEventsByType("command")
+	if ev := events[len(events)-1]; ev.Status != "failure" || ev.User != "alice" || ev.Resource != "widgets/web-1" || ev.Duration < 1500*time.Millisecond {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+}
+
+func TestCommandPath(t *testing.T) {
+	for args, want := range map[string]string{
+		"k8s pods list prod -n web":   "k8s pods list",
+		"observe alerts list -o json": "observe alerts list",
+		"helm list":                   "helm list",
+	} {
+		if got, err := commandPath(strings.Fields(args)); err != nil || got != want {
+			t.Errorf("%q: %q, %v", args, got, err)
+		}
+	}
+	for _, bad := range []string{"", "kubectl get pods"} {
+		if got, err := commandPath(strings.Fields(bad)); err == nil {
+			t.Errorf("%q resolved to %q", bad, got)
+		}
+	}
+}
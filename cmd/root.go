@@ -1,7 +1,13 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+	"time"
+
 	"github.com/spf13/cobra"
+	"github.com/yourusername/devops-mission-control/pkg/audit"
+	"github.com/yourusername/devops-mission-control/pkg/printer"
 )
 
 var rootCmd = &cobra.Command{
@@ -19,7 +25,15 @@ Think of it as k9s for the entire DevOps ecosystem.`,
 }
 
 func Execute() error {
-	return rootCmd.Execute()
+	registerPlugins(os.Args[1:])
+	start := time.Now()
+	cmd, err := rootCmd.ExecuteC()
+	recordCommand(cmd, os.Args[1:], err, start)
+	// Flush entries still queued for the audit sinks.
+	if cerr := audit.Close(); cerr != nil {
+		fmt.Fprintf(os.Stderr, "audit close error: %v\n", cerr)
+	}
+	return err
 }
 
 func init() {
@@ -33,9 +47,13 @@ func init() {
 	// These are defined in user.go and must be imported for registration
 	// The blank import in main.go ensures user.go's init runs
 
+	// Every command starts by loading the config file and active profile
+	rootCmd.PersistentPreRunE = loadConfig
+
 	// Global flags
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
-	rootCmd.PersistentFlags().StringP("config", "c", "", "config file")
+	rootCmd.PersistentFlags().StringP("config", "c", "", "config file (default $XDG_CONFIG_HOME/missionctl/config.yaml)")
+	rootCmd.PersistentFlags().StringP("output", "o", "table", "output format: "+printer.Formats)
 	// Authorization flags: either supply `--actor <username>` for interactive user
 	// or `--token <token>` for API token-based calls. These are used by RBAC checks.
 	rootCmd.PersistentFlags().String("actor", "", "actor username performing the action")
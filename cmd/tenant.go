@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/devops-mission-control/pkg/audit"
+	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+	"github.com/yourusername/devops-mission-control/pkg/printer"
+)
+
+var tenantStore = authpkg.OpenTenantStore(authpkg.NewFileStore("", ""))
+
+var tenantCmd = &cobra.Command{
+	Use:   "tenant",
+	Short: "Tenant management",
+	Long: `Tenants isolate groups of users and their tokens. A user in a tenant may
+only use the kube contexts, AWS profiles, GCP projects and Azure
+subscriptions of the tenant, only sees and manages the users, tokens and
+audit entries of the tenant, and gets the same limits on the dashboard.
+Users without a tenant are global. Only global admins manage tenants.`,
+}
+
+var tenantCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a tenant and the accounts it may use",
+	Example: `  missionctl tenant create acme --kube-context 'acme-*' --aws-profile acme-prod,acme-dev
+  missionctl tenant create beta --gcp-project beta-123 --azure-subscription 0000-aaaa`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireGlobalAdmin(cmd); err != nil {
+			return err
+		}
+		t := &authpkg.Tenant{Name: args[0]}
+		t.KubeContexts, _ = cmd.Flags().GetStringSlice("kube-context")
+		t.AWSProfiles, _ = cmd.Flags().GetStringSlice("aws-profile")
+		t.GCPProjects, _ = cmd.Flags().GetStringSlice("gcp-project")
+		t.AzureSubscriptions, _ = cmd.Flags().GetStringSlice("azure-subscription")
+		if err := tenantStore.CreateTenant(t); err != nil {
+			return err
+		}
+		actor, _ := resolveActor(cmd)
+		details := map[string]any{
+			"kube_contexts":       t.KubeContexts,
+			"aws_profiles":        t.AWSProfiles,
+			"gcp_projects":        t.GCPProjects,
+			"azure_subscriptions": t.AzureSubscriptions,
+		}
+		if rerr := audit.Record(t.Name, "tenant.create", actor, t.Name, details); rerr != nil {
+			fmt.Fprintf(os.Stderr, "audit record error: %v\n", rerr)
+		}
+		fmt.Printf("✅ Tenant '%s' created\n", t.Name)
+		return nil
+	},
+}
+
+var tenantListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tenants",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireMinRole(cmd, authpkg.RoleViewer); err != nil {
+			return err
+		}
+		members := map[string]int{}
+		for _, u := range userStore.ListUsers() {
+			members[u.Tenant]++
+		}
+		tenants := []*authpkg.Tenant{}
+		for _, t := range tenantStore.ListTenants() {
+			if cliTenant == "" || t.Name == cliTenant {
+				tenants = append(tenants, t)
+			}
+		}
+		t := printer.Table{
+			Headers:  []string{"NAME", "USERS", "KUBE CONTEXTS", "AWS PROFILES", "GCP PROJECTS", "AZURE SUBSCRIPTIONS", "CREATED"},
+			WideFrom: 6,
+			Empty:    "No tenants found",
+		}
+		for _, tn := range tenants {
+			t.AddRow(tn.Name, strconv.Itoa(members[tn.Name]), printer.Join(tn.KubeContexts), printer.Join(tn.AWSProfiles),
+				printer.Join(tn.GCPProjects), printer.Join(tn.AzureSubscriptions), tn.CreatedAt.Format(time.RFC3339))
+		}
+		return printResult(cmd, tenants, t)
+	},
+}
+
+var tenantAssignCmd = &cobra.Command{
+	Use:   "assign <username> <tenant|->",
+	Short: "Move a user and their tokens into a tenant",
+	Long:  `Move a user, and every token issued to them, into a tenant. Use "-" to make the user global again.`,
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		username, tenant := args[0], args[1]
+		if err := requireGlobalAdmin(cmd); err != nil {
+			return err
+		}
+		if tenant == "-" {
+			tenant = ""
+		} else if _, err := tenantStore.GetTenant(tenant); err != nil {
+			return fmt.Errorf("tenant %s not found", tenant)
+		}
+		u, err := userStore.GetUser(username)
+		if err != nil {
+			return fmt.Errorf("user %s not found", username)
+		}
+		from := u.Tenant
+		if err := userStore.SetUserTenant(username, tenant); err != nil {
+			return err
+		}
+		if err := tokenStore.SetTenant(username, tenant); err != nil {
+			return err
+		}
+		actor, _ := resolveActor(cmd)
+		if rerr := audit.Record(tenant, "tenant.assign", actor, username, map[string]any{"from": from, "to": tenant}); rerr != nil {
+			fmt.Fprintf(os.Stderr, "audit record error: %v\n", rerr)
+		}
+		if tenant == "" {
+			fmt.Printf("✅ User '%s' is now global\n", username)
+		} else {
+			fmt.Printf("✅ User '%s' assigned to tenant '%s'\n", username, tenant)
+		}
+		return nil
+	},
+}
+
+// requireGlobalAdmin allows admins that are not in a tenant.
+func requireGlobalAdmin(cmd *cobra.Command) error {
+	if err := requireAdmin(cmd); err != nil {
+		return err
+	}
+	if cliTenant != "" {
+		return errors.New("forbidden: only global admins manage tenants")
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(tenantCmd)
+	tenantCmd.AddCommand(tenantCreateCmd, tenantListCmd, tenantAssignCmd)
+	tenantCreateCmd.Flags().StringSlice("kube-context", nil, "kube contexts the tenant may use (globs, repeatable)")
+	tenantCreateCmd.Flags().StringSlice("aws-profile", nil, "AWS profiles the tenant may use (globs, repeatable)")
+	tenantCreateCmd.Flags().StringSlice("gcp-project", nil, "GCP projects the tenant may use (globs, repeatable)")
+	tenantCreateCmd.Flags().StringSlice("azure-subscription", nil, "Azure subscriptions the tenant may use (globs, repeatable)")
+	authzEngine.UseTenants(func(name string) (*authpkg.Tenant, error) { return tenantStore.GetTenant(name) })
+}
@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+	"github.com/yourusername/devops-mission-control/pkg/printer"
 	terraformpkg "github.com/yourusername/devops-mission-control/pkg/terraform"
 )
 
@@ -33,8 +38,7 @@ var terraformVersionCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to get version: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -50,8 +54,7 @@ var terraformValidateCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to validate: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -67,8 +70,7 @@ var terraformInitCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to init: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -84,8 +86,7 @@ var terraformPlanCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to plan: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -96,13 +97,17 @@ var terraformApplyCmd = &cobra.Command{
 		if err := requireMinRole(cmd, authpkg.RoleAdmin); err != nil {
 			return err
 		}
+		approved, err := requireApproval(cmd, terraformCommand("apply"))
+		if err != nil {
+			return err
+		}
 		client := terraformpkg.NewClient(terraformWorkdir)
 		output, err := client.Apply("")
+		approved.Finish(err)
 		if err != nil {
 			return fmt.Errorf("failed to apply: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -113,13 +118,17 @@ var terraformDestroyCmd = &cobra.Command{
 		if err := requireMinRole(cmd, authpkg.RoleViewer); err != nil {
 			return err
 		}
+		approved, err := requireApproval(cmd, terraformCommand("destroy"))
+		if err != nil {
+			return err
+		}
 		client := terraformpkg.NewClient(terraformWorkdir)
 		output, err := client.Destroy("")
+		approved.Finish(err)
 		if err != nil {
 			return fmt.Errorf("failed to destroy: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -135,8 +144,7 @@ var terraformFormatCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to format: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -158,12 +166,11 @@ var terraformStateListCmd = &cobra.Command{
 			return err
 		}
 		client := terraformpkg.NewClient(terraformWorkdir)
-		output, err := client.StateList()
+		resources, err := client.StateList()
 		if err != nil {
 			return fmt.Errorf("failed to list state: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, resources, stateTable(resources))
 	},
 }
 
@@ -180,8 +187,7 @@ var terraformStateShowCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("failed to show state: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printText(cmd, output)
 	},
 }
 
@@ -193,12 +199,11 @@ var terraformOutputCmd = &cobra.Command{
 			return err
 		}
 		client := terraformpkg.NewClient(terraformWorkdir)
-		output, err := client.OutputAll()
+		outputs, err := client.OutputAll()
 		if err != nil {
 			return fmt.Errorf("failed to get output: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, outputs, outputTable(outputs))
 	},
 }
 
@@ -220,15 +225,53 @@ var terraformWorkspaceListCmd = &cobra.Command{
 			return err
 		}
 		client := terraformpkg.NewClient(terraformWorkdir)
-		output, err := client.WorkspaceList()
+		workspaces, err := client.WorkspaceList()
 		if err != nil {
 			return fmt.Errorf("failed to list workspaces: %w", err)
 		}
-		fmt.Println(output)
-		return nil
+		return printResult(cmd, workspaces, workspaceTable(workspaces))
 	},
 }
 
+func stateTable(resources []string) printer.Table {
+	t := printer.Table{Headers: []string{"RESOURCE"}, Empty: "No resources in state"}
+	for _, r := range resources {
+		t.AddRow(r)
+	}
+	return t
+}
+
+func outputTable(outputs map[string]terraformpkg.Output) printer.Table {
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	t := printer.Table{Headers: []string{"NAME", "VALUE", "SENSITIVE"}, Empty: "No outputs defined"}
+	for _, name := range names {
+		o := outputs[name]
+		value := "<sensitive>"
+		if !o.Sensitive {
+			b, _ := json.Marshal(o.Value)
+			value = strings.Trim(string(b), `"`)
+		}
+		t.AddRow(name, value, strconv.FormatBool(o.Sensitive))
+	}
+	return t
+}
+
+func workspaceTable(workspaces []terraformpkg.Workspace) printer.Table {
+	t := printer.Table{Headers: []string{"CURRENT", "NAME"}}
+	for _, ws := range workspaces {
+		current := ""
+		if ws.Current {
+			current = "*"
+		}
+		t.AddRow(current, ws.Name)
+	}
+	return t
+}
+
 func init() {
 	rootCmd.AddCommand(terraformCmd)
 	terraformCmd.AddCommand(
@@ -253,3 +296,12 @@ func init() {
 	// Flags
 	terraformCmd.PersistentFlags().StringVarP(&terraformWorkdir, "workdir", "d", ".", "Terraform working directory")
 }
+
+// terraformCommand describes the subcommand run in the working directory
+// for approval requests.
+func terraformCommand(sub string) string {
+	if terraformWorkdir == "" || terraformWorkdir == "." {
+		return "terraform " + sub
+	}
+	return "terraform " + sub + " -d " + terraformWorkdir
+}
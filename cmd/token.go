@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/spf13/cobra"
 	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+	"github.com/yourusername/devops-mission-control/pkg/printer"
 )
 
 var tokenStore = authpkg.NewTokenStore("", "tokens.json")
@@ -32,11 +35,15 @@ var tokenCreateCmd = &cobra.Command{
 		if err := requireAdminOrSelf(cmd, username); err != nil {
 			return err
 		}
+		if err := requireSameTenant(cmd, username); err != nil {
+			return err
+		}
 		tok, err := tokenStore.GenerateToken(username, name, duration)
 		if err != nil {
 			return err
 		}
 		fmt.Printf("✅ Token created: %s\n", tok.Token)
+		fmt.Printf("   Copy it now: only its prefix (%s) is kept and shown from here on.\n", tok.Prefix)
 		return nil
 	},
 }
@@ -59,10 +66,14 @@ var tokenListCmd = &cobra.Command{
 			}
 			filtered = toks
 		} else {
-			// check role; if admin see all, else filter
+			// check role; admins see all tokens of their tenant, others their own
 			u, err := userStore.GetUser(actor)
 			if err == nil && u.Role == authpkg.RoleAdmin {
-				filtered = toks
+				for _, t := range toks {
+					if u.Tenant == "" || t.Tenant == u.Tenant {
+						filtered = append(filtered, t)
+					}
+				}
 			} else {
 				for _, t := range toks {
 					if t.User == actor {
@@ -71,51 +82,62 @@ var tokenListCmd = &cobra.Command{
 				}
 			}
 		}
-		if len(filtered) == 0 {
-			fmt.Println("No tokens found")
-			return nil
-		}
-		fmt.Println("Tokens:")
-		for _, t := range filtered {
-			exp := "never"
-			if t.ExpiresAt != nil {
-				exp = t.ExpiresAt.String()
-			}
-			fmt.Printf("  %s (user: %s, name: %s, revoked: %v, expires: %s)\n", t.Token, t.User, t.Name, t.Revoked, exp)
+		sort.Slice(filtered, func(i, j int) bool { return filtered[i].CreatedAt.Before(filtered[j].CreatedAt) })
+		// structured output gets the same fields as the table, without hashes
+		shown := make([]*authpkg.Token, len(filtered))
+		for i, t := range filtered {
+			c := *t
+			c.SecretHash = ""
+			shown[i] = &c
 		}
-		return nil
+		return printResult(cmd, shown, tokenTable(shown))
 	},
 }
 
+func tokenTable(toks []*authpkg.Token) printer.Table {
+	t := printer.Table{Headers: []string{"PREFIX", "USER", "NAME", "REVOKED", "EXPIRES", "CREATED", "TENANT"}, WideFrom: 5, Empty: "No tokens found"}
+	for _, tok := range toks {
+		exp := "never"
+		if tok.ExpiresAt != nil {
+			exp = tok.ExpiresAt.Format(time.RFC3339)
+		}
+		t.AddRow(tok.Prefix, tok.User, tok.Name, strconv.FormatBool(tok.Revoked), exp, tok.CreatedAt.Format(time.RFC3339), printer.Cell(tok.Tenant))
+	}
+	return t
+}
+
 var tokenRevokeCmd = &cobra.Command{
-	Use:   "revoke",
+	Use:   "revoke <prefix|token>",
 	Short: "Revoke an API token",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		t := args[0]
 		// only admin or token owner may revoke
-		tok, err := tokenStore.Validate(t)
+		tok, err := tokenStore.Lookup(t)
 		if err != nil {
 			return err
 		}
 		if err := requireAdminOrSelf(cmd, tok.User); err != nil {
 			return err
 		}
+		if err := requireSameTenant(cmd, tok.User); err != nil {
+			return err
+		}
 		if err := tokenStore.Revoke(t); err != nil {
 			return err
 		}
-		fmt.Println("✅ Token revoked")
+		fmt.Printf("✅ Token %s revoked\n", tok.Prefix)
 		return nil
 	},
 }
 
 var tokenRotateCmd = &cobra.Command{
-	Use:   "rotate",
+	Use:   "rotate <prefix|token>",
 	Short: "Rotate an API token (issue new, revoke old)",
-	Args:  cobra.ExactArgs(1), // old token
+	Args:  cobra.ExactArgs(1), // old token or its prefix
 	RunE: func(cmd *cobra.Command, args []string) error {
 		old := args[0]
-		tok, err := tokenStore.Validate(old)
+		tok, err := tokenStore.Lookup(old)
 		if err != nil {
 			return err
 		}
@@ -123,19 +145,22 @@ var tokenRotateCmd = &cobra.Command{
 		if err := requireAdminOrSelf(cmd, tok.User); err != nil {
 			return err
 		}
+		if err := requireSameTenant(cmd, tok.User); err != nil {
+			return err
+		}
 		ttlHours, _ := cmd.Flags().GetInt("ttl")
 		duration := time.Duration(ttlHours) * time.Hour
 		newTok, err := tokenStore.Rotate(old, duration)
 		if err != nil {
 			return err
 		}
-		fmt.Printf("✅ Token rotated: %s (replaced %s)\n", newTok.Token, old)
+		fmt.Printf("✅ Token rotated: %s (replaced %s)\n", newTok.Token, tok.Prefix)
 		return nil
 	},
 }
 
 var tokenValidateCmd = &cobra.Command{
-	Use:   "validate",
+	Use:   "validate <token>",
 	Short: "Validate an API token",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -151,7 +176,7 @@ var tokenValidateCmd = &cobra.Command{
 		if tok.ExpiresAt != nil {
 			exp = tok.ExpiresAt.String()
 		}
-		fmt.Printf("Token valid: user=%s name=%s expires=%s revoked=%v\n", tok.User, tok.Name, exp, tok.Revoked)
+		fmt.Printf("Token valid: prefix=%s user=%s name=%s expires=%s revoked=%v\n", tok.Prefix, tok.User, tok.Name, exp, tok.Revoked)
 		return nil
 	},
 }
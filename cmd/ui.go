@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourusername/devops-mission-control/pkg/audit"
+	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+	awspkg "github.com/yourusername/devops-mission-control/pkg/aws"
+	azurepkg "github.com/yourusername/devops-mission-control/pkg/azure"
+	"github.com/yourusername/devops-mission-control/pkg/docker"
+	gcppkg "github.com/yourusername/devops-mission-control/pkg/gcp"
+	helmpkg "github.com/yourusername/devops-mission-control/pkg/helm"
+	"github.com/yourusername/devops-mission-control/pkg/k8s"
+	"github.com/yourusername/devops-mission-control/pkg/printer"
+	"github.com/yourusername/devops-mission-control/pkg/tui"
+	"github.com/yourusername/devops-mission-control/pkg/yamlite"
+)
+
+var uiCmd = &cobra.Command{
+	Use:   "ui",
+	Short: "Full-screen terminal UI for Kubernetes, Docker and cloud resources",
+	Long: `Open a k9s-style terminal UI with views for pods, deployments, containers,
+EC2/GCE/Azure VMs and helm releases.
+
+Switch views with 1-9, tab or :<view>, filter with /, refresh with r and press
+? for every key binding. Actions (logs, describe, exec, scale, delete,
+port-forward) are checked against your role and written to the audit log;
+a view only loads when you may list its resources.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := requireMinRole(cmd, authpkg.RoleViewer); err != nil {
+			return err
+		}
+		ns, _ := cmd.Flags().GetString("namespace")
+		kubeContext, _ := cmd.Flags().GetString("context")
+		refresh, _ := cmd.Flags().GetDuration("refresh")
+		if ns == "" {
+			ns = cliProfile.Namespace
+		}
+		if kubeContext == "" {
+			kubeContext = cliProfile.KubeContext
+		}
+		actor, _ := resolveActor(cmd)
+
+		title := "missionctl"
+		if p := cliConfig.ActiveProfile(); p != "" {
+			title += " [" + p + "]"
+		}
+		if kubeContext != "" {
+			title += " │ " + kubeContext
+		}
+
+		views := uiViews(ns, kubeContext)
+		authorizeLoads(cmd, views)
+		app := &tui.App{
+			Title:   title,
+			Views:   views,
+			Refresh: refresh,
+			Authorize: func(v *tui.View, a *tui.Action, row tui.Row) error {
+				return authorize(cmd, a.Name, uiResources[v.Name], a.Role)
+			},
+			Audit: func(v *tui.View, a *tui.Action, row tui.Row, input string, err error) {
+				details := map[string]any{"allowed": true}
+				if input != "" {
+					details["input"] = input
+				}
+				if err != nil {
+					details["error"] = err.Error()
+					if strings.HasPrefix(err.Error(), "forbidden") {
+						details["allowed"] = false
+					}
+				}
+				if rerr := audit.Record(cliTenant, "ui."+a.Name, actor, v.Name+"/"+row.ID, details); rerr != nil {
+					fmt.Fprintf(os.Stderr, "audit record error: %v\n", rerr)
+				}
+			},
+		}
+		return app.Run()
+	},
+}
+
+// uiResources names the resource of each view the way the matching CLI
+// command does, so policy rules apply to UI actions as well.
+var uiResources = map[string]string{
+	"pods":        "k8s/pods",
+	"deployments": "k8s/deployments",
+	"containers":  "docker/containers",
+	"ec2":         "aws/ec2",
+	"gce":         "gcp/compute",
+	"azure-vms":   "azure/vm",
+	"releases":    "helm",
+}
+
+// authorizeLoads makes every view check that the actor may list its
+// resource, as the matching CLI list command does, before loading it. A
+// denial shows in the view instead of the rows.
+func authorizeLoads(cmd *cobra.Command, views []*tui.View) {
+	for _, v := range views {
+		v, load := v, v.Load
+		v.Load = func() ([]tui.Row, error) {
+			if err := authorize(cmd, "list", uiResources[v.Name], authpkg.RoleViewer); err != nil {
+				return nil, err
+			}
+			return load()
+		}
+	}
+}
+
+// lazy builds a client on first use, so views that are never opened never
+// shell out (the gcloud and az clients look up defaults when created).
+func lazy[T any](build func() T) func() T {
+	var once sync.Once
+	var v T
+	return func() T {
+		once.Do(func() { v = build() })
+		return v
+	}
+}
+
+// tableRows turns the rows of a command's printer.Table into UI rows, so the
+// UI shows the same columns as `-o wide`.
+func tableRows[T any](items []T, t printer.Table, id func(T) string) []tui.Row {
+	rows := make([]tui.Row, len(items))
+	for i, item := range items {
+		rows[i] = tui.Row{ID: id(item), Cells: t.Rows[i], Object: item}
+	}
+	return rows
+}
+
+// describeObject renders the row's typed resource as YAML, for clouds whose
+// client has no describe call.
+func describeObject(row tui.Row, _ string) (string, error) {
+	out, err := yamlite.Marshal(row.Object)
+	return string(out), err
+}
+
+func uiViews(ns, kubeContext string) []*tui.View {
+	kube := lazy(func() *k8s.Client { return k8s.NewClient(ns, kubeContext) })
+	dock := lazy(func() *docker.Client { return docker.NewClient() })
+	ec2 := lazy(func() *awspkg.Client { return awspkg.NewClient(cliProfile.AWSRegion, cliProfile.AWSProfile) })
+	gce := lazy(func() *gcppkg.Client { return gcppkg.NewClient(cliProfile.GCPProject, cliProfile.GCPRegion) })
+	az := lazy(func() *azurepkg.Client {
+		return azurepkg.NewClient(cliProfile.AzureSubscription, cliProfile.AzureResourceGroup)
+	})
+	helm := lazy(func() *helmpkg.Client { return helmpkg.NewClient(kube().Namespace) })
+
+	pods := &tui.View{
+		Name:    "pods",
+		Headers: podTable(nil).Headers,
+		Load: func() ([]tui.Row, error) {
+			pods, err := kube().ListPods(kube().Namespace)
+			return tableRows(pods, podTable(pods), func(p k8s.Pod) string { return p.Name }), err
+		},
+		Actions: []*tui.Action{
+			{Key: tui.R('l'), Name: "logs", Role: authpkg.RoleViewer, Interactive: true, Run: func(row tui.Row, _ string) (string, error) {
+				return "", kube().GetPodLogs(row.ID, "", true)
+			}},
+			{Key: tui.R('d'), Name: "describe", Role: authpkg.RoleViewer, ShowOutput: true, Run: func(row tui.Row, _ string) (string, error) {
+				return kube().DescribePod(row.ID, "")
+			}},
+			{Key: tui.R('s'), Name: "exec", Role: authpkg.RoleOperator, Interactive: true, Run: func(row tui.Row, _ string) (string, error) {
+				return "", kube().ExecInPod(row.ID, "", "", []string{"sh"})
+			}},
+			{Key: tui.R('f'), Name: "port-forward", Role: authpkg.RoleOperator, Interactive: true, Prompt: "local:remote ports", Run: func(row tui.Row, ports string) (string, error) {
+				return "", kube().PortForward(row.ID, "", ports)
+			}},
+			{Key: tui.Key{Name: "ctrl-d"}, Name: "delete", Role: authpkg.RoleOperator, Confirm: true, Run: func(row tui.Row, _ string) (string, error) {
+				return kube().DeletePod(row.ID, "")
+			}},
+		},
+	}
+
+	deployments := &tui.View{
+		Name:    "deployments",
+		Headers: deploymentTable(nil).Headers,
+		Load: func() ([]tui.Row, error) {
+			deps, err := kube().ListDeployments(kube().Namespace)
+			return tableRows(deps, deploymentTable(deps), func(d k8s.Deployment) string { return d.Name }), err
+		},
+		Actions: []*tui.Action{
+			{Key: tui.R('d'), Name: "describe", Role: authpkg.RoleViewer, ShowOutput: true, Run: func(row tui.Row, _ string) (string, error) {
+				return kube().DescribeDeployment(row.ID, "")
+			}},
+			{Key: tui.R('S'), Name: "scale", Role: authpkg.RoleOperator, Prompt: "replicas", Run: func(row tui.Row, input string) (string, error) {
+				replicas, err := strconv.Atoi(input)
+				if err != nil || replicas < 0 {
+					return "", fmt.Errorf("invalid replica count %q", input)
+				}
+				return kube().ScaleDeployment(row.ID, "", replicas)
+			}},
+		},
+	}
+
+	containers := &tui.View{
+		Name:    "containers",
+		Headers: containerTable(nil).Headers,
+		Load: func() ([]tui.Row, error) {
+			cs, err := dock().ListContainers(false)
+			return tableRows(cs, containerTable(cs), func(c docker.Container) string { return c.Name }), err
+		},
+		Actions: []*tui.Action{
+			{Key: tui.R('l'), Name: "logs", Role: authpkg.RoleViewer, Interactive: true, Run: func(row tui.Row, _ string) (string, error) {
+				return "", dock().GetContainerLogs(row.ID, true)
+			}},
+			{Key: tui.R('d'), Name: "describe", Role: authpkg.RoleViewer, ShowOutput: true, Run: func(row tui.Row, _ string) (string, error) {
+				return dock().GetContainerInspect(row.ID)
+			}},
+			{Key: tui.R('s'), Name: "exec", Role: authpkg.RoleOperator, Interactive: true, Run: func(row tui.Row, _ string) (string, error) {
+				return "", dock().ExecInContainer(row.ID, []string{"sh"})
+			}},
+			{Key: tui.Key{Name: "ctrl-d"}, Name: "delete", Role: authpkg.RoleOperator, Confirm: true, Run: func(row tui.Row, _ string) (string, error) {
+				return dock().RemoveContainer(row.ID, true)
+			}},
+		},
+	}
+
+	ec2View := &tui.View{
+		Name:    "ec2",
+		Headers: instanceTable(nil).Headers,
+		Load: func() ([]tui.Row, error) {
+			is, err := ec2().ListEC2Instances(false)
+			return tableRows(is, instanceTable(is), func(i awspkg.Instance) string { return i.ID }), err
+		},
+		Actions: []*tui.Action{
+			{Key: tui.R('d'), Name: "describe", Role: authpkg.RoleViewer, ShowOutput: true, Run: func(row tui.Row, _ string) (string, error) {
+				return ec2().DescribeEC2Instance(row.ID)
+			}},
+			{Key: tui.Key{Name: "ctrl-d"}, Name: "terminate", Role: authpkg.RoleOperator, Confirm: true, Run: func(row tui.Row, _ string) (string, error) {
+				return ec2().TerminateEC2Instance(row.ID)
+			}},
+		},
+	}
+
+	gceView := &tui.View{
+		Name:    "gce",
+		Headers: gcpInstanceTable(nil).Headers,
+		Load: func() ([]tui.Row, error) {
+			is, err := gce().ListInstances()
+			return tableRows(is, gcpInstanceTable(is), func(i gcppkg.Instance) string { return i.Name }), err
+		},
+		Actions: []*tui.Action{
+			{Key: tui.R('d'), Name: "describe", Role: authpkg.RoleViewer, ShowOutput: true, Run: func(row tui.Row, _ string) (string, error) {
+				return gce().DescribeInstance(row.ID)
+			}},
+			{Key: tui.Key{Name: "ctrl-d"}, Name: "delete", Role: authpkg.RoleOperator, Confirm: true, Run: func(row tui.Row, _ string) (string, error) {
+				return gce().DeleteInstance(row.ID)
+			}},
+		},
+	}
+
+	azureView := &tui.View{
+		Name:    "azure-vms",
+		Headers: azureVMTable(nil).Headers,
+		Load: func() ([]tui.Row, error) {
+			vms, err := az().ListVMs()
+			return tableRows(vms, azureVMTable(vms), func(vm azurepkg.VM) string { return vm.Name }), err
+		},
+		Actions: []*tui.Action{
+			{Key: tui.R('d'), Name: "describe", Role: authpkg.RoleViewer, ShowOutput: true, Run: describeObject},
+			{Key: tui.Key{Name: "ctrl-d"}, Name: "delete", Role: authpkg.RoleOperator, Confirm: true, Run: func(row tui.Row, _ string) (string, error) {
+				return az().DeleteVM(row.ID)
+			}},
+		},
+	}
+
+	releases := &tui.View{
+		Name:    "releases",
+		Headers: releaseTable(nil).Headers,
+		Load: func() ([]tui.Row, error) {
+			rs, err := helm().ListReleases()
+			return tableRows(rs, releaseTable(rs), func(r helmpkg.Release) string { return r.Name }), err
+		},
+		Actions: []*tui.Action{
+			{Key: tui.R('d'), Name: "describe", Role: authpkg.RoleViewer, ShowOutput: true, Run: func(row tui.Row, _ string) (string, error) {
+				return helm().GetReleaseStatus(row.ID)
+			}},
+			{Key: tui.Key{Name: "ctrl-d"}, Name: "uninstall", Role: authpkg.RoleOperator, Confirm: true, Run: func(row tui.Row, _ string) (string, error) {
+				return helm().UninstallRelease(row.ID)
+			}},
+		},
+	}
+
+	return []*tui.View{pods, deployments, containers, ec2View, gceView, azureView, releases}
+}
+
+func init() {
+	rootCmd.AddCommand(uiCmd)
+	uiCmd.Flags().StringP("namespace", "n", "", "Kubernetes namespace (default from config, else default)")
+	uiCmd.Flags().String("context", "", "Kubernetes context")
+	uiCmd.Flags().Duration("refresh", 5*time.Second, "How often the current view refreshes (0 disables)")
+}
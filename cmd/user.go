@@ -1,11 +1,23 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
+	"github.com/yourusername/devops-mission-control/pkg/audit"
 	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
 )
 
+// authStore is the backend configured by auth_store/users_file/tokens_file;
+// nil means users.json and tokens.json in the working directory.
+var authStore authpkg.Store
+
 var userStore = authpkg.NewUserStore("")
 
 var userCmd = &cobra.Command{
@@ -14,19 +26,42 @@ var userCmd = &cobra.Command{
 }
 
 var userCreateCmd = &cobra.Command{
-	Use:   "create",
+	Use:   "create <username> <password> <role>",
 	Short: "Create a new user",
-	Args:  cobra.ExactArgs(3),
+	Long: `Create a user. Pass "-" as the password to be prompted for it instead of
+leaving it in your shell history. Passwords must be at least 12 characters
+and mix 3 of lower case, upper case, digits and symbols.`,
+	Args: cobra.ExactArgs(3),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		username, password, role := args[0], args[1], args[2]
 		// Only admin may create arbitrary users; allow creating self if actor==username
 		if err := requireAdminOrSelf(cmd, username); err != nil {
 			return err
 		}
-		err := userStore.AddUser(username, password, authpkg.Role(role))
+		if err := requireSameTenant(cmd, username); err != nil {
+			return err
+		}
+		tenant, err := newUserTenant(cmd)
 		if err != nil {
 			return err
 		}
+		if password == "-" {
+			var err error
+			if password, err = readPassword("Password: "); err != nil {
+				return err
+			}
+		}
+		if err := userStore.Policy.Validate(username, password); err != nil {
+			return err
+		}
+		if err := userStore.AddUser(username, password, authpkg.Role(role)); err != nil {
+			return err
+		}
+		if tenant != "" {
+			if err := userStore.SetUserTenant(username, tenant); err != nil {
+				return err
+			}
+		}
 		fmt.Printf("✅ User '%s' created with role '%s'\n", username, role)
 		return nil
 	},
@@ -39,14 +74,28 @@ var userListCmd = &cobra.Command{
 		if err := requireMinRole(cmd, authpkg.RoleViewer); err != nil {
 			return err
 		}
-		users := userStore.ListUsers()
+		users, err := visibleUsers(cmd)
+		if err != nil {
+			return err
+		}
 		if len(users) == 0 {
 			fmt.Println("No users found")
 			return nil
 		}
 		fmt.Println("Users:")
+		now := time.Now()
 		for _, u := range users {
-			fmt.Printf("  %s (role: %s, active: %v)\n", u.Username, u.Role, u.Active)
+			status := "active"
+			if u.Locked(now) {
+				status = "locked until " + u.LockedUntil.Format(time.RFC3339)
+			}
+			if u.Tenant != "" {
+				status += ", tenant: " + u.Tenant
+			}
+			if u.SlackID != "" {
+				status += ", slack: " + u.SlackID
+			}
+			fmt.Printf("  %s (role: %s, %s)\n", u.Username, u.Role, status)
 		}
 		return nil
 	},
@@ -61,6 +110,9 @@ var userDeleteCmd = &cobra.Command{
 		if err := requireAdmin(cmd); err != nil {
 			return err
 		}
+		if err := requireSameTenant(cmd, username); err != nil {
+			return err
+		}
 		err := userStore.DeleteUser(username)
 		if err != nil {
 			return err
@@ -80,6 +132,9 @@ var userSetRoleCmd = &cobra.Command{
 		if err := requireAdmin(cmd); err != nil {
 			return err
 		}
+		if err := requireSameTenant(cmd, username); err != nil {
+			return err
+		}
 		if err := userStore.SetUserRole(username, authpkg.Role(role)); err != nil {
 			return err
 		}
@@ -88,13 +143,125 @@ var userSetRoleCmd = &cobra.Command{
 	},
 }
 
+var userPasswdCmd = &cobra.Command{
+	Use:   "passwd <username> [password]",
+	Short: "Change a user's password",
+	Long:  "Change a user's password. The password is prompted for when omitted. This also clears a lockout.",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		username := args[0]
+		if err := requireAdminOrSelf(cmd, username); err != nil {
+			return err
+		}
+		if err := requireSameTenant(cmd, username); err != nil {
+			return err
+		}
+		if _, err := userStore.GetUser(username); err != nil {
+			return fmt.Errorf("user %s not found", username)
+		}
+		password, err := passwordArg(args, 1, "New password: ")
+		if err != nil {
+			return err
+		}
+		if err := userStore.Policy.Validate(username, password); err != nil {
+			return err
+		}
+		if err := userStore.SetPassword(username, password); err != nil {
+			return err
+		}
+		actor, _ := resolveActor(cmd)
+		if rerr := audit.Record(cliTenant, "user.passwd", actor, username, nil); rerr != nil {
+			fmt.Fprintf(os.Stderr, "audit record error: %v\n", rerr)
+		}
+		fmt.Printf("✅ Password changed for '%s'\n", username)
+		return nil
+	},
+}
+
+var userLinkSlackCmd = &cobra.Command{
+	Use:   "link-slack <username> <slack-user-id|->",
+	Short: "Link a user to their Slack account for slash commands",
+	Long: `Link a user to a Slack user ID (e.g. U024BE7LH, shown in their Slack
+profile under "Copy member ID"), so that the commands they run with the
+missionctl slash command run as them. Use "-" to unlink.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		username, slackID := args[0], args[1]
+		if err := requireAdmin(cmd); err != nil {
+			return err
+		}
+		if err := requireSameTenant(cmd, username); err != nil {
+			return err
+		}
+		if slackID == "-" {
+			slackID = ""
+		}
+		if err := userStore.SetUserSlackID(username, slackID); err != nil {
+			return err
+		}
+		actor, _ := resolveActor(cmd)
+		if rerr := audit.Record(cliTenant, "user.link_slack", actor, username, map[string]any{"slack_user": slackID}); rerr != nil {
+			fmt.Fprintf(os.Stderr, "audit record error: %v\n", rerr)
+		}
+		if slackID == "" {
+			fmt.Printf("✅ User '%s' unlinked from Slack\n", username)
+		} else {
+			fmt.Printf("✅ User '%s' linked to Slack user %s\n", username, slackID)
+		}
+		return nil
+	},
+}
+
+var userUnlockCmd = &cobra.Command{
+	Use:   "unlock <username>",
+	Short: "Clear a user's lockout after failed logins",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		username := args[0]
+		if err := requireAdmin(cmd); err != nil {
+			return err
+		}
+		if err := requireSameTenant(cmd, username); err != nil {
+			return err
+		}
+		if err := userStore.Unlock(username); err != nil {
+			return err
+		}
+		actor, _ := resolveActor(cmd)
+		if rerr := audit.Record(cliTenant, "user.unlock", actor, username, nil); rerr != nil {
+			fmt.Fprintf(os.Stderr, "audit record error: %v\n", rerr)
+		}
+		fmt.Printf("✅ User '%s' unlocked\n", username)
+		return nil
+	},
+}
+
 var loginCmd = &cobra.Command{
-	Use:   "login",
+	Use:   "login <username> [password]",
 	Short: "Authenticate as a user",
-	Args:  cobra.ExactArgs(2),
+	Long: `Check a username and password. The password is prompted for when omitted.
+After 5 consecutive failures the account is locked for 15 minutes; every
+attempt is written to the audit log as auth.login.`,
+	Args: cobra.RangeArgs(1, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		username, password := args[0], args[1]
+		username := args[0]
+		password, err := passwordArg(args, 1, "Password: ")
+		if err != nil {
+			return err
+		}
 		user, err := userStore.Authenticate(username, password)
+		details := map[string]any{"success": err == nil}
+		if err != nil {
+			details["reason"] = err.Error()
+			details["locked"] = errors.Is(err, authpkg.ErrAccountLocked)
+		}
+		tenant := ""
+		if u, lerr := userStore.GetUser(username); lerr == nil {
+			tenant = u.Tenant
+		}
+		if rerr := audit.Record(tenant, "auth.login", username, "cli", details); rerr != nil {
+			fmt.Fprintf(os.Stderr, "audit record error: %v\n", rerr)
+		}
 		if err != nil {
 			return err
 		}
@@ -103,8 +270,59 @@ var loginCmd = &cobra.Command{
 	},
 }
 
+// newUserTenant is the tenant for a user created by the actor: --tenant,
+// which only global admins may pass, else the actor's own tenant.
+func newUserTenant(cmd *cobra.Command) (string, error) {
+	actorTenant := ""
+	if u, err := actorUser(cmd); err == nil {
+		actorTenant = u.Tenant
+	}
+	tenant, _ := cmd.Flags().GetString("tenant")
+	if tenant == "" || tenant == actorTenant {
+		return actorTenant, nil
+	}
+	if err := requireGlobalAdmin(cmd); err != nil {
+		return "", err
+	}
+	if _, err := tenantStore.GetTenant(tenant); err != nil {
+		return "", fmt.Errorf("tenant %s not found", tenant)
+	}
+	return tenant, nil
+}
+
+// passwordArg returns args[i], or prompts for the password when it was not
+// given on the command line.
+func passwordArg(args []string, i int, prompt string) (string, error) {
+	if i < len(args) && args[i] != "-" {
+		return args[i], nil
+	}
+	return readPassword(prompt)
+}
+
+// readPassword reads a line from stdin, turning off echo when stdin is a
+// terminal.
+func readPassword(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	noEcho := exec.Command("stty", "-echo")
+	noEcho.Stdin = os.Stdin
+	if noEcho.Run() == nil {
+		defer func() {
+			echo := exec.Command("stty", "echo")
+			echo.Stdin = os.Stdin
+			_ = echo.Run()
+			fmt.Fprintln(os.Stderr)
+		}()
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("read password error: %s", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
 func init() {
 	rootCmd.AddCommand(userCmd)
-	userCmd.AddCommand(userCreateCmd, userListCmd, userDeleteCmd, userSetRoleCmd)
+	userCmd.AddCommand(userCreateCmd, userListCmd, userDeleteCmd, userSetRoleCmd, userPasswdCmd, userUnlockCmd, userLinkSlackCmd)
+	userCreateCmd.Flags().String("tenant", "", "tenant of the new user (global admins only; default: your tenant)")
 	rootCmd.AddCommand(loginCmd)
 }
@@ -3,15 +3,17 @@ module github.com/yourusername/devops-mission-control
 go 1.21
 
 require (
-	github.com/fsnotify/fsnotify v1.9.0
 	github.com/go-logr/logr v1.4.3
+	github.com/golang/snappy v0.0.4
+	github.com/mattn/go-sqlite3 v1.14.28
 	github.com/spf13/cobra v1.7.0
+	github.com/spf13/pflag v1.0.5
 	go.uber.org/zap v1.27.1
+	golang.org/x/crypto v0.14.0
 )
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/pflag v1.0.5 // indirect
 	go.uber.org/multierr v1.10.0 // indirect
 	golang.org/x/sys v0.13.0 // indirect
 )
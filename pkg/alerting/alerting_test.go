@@ -0,0 +1,177 @@
+package alerting
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourusername/devops-mission-control/pkg/metrics"
+)
+
+const rulesYAML = `interval: 10s
+rules:
+  - name: high-cpu
+    metric: cpu
+    tags: ["host!=db-*"]
+    by: [host, tenant]
+    agg: max
+    window: 5m
+    op: gt
+    threshold: 90
+    for: 1m
+    severity: critical
+    message: "CPU at {{.Value}} on {{.Labels.host}}"
+    labels: {team: infra}
+`
+
+func TestLoadRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.yaml")
+	if err := os.WriteFile(path, []byte(rulesYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rs, err := LoadRules(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rs.Rules[0]
+	if time.Duration(rs.Interval) != 10*time.Second || time.Duration(r.For) != time.Minute || r.Threshold != 90 ||
+		r.Labels["team"] != "infra" || len(r.filters) != 1 || !r.Compare(91) || r.Compare(90) {
+		t.Fatalf("loaded %+v", rs)
+	}
+	for _, bad := range []string{
+		"rules:\n  - metric: cpu\n    op: gt\n",
+		"rules:\n  - name: x\n    metric: cpu\n    op: '=>'\n",
+		"rules:\n  - name: x\n    metric: cpu\n    op: gt\n    agg: median\n",
+		"rules:\n  - name: x\n    metric: cpu\n    op: gt\n    for: soon\n",
+		"rules:\n  - name: x\n    metric: cpu\n    op: gt\n  - name: x\n    metric: mem\n    op: lt\n",
+	} {
+		if err := os.WriteFile(path, []byte(bad), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadRules(path); err == nil {
+			t.Errorf("%q should be rejected", bad)
+		}
+	}
+	if rs, err := LoadRulesIfExists(filepath.Join(t.TempDir(), "none.yaml")); err != nil || len(rs.Rules) != 0 {
+		t.Fatalf("missing file: %+v %v", rs, err)
+	}
+}
+
+func TestEngine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.yaml")
+	if err := os.WriteFile(path, []byte(rulesYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store := metrics.NewMetricsStore(100)
+	e := NewEngine(store, path)
+	if changed, err := e.Reload(); err != nil || !changed {
+		t.Fatalf("first load: %v %v", changed, err)
+	}
+	record := func(host string, v float64) {
+		if err := store.RecordMetric("cpu", v, "%", map[string]string{"host": host, "tenant": "acme"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	record("web-1", 95)
+	record("web-2", 50)
+	record("db-1", 99)
+
+	now := time.Now()
+	if err := e.Evaluate(now); err != nil {
+		t.Fatal(err)
+	}
+	if a := store.GetActiveAlerts(); len(a) != 0 {
+		t.Fatalf("fired before for: %+v", a)
+	}
+	for i := 0; i < 2; i++ {
+		if err := e.Evaluate(now.Add(time.Minute + time.Duration(i)*time.Second)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	active := store.GetActiveAlerts()
+	if len(active) != 1 {
+		t.Fatalf("want one deduplicated alert, got %+v", active)
+	}
+	a := active[0]
+	if a.Name != "high-cpu" || a.Severity != "critical" || a.Message != "CPU at 95 on web-1" ||
+		a.Metadata["tenant"] != "acme" || a.Metadata["team"] != "infra" || a.Metadata[MetaSeries] != "host=web-1,tenant=acme" {
+		t.Fatalf("alert %+v", a)
+	}
+	if got := store.ForTenant("acme").GetActiveAlerts(); len(got) != 1 {
+		t.Fatalf("the tenant should see its alert: %+v", got)
+	}
+
+	// a second engine, e.g. after a restart, does not fire it again
+	other := NewEngine(store, path)
+	if _, err := other.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	other.pending = map[string]time.Time{"high-cpu\x00host=web-1,tenant=acme": now}
+	if err := other.Evaluate(now.Add(2 * time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(store.GetActiveAlerts()); n != 1 {
+		t.Fatalf("%d active alerts after another engine ran", n)
+	}
+
+	// removing the rule from the file resolves its alert
+	if err := os.WriteFile(path, []byte("interval: 1m\nrules: []\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := e.Reload(); err != nil || !changed {
+		t.Fatalf("reload: %v %v", changed, err)
+	}
+	if time.Duration(e.Rules().Interval) != time.Minute {
+		t.Fatalf("interval not reloaded: %v", e.Rules().Interval)
+	}
+	if err := e.Evaluate(now.Add(3 * time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(store.GetActiveAlerts()); n != 0 {
+		t.Fatalf("%d active alerts after the rule was removed", n)
+	}
+
+	// a broken file keeps the rules in place and is reported once
+	if err := os.WriteFile(path, []byte("rules:\n  - name: broken\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Reload(); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Fatalf("broken file: %v", err)
+	}
+	if changed, err := e.Reload(); err != nil || changed {
+		t.Fatalf("unchanged broken file: %v %v", changed, err)
+	}
+	if time.Duration(e.Rules().Interval) != time.Minute {
+		t.Fatal("a broken file must not replace the rules")
+	}
+}
+
+func TestEngineResolvesWhenBelowThreshold(t *testing.T) {
+	store := metrics.NewMetricsStore(100)
+	e := NewEngine(store, "")
+	if err := e.SetRules(RuleSet{Rules: []Rule{{Name: "low-disk", Metric: "disk_free", Op: "<", Threshold: 10, Window: Duration(time.Minute)}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RecordMetric("disk_free", 5, "%", nil); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if err := e.Evaluate(now); err != nil {
+		t.Fatal(err)
+	}
+	active := store.GetActiveAlerts()
+	if len(active) != 1 || active[0].Severity != DefaultSeverity || active[0].Message != "avg(disk_free) = 5 < 10" {
+		t.Fatalf("alert %+v", active)
+	}
+	if err := store.RecordMetric("disk_free", 50, "%", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Evaluate(now.Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if a := store.GetAlerts(); len(a) != 1 || !a[0].Resolved {
+		t.Fatalf("alert should be resolved: %+v", a)
+	}
+}
@@ -0,0 +1,213 @@
+package alerting
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/devops-mission-control/pkg/metrics"
+)
+
+// Metadata keys of the alerts the engine creates, next to the series'
+// group-by tags and the rule's labels.
+const (
+	MetaRule      = "rule"
+	MetaSeries    = "series"
+	MetaValue     = "value"
+	MetaThreshold = "threshold"
+)
+
+// Engine evaluates a rule set against a store. A series that breaches its
+// rule for the rule's For gets one alert, which is resolved once the series
+// stops breaching, has no data in the window or its rule is removed. The
+// firing alerts are those active in the store, so several engines or a
+// restart never fire the same alert twice.
+type Engine struct {
+	Store *metrics.MetricsStore
+	// Path is the rules file, reloaded by Run whenever it changes. Without
+	// it the engine evaluates what SetRules gave it.
+	Path string
+
+	mu      sync.Mutex
+	rules   RuleSet
+	pending map[string]time.Time // rule + series -> first breach
+	modTime time.Time
+	size    int64
+	exists  bool
+}
+
+// NewEngine returns an engine over store with the rules at path.
+func NewEngine(store *metrics.MetricsStore, path string) *Engine {
+	return &Engine{Store: store, Path: path, rules: RuleSet{Interval: Duration(DefaultInterval)}, pending: map[string]time.Time{}}
+}
+
+// Rules returns the current rule set.
+func (e *Engine) Rules() RuleSet {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.rules
+}
+
+// SetRules replaces the rules after validating them.
+func (e *Engine) SetRules(rs RuleSet) error {
+	if err := rs.Validate(); err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rules = rs
+	return nil
+}
+
+// Reload reads Path again if it changed since the last load and reports
+// whether the rules changed. A file that goes away leaves no rules; a file
+// that does not load keeps the rules in place until it is fixed.
+func (e *Engine) Reload() (bool, error) {
+	if e.Path == "" {
+		return false, nil
+	}
+	fi, err := os.Stat(e.Path)
+	exists := err == nil
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+	e.mu.Lock()
+	unchanged := exists == e.exists && (!exists || fi.ModTime().Equal(e.modTime) && fi.Size() == e.size)
+	e.mu.Unlock()
+	if unchanged {
+		return false, nil
+	}
+	rs, err := LoadRulesIfExists(e.Path)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	// remember the version either way, so a broken file is reported once
+	e.exists = exists
+	if exists {
+		e.modTime, e.size = fi.ModTime(), fi.Size()
+	}
+	if err != nil {
+		return false, err
+	}
+	e.rules = rs
+	return true, nil
+}
+
+// Evaluate runs every rule at now, creating and resolving alerts.
+func (e *Engine) Evaluate(now time.Time) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	firing := map[string]metrics.Alert{}
+	for _, a := range e.Store.GetActiveAlerts() {
+		if rule := a.Metadata[MetaRule]; rule != "" {
+			firing[rule+"\x00"+a.Metadata[MetaSeries]] = a
+		}
+	}
+	var errs []error
+	breaching := map[string]bool{}
+	for i := range e.rules.Rules {
+		r := &e.rules.Rules[i]
+		series, err := e.Store.Query(r.query(now))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule %q: %w", r.Name, err))
+			// keep its alerts as they are
+			for key := range firing {
+				if strings.HasPrefix(key, r.Name+"\x00") {
+					breaching[key] = true
+				}
+			}
+			continue
+		}
+		for _, s := range series {
+			if len(s.Points) == 0 || math.IsNaN(s.Points[0].Value) || !r.Compare(s.Points[0].Value) {
+				continue
+			}
+			v := s.Points[0].Value
+			key := r.Name + "\x00" + seriesKey(s.Tags, "", "")
+			breaching[key] = true
+			first, ok := e.pending[key]
+			if !ok {
+				first = now
+				e.pending[key] = now
+			}
+			if now.Sub(first) < time.Duration(r.For) {
+				continue
+			}
+			if _, ok := firing[key]; ok {
+				continue
+			}
+			metadata := map[string]string{}
+			for k, v := range s.Tags {
+				metadata[k] = v
+			}
+			for k, v := range r.Labels {
+				metadata[k] = v
+			}
+			metadata[MetaRule] = r.Name
+			metadata[MetaSeries] = seriesKey(s.Tags, "", "")
+			metadata[MetaValue] = strconv.FormatFloat(v, 'g', -1, 64)
+			metadata[MetaThreshold] = strconv.FormatFloat(r.Threshold, 'g', -1, 64)
+			if err := e.Store.CreateAlert(r.Name, r.Severity, r.render(s.Tags, v), metadata); err != nil {
+				errs = append(errs, fmt.Errorf("rule %q: %w", r.Name, err))
+			}
+		}
+	}
+	for key := range e.pending {
+		if !breaching[key] {
+			delete(e.pending, key)
+		}
+	}
+	for key, a := range firing {
+		if breaching[key] {
+			continue
+		}
+		if err := e.Store.ResolveAlert(a.ID); err != nil {
+			errs = append(errs, fmt.Errorf("rule %q: %w", a.Metadata[MetaRule], err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run reloads and evaluates the rules every rule set interval until done
+// is closed.
+func (e *Engine) Run(done <-chan struct{}) {
+	for {
+		if _, err := e.Reload(); err != nil {
+			fmt.Fprintf(os.Stderr, "alert rules reload failed: %v\n", err)
+		}
+		if err := e.Evaluate(time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "alert rules error: %v\n", err)
+		}
+		t := time.NewTimer(time.Duration(e.Rules().Interval))
+		select {
+		case <-done:
+			t.Stop()
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// seriesKey renders labels sorted as k=v pairs between open and close, ""
+// without labels.
+func seriesKey(labels map[string]string, open, close string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + labels[k]
+	}
+	return open + strings.Join(parts, ",") + close
+}
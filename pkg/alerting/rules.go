@@ -0,0 +1,222 @@
+// Package alerting evaluates alert rules against the metrics store and
+// creates and resolves alerts as they start and stop firing.
+package alerting
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/yourusername/devops-mission-control/pkg/metrics"
+	"github.com/yourusername/devops-mission-control/pkg/yamlite"
+)
+
+// Duration is a time.Duration written as "30s", "5m" or "2h" in rule
+// files.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like 5m, got %s", b)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Comparison operators of Rule.Op. The word forms save quoting > in YAML.
+var ops = map[string]func(v, threshold float64) bool{
+	">":  func(v, t float64) bool { return v > t },
+	">=": func(v, t float64) bool { return v >= t },
+	"<":  func(v, t float64) bool { return v < t },
+	"<=": func(v, t float64) bool { return v <= t },
+	"==": func(v, t float64) bool { return v == t },
+	"!=": func(v, t float64) bool { return v != t },
+}
+
+var opWords = map[string]string{"gt": ">", "ge": ">=", "lt": "<", "le": "<=", "eq": "==", "ne": "!="}
+
+// Rule fires for every series of Metric whose Agg over the last Window
+// compares to Threshold with Op for at least For. Tags filters the series
+// (key=glob or key!=glob) and By splits them, as in metrics.Query; the By
+// tags of a series, tenant included, become metadata of its alert.
+type Rule struct {
+	Name      string   `json:"name"`
+	Metric    string   `json:"metric"`
+	Tags      []string `json:"tags,omitempty"`
+	By        []string `json:"by,omitempty"`
+	Agg       string   `json:"agg,omitempty"`
+	Window    Duration `json:"window,omitempty"`
+	Op        string   `json:"op"`
+	Threshold float64  `json:"threshold"`
+	For       Duration `json:"for,omitempty"`
+	Severity  string   `json:"severity,omitempty"`
+	// Message is a text/template over .Rule, .Value, .Threshold and .Labels;
+	// the default describes the comparison.
+	Message string `json:"message,omitempty"`
+	// Labels are added to the metadata of the rule's alerts.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	filters []metrics.TagFilter
+	message *template.Template
+}
+
+// RuleSet is the content of a rules file.
+type RuleSet struct {
+	// Interval is how often the rules are evaluated (default 30s).
+	Interval Duration `json:"interval,omitempty"`
+	Rules    []Rule   `json:"rules"`
+}
+
+// Defaults of rules and rule sets.
+const (
+	DefaultInterval = 30 * time.Second
+	DefaultWindow   = 5 * time.Minute
+	DefaultSeverity = "warning"
+)
+
+// Validate checks the rules and fills in defaults.
+func (rs *RuleSet) Validate() error {
+	if rs.Interval == 0 {
+		rs.Interval = Duration(DefaultInterval)
+	}
+	if rs.Interval < 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	seen := map[string]bool{}
+	for i := range rs.Rules {
+		r := &rs.Rules[i]
+		if r.Name == "" {
+			return fmt.Errorf("rule #%d: name is required", i+1)
+		}
+		if seen[r.Name] {
+			return fmt.Errorf("rule %q: duplicate name", r.Name)
+		}
+		seen[r.Name] = true
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+	}
+	return nil
+}
+
+func (r *Rule) validate() error {
+	if r.Metric == "" {
+		return fmt.Errorf("metric is required")
+	}
+	if op, ok := opWords[r.Op]; ok {
+		r.Op = op
+	}
+	if _, ok := ops[r.Op]; !ok {
+		return fmt.Errorf("op must be one of >, >=, <, <=, ==, != (or gt, ge, lt, le, eq, ne), got %q", r.Op)
+	}
+	if r.Agg == "" {
+		r.Agg = metrics.AggAvg
+	}
+	if r.Window == 0 {
+		r.Window = Duration(DefaultWindow)
+	}
+	if r.Window < 0 || r.For < 0 {
+		return fmt.Errorf("window and for must be positive")
+	}
+	if r.Severity == "" {
+		r.Severity = DefaultSeverity
+	}
+	r.filters = nil
+	for _, t := range r.Tags {
+		f, err := metrics.ParseTagFilter(t)
+		if err != nil {
+			return err
+		}
+		r.filters = append(r.filters, f)
+	}
+	if !strings.Contains("|"+metrics.Aggregations+"|", "|"+r.Agg+"|") {
+		return fmt.Errorf("unknown aggregation %q (expected %s)", r.Agg, metrics.Aggregations)
+	}
+	r.message = nil
+	if r.Message != "" {
+		t, err := template.New(r.Name).Option("missingkey=zero").Parse(r.Message)
+		if err != nil {
+			return fmt.Errorf("message: %w", err)
+		}
+		r.message = t
+	}
+	return nil
+}
+
+// query is what the rule evaluates at now.
+func (r *Rule) query(now time.Time) metrics.Query {
+	return metrics.Query{
+		Name:    r.Metric,
+		From:    now.Add(-time.Duration(r.Window)),
+		To:      now,
+		Filters: r.filters,
+		GroupBy: r.By,
+		Agg:     r.Agg,
+	}
+}
+
+// Compare reports whether v breaches the rule's threshold.
+func (r *Rule) Compare(v float64) bool {
+	return ops[r.Op](v, r.Threshold)
+}
+
+// render returns the alert message for a series at v.
+func (r *Rule) render(labels map[string]string, v float64) string {
+	if r.message != nil {
+		var b bytes.Buffer
+		data := map[string]any{"Rule": r.Name, "Value": v, "Threshold": r.Threshold, "Labels": labels}
+		if err := r.message.Execute(&b, data); err == nil {
+			return b.String()
+		}
+	}
+	return fmt.Sprintf("%s(%s%s) = %s %s %s", r.Agg, r.Metric, seriesKey(labels, "{", "}"),
+		strconv.FormatFloat(v, 'g', 4, 64), r.Op, strconv.FormatFloat(r.Threshold, 'g', -1, 64))
+}
+
+// LoadRules reads a rule set from a YAML or JSON file (chosen by
+// extension).
+func LoadRules(path string) (RuleSet, error) {
+	var rs RuleSet
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rs, err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &rs)
+	} else {
+		err = yamlite.Unmarshal(data, &rs)
+	}
+	if err != nil {
+		return rs, fmt.Errorf("invalid alert rules %s: %w", path, err)
+	}
+	if err := rs.Validate(); err != nil {
+		return rs, fmt.Errorf("invalid alert rules %s: %w", path, err)
+	}
+	return rs, nil
+}
+
+// LoadRulesIfExists is LoadRules, except a missing file yields no rules.
+func LoadRulesIfExists(path string) (RuleSet, error) {
+	rs, err := LoadRules(path)
+	if errors.Is(err, os.ErrNotExist) {
+		rs = RuleSet{}
+		return rs, rs.Validate()
+	}
+	return rs, err
+}
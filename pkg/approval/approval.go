@@ -0,0 +1,200 @@
+// Package approval gates dangerous commands behind an Approve / Reject
+// decision taken in Slack. The CLI posts the request to a channel and
+// waits; the dashboard's interactivity endpoint records the decision.
+// Both sides share the requests through a directory of JSON files.
+package approval
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Request statuses.
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+	StatusExpired  = "expired"
+)
+
+// Request is a command waiting for, or given, a decision.
+type Request struct {
+	ID string `json:"id"`
+	// Command is what will run, e.g. "aws ec2 terminate i-0abc".
+	Command     string    `json:"command"`
+	Actor       string    `json:"actor"`
+	Tenant      string    `json:"tenant,omitempty"`
+	Status      string    `json:"status"`
+	RequestedAt time.Time `json:"requested_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	// DecidedBy is the Slack user ID that approved or rejected.
+	DecidedBy string    `json:"decided_by,omitempty"`
+	DecidedAt time.Time `json:"decided_at,omitempty"`
+	// Channel and TS locate the Slack message with the buttons.
+	Channel string `json:"channel,omitempty"`
+	TS      string `json:"ts,omitempty"`
+	// Result is how the command ended once it ran.
+	Result string `json:"result,omitempty"`
+}
+
+// Pending reports whether r still waits for a decision at now.
+func (r Request) Pending(now time.Time) bool {
+	return r.Status == StatusPending && now.Before(r.ExpiresAt)
+}
+
+// validID guards the store against paths smuggled in a request ID, which
+// the dashboard takes from a button value.
+var validID = regexp.MustCompile(`^apr_[0-9]+$`)
+
+// Store keeps each request in its own file under Dir, so the CLI waiting
+// on a request and the dashboard deciding it never rewrite each other's
+// requests. Writes go through a rename, so readers never see a partial
+// file.
+type Store struct {
+	Dir string
+	// Retention is how long finished requests are kept, a week by default.
+	Retention time.Duration
+
+	mu sync.Mutex
+}
+
+// NewStore returns a store on the directory dir.
+func NewStore(dir string) *Store {
+	return &Store{Dir: dir, Retention: 7 * 24 * time.Hour}
+}
+
+// Create gives r an ID, a pending status and a request time, and saves it.
+// It also removes requests older than the retention.
+func (s *Store) Create(r Request) (Request, error) {
+	now := time.Now()
+	r.ID = fmt.Sprintf("apr_%d", now.UnixNano())
+	r.Status = StatusPending
+	r.RequestedAt = now
+	s.prune(now)
+	return r, s.Save(r)
+}
+
+// Get returns the request with id.
+func (s *Store) Get(id string) (Request, error) {
+	var r Request
+	if !validID.MatchString(id) {
+		return r, fmt.Errorf("approval request %q not found", id)
+	}
+	data, err := os.ReadFile(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return r, fmt.Errorf("approval request %s not found", id)
+	}
+	if err != nil {
+		return r, err
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return r, fmt.Errorf("invalid approval request %s: %w", s.path(id), err)
+	}
+	return r, nil
+}
+
+// Save writes r.
+func (s *Store) Save(r Request) error {
+	if !validID.MatchString(r.ID) {
+		return fmt.Errorf("invalid approval request ID %q", r.ID)
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.Dir, "."+r.ID+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(r.ID))
+}
+
+// Decide approves or rejects the pending request with id on behalf of
+// the Slack user by. The first decision wins; later ones fail.
+func (s *Store) Decide(id, by string, approve bool, now time.Time) (Request, error) {
+	status := StatusRejected
+	if approve {
+		status = StatusApproved
+	}
+	return s.decide(id, status, by, now)
+}
+
+// Expire marks the pending request with id expired once its time is up.
+// Like a decision, it fails when the request was decided first.
+func (s *Store) Expire(id string, now time.Time) (Request, error) {
+	return s.decide(id, StatusExpired, "", now)
+}
+
+// decide moves the pending request with id to status. The move is claimed
+// by creating a marker file exclusively, so of two concurrent decisions,
+// in this process or another, only the first is saved.
+func (s *Store) decide(id, status, by string, now time.Time) (Request, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, err := s.Get(id)
+	if err != nil {
+		return r, err
+	}
+	expired := !now.Before(r.ExpiresAt)
+	if r.Status == StatusPending && expired && status != StatusExpired {
+		r.Status = StatusExpired
+	}
+	if r.Status != StatusPending {
+		return r, fmt.Errorf("approval request %s is already %s", id, r.Status)
+	}
+	if status == StatusExpired && !expired {
+		return r, fmt.Errorf("approval request %s has not expired", id)
+	}
+	f, err := os.OpenFile(s.marker(id), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return r, fmt.Errorf("approval request %s is already decided", id)
+	}
+	if err != nil {
+		return r, err
+	}
+	f.Close()
+	r.Status = status
+	if status != StatusExpired {
+		r.DecidedBy, r.DecidedAt = by, now
+	}
+	return r, s.Save(r)
+}
+
+func (s *Store) path(id string) string {
+	return filepath.Join(s.Dir, id+".json")
+}
+
+// marker is the file whose creation claims the decision of request id.
+func (s *Store) marker(id string) string {
+	return filepath.Join(s.Dir, id+".decided")
+}
+
+// prune removes the requests that expired longer than the retention ago.
+func (s *Store) prune(now time.Time) {
+	if s.Retention <= 0 {
+		return
+	}
+	files, _ := filepath.Glob(filepath.Join(s.Dir, "apr_*"))
+	for _, f := range files {
+		if fi, err := os.Stat(f); err == nil && now.Sub(fi.ModTime()) > s.Retention {
+			os.Remove(f)
+		}
+	}
+}
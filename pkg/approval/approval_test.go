@@ -0,0 +1,181 @@
+package approval
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+	"github.com/yourusername/devops-mission-control/pkg/slack"
+)
+
+// call is a Web API call the fake Slack received.
+type call struct {
+	method string
+	body   map[string]any
+}
+
+// fakeSlack answers every Web API call with ok and records the calls.
+func fakeSlack(t *testing.T) (*slack.Client, func() []call) {
+	var mu sync.Mutex
+	var calls []call
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		_ = json.Unmarshal(raw, &body)
+		mu.Lock()
+		calls = append(calls, call{r.URL.Path, body})
+		n := len(calls)
+		mu.Unlock()
+		fmt.Fprintf(w, `{"ok":true,"channel":"C1","ts":"1700000000.%06d"}`, n)
+	}))
+	t.Cleanup(srv.Close)
+	return slack.NewClient("", "#approvals", slack.WithBotToken("xoxb-test"), slack.WithAPIURL(srv.URL)), func() []call {
+		mu.Lock()
+		defer mu.Unlock()
+		out := calls
+		calls = nil
+		return out
+	}
+}
+
+// text joins the texts of a message's blocks.
+func text(body map[string]any) string {
+	b, _ := json.Marshal(body["blocks"])
+	return string(b)
+}
+
+func TestGate(t *testing.T) {
+	client, calls := fakeSlack(t)
+	g := NewGate(NewStore(t.TempDir()), client, "#approvals")
+	g.Approvers = []string{"U1", "U3"}
+	g.Users = authpkg.OpenUserStore(authpkg.NewMemoryStore())
+	if err := g.Users.AddUser("ann", "", authpkg.RoleOperator); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Users.SetUserSlackID("ann", "U3"); err != nil {
+		t.Fatal(err)
+	}
+	g.Poll = 5 * time.Millisecond
+
+	a, err := g.Open("terraform apply", "ann", "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	posted := calls()
+	if len(posted) != 1 || posted[0].method != "/chat.postMessage" || posted[0].body["channel"] != "#approvals" ||
+		!strings.Contains(text(posted[0].body), ActionApprove) || !strings.Contains(text(posted[0].body), a.ID) {
+		t.Fatalf("request message %+v", posted)
+	}
+	waited := make(chan error)
+	go func() { waited <- a.Wait() }()
+
+	if _, err := g.Decide(a.ID, slack.User{ID: "U2", Username: "intern"}, true); err == nil {
+		t.Fatal("a user outside the approvers decided")
+	}
+	if _, err := g.Decide(a.ID, slack.User{ID: "U2", Username: "U1", Name: "U1"}, true); err == nil {
+		t.Fatal("a user named after an approver decided")
+	}
+	if _, err := g.Decide(a.ID, slack.User{ID: "U3", Username: "ann"}, true); err == nil || !strings.Contains(err.Error(), "cannot approve") {
+		t.Fatalf("the requester approved their own request: %v", err)
+	}
+	r, err := g.Decide(a.ID, slack.User{ID: "U1"}, true)
+	if err != nil || r.Status != StatusApproved || r.DecidedBy != "U1" {
+		t.Fatalf("decide %+v: %v", r, err)
+	}
+	if _, err := g.Decide(a.ID, slack.User{ID: "U1"}, false); err == nil {
+		t.Fatal("a second decision was taken")
+	}
+	select {
+	case err := <-waited:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Wait did not see the approval")
+	}
+	a.Finish(errors.New("exit status 1"))
+	got := calls()
+	if len(got) != 3 || got[0].method != "/chat.update" || !strings.Contains(text(got[0].body), "Running") ||
+		!strings.Contains(text(got[1].body), "Failed: exit status 1") || got[2].body["thread_ts"] != a.TS {
+		t.Fatalf("progress calls %+v", got)
+	}
+	if r, _ := g.Store.Get(a.ID); r.Result != "failed: exit status 1" {
+		t.Fatalf("stored %+v", r)
+	}
+
+	// rejections and expiries fail the command
+	b, _ := g.Open("aws ec2 terminate i-1", "ann", "")
+	if _, err := g.Decide(b.ID, slack.User{ID: "U3", Name: "ann"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Wait(); err == nil || !strings.Contains(err.Error(), "rejected by U3") {
+		t.Fatalf("rejected: %v", err)
+	}
+	g.Timeout = 20 * time.Millisecond
+	c, _ := g.Open("terraform destroy", "ann", "")
+	if err := c.Wait(); err == nil || !strings.Contains(err.Error(), "not approved") {
+		t.Fatalf("expired: %v", err)
+	}
+	if _, err := g.Decide(c.ID, slack.User{ID: "U1"}, true); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Fatalf("decided an expired request: %v", err)
+	}
+
+	var none *Approval
+	none.Finish(nil)
+}
+
+func TestStoreRejectsPaths(t *testing.T) {
+	s := NewStore(t.TempDir())
+	for _, id := range []string{"../users", "apr_1/../../x", ""} {
+		if _, err := s.Get(id); err == nil {
+			t.Errorf("%q: read", id)
+		}
+		if _, err := s.Decide(id, "U1", true, time.Now()); err == nil {
+			t.Errorf("%q: decided", id)
+		}
+	}
+}
+
+func TestStoreDecideOnce(t *testing.T) {
+	dir := t.TempDir()
+	s := NewStore(dir)
+	r := Request{ID: "apr_1", Command: "terraform apply", Status: StatusPending, ExpiresAt: time.Now().Add(time.Hour)}
+	if err := s.Save(r); err != nil {
+		t.Fatal(err)
+	}
+	// separate stores stand in for the CLI and the dashboard
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := NewStore(dir).Decide(r.ID, fmt.Sprintf("U%d", i), i%2 == 0, time.Now())
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	decided := 0
+	for err := range errs {
+		if err == nil {
+			decided++
+		} else if !strings.Contains(err.Error(), "already") {
+			t.Fatal(err)
+		}
+	}
+	if decided != 1 {
+		t.Fatalf("%d decisions taken", decided)
+	}
+	if _, err := s.Expire(r.ID, time.Now().Add(2*time.Hour)); err == nil {
+		t.Fatal("expired a decided request")
+	}
+}
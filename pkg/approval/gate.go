@@ -0,0 +1,238 @@
+package approval
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+	"github.com/yourusername/devops-mission-control/pkg/slack"
+)
+
+// Action IDs of the buttons on a request message; their value is the
+// request ID.
+const (
+	ActionApprove = "approval_approve"
+	ActionReject  = "approval_reject"
+)
+
+// DefaultTimeout is how long a request waits for a decision.
+const DefaultTimeout = 15 * time.Minute
+
+// Result values of a request as its command runs.
+const (
+	ResultRunning   = "running"
+	ResultSucceeded = "succeeded"
+)
+
+// Gate asks for approvals in a Slack channel through the Web API, which
+// it needs to update the request message as the request is decided and
+// its command runs.
+type Gate struct {
+	Store   *Store
+	Slack   *slack.Client
+	Channel string
+	// Approvers are the Slack user IDs allowed to decide; anyone in the
+	// channel may when empty. Names are not used, since any Slack user can
+	// change theirs.
+	Approvers []string
+	// Users, when set, maps Slack users to missionctl users so a requester
+	// cannot approve their own request.
+	Users   *authpkg.UserStore
+	Timeout time.Duration
+	// Poll is how often Wait checks for a decision.
+	Poll time.Duration
+}
+
+// NewGate returns a gate posting to channel with client and keeping
+// requests in store.
+func NewGate(store *Store, client *slack.Client, channel string) *Gate {
+	return &Gate{Store: store, Slack: client, Channel: channel, Timeout: DefaultTimeout, Poll: time.Second}
+}
+
+// Approval is a request the CLI opened and waits on. A nil Approval is a
+// command that needs none, so callers can Finish it unconditionally.
+type Approval struct {
+	Request
+	gate *Gate
+}
+
+// Open saves a request for actor to run command and posts it with Approve
+// and Reject buttons.
+func (g *Gate) Open(command, actor, tenant string) (*Approval, error) {
+	timeout := g.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	r, err := g.Store.Create(Request{Command: command, Actor: actor, Tenant: tenant, ExpiresAt: time.Now().Add(timeout)})
+	if err != nil {
+		return nil, fmt.Errorf("approval error: %s", err)
+	}
+	ref, err := g.Slack.PostMessage(slack.Message{Channel: g.Channel, Text: Text(r), Blocks: Blocks(r)})
+	if err != nil {
+		r.Status = StatusExpired
+		_ = g.Store.Save(r)
+		return nil, fmt.Errorf("failed to post approval request: %w", err)
+	}
+	r.Channel, r.TS = ref.Channel, ref.TS
+	if err := g.Store.Save(r); err != nil {
+		return nil, fmt.Errorf("approval error: %s", err)
+	}
+	return &Approval{Request: r, gate: g}, nil
+}
+
+// Decide records the decision of the Slack user on the request with id.
+// The caller redraws the message with Update.
+func (g *Gate) Decide(id string, user slack.User, approve bool) (Request, error) {
+	if !g.approver(user) {
+		r, _ := g.Store.Get(id)
+		return r, fmt.Errorf("<@%s> is not allowed to decide approval requests", user.ID)
+	}
+	if approve && g.Users != nil {
+		r, err := g.Store.Get(id)
+		if err != nil {
+			return r, err
+		}
+		if u, err := g.Users.GetUserBySlackID(user.ID); err == nil && u.Username == r.Actor {
+			return r, fmt.Errorf("<@%s> requested %s and cannot approve it", user.ID, r.Command)
+		}
+	}
+	return g.Store.Decide(id, user.ID, approve, time.Now())
+}
+
+// Update redraws the message of r.
+func (g *Gate) Update(r Request) error {
+	if r.TS == "" {
+		return nil
+	}
+	return g.Slack.UpdateMessage(slack.MessageRef{Channel: r.Channel, TS: r.TS}, slack.Message{Text: Text(r), Blocks: Blocks(r)})
+}
+
+func (g *Gate) approver(u slack.User) bool {
+	if len(g.Approvers) == 0 {
+		return true
+	}
+	for _, a := range g.Approvers {
+		if a != "" && a == u.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// Wait polls until the request is decided or expires. It returns nil once
+// approved, marking the request running.
+func (a *Approval) Wait() error {
+	g := a.gate
+	poll := g.Poll
+	if poll <= 0 {
+		poll = time.Second
+	}
+	for {
+		r, err := g.Store.Get(a.ID)
+		if err != nil {
+			return fmt.Errorf("approval error: %s", err)
+		}
+		a.Request = r
+		if r.Status != StatusPending {
+			break
+		}
+		if !r.Pending(time.Now()) {
+			// the store refuses decisions past the expiry, so this is
+			// final unless a decision was claimed just before it
+			r, err := g.Store.Expire(a.ID, time.Now())
+			if err != nil {
+				time.Sleep(poll)
+				continue
+			}
+			a.Request = r
+			_ = g.Update(a.Request)
+			break
+		}
+		time.Sleep(poll)
+	}
+	switch a.Status {
+	case StatusApproved:
+		a.progress(ResultRunning)
+		return nil
+	case StatusRejected:
+		return fmt.Errorf("%s was rejected by %s", a.Command, a.DecidedBy)
+	default:
+		return fmt.Errorf("%s was not approved within %s", a.Command, a.ExpiresAt.Sub(a.RequestedAt).Round(time.Second))
+	}
+}
+
+// Finish records how the approved command ended, updates the request
+// message and replies in its thread.
+func (a *Approval) Finish(runErr error) {
+	if a == nil {
+		return
+	}
+	reply := "✅ `" + a.Command + "` finished"
+	result := ResultSucceeded
+	if runErr != nil {
+		result = "failed: " + runErr.Error()
+		reply = "❌ `" + a.Command + "` failed: " + runErr.Error()
+	}
+	a.progress(result)
+	if a.TS != "" {
+		_, _ = a.gate.Slack.Reply(slack.MessageRef{Channel: a.Channel, TS: a.TS}, reply)
+	}
+}
+
+// progress saves result and redraws the message. Both are best effort:
+// the command has been approved either way.
+func (a *Approval) progress(result string) {
+	a.Result = result
+	_ = a.gate.Store.Save(a.Request)
+	_ = a.gate.Update(a.Request)
+}
+
+// Text is the notification text of the message for r.
+func Text(r Request) string {
+	switch r.Status {
+	case StatusApproved:
+		return fmt.Sprintf("%s approved", r.Command)
+	case StatusRejected:
+		return fmt.Sprintf("%s rejected", r.Command)
+	case StatusExpired:
+		return fmt.Sprintf("%s expired without approval", r.Command)
+	}
+	return fmt.Sprintf("Approval needed: %s (requested by %s)", r.Command, r.Actor)
+}
+
+// Blocks is the Block Kit message for r: the command and requester, then
+// the buttons while it is pending, else who decided and how the command
+// went.
+func Blocks(r Request) []slack.Block {
+	who := "Requested by *" + r.Actor + "*"
+	if r.Tenant != "" {
+		who += " for tenant *" + r.Tenant + "*"
+	}
+	blocks := []slack.Block{
+		slack.Section("*Approval needed*\n`" + r.Command + "`\n" + who),
+		slack.Context(fmt.Sprintf("Request %s · expires %s", r.ID, r.ExpiresAt.UTC().Format("2006-01-02 15:04 UTC"))),
+	}
+	switch r.Status {
+	case StatusPending:
+		return append(blocks, slack.Actions(r.ID,
+			slack.NewButton("Approve", ActionApprove, r.ID, "primary"),
+			slack.NewButton("Reject", ActionReject, r.ID, "danger"),
+		))
+	case StatusApproved:
+		blocks = append(blocks, slack.Section("✅ Approved by <@"+r.DecidedBy+">"))
+	case StatusRejected:
+		blocks = append(blocks, slack.Section("❌ Rejected by <@"+r.DecidedBy+">"))
+	default:
+		blocks = append(blocks, slack.Section("⌛ Expired without a decision"))
+	}
+	switch {
+	case r.Result == ResultRunning:
+		blocks = append(blocks, slack.Context("▶️ Running…"))
+	case r.Result == ResultSucceeded:
+		blocks = append(blocks, slack.Context("✅ Finished"))
+	case r.Result != "":
+		blocks = append(blocks, slack.Context("❌ "+strings.ToUpper(r.Result[:1])+r.Result[1:]))
+	}
+	return blocks
+}
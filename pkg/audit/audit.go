@@ -1,79 +1,121 @@
 package audit
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
-// Entry represents an audit log entry
+// Entry represents an audit log entry. Seq, PrevHash and Hash chain every
+// entry to the one before it (see Verify); Sig is set when a SigningKey is
+// configured.
 type Entry struct {
+	Seq       uint64         `json:"seq,omitempty"`
 	Timestamp time.Time      `json:"timestamp"`
 	TenantID  string         `json:"tenant_id,omitempty"`
 	Action    string         `json:"action"`
 	Actor     string         `json:"actor,omitempty"`
 	Target    string         `json:"target,omitempty"`
 	Details   map[string]any `json:"details,omitempty"`
+	PrevHash  string         `json:"prev_hash,omitempty"`
+	Hash      string         `json:"hash,omitempty"`
+	Sig       string         `json:"sig,omitempty"`
 }
 
 var (
 	DefaultFile = "audit.log.jsonl"
-	mu          sync.Mutex
+	// SigningKey signs every new entry when set.
+	SigningKey Key
+	mu         sync.Mutex
 )
 
-// Record appends an audit entry to the audit file (JSON Lines)
-// tenant may be empty for global entries.
+// Record appends an audit entry to the audit file (JSON Lines), or to the
+// sinks set by UseSinks. tenant may be empty for global entries. The log is
+// locked while the entry is chained to the last one and the file rotated if
+// due, so several processes may share it.
 func Record(tenant, action, actor, target string, details map[string]any) error {
-	mu.Lock()
-	defer mu.Unlock()
-	e := Entry{
+	err := currentSink().Write(Entry{
 		Timestamp: time.Now(),
 		TenantID:  tenant,
 		Action:    action,
 		Actor:     actor,
 		Target:    target,
 		Details:   details,
+	})
+	if err != nil {
+		failed.Add(1)
+	} else {
+		recorded.Add(1)
 	}
-	f, err := os.OpenFile(DefaultFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	return err
+}
+
+// recorded and failed count the outcomes of Record in this process.
+var recorded, failed atomic.Uint64
+
+// RecordStats counts the entries this process has recorded. With a buffer
+// (see UseSinks) Recorded counts queued entries and Failed those dropped
+// because the queue was full.
+type RecordStats struct {
+	Recorded uint64
+	Failed   uint64
+}
+
+// Stats returns the counts of Record calls so far.
+func Stats() RecordStats {
+	return RecordStats{Recorded: recorded.Load(), Failed: failed.Load()}
+}
+
+// lockLog takes the lock shared by every process writing file. It is a
+// separate file because rotation replaces file itself.
+func lockLog(file string) (unlock func(), err error) {
+	lf, err := os.OpenFile(file+".lock", os.O_CREATE|os.O_RDWR, 0o644)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer func() {
-		if cerr := f.Close(); cerr != nil {
-			fmt.Fprintf(os.Stderr, "audit file close failed: %v\n", cerr)
-		}
-	}()
-	enc := json.NewEncoder(f)
-	return enc.Encode(e)
+	if err := lockFile(lf); err != nil {
+		_ = lf.Close()
+		return nil, fmt.Errorf("audit lock error: %s", err)
+	}
+	return func() {
+		unlockFile(lf)
+		_ = lf.Close()
+	}, nil
 }
 
-// ReadEntries reads audit entries from the given file (JSON Lines). If file=="" uses DefaultFile.
+// ReadEntries reads audit entries from the given file (JSON Lines) and its
+// rotated segments. If file=="" uses DefaultFile. It holds every entry in
+// memory; use Open or Page for large logs.
 func ReadEntries(file string) ([]Entry, error) {
-	if file == "" {
-		file = DefaultFile
-	}
-	f, err := os.Open(file)
+	r, err := Open(file)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if cerr := f.Close(); cerr != nil {
-			fmt.Fprintf(os.Stderr, "audit file close failed: %v\n", cerr)
-		}
-	}()
-	dec := json.NewDecoder(f)
+	defer r.Close()
 	var res []Entry
 	for {
-		var e Entry
-		if err := dec.Decode(&e); err != nil {
-			if err.Error() == "EOF" {
-				break
-			}
+		e, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
 			return nil, err
 		}
 		res = append(res, e)
 	}
 	return res, nil
 }
+
+// ForTenant returns the entries recorded for tenant.
+func ForTenant(entries []Entry, tenant string) []Entry {
+	out := []Entry{}
+	for _, e := range entries {
+		if e.TenantID == tenant {
+			out = append(out, e)
+		}
+	}
+	return out
+}
@@ -1,8 +1,18 @@
 package audit
 
 import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestRecordAndRead(t *testing.T) {
@@ -30,3 +40,407 @@ func TestRecordAndRead(t *testing.T) {
 	// cleanup file
 	_ = os.Remove(DefaultFile)
 }
+
+// inTempDir runs the rest of the test in a new working directory, so the
+// default audit file starts empty.
+func inTempDir(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	cwd, _ := os.Getwd()
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeChain records n entries into a fresh log and returns its lines.
+func writeChain(t *testing.T, n int) []string {
+	t.Helper()
+	inTempDir(t)
+	for i := 0; i < n; i++ {
+		if err := Record("acme", "test.action", "bob", fmt.Sprintf("t%d", i), map[string]any{"n": i, "ok": true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(DefaultFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	lines := writeChain(t, 4)
+	rep, err := Verify("", nil)
+	if err != nil || rep.Break != nil || rep.Entries != 4 || rep.HeadSeq != 4 {
+		t.Fatalf("intact log: %+v, %v", rep, err)
+	}
+	cases := []struct {
+		name   string
+		lines  []string
+		line   int
+		reason string
+	}{
+		{"edit", []string{lines[0], strings.Replace(lines[1], `"bob"`, `"eve"`, 1), lines[2], lines[3]}, 2, "modified"},
+		{"delete", []string{lines[0], lines[2], lines[3]}, 2, "1 entries were deleted or moved"},
+		{"reorder", []string{lines[0], lines[2], lines[1], lines[3]}, 2, "deleted"},
+		{"swap back", []string{lines[0], lines[1], lines[3], lines[2]}, 3, "deleted"},
+		{"duplicate", []string{lines[0], lines[1], lines[1], lines[2]}, 3, "reordered or duplicated"},
+		{"drop head", lines[1:], 1, "entries 1-1 are missing"},
+	}
+	for _, c := range cases {
+		if err := os.WriteFile(DefaultFile, []byte(strings.Join(c.lines, "\n")+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		rep, err := Verify("", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rep.Break == nil || rep.Break.Line != c.line || !strings.Contains(rep.Break.Reason, c.reason) {
+			t.Errorf("%s: got %+v", c.name, rep.Break)
+		}
+	}
+}
+
+func TestVerifyContinuesUnchainedLog(t *testing.T) {
+	inTempDir(t)
+	legacy := `{"timestamp":"2024-01-02T03:04:05Z","action":"old.action","actor":"bob"}` + "\n"
+	if err := os.WriteFile(DefaultFile, []byte(legacy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Record("", "new.action", "bob", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	rep, err := Verify("", nil)
+	if err != nil || rep.Break != nil || rep.Unchained != 1 || rep.Entries != 1 {
+		t.Fatalf("got %+v, %v", rep, err)
+	}
+}
+
+func TestVerifySignatures(t *testing.T) {
+	dir := t.TempDir()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privDER, _ := x509.MarshalPKCS8PrivateKey(priv)
+	pubDER, _ := x509.MarshalPKIXPublicKey(pub)
+	files := map[string][]byte{
+		"hmac.key": []byte("0123456789abcdef-secret\n"),
+		"ed.pem":   pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}),
+		"ed.pub":   pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}),
+		"short":    []byte("tiny"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := LoadKey(filepath.Join(dir, "short")); err == nil {
+		t.Fatal("short HMAC secret accepted")
+	}
+	if _, err := LoadSigningKey(filepath.Join(dir, "ed.pub")); err == nil {
+		t.Fatal("public key accepted for signing")
+	}
+	otherHMAC := hmacKey("another-secret-of-16+")
+
+	for _, signer := range []string{"hmac.key", "ed.pem"} {
+		key, err := LoadSigningKey(filepath.Join(dir, signer))
+		if err != nil {
+			t.Fatal(err)
+		}
+		SigningKey = key
+		lines := writeChain(t, 3)
+		SigningKey = nil
+		check := key
+		if signer == "ed.pem" {
+			if check, err = LoadKey(filepath.Join(dir, "ed.pub")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if rep, err := Verify("", check); err != nil || rep.Break != nil || rep.Signed != 3 || !rep.SignaturesChecked {
+			t.Fatalf("%s: signed log: %+v, %v", signer, rep, err)
+		}
+		if rep, _ := Verify("", otherHMAC); rep.Break == nil || rep.Break.Reason != "bad signature" {
+			t.Fatalf("%s: wrong key accepted: %+v", signer, rep)
+		}
+		if rep, _ := Verify("", nil); rep.Break != nil || rep.SignaturesChecked {
+			t.Fatalf("%s: hash-only verify: %+v", signer, rep)
+		}
+		// an unsigned entry appended by someone without the key
+		if err := Record("", "forged", "eve", "", nil); err != nil {
+			t.Fatal(err)
+		}
+		if rep, _ := Verify("", check); rep.Break == nil || rep.Break.Line != len(lines)+1 {
+			t.Fatalf("%s: unsigned tail accepted: %+v", signer, rep)
+		}
+
+		// every signature stripped and the chain rebuilt without the key
+		var rechained []string
+		var last *Entry
+		for _, line := range lines {
+			var e Entry
+			if err := json.Unmarshal([]byte(line), &e); err != nil {
+				t.Fatal(err)
+			}
+			e.Sig = ""
+			if err := chain(&e, last, nil); err != nil {
+				t.Fatal(err)
+			}
+			b, _ := json.Marshal(e)
+			rechained = append(rechained, string(b))
+			last = &e
+		}
+		if err := os.WriteFile(DefaultFile, []byte(strings.Join(rechained, "\n")+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if rep, _ := Verify("", nil); rep.Break != nil {
+			t.Fatalf("%s: re-chained log is not a valid chain: %+v", signer, rep.Break)
+		}
+		if rep, _ := Verify("", check); rep.Break == nil || rep.Break.Line != 1 || rep.Break.Reason != "entry is not signed" {
+			t.Fatalf("%s: stripped signatures accepted: %+v", signer, rep)
+		}
+	}
+}
+
+func TestRotationKeepsChain(t *testing.T) {
+	inTempDir(t)
+	DefaultRotation = Rotation{MaxSize: 600}
+	t.Cleanup(func() { DefaultRotation = Rotation{} })
+	for i := 0; i < 12; i++ {
+		if err := Record("", "test.action", "bob", fmt.Sprintf("t%d", i), map[string]any{"n": i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	segs, err := Segments(DefaultFile)
+	if err != nil || len(segs) < 2 {
+		t.Fatalf("expected several segments, got %v, %v", segs, err)
+	}
+	for _, s := range segs {
+		if !strings.HasSuffix(s, ".gz") {
+			t.Fatalf("segment %s not compressed", s)
+		}
+	}
+	entries, err := ReadEntries("")
+	if err != nil || len(entries) != 12 || entries[11].Seq != 12 {
+		t.Fatalf("ReadEntries over segments: %d entries, %v", len(entries), err)
+	}
+	rep, err := Verify("", nil)
+	if err != nil || rep.Break != nil || rep.Entries != 12 || rep.Segments != len(segs) {
+		t.Fatalf("verify across segments: %+v, %v", rep, err)
+	}
+
+	// retention drops whole segments; the chain may then start later
+	if err := prune(DefaultFile, time.Hour, time.Now().Add(2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if left, _ := Segments(DefaultFile); len(left) != 1 || left[0] != segs[len(segs)-1] {
+		t.Fatalf("retention kept %v", left)
+	}
+	rep, err = Verify("", nil)
+	if err != nil || rep.Break != nil || rep.FirstSeq <= 1 || rep.HeadSeq != 12 {
+		t.Fatalf("verify after retention: %+v, %v", rep, err)
+	}
+	// ...but the active file must continue the newest segment
+	DefaultRotation = Rotation{}
+	for i := 0; i < 2; i++ {
+		if err := Record("", "test.action", "bob", "", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lines, _ := os.ReadFile(DefaultFile)
+	first := strings.SplitN(string(lines), "\n", 2)
+	if err := os.WriteFile(DefaultFile, []byte(first[1]), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if rep, _ := Verify("", nil); rep.Break == nil || rep.Break.File != DefaultFile || !strings.Contains(rep.Break.Reason, "deleted") {
+		t.Fatalf("entry cut from the active file not detected: %+v", rep)
+	}
+}
+
+func TestRotateOnAge(t *testing.T) {
+	inTempDir(t)
+	if err := Record("", "old", "bob", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if due, err := dueForRotation(DefaultFile, Rotation{MaxAge: time.Hour}, time.Now()); err != nil || due {
+		t.Fatalf("fresh log due for rotation: %v, %v", due, err)
+	}
+	if due, err := dueForRotation(DefaultFile, Rotation{MaxAge: time.Hour}, time.Now().Add(2*time.Hour)); err != nil || !due {
+		t.Fatalf("old log not due for rotation: %v, %v", due, err)
+	}
+	if err := Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Record("", "new", "bob", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ReadEntries("")
+	if err != nil || len(entries) != 2 || entries[1].Seq != 2 || entries[1].PrevHash != entries[0].Hash {
+		t.Fatalf("chain across a manual rotation: %+v, %v", entries, err)
+	}
+}
+
+func TestPage(t *testing.T) {
+	inTempDir(t)
+	legacy := `{"timestamp":"2024-01-02T03:04:05Z","action":"old","actor":"bob"}` + "\n"
+	if err := os.WriteFile(DefaultFile, []byte(legacy+legacy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	DefaultRotation = Rotation{MaxSize: 800}
+	t.Cleanup(func() { DefaultRotation = Rotation{} })
+	for i := 0; i < 10; i++ {
+		actor := "bob"
+		if i%2 == 1 {
+			actor = "ann"
+		}
+		if err := Record("", "new", actor, "", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var all []Entry
+	cursor, pages := "", 0
+	for {
+		page, next, err := Page("", cursor, 3, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		all = append(all, page...)
+		pages++
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	if len(all) != 12 || all[1].Action != "old" || all[2].Seq != 1 || all[11].Seq != 10 || pages != 4 && pages != 5 {
+		t.Fatalf("paged %d entries in %d pages: %+v", len(all), pages, all)
+	}
+	anns, next, err := Page("", "4", 2, func(e Entry) bool { return e.Actor == "ann" })
+	if err != nil || len(anns) != 2 || anns[0].Seq != 6 || anns[1].Seq != 8 || next != "8" {
+		t.Fatalf("filtered page: %+v, %q, %v", anns, next, err)
+	}
+	if _, _, err := Page("", "x1", 1, nil); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("bad cursor: %v", err)
+	}
+}
+
+func TestParseSizeAndDuration(t *testing.T) {
+	for in, want := range map[string]int64{"100": 100, "2KB": 2048, "64MB": 64 << 20, "1g": 1 << 30} {
+		if got, err := ParseSize(in); err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %d, %v", in, got, err)
+		}
+	}
+	if d, err := ParseDuration("90d"); err != nil || d != 90*24*time.Hour {
+		t.Errorf("ParseDuration(90d) = %v, %v", d, err)
+	}
+	if _, err := ParseSize("lots"); err == nil {
+		t.Error("ParseSize(lots) should fail")
+	}
+}
+
+func TestQuery(t *testing.T) {
+	base := time.Date(2026, 1, 2, 3, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Seq: 1, Timestamp: base, TenantID: "acme", Action: "auth.check", Actor: "ann", Target: "/api/metrics", Details: map[string]any{"allowed": true}},
+		{Seq: 2, Timestamp: base.Add(time.Hour), Action: "auth.check", Actor: "bob", Target: "/api/audit", Details: map[string]any{"allowed": false, "reason": "role", "scope": map[string]any{"ns": "prod"}}},
+		{Seq: 3, Timestamp: base.Add(2 * time.Hour), Action: "plugin.run", Actor: "bob", Target: "backup"},
+	}
+	terms := func(ss ...string) []Term {
+		var out []Term
+		for _, s := range ss {
+			tm, err := ParseTerm(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out = append(out, tm)
+		}
+		return out
+	}
+	cases := []struct {
+		name string
+		q    Query
+		want []uint64
+	}{
+		{"all", Query{}, []uint64{1, 2, 3}},
+		{"target glob", Query{Terms: terms("target=/api/*")}, []uint64{1, 2}},
+		{"denied", Query{Terms: terms("details.allowed=false")}, []uint64{2}},
+		{"nested detail", Query{Terms: terms("details.scope.ns=prod")}, []uint64{2}},
+		{"missing detail", Query{Terms: terms("details.reason=")}, []uint64{1, 3}},
+		{"negated", Query{Terms: terms("actor!=bob")}, []uint64{1}},
+		{"global tenant", Query{Terms: terms("tenant=", "action=auth.*")}, []uint64{2}},
+		{"time range", Query{Since: base.Add(30 * time.Minute), Until: base.Add(2 * time.Hour)}, []uint64{2}},
+	}
+	for _, c := range cases {
+		var got []uint64
+		for _, e := range entries {
+			if c.q.Match(e) {
+				got = append(got, e.Seq)
+			}
+		}
+		if fmt.Sprint(got) != fmt.Sprint(c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+	for _, bad := range []string{"actor", "=x", "who=bob", "details.=x"} {
+		if _, err := ParseTerm(bad); err == nil {
+			t.Errorf("ParseTerm(%q) should fail", bad)
+		}
+	}
+
+	agg, err := NewAggregator([]string{"actor"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		agg.Add(e)
+	}
+	groups := agg.Groups()
+	if len(groups) != 2 || groups[0].Values["actor"] != "bob" || groups[0].Count != 2 || groups[1].Count != 1 {
+		t.Fatalf("groups %+v", groups)
+	}
+	if got, err := ParseTime("24h", base); err != nil || !got.Equal(base.Add(-24*time.Hour)) {
+		t.Fatalf("ParseTime(24h) = %v, %v", got, err)
+	}
+}
+
+func TestExporters(t *testing.T) {
+	e := Entry{Seq: 7, Timestamp: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), TenantID: "acme", Action: "auth.check",
+		Actor: "bob", Target: "/api/a=b|c", Details: map[string]any{"allowed": false}, Hash: "abc"}
+	render := func(format string) string {
+		var b strings.Builder
+		exp, err := NewExporter(&b, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 2; i++ {
+			if err := exp.Write(e); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := exp.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return b.String()
+	}
+	var arr []Entry
+	if err := json.Unmarshal([]byte(render(ExportJSON)), &arr); err != nil || len(arr) != 2 || arr[1].Seq != 7 {
+		t.Fatalf("json export: %v, %v", arr, err)
+	}
+	if lines := strings.Split(strings.TrimSpace(render(ExportNDJSON)), "\n"); len(lines) != 2 {
+		t.Fatalf("ndjson export: %q", lines)
+	}
+	if rows, err := csv.NewReader(strings.NewReader(render(ExportCSV))).ReadAll(); err != nil || len(rows) != 3 || rows[1][0] != "7" || rows[1][6] != `{"allowed":false}` {
+		t.Fatalf("csv export: %v, %v", rows, err)
+	}
+	wantCEF := `CEF:0|missionctl|missionctl|1|auth.check|auth.check|7|rt=1767323045000 suser=bob act=auth.check cs1Label=tenant cs1=acme cs2Label=target cs2=/api/a\=b|c cn1Label=seq cn1=7 msg={"allowed":false}`
+	if got := FormatCEF(e); got != wantCEF {
+		t.Fatalf("cef:\n got %s\nwant %s", got, wantCEF)
+	}
+	wantSyslog := `<84>1 2026-01-02T03:04:05Z host missionctl - auth.check [missionctl@32473 seq="7" tenant="acme" actor="bob" target="/api/a=b|c"] {"allowed":false}`
+	if got := FormatSyslog(e, "host"); got != wantSyslog {
+		t.Fatalf("syslog:\n got %s\nwant %s", got, wantSyslog)
+	}
+	if _, err := NewExporter(&strings.Builder{}, "xml"); err == nil {
+		t.Fatal("unknown format accepted")
+	}
+}
@@ -0,0 +1,348 @@
+package audit
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Key signs and checks the hashes of audit entries. Signatures are
+// prefixed with their algorithm, e.g. "ed25519:<base64>".
+type Key interface {
+	Sign(digest []byte) (string, error)
+	Verify(digest []byte, sig string) bool
+}
+
+// minHMACKey is the shortest HMAC secret LoadKey accepts.
+const minHMACKey = 16
+
+type hmacKey []byte
+
+func (k hmacKey) Sign(digest []byte) (string, error) {
+	m := hmac.New(sha256.New, k)
+	m.Write(digest)
+	return "hmac-sha256:" + base64.StdEncoding.EncodeToString(m.Sum(nil)), nil
+}
+
+func (k hmacKey) Verify(digest []byte, sig string) bool {
+	want, _ := k.Sign(digest)
+	return hmac.Equal([]byte(want), []byte(sig))
+}
+
+// ed25519Key checks signatures with pub and, when priv is set, signs.
+type ed25519Key struct {
+	priv ed25519.PrivateKey
+	pub  ed25519.PublicKey
+}
+
+func (k ed25519Key) Sign(digest []byte) (string, error) {
+	if k.priv == nil {
+		return "", errors.New("an ed25519 public key cannot sign audit entries")
+	}
+	return "ed25519:" + base64.StdEncoding.EncodeToString(ed25519.Sign(k.priv, digest)), nil
+}
+
+func (k ed25519Key) Verify(digest []byte, sig string) bool {
+	raw, ok := strings.CutPrefix(sig, "ed25519:")
+	if !ok {
+		return false
+	}
+	b, err := base64.StdEncoding.DecodeString(raw)
+	return err == nil && ed25519.Verify(k.pub, digest, b)
+}
+
+// LoadKey reads a signing or verification key. A PEM file holds an ed25519
+// private key (PKCS#8, as written by `openssl genpkey -algorithm ed25519`)
+// or public key (PKIX), anything else is an HMAC-SHA256 secret of at least
+// 16 bytes.
+func LoadKey(path string) (Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		secret := bytes.TrimSpace(data)
+		if len(secret) < minHMACKey {
+			return nil, fmt.Errorf("audit key %s: HMAC secret must be at least %d bytes", path, minHMACKey)
+		}
+		return hmacKey(secret), nil
+	}
+	switch block.Type {
+	case "PRIVATE KEY":
+		k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("audit key %s: %w", path, err)
+		}
+		if priv, ok := k.(ed25519.PrivateKey); ok {
+			return ed25519Key{priv: priv, pub: priv.Public().(ed25519.PublicKey)}, nil
+		}
+	case "PUBLIC KEY":
+		k, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("audit key %s: %w", path, err)
+		}
+		if pub, ok := k.(ed25519.PublicKey); ok {
+			return ed25519Key{pub: pub}, nil
+		}
+	}
+	return nil, fmt.Errorf("audit key %s: only ed25519 keys and HMAC secrets are supported", path)
+}
+
+// LoadSigningKey is LoadKey for keys that must be able to sign.
+func LoadSigningKey(path string) (Key, error) {
+	k, err := LoadKey(path)
+	if err != nil {
+		return nil, err
+	}
+	if ek, ok := k.(ed25519Key); ok && ek.priv == nil {
+		return nil, fmt.Errorf("audit key %s: a public key cannot sign, use the private key", path)
+	}
+	return k, nil
+}
+
+// digest hashes e without its Hash and Sig.
+func digest(e Entry) ([]byte, error) {
+	e.Hash, e.Sig = "", ""
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(b)
+	return sum[:], nil
+}
+
+// chain links e to last (nil for the first entry), then hashes and signs it.
+func chain(e *Entry, last *Entry, key Key) error {
+	e.Seq, e.PrevHash = 1, ""
+	if last != nil && last.Hash != "" {
+		e.Seq, e.PrevHash = last.Seq+1, last.Hash
+	}
+	d, err := digest(*e)
+	if err != nil {
+		return err
+	}
+	e.Hash = hex.EncodeToString(d)
+	if key != nil {
+		if e.Sig, err = key.Sign(d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lastRecorded returns the last entry of file, or of its newest segment
+// when file is empty or missing; nil for a new log.
+func lastRecorded(file string) (*Entry, error) {
+	f, err := os.Open(file)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		defer f.Close()
+		last, err := lastEntry(f)
+		if last != nil || err != nil {
+			return last, err
+		}
+	}
+	segs, err := Segments(file)
+	if err != nil || len(segs) == 0 {
+		return nil, err
+	}
+	r := &Reader{paths: segs[len(segs)-1:]}
+	defer r.Close()
+	var last *Entry
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			return last, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		last = &e
+	}
+}
+
+// lastEntry returns the last entry of f, or nil when f is empty.
+func lastEntry(f *os.File) (*Entry, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	end := info.Size()
+	var line []byte
+	buf := make([]byte, 4096)
+	for off := end; off > 0; {
+		n := int64(len(buf))
+		if off < n {
+			n = off
+		}
+		off -= n
+		if _, err := f.ReadAt(buf[:n], off); err != nil && err != io.EOF {
+			return nil, err
+		}
+		line = append(append([]byte{}, buf[:n]...), line...)
+		trimmed := bytes.TrimRight(line, "\n")
+		if i := bytes.LastIndexByte(trimmed, '\n'); i >= 0 {
+			line = trimmed[i+1:]
+			break
+		}
+	}
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil, nil
+	}
+	var e Entry
+	if err := json.Unmarshal(line, &e); err != nil {
+		return nil, fmt.Errorf("audit log ends with a corrupt entry: %s", err)
+	}
+	return &e, nil
+}
+
+// Break is the first place an audit log fails verification.
+type Break struct {
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Seq    uint64 `json:"seq,omitempty"`
+	Reason string `json:"reason"`
+}
+
+func (b *Break) Error() string {
+	if b.Seq == 0 {
+		return fmt.Sprintf("%s line %d: %s", b.File, b.Line, b.Reason)
+	}
+	return fmt.Sprintf("%s line %d (seq %d): %s", b.File, b.Line, b.Seq, b.Reason)
+}
+
+// Report is the result of Verify.
+type Report struct {
+	File     string `json:"file"`
+	Segments int    `json:"segments,omitempty"`
+	// Entries counts the chained entries checked, Unchained the entries
+	// written before chaining existed, which can only appear at the start.
+	Entries   int `json:"entries"`
+	Unchained int `json:"unchained,omitempty"`
+	Signed    int `json:"signed"`
+	// SignaturesChecked is whether a key was given to check signatures.
+	SignaturesChecked bool `json:"signatures_checked"`
+	// FirstSeq is above 1 when retention deleted the oldest segments.
+	FirstSeq uint64 `json:"first_seq,omitempty"`
+	HeadSeq  uint64 `json:"head_seq,omitempty"`
+	HeadHash string `json:"head_hash,omitempty"`
+	Break    *Break `json:"break,omitempty"`
+}
+
+// Verify walks the hash chain of file (DefaultFile if "") through its
+// rotated segments and reports the first entry that was edited, removed,
+// inserted or moved, or whose signature key rejects. With a key every
+// chained entry must be signed, so stripping the signatures and chaining
+// the log again is detected; without one, every entry after a signed one
+// must be signed. key may be nil to check hashes only. The returned error
+// is for I/O failures; a broken chain is reported in Report.Break.
+//
+// The chain may start past seq 1 in a segment, since retention deletes
+// whole segments; it may not in the active file. Entries removed from the
+// end cannot be detected from the log alone; compare HeadSeq and HeadHash
+// with a copy kept elsewhere.
+func Verify(file string, key Key) (*Report, error) {
+	if file == "" {
+		file = DefaultFile
+	}
+	r, err := Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	rep := &Report{File: file, Segments: len(r.paths), SignaturesChecked: key != nil}
+	if r.paths[len(r.paths)-1] == file {
+		rep.Segments--
+	}
+	var prev *Entry
+	signing := false
+	for {
+		line, err := r.nextLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if b := rep.check(line, r.path != file, &prev, &signing, key); b != nil {
+			b.File, b.Line = r.path, r.line
+			rep.Break = b
+			return rep, nil
+		}
+	}
+	return rep, nil
+}
+
+// check verifies one line against the entry before it. inSegment allows
+// the chain to start past seq 1.
+func (rep *Report) check(line []byte, inSegment bool, prev **Entry, signing *bool, key Key) *Break {
+	var e Entry
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber() // re-encode numbers exactly as written
+	if err := dec.Decode(&e); err != nil {
+		return &Break{Reason: "not a valid audit entry: " + err.Error()}
+	}
+	brk := func(format string, args ...any) *Break {
+		return &Break{Seq: e.Seq, Reason: fmt.Sprintf(format, args...)}
+	}
+	if e.Hash == "" {
+		if *prev == nil {
+			rep.Unchained++
+			return nil
+		}
+		return brk("entry has no hash after the chain started (inserted or edited)")
+	}
+	d, err := digest(e)
+	if err != nil {
+		return brk("cannot hash entry: %s", err)
+	}
+	if hex.EncodeToString(d) != e.Hash {
+		return brk("entry was modified: its hash does not match its contents")
+	}
+	p := *prev
+	switch {
+	case p == nil && e.Seq != 1 && !inSegment:
+		return brk("chain starts at seq %d: entries 1-%d are missing", e.Seq, e.Seq-1)
+	case p == nil && e.Seq == 1 && e.PrevHash != "":
+		return brk("first entry points to a previous entry that is missing")
+	case p != nil && e.Seq <= p.Seq:
+		return brk("seq %d follows seq %d: entries were reordered or duplicated", e.Seq, p.Seq)
+	case p != nil && e.Seq != p.Seq+1:
+		return brk("seq %d follows seq %d: %d entries were deleted or moved", e.Seq, p.Seq, e.Seq-p.Seq-1)
+	case p != nil && e.PrevHash != p.Hash:
+		return brk("prev_hash does not match the hash of seq %d: an entry was replaced", p.Seq)
+	}
+	if e.Sig != "" {
+		rep.Signed++
+		*signing = true
+		if key != nil && !key.Verify(d, e.Sig) {
+			return brk("bad signature")
+		}
+	} else if key != nil {
+		return brk("entry is not signed")
+	} else if *signing {
+		return brk("entry is not signed but earlier entries are")
+	}
+	if p == nil {
+		rep.FirstSeq = e.Seq
+	}
+	rep.Entries++
+	rep.HeadSeq, rep.HeadHash = e.Seq, e.Hash
+	*prev = &e
+	return nil
+}
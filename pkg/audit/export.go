@@ -0,0 +1,222 @@
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Export formats accepted by NewExporter.
+const (
+	ExportCSV    = "csv"
+	ExportJSON   = "json"
+	ExportNDJSON = "ndjson"
+	ExportCEF    = "cef"
+	ExportSyslog = "syslog"
+)
+
+// ExportFormats lists the formats for help text.
+const ExportFormats = "csv|json|ndjson|cef|syslog"
+
+// Exporter writes entries one at a time, so an export never holds more
+// than one entry in memory. Close finishes the document.
+type Exporter interface {
+	Write(e Entry) error
+	Close() error
+}
+
+// NewExporter returns an exporter writing format to w.
+func NewExporter(w io.Writer, format string) (Exporter, error) {
+	switch format {
+	case ExportCSV:
+		return newCSVExporter(w)
+	case ExportJSON:
+		return &jsonExporter{w: w}, nil
+	case ExportNDJSON:
+		return &lineExporter{w: w, format: func(e Entry) (string, error) {
+			b, err := json.Marshal(e)
+			return string(b), err
+		}}, nil
+	case ExportCEF:
+		return &lineExporter{w: w, format: func(e Entry) (string, error) { return FormatCEF(e), nil }}, nil
+	case ExportSyslog:
+		host, _ := os.Hostname()
+		return &lineExporter{w: w, format: func(e Entry) (string, error) { return FormatSyslog(e, host), nil }}, nil
+	}
+	return nil, fmt.Errorf("unknown export format %q (expected %s)", format, ExportFormats)
+}
+
+var csvHeader = []string{"seq", "timestamp", "tenant", "action", "actor", "target", "details", "hash"}
+
+type csvExporter struct {
+	w *csv.Writer
+}
+
+func newCSVExporter(w io.Writer) (*csvExporter, error) {
+	c := &csvExporter{w: csv.NewWriter(w)}
+	return c, c.w.Write(csvHeader)
+}
+
+func (c *csvExporter) Write(e Entry) error {
+	details := ""
+	if len(e.Details) > 0 {
+		b, err := json.Marshal(e.Details)
+		if err != nil {
+			return err
+		}
+		details = string(b)
+	}
+	return c.w.Write([]string{e.Field("seq"), e.Timestamp.Format(time.RFC3339Nano), e.TenantID, e.Action, e.Actor, e.Target, details, e.Hash})
+}
+
+func (c *csvExporter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonExporter writes one JSON array.
+type jsonExporter struct {
+	w io.Writer
+	n int
+}
+
+func (j *jsonExporter) Write(e Entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	sep := ",\n  "
+	if j.n == 0 {
+		sep = "[\n  "
+	}
+	j.n++
+	_, err = fmt.Fprint(j.w, sep, string(b))
+	return err
+}
+
+func (j *jsonExporter) Close() error {
+	if j.n == 0 {
+		_, err := fmt.Fprintln(j.w, "[]")
+		return err
+	}
+	_, err := fmt.Fprintln(j.w, "\n]")
+	return err
+}
+
+type lineExporter struct {
+	w      io.Writer
+	format func(Entry) (string, error)
+}
+
+func (l *lineExporter) Write(e Entry) error {
+	s, err := l.format(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(l.w, s)
+	return err
+}
+
+func (l *lineExporter) Close() error { return nil }
+
+// denied reports whether e records a refused action.
+func denied(e Entry) bool {
+	return e.Field("details.allowed") == "false"
+}
+
+// FormatCEF renders e as an ArcSight Common Event Format line. Refused
+// actions (details.allowed=false) get severity 7, everything else 3.
+func FormatCEF(e Entry) string {
+	severity := "3"
+	if denied(e) {
+		severity = "7"
+	}
+	header := []string{"CEF:0", "missionctl", "missionctl", "1", e.Action, e.Action, severity}
+	for i := 1; i < len(header); i++ {
+		header[i] = cefHeaderEscaper.Replace(header[i])
+	}
+	ext := []string{"rt=" + strconv.FormatInt(e.Timestamp.UnixMilli(), 10)}
+	add := func(key, value string) {
+		if value != "" {
+			ext = append(ext, key+"="+cefExtEscaper.Replace(value))
+		}
+	}
+	add("suser", e.Actor)
+	add("act", e.Action)
+	for _, c := range [][3]string{{"cs1", "tenant", e.TenantID}, {"cs2", "target", e.Target}, {"cn1", "seq", e.Field("seq")}} {
+		if c[2] != "" {
+			add(c[0]+"Label", c[1])
+			add(c[0], c[2])
+		}
+	}
+	if len(e.Details) > 0 {
+		if b, err := json.Marshal(e.Details); err == nil {
+			add("msg", string(b))
+		}
+	}
+	return strings.Join(header, "|") + "|" + strings.Join(ext, " ")
+}
+
+var (
+	cefHeaderEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ")
+	cefExtEscaper    = strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`)
+)
+
+// syslogFacility is authpriv, where security events belong.
+const syslogFacility = 10
+
+// syslogSDID identifies missionctl's structured data element.
+const syslogSDID = "missionctl@32473"
+
+// FormatSyslog renders e as an RFC 5424 message from host: notice
+// severity, warning for refused actions, with the entry's fields as
+// structured data and its details as the message.
+func FormatSyslog(e Entry, host string) string {
+	severity := 5
+	if denied(e) {
+		severity = 4
+	}
+	if host == "" {
+		host = "-"
+	}
+	var sd strings.Builder
+	sd.WriteString("[" + syslogSDID)
+	for _, kv := range [][2]string{{"seq", e.Field("seq")}, {"tenant", e.TenantID}, {"actor", e.Actor}, {"target", e.Target}} {
+		if kv[1] != "" {
+			sd.WriteString(" " + kv[0] + `="` + sdEscaper.Replace(kv[1]) + `"`)
+		}
+	}
+	sd.WriteString("]")
+	msg := ""
+	if len(e.Details) > 0 {
+		if b, err := json.Marshal(e.Details); err == nil {
+			msg = " " + string(b)
+		}
+	}
+	return fmt.Sprintf("<%d>1 %s %s missionctl - %s %s%s",
+		syslogFacility*8+severity, e.Timestamp.UTC().Format(time.RFC3339Nano), host, syslogMsgID(e.Action), sd.String(), msg)
+}
+
+var sdEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// syslogMsgID fits action into a MSGID: at most 32 printable characters.
+func syslogMsgID(action string) string {
+	if action == "" {
+		return "-"
+	}
+	id := strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return '_'
+		}
+		return r
+	}, action)
+	if len(id) > 32 {
+		id = id[:32]
+	}
+	return id
+}
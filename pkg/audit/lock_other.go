@@ -0,0 +1,11 @@
+//go:build !unix
+
+package audit
+
+import "os"
+
+// lockFile is a no-op where flock is unavailable; Record still serialises
+// writers within one process.
+func lockFile(f *os.File) error { return nil }
+
+func unlockFile(f *os.File) {}
@@ -0,0 +1,206 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourusername/devops-mission-control/pkg/authz"
+)
+
+// Query selects audit entries: those in [Since, Until) that match every
+// term. The zero Query matches everything.
+type Query struct {
+	Since time.Time
+	Until time.Time
+	Terms []Term
+}
+
+// Term compares one field of an entry with a glob pattern (see authz.Glob).
+// Fields are seq, tenant, action, actor, target and details.<key>, where
+// <key> may be a dotted path into nested details.
+type Term struct {
+	Field   string
+	Pattern string
+	Negate  bool
+}
+
+// ParseTerm parses field=pattern or field!=pattern. A term on a details key
+// the entry lacks matches the empty pattern, so details.reason= finds
+// entries without a reason.
+func ParseTerm(s string) (Term, error) {
+	i := strings.Index(s, "=")
+	if i <= 0 {
+		return Term{}, fmt.Errorf("invalid query term %q (expected field=value or field!=value)", s)
+	}
+	t := Term{Field: s[:i], Pattern: s[i+1:]}
+	if strings.HasSuffix(t.Field, "!") {
+		t.Field, t.Negate = strings.TrimSuffix(t.Field, "!"), true
+	}
+	if !validField(t.Field) {
+		return Term{}, fmt.Errorf("unknown query field %q (use seq, tenant, action, actor, target or details.<key>)", t.Field)
+	}
+	return t, nil
+}
+
+func validField(f string) bool {
+	switch f {
+	case "seq", "tenant", "action", "actor", "target":
+		return true
+	}
+	return strings.HasPrefix(f, "details.") && len(f) > len("details.")
+}
+
+// Match reports whether e satisfies the term.
+func (t Term) Match(e Entry) bool {
+	return authz.Glob(t.Pattern, e.Field(t.Field)) != t.Negate
+}
+
+// Match reports whether e satisfies q.
+func (q Query) Match(e Entry) bool {
+	if !q.Since.IsZero() && e.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !e.Timestamp.Before(q.Until) {
+		return false
+	}
+	for _, t := range q.Terms {
+		if !t.Match(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// Field renders one field of e as text, "" when it is not set. Details
+// values print as JSON unless they are strings.
+func (e Entry) Field(name string) string {
+	switch name {
+	case "seq":
+		if e.Seq == 0 {
+			return ""
+		}
+		return strconv.FormatUint(e.Seq, 10)
+	case "tenant":
+		return e.TenantID
+	case "action":
+		return e.Action
+	case "actor":
+		return e.Actor
+	case "target":
+		return e.Target
+	}
+	path, ok := strings.CutPrefix(name, "details.")
+	if !ok {
+		return ""
+	}
+	var v any = e.Details
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return ""
+		}
+		if v, ok = m[key]; !ok {
+			return ""
+		}
+	}
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// ParseTime parses an RFC 3339 timestamp or a duration before now such as
+// 90m, 24h or 7d.
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	d, err := ParseDuration(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q (expected RFC 3339 or a duration such as 24h or 7d)", s)
+	}
+	return now.Add(-d), nil
+}
+
+// Group is one row of an aggregation: the values of the grouped fields and
+// how many entries had them.
+type Group struct {
+	Values map[string]string `json:"values"`
+	Count  int               `json:"count"`
+}
+
+// Aggregator counts entries by the values of some fields.
+type Aggregator struct {
+	fields []string
+	counts map[string]*Group
+}
+
+// NewAggregator groups by fields, which use the names Term accepts.
+func NewAggregator(fields []string) (*Aggregator, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("nothing to group by")
+	}
+	for _, f := range fields {
+		if !validField(f) {
+			return nil, fmt.Errorf("unknown group-by field %q", f)
+		}
+	}
+	return &Aggregator{fields: fields, counts: map[string]*Group{}}, nil
+}
+
+// Add counts e.
+func (a *Aggregator) Add(e Entry) {
+	values := make([]string, len(a.fields))
+	for i, f := range a.fields {
+		values[i] = e.Field(f)
+	}
+	key := strings.Join(values, "\x00")
+	g, ok := a.counts[key]
+	if !ok {
+		g = &Group{Values: make(map[string]string, len(a.fields))}
+		for i, f := range a.fields {
+			g.Values[f] = values[i]
+		}
+		a.counts[key] = g
+	}
+	g.Count++
+}
+
+// Fields returns the grouped fields.
+func (a *Aggregator) Fields() []string {
+	return a.fields
+}
+
+// Groups returns the groups, largest first.
+func (a *Aggregator) Groups() []Group {
+	out := make([]Group, 0, len(a.counts))
+	for _, g := range a.counts {
+		out = append(out, *g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return groupKey(out[i], a.fields) < groupKey(out[j], a.fields)
+	})
+	return out
+}
+
+func groupKey(g Group, fields []string) string {
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = g.Values[f]
+	}
+	return strings.Join(parts, "\x00")
+}
@@ -0,0 +1,210 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Reader streams the entries of an audit log, oldest first: the rotated
+// segments, then the active file. Only the current entry is held in memory.
+type Reader struct {
+	paths []string
+	idx   int
+	f     *os.File
+	gz    *gzip.Reader
+	br    *bufio.Reader
+	path  string
+	line  int
+}
+
+// Open returns a Reader over file (DefaultFile if "") and its segments. It
+// fails with os.ErrNotExist when neither exists.
+func Open(file string) (*Reader, error) {
+	if file == "" {
+		file = DefaultFile
+	}
+	paths, err := Segments(file)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(file); err == nil {
+		paths = append(paths, file)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("audit log %s: %w", file, os.ErrNotExist)
+	}
+	return &Reader{paths: paths}, nil
+}
+
+// Next returns the next entry, or io.EOF after the last one.
+func (r *Reader) Next() (Entry, error) {
+	var e Entry
+	line, err := r.nextLine()
+	if err != nil {
+		return e, err
+	}
+	if err := json.Unmarshal(line, &e); err != nil {
+		return e, fmt.Errorf("%s line %d: %w", r.path, r.line, err)
+	}
+	return e, nil
+}
+
+// nextLine returns the next non-blank line, moving on to the next segment
+// when the current one ends.
+func (r *Reader) nextLine() ([]byte, error) {
+	for {
+		if r.br == nil {
+			if r.idx >= len(r.paths) {
+				return nil, io.EOF
+			}
+			if err := r.openNext(); err != nil {
+				return nil, err
+			}
+		}
+		line, err := r.br.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			r.line++
+			return line, nil
+		}
+		if err == io.EOF {
+			r.closeCurrent()
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		r.line++
+	}
+}
+
+func (r *Reader) openNext() error {
+	path := r.paths[r.idx]
+	r.idx++
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	r.f, r.path, r.line = f, path, 0
+	if strings.HasSuffix(path, ".gz") {
+		if r.gz, err = gzip.NewReader(f); err != nil {
+			r.closeCurrent()
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		r.br = bufio.NewReader(r.gz)
+		return nil
+	}
+	r.br = bufio.NewReader(f)
+	return nil
+}
+
+func (r *Reader) closeCurrent() {
+	if r.gz != nil {
+		_ = r.gz.Close()
+	}
+	if r.f != nil {
+		_ = r.f.Close()
+	}
+	r.f, r.gz, r.br = nil, nil, nil
+}
+
+// Close releases the file being read.
+func (r *Reader) Close() error {
+	r.closeCurrent()
+	r.idx = len(r.paths)
+	return nil
+}
+
+// skipBefore drops whole segments whose successor starts at or before seq,
+// so seeking near the end of a long log does not decompress all of it.
+func (r *Reader) skipBefore(seq uint64) {
+	for r.br == nil && r.idx+1 < len(r.paths) {
+		next, err := firstSeq(r.paths[r.idx+1])
+		if err != nil || next == 0 || next > seq {
+			return
+		}
+		r.idx++
+	}
+}
+
+// firstSeq returns the seq of the first entry in path, 0 if it has none.
+func firstSeq(path string) (uint64, error) {
+	r := &Reader{paths: []string{path}}
+	defer r.Close()
+	e, err := r.Next()
+	if err != nil {
+		return 0, err
+	}
+	return e.Seq, nil
+}
+
+// ErrInvalidCursor is returned by Page for a cursor it did not produce.
+var ErrInvalidCursor = errors.New("invalid audit cursor")
+
+// Page returns up to limit entries after cursor that match keep (nil keeps
+// everything), and the cursor of the next page, "" when there is none. A
+// cursor is the seq of the last entry returned, or "u<n>" inside the
+// unchained entries that predate hash chaining. limit <= 0 means no limit.
+func Page(file, cursor string, limit int, keep func(Entry) bool) ([]Entry, string, error) {
+	var afterSeq uint64
+	skipUnchained, inUnchained := 0, false
+	switch {
+	case cursor == "":
+	case strings.HasPrefix(cursor, "u"):
+		n, err := strconv.Atoi(cursor[1:])
+		if err != nil || n < 0 {
+			return nil, "", fmt.Errorf("%w %q", ErrInvalidCursor, cursor)
+		}
+		skipUnchained, inUnchained = n, true
+	default:
+		n, err := strconv.ParseUint(cursor, 10, 64)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w %q", ErrInvalidCursor, cursor)
+		}
+		afterSeq = n
+	}
+	r, err := Open(file)
+	if err != nil {
+		return nil, "", err
+	}
+	defer r.Close()
+	if afterSeq > 0 {
+		r.skipBefore(afterSeq)
+	}
+	out := []Entry{}
+	unchained := 0
+	for limit <= 0 || len(out) < limit {
+		e, err := r.Next()
+		if err == io.EOF {
+			return out, "", nil
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		if e.Seq == 0 {
+			unchained++
+			if afterSeq > 0 || (inUnchained && unchained <= skipUnchained) {
+				continue
+			}
+		} else if e.Seq <= afterSeq {
+			continue
+		}
+		if keep == nil || keep(e) {
+			out = append(out, e)
+		}
+		cursor = strconv.FormatUint(e.Seq, 10)
+		if e.Seq == 0 {
+			cursor = "u" + strconv.Itoa(unchained)
+		}
+	}
+	return out, cursor, nil
+}
@@ -0,0 +1,210 @@
+package audit
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rotation controls when Record moves the active file to a gzipped segment
+// and how long segments are kept. Zero fields disable that limit.
+type Rotation struct {
+	// MaxSize rotates once the active file reaches this many bytes.
+	MaxSize int64
+	// MaxAge rotates once the oldest entry of the active file is this old.
+	MaxAge time.Duration
+	// Retention deletes segments rotated longer ago than this, except the
+	// newest.
+	Retention time.Duration
+}
+
+// DefaultRotation applies to DefaultFile.
+var DefaultRotation Rotation
+
+// segmentLayout names segments <file>.<rotation time>.gz; it has a fixed
+// width so segments sort by name.
+const segmentLayout = "20060102T150405.000000000Z"
+
+// Segments returns the rotated segments of file, oldest first.
+func Segments(file string) ([]string, error) {
+	matches, err := filepath.Glob(globEscape(file) + ".*")
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, m := range matches {
+		if _, ok := segmentTime(file, m); ok {
+			out = append(out, m)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// segmentTime parses the rotation time out of a segment name. Segments not
+// yet compressed (the process died between rename and gzip) count too.
+func segmentTime(file, path string) (time.Time, bool) {
+	stamp := strings.TrimSuffix(strings.TrimPrefix(path, file+"."), ".gz")
+	t, err := time.Parse(segmentLayout, stamp)
+	return t, err == nil && len(stamp) == len(segmentLayout)
+}
+
+func globEscape(s string) string {
+	r := strings.NewReplacer(`*`, `\*`, `?`, `\?`, `[`, `\[`)
+	return r.Replace(s)
+}
+
+// Rotate moves DefaultFile to a new segment, if it holds any entries, and
+// applies DefaultRotation.Retention. Record does this on its own when a
+// limit is reached; Rotate is for cron jobs and tests.
+func Rotate() error {
+	mu.Lock()
+	defer mu.Unlock()
+	unlock, err := lockLog(DefaultFile)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return rotateLocked(DefaultFile, DefaultRotation, time.Now())
+}
+
+// dueForRotation reports whether file has outgrown rot.
+func dueForRotation(file string, rot Rotation, now time.Time) (bool, error) {
+	if rot.MaxSize <= 0 && rot.MaxAge <= 0 {
+		return false, nil
+	}
+	info, err := os.Stat(file)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && info.Size() == 0) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if rot.MaxSize > 0 && info.Size() >= rot.MaxSize {
+		return true, nil
+	}
+	if rot.MaxAge > 0 {
+		r := &Reader{paths: []string{file}}
+		defer r.Close()
+		first, err := r.Next()
+		if err != nil && err != io.EOF {
+			return false, err
+		}
+		return err == nil && now.Sub(first.Timestamp) >= rot.MaxAge, nil
+	}
+	return false, nil
+}
+
+// rotateLocked gzips file into a segment and prunes expired segments. The
+// caller holds the log lock.
+func rotateLocked(file string, rot Rotation, now time.Time) error {
+	info, err := os.Stat(file)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err == nil && info.Size() > 0 {
+		seg := file + "." + now.UTC().Format(segmentLayout)
+		if err := os.Rename(file, seg); err != nil {
+			return fmt.Errorf("audit rotate error: %s", err)
+		}
+		if err := compress(seg); err != nil {
+			return fmt.Errorf("audit rotate error: %s", err)
+		}
+	}
+	return prune(file, rot.Retention, now)
+}
+
+// compress replaces path with path.gz.
+func compress(path string) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	tmp := path + ".gz.tmp"
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(out)
+	_, err = io.Copy(zw, in)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path+".gz")
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Remove(path)
+}
+
+// prune deletes segments rotated more than retention before now. The
+// newest segment is always kept: the chain then starts in a segment, which
+// lets Verify tell retention from entries cut off the active file.
+func prune(file string, retention time.Duration, now time.Time) error {
+	if retention <= 0 {
+		return nil
+	}
+	segs, err := Segments(file)
+	if err != nil || len(segs) == 0 {
+		return err
+	}
+	for _, s := range segs[:len(segs)-1] {
+		if t, _ := segmentTime(file, s); now.Sub(t) > retention {
+			if err := os.Remove(s); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("audit retention error: %s", err)
+			}
+		}
+	}
+	return nil
+}
+
+// ParseSize parses a byte count such as 500000, 100KB, 64MB or 1GB.
+func ParseSize(s string) (int64, error) {
+	units := []struct {
+		suffix string
+		mult   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1}}
+	v := strings.ToUpper(strings.TrimSpace(s))
+	mult := int64(1)
+	for _, u := range units {
+		if strings.HasSuffix(v, u.suffix) {
+			v, mult = strings.TrimSpace(strings.TrimSuffix(v, u.suffix)), u.mult
+			break
+		}
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * mult, nil
+}
+
+// ParseDuration is time.ParseDuration plus a d suffix for days, e.g. 90d.
+func ParseDuration(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(strings.TrimSpace(s), "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return d, nil
+}
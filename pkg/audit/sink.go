@@ -0,0 +1,315 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Sink receives recorded audit entries.
+type Sink interface {
+	Write(e Entry) error
+	// Close flushes anything buffered and releases the sink.
+	Close() error
+}
+
+// chainer is a Sink that links entries into the hash chain as it stores
+// them. Fan-outs run chainers first and hand the chained entry on.
+type chainer interface {
+	Append(e Entry) (Entry, error)
+}
+
+// ErrBufferFull is returned by an async sink that had to drop an entry.
+var ErrBufferFull = errors.New("audit buffer full, entry dropped")
+
+var (
+	sinkMu sync.RWMutex
+	sink   Sink // nil: a FileSink on DefaultFile
+)
+
+// UseSinks makes Record write to every one of sinks. With buffer > 0 Record
+// only queues entries, up to buffer of them, and a background goroutine
+// writes them, so callers never wait on a slow sink. The sinks in use
+// before are closed. Call Close before exiting to flush the queue.
+func UseSinks(buffer int, sinks ...Sink) error {
+	var s Sink = Fanout(sinks...)
+	if len(sinks) == 1 {
+		s = sinks[0]
+	}
+	if buffer > 0 {
+		s = NewAsync(s, buffer)
+	}
+	sinkMu.Lock()
+	old := sink
+	sink = s
+	sinkMu.Unlock()
+	if old != nil {
+		return old.Close()
+	}
+	return nil
+}
+
+// Close flushes and closes the sinks set by UseSinks; Record then writes
+// to DefaultFile again.
+func Close() error {
+	sinkMu.Lock()
+	old := sink
+	sink = nil
+	sinkMu.Unlock()
+	if old != nil {
+		return old.Close()
+	}
+	return nil
+}
+
+// currentSink returns the sink Record writes to.
+func currentSink() Sink {
+	sinkMu.RLock()
+	defer sinkMu.RUnlock()
+	if sink != nil {
+		return sink
+	}
+	return DefaultFileSink()
+}
+
+// FileSink appends entries to a JSON Lines file, chaining, signing and
+// rotating it as Record always has. The file is locked while an entry is
+// appended, so several processes may share it.
+type FileSink struct {
+	Path     string
+	Rotation Rotation
+	Key      Key
+}
+
+// DefaultFileSink writes to DefaultFile with DefaultRotation and SigningKey.
+func DefaultFileSink() *FileSink {
+	return &FileSink{Path: DefaultFile, Rotation: DefaultRotation, Key: SigningKey}
+}
+
+// Append chains e to the last entry of the file, writes it and returns it
+// as written.
+func (s *FileSink) Append(e Entry) (Entry, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	unlock, err := lockLog(s.Path)
+	if err != nil {
+		return e, err
+	}
+	defer unlock()
+	last, err := lastRecorded(s.Path)
+	if err != nil {
+		return e, err
+	}
+	if due, err := dueForRotation(s.Path, s.Rotation, e.Timestamp); err != nil {
+		return e, err
+	} else if due {
+		if err := rotateLocked(s.Path, s.Rotation, e.Timestamp); err != nil {
+			return e, err
+		}
+	}
+	if err := chain(&e, last, s.Key); err != nil {
+		return e, err
+	}
+	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return e, err
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil {
+			fmt.Fprintf(os.Stderr, "audit file close failed: %v\n", cerr)
+		}
+	}()
+	return e, json.NewEncoder(f).Encode(e)
+}
+
+func (s *FileSink) Write(e Entry) error {
+	_, err := s.Append(e)
+	return err
+}
+
+func (s *FileSink) Close() error { return nil }
+
+// WriterSink writes entries to W as JSON Lines, e.g. to stdout for a log
+// collector.
+type WriterSink struct {
+	W  io.Writer
+	mu sync.Mutex
+}
+
+func (s *WriterSink) Write(e Entry) error {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = fmt.Fprintln(s.W, string(b))
+	return err
+}
+
+func (s *WriterSink) Close() error { return nil }
+
+type fanout []Sink
+
+// Fanout returns a sink writing every entry to all of sinks. Chaining sinks
+// (FileSink) are written first, so the others receive entries with their
+// seq and hash. A failing sink does not stop the others.
+func Fanout(sinks ...Sink) Sink {
+	ordered := make(fanout, 0, len(sinks))
+	for _, s := range sinks {
+		if _, ok := s.(chainer); ok {
+			ordered = append(ordered, s)
+		}
+	}
+	for _, s := range sinks {
+		if _, ok := s.(chainer); !ok {
+			ordered = append(ordered, s)
+		}
+	}
+	return ordered
+}
+
+func (f fanout) Write(e Entry) error {
+	var errs []error
+	chained := false
+	for _, s := range f {
+		if c, ok := s.(chainer); ok {
+			out, err := c.Append(e)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if !chained {
+				e, chained = out, true
+			}
+			continue
+		}
+		if err := s.Write(e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f fanout) Close() error {
+	var errs []error
+	for _, s := range f {
+		if err := s.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// AsyncSink queues entries for another sink and writes them from a
+// goroutine. Write never blocks: when the queue is full the entry is
+// dropped, and once there is room again an audit.dropped entry records how
+// many were lost.
+type AsyncSink struct {
+	sink    Sink
+	queue   chan Entry
+	done    chan struct{}
+	dropped atomic.Int64
+	mu      sync.RWMutex
+	closed  bool
+	// OnError reports failed writes; they go to stderr by default.
+	OnError func(error)
+}
+
+// NewAsync starts an AsyncSink holding up to size entries for s.
+func NewAsync(s Sink, size int) *AsyncSink {
+	a := &AsyncSink{sink: s, queue: make(chan Entry, size), done: make(chan struct{})}
+	go a.run()
+	return a
+}
+
+func (a *AsyncSink) Write(e Entry) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.closed {
+		return errors.New("audit sink closed")
+	}
+	select {
+	case a.queue <- e:
+		return nil
+	default:
+		a.dropped.Add(1)
+		return ErrBufferFull
+	}
+}
+
+// Dropped returns how many entries were dropped and not yet reported.
+func (a *AsyncSink) Dropped() int64 {
+	return a.dropped.Load()
+}
+
+func (a *AsyncSink) run() {
+	defer close(a.done)
+	for e := range a.queue {
+		a.write(e)
+		if n := a.dropped.Swap(0); n > 0 {
+			a.write(Entry{Timestamp: time.Now(), Action: "audit.dropped", Details: map[string]any{"count": n}})
+		}
+	}
+}
+
+func (a *AsyncSink) write(e Entry) {
+	if err := a.sink.Write(e); err != nil {
+		if a.OnError != nil {
+			a.OnError(err)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "audit sink error: %v\n", err)
+	}
+}
+
+// Close writes what is queued and closes the underlying sink.
+func (a *AsyncSink) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	close(a.queue)
+	a.mu.Unlock()
+	<-a.done
+	return a.sink.Close()
+}
+
+// ParseSink builds a sink from a spec:
+//
+//	file                      DefaultFile (audit_file)
+//	file:<path>               another JSON Lines file
+//	stdout                    JSON Lines on standard output
+//	syslog+udp://host:514     RFC 5424 syslog over UDP (also syslog://)
+//	syslog+tcp://host:601     ... over TCP
+//	syslog+tls://host:6514    ... over TLS
+//	http(s)://host/path       batched JSON webhook
+func ParseSink(spec string) (Sink, error) {
+	spec = strings.TrimSpace(spec)
+	switch {
+	case spec == "file":
+		return DefaultFileSink(), nil
+	case strings.HasPrefix(spec, "file:"):
+		return &FileSink{Path: strings.TrimPrefix(spec, "file:"), Rotation: DefaultRotation, Key: SigningKey}, nil
+	case spec == "stdout":
+		return &WriterSink{W: os.Stdout}, nil
+	case strings.HasPrefix(spec, "syslog"):
+		scheme, addr, ok := strings.Cut(spec, "://")
+		network := map[string]string{"syslog": "udp", "syslog+udp": "udp", "syslog+tcp": "tcp", "syslog+tls": "tls"}[scheme]
+		if !ok || network == "" || addr == "" {
+			return nil, fmt.Errorf("invalid syslog sink %q (expected syslog+udp|tcp|tls://host:port)", spec)
+		}
+		return NewSyslogSink(network, addr, nil), nil
+	case strings.HasPrefix(spec, "http://"), strings.HasPrefix(spec, "https://"):
+		return NewWebhookSink(spec), nil
+	}
+	return nil, fmt.Errorf("unknown audit sink %q (use file, file:<path>, stdout, syslog+udp|tcp|tls://host:port or an http(s) URL)", spec)
+}
@@ -0,0 +1,259 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memSink keeps what it is given; write may block it.
+type memSink struct {
+	mu      sync.Mutex
+	entries []Entry
+	block   chan struct{}
+}
+
+func (m *memSink) Write(e Entry) error {
+	if m.block != nil {
+		<-m.block
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = append(m.entries, e)
+	return nil
+}
+
+func (m *memSink) Close() error { return nil }
+
+func (m *memSink) got() []Entry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Entry(nil), m.entries...)
+}
+
+func TestFanoutChainsBeforeOtherSinks(t *testing.T) {
+	inTempDir(t)
+	mem := &memSink{}
+	// The file sink is listed last but must still run first.
+	if err := UseSinks(0, mem, DefaultFileSink()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = Close() })
+	for i := 0; i < 3; i++ {
+		if err := Record("", "fan.out", "bob", "", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := mem.got()
+	if len(got) != 3 || got[2].Seq != 3 || got[2].Hash == "" || got[2].PrevHash != got[1].Hash {
+		t.Fatalf("other sinks should get chained entries: %+v", got)
+	}
+	if rep, err := Verify(DefaultFile, nil); err != nil || rep.Break != nil || rep.Entries != 3 {
+		t.Fatalf("file chain: %+v %v", rep, err)
+	}
+}
+
+func TestAsyncSinkDropsWhenFull(t *testing.T) {
+	mem := &memSink{block: make(chan struct{})}
+	a := NewAsync(mem, 2)
+	// The worker takes the first entry and blocks on it; two more fill the
+	// queue and the rest are dropped without waiting.
+	var dropped int
+	for i := 0; i < 6; i++ {
+		done := make(chan error, 1)
+		go func() { done <- a.Write(Entry{Action: "e" + strconv.Itoa(i)}) }()
+		select {
+		case err := <-done:
+			if errors.Is(err, ErrBufferFull) {
+				dropped++
+			} else if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Write blocked on a slow sink")
+		}
+		if i == 0 {
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+	if dropped != 3 || a.Dropped() != 3 {
+		t.Fatalf("dropped %d (counted %d), want 3", dropped, a.Dropped())
+	}
+	close(mem.block)
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	got := mem.got()
+	if len(got) != 4 {
+		t.Fatalf("want 3 entries and a drop report, got %+v", got)
+	}
+	// The report follows the entry the worker was stuck on.
+	if r := got[1]; r.Action != "audit.dropped" || r.Field("details.count") != "3" || got[3].Action != "e2" {
+		t.Fatalf("drop report: %+v", got)
+	}
+	if err := a.Write(Entry{}); err == nil {
+		t.Fatal("Write after Close should fail")
+	}
+}
+
+func TestWebhookSinkBatchesAndRetries(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		batches [][]Entry
+		calls   int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if u, p, ok := r.BasicAuth(); !ok || u != "audit" || p != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var batch []Entry
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		batches = append(batches, batch)
+	}))
+	defer srv.Close()
+
+	s := NewWebhookSink(strings.Replace(srv.URL, "http://", "http://audit:s3cret@", 1))
+	s.BatchSize, s.FlushInterval, s.RetryDelay = 2, time.Hour, time.Millisecond
+	for i := 1; i <= 3; i++ {
+		if err := s.Write(Entry{Seq: uint64(i), Action: "hook"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The third entry waits for Close.
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 3 || len(batches) != 2 || len(batches[0]) != 2 || len(batches[1]) != 1 || batches[1][0].Seq != 3 {
+		t.Fatalf("calls=%d batches=%+v", calls, batches)
+	}
+
+	rejected := 0
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rejected++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer rejecting.Close()
+	s = NewWebhookSink(rejecting.URL)
+	s.BatchSize, s.RetryDelay = 1, time.Millisecond
+	if err := s.Write(Entry{Action: "hook"}); err == nil || rejected != 1 {
+		t.Fatalf("a 400 should fail without retrying: calls=%d err=%v", rejected, err)
+	}
+}
+
+func TestSyslogSink(t *testing.T) {
+	e := Entry{Seq: 7, Timestamp: time.Now(), Action: "auth.check", Actor: "bob", Details: map[string]any{"allowed": false}}
+
+	udp, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer udp.Close()
+	s := NewSyslogSink("udp", udp.LocalAddr().String(), nil)
+	if err := s.Write(e); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4096)
+	_ = udp.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := udp.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(buf[:n]); !strings.HasPrefix(got, "<84>1 ") || !strings.Contains(got, `seq="7"`) {
+		t.Fatalf("udp message: %q", got)
+	}
+	_ = s.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	msgs := make(chan string, 2)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			size, err := r.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(size))
+			msg := make([]byte, n)
+			if _, err := io.ReadFull(r, msg); err != nil {
+				return
+			}
+			msgs <- string(msg)
+		}
+	}()
+	s = NewSyslogSink("tcp", ln.Addr().String(), nil)
+	defer s.Close()
+	for i := 0; i < 2; i++ {
+		if err := s.Write(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case m := <-msgs:
+			if !strings.Contains(m, "auth.check") {
+				t.Fatalf("tcp message: %q", m)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("octet-counted message not received")
+		}
+	}
+}
+
+func TestParseSink(t *testing.T) {
+	for spec, want := range map[string]string{
+		"file":                    "*audit.FileSink",
+		"file:/var/log/audit.log": "*audit.FileSink",
+		"stdout":                  "*audit.WriterSink",
+		"syslog://logs:514":       "*audit.SyslogSink",
+		"syslog+tls://logs:6514":  "*audit.SyslogSink",
+		"https://siem/ingest":     "*audit.WebhookSink",
+	} {
+		s, err := ParseSink(spec)
+		if err != nil {
+			t.Fatalf("%s: %v", spec, err)
+		}
+		if got := fmt.Sprintf("%T", s); got != want {
+			t.Fatalf("%s: got %s, want %s", spec, got, want)
+		}
+	}
+	if s, _ := ParseSink("syslog+tls://logs:6514"); s.(*SyslogSink).Network != "tls" {
+		t.Fatal("syslog+tls should dial tls")
+	}
+	for _, spec := range []string{"", "syslog+quic://x:1", "syslog+tcp://", "kafka://x"} {
+		if _, err := ParseSink(spec); err == nil {
+			t.Fatalf("%q should be rejected", spec)
+		}
+	}
+}
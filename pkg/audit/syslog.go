@@ -0,0 +1,80 @@
+package audit
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// syslogDialTimeout bounds connecting to a syslog server.
+const syslogDialTimeout = 5 * time.Second
+
+// SyslogSink sends entries to a syslog server as RFC 5424 messages (see
+// FormatSyslog). Over UDP each message is one datagram; over TCP and TLS
+// messages are octet-counted as RFC 6587 describes. A broken connection is
+// redialled once per entry.
+type SyslogSink struct {
+	// Network is udp, tcp or tls.
+	Network string
+	Addr    string
+	// TLS configures tls connections; nil verifies against the system roots.
+	TLS      *tls.Config
+	Hostname string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslogSink returns a sink for the server at addr.
+func NewSyslogSink(network, addr string, tlsConfig *tls.Config) *SyslogSink {
+	host, _ := os.Hostname()
+	return &SyslogSink{Network: network, Addr: addr, TLS: tlsConfig, Hostname: host}
+}
+
+func (s *SyslogSink) Write(e Entry) error {
+	msg := FormatSyslog(e, s.Hostname)
+	if s.Network != "udp" {
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if s.conn == nil {
+			if s.conn, err = s.dial(); err != nil {
+				return fmt.Errorf("audit syslog error: %s", err)
+			}
+		}
+		if _, err = s.conn.Write([]byte(msg)); err == nil {
+			return nil
+		}
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	return fmt.Errorf("audit syslog error: %s", err)
+}
+
+func (s *SyslogSink) dial() (net.Conn, error) {
+	d := &net.Dialer{Timeout: syslogDialTimeout}
+	switch s.Network {
+	case "udp", "tcp":
+		return d.Dial(s.Network, s.Addr)
+	case "tls":
+		return tls.DialWithDialer(d, "tcp", s.Addr, s.TLS)
+	}
+	return nil, fmt.Errorf("unknown syslog network %q", s.Network)
+}
+
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -0,0 +1,151 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// WebhookSink POSTs entries to a URL as JSON arrays of up to BatchSize
+// entries, sent when a batch fills up and at least every FlushInterval.
+// Network errors, 429 and 5xx responses are retried with exponential
+// backoff; other failures drop the batch. Credentials in the URL are sent
+// as basic auth.
+type WebhookSink struct {
+	URL           string
+	BatchSize     int
+	FlushInterval time.Duration
+	// Retries is how many times a failed batch is resent.
+	Retries    int
+	RetryDelay time.Duration
+	Client     *http.Client
+
+	mu    sync.Mutex
+	batch []Entry
+	once  sync.Once
+	stop  chan struct{}
+	done  chan struct{}
+}
+
+// NewWebhookSink returns a sink posting to url with batches of 100, a two
+// second flush interval and three retries.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{
+		URL:           url,
+		BatchSize:     100,
+		FlushInterval: 2 * time.Second,
+		Retries:       3,
+		RetryDelay:    500 * time.Millisecond,
+		Client:        &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *WebhookSink) Write(e Entry) error {
+	s.once.Do(s.start)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batch = append(s.batch, e)
+	if len(s.batch) < s.BatchSize {
+		return nil
+	}
+	return s.flushLocked()
+}
+
+// start runs the flush timer.
+func (s *WebhookSink) start() {
+	s.stop, s.done = make(chan struct{}), make(chan struct{})
+	if s.FlushInterval <= 0 {
+		close(s.done)
+		return
+	}
+	go func() {
+		defer close(s.done)
+		t := time.NewTicker(s.FlushInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-t.C:
+				if err := s.Flush(); err != nil {
+					fmt.Fprintf(os.Stderr, "audit sink error: %v\n", err)
+				}
+			}
+		}
+	}()
+}
+
+// Flush sends the pending batch.
+func (s *WebhookSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.flushLocked()
+}
+
+func (s *WebhookSink) flushLocked() error {
+	if len(s.batch) == 0 {
+		return nil
+	}
+	batch := s.batch
+	s.batch = nil
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	delay := s.RetryDelay
+	for attempt := 0; ; attempt++ {
+		retry, err := s.post(body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= s.Retries {
+			return fmt.Errorf("audit webhook error: %s (%d entries dropped)", err, len(batch))
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// post sends body once and reports whether a failure is worth retrying.
+func (s *WebhookSink) post(body []byte) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "missionctl-audit")
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("%s returned %s", req.URL.Redacted(), resp.Status)
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+// Close stops the flush timer and sends what is left.
+func (s *WebhookSink) Close() error {
+	s.once.Do(s.start)
+	s.mu.Lock()
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+	s.mu.Unlock()
+	<-s.done
+	return s.Flush()
+}
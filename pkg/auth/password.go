@@ -0,0 +1,185 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrInvalidCredentials is returned for an unknown user or a wrong
+	// password; the two are deliberately indistinguishable.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrAccountLocked is returned while a user is locked out after too many
+	// failed logins.
+	ErrAccountLocked = errors.New("account locked")
+)
+
+// argon2id parameters for new hashes (RFC 9106 recommendation for memory
+// constrained environments: 64 MiB, 3 passes).
+const (
+	argonTime    uint32 = 3
+	argonMemory  uint32 = 64 * 1024
+	argonThreads uint8  = 2
+	argonKeyLen  uint32 = 32
+	argonSaltLen        = 16
+)
+
+// HashPassword hashes a password with argon2id and returns it in the PHC
+// string format ($argon2id$v=19$m=...,t=...,p=...$salt$hash).
+func HashPassword(password string) (string, error) {
+	salt := make([]byte, argonSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, argonTime, argonMemory, argonThreads, argonKeyLen)
+	b64 := base64.RawStdEncoding
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, argonMemory, argonTime, argonThreads, b64.EncodeToString(salt), b64.EncodeToString(key)), nil
+}
+
+// VerifyPassword checks password against an argon2id or bcrypt hash in
+// constant time.
+func VerifyPassword(hash, password string) (bool, error) {
+	switch {
+	case strings.HasPrefix(hash, "$argon2id$"):
+		p, salt, key, err := decodeArgon2(hash)
+		if err != nil {
+			return false, err
+		}
+		got := argon2.IDKey([]byte(password), salt, p.time, p.memory, p.threads, uint32(len(key)))
+		return subtle.ConstantTimeCompare(got, key) == 1, nil
+	case isBcrypt(hash):
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+	return false, errors.New("unsupported password hash")
+}
+
+// IsPasswordHash reports whether s looks like a hash VerifyPassword accepts,
+// as opposed to a legacy plaintext password.
+func IsPasswordHash(s string) bool {
+	if strings.HasPrefix(s, "$argon2id$") {
+		_, _, _, err := decodeArgon2(s)
+		return err == nil
+	}
+	return isBcrypt(s)
+}
+
+// needsRehash reports whether hash should be replaced with one using the
+// current algorithm and parameters (bcrypt imports, older argon2 settings).
+func needsRehash(hash string) bool {
+	p, _, _, err := decodeArgon2(hash)
+	if err != nil {
+		return true
+	}
+	return p.time != argonTime || p.memory != argonMemory || p.threads != argonThreads
+}
+
+type argonParams struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+}
+
+func decodeArgon2(hash string) (argonParams, []byte, []byte, error) {
+	var p argonParams
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return p, nil, nil, errors.New("malformed argon2id hash")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, errors.New("unsupported argon2 version")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.threads); err != nil {
+		return p, nil, nil, fmt.Errorf("malformed argon2id parameters: %w", err)
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, err
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, errors.New("malformed argon2id hash")
+	}
+	return p, salt, key, nil
+}
+
+func isBcrypt(s string) bool {
+	return len(s) == 60 && (strings.HasPrefix(s, "$2a$") || strings.HasPrefix(s, "$2b$") || strings.HasPrefix(s, "$2y$"))
+}
+
+// dummyHash is verified against when the user does not exist, so a login
+// for an unknown user takes as long as one with a wrong password.
+var dummyHash = sync.OnceValue(func() string {
+	h, _ := HashPassword("missionctl-dummy-password")
+	return h
+})
+
+// PasswordPolicy describes what a password chosen by a person must satisfy.
+type PasswordPolicy struct {
+	MinLength int
+	// MinClasses is how many of lower case, upper case, digits and symbols
+	// the password must mix.
+	MinClasses int
+}
+
+// DefaultPasswordPolicy requires 12 characters from at least 3 classes.
+var DefaultPasswordPolicy = PasswordPolicy{MinLength: 12, MinClasses: 3}
+
+// Validate returns an error describing why password is not acceptable for
+// username.
+func (p PasswordPolicy) Validate(username, password string) error {
+	if n := len([]rune(password)); n < p.MinLength {
+		return fmt.Errorf("password too short: %d characters, need at least %d", n, p.MinLength)
+	}
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	classes := 0
+	for _, ok := range []bool{lower, upper, digit, symbol} {
+		if ok {
+			classes++
+		}
+	}
+	if classes < p.MinClasses {
+		return fmt.Errorf("password too weak: mix at least %d of lower case, upper case, digits and symbols", p.MinClasses)
+	}
+	if username != "" && strings.Contains(strings.ToLower(password), strings.ToLower(username)) {
+		return errors.New("password must not contain the username")
+	}
+	return nil
+}
+
+// LockoutPolicy locks a user out after MaxFailures consecutive failed
+// logins, for Duration.
+type LockoutPolicy struct {
+	MaxFailures int
+	Duration    time.Duration
+}
+
+// DefaultLockoutPolicy locks an account for 15 minutes after 5 failures.
+var DefaultLockoutPolicy = LockoutPolicy{MaxFailures: 5, Duration: 15 * time.Minute}
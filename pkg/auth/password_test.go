@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHashAndVerifyPassword(t *testing.T) {
+	hash, err := HashPassword("Correct-Horse-9")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$") || !IsPasswordHash(hash) {
+		t.Fatalf("unexpected hash %q", hash)
+	}
+	if ok, err := VerifyPassword(hash, "Correct-Horse-9"); !ok || err != nil {
+		t.Fatalf("verify correct password: %v %v", ok, err)
+	}
+	if ok, _ := VerifyPassword(hash, "correct-horse-9"); ok {
+		t.Fatal("wrong password verified")
+	}
+	other, _ := HashPassword("Correct-Horse-9")
+	if other == hash {
+		t.Fatal("hashes of the same password should be salted")
+	}
+
+	bc, err := bcrypt.GenerateFromPassword([]byte("Legacy-Pass-1"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := VerifyPassword(string(bc), "Legacy-Pass-1"); !ok || err != nil {
+		t.Fatalf("verify bcrypt: %v %v", ok, err)
+	}
+	if !needsRehash(string(bc)) || needsRehash(hash) {
+		t.Fatal("bcrypt hashes should be upgraded, current argon2id hashes not")
+	}
+	if IsPasswordHash("hunter2") {
+		t.Fatal("plaintext reported as a hash")
+	}
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	p := DefaultPasswordPolicy
+	cases := map[string]bool{
+		"short1A!":             false,
+		"alllowercaseletters":  false,
+		"lowercase-and-digit1": true,
+		"Mixed-Case-Words":     true,
+		"alice-Secret-2024":    false, // contains the username
+	}
+	for pw, ok := range cases {
+		if err := p.Validate("Alice", pw); (err == nil) != ok {
+			t.Errorf("Validate(%q) = %v, want ok=%v", pw, err, ok)
+		}
+	}
+}
+
+func TestAuthenticateLockout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.json")
+	us := NewUserStoreFile(path)
+	us.Lockout = LockoutPolicy{MaxFailures: 3, Duration: time.Hour}
+	if err := us.AddUser("alice", "Sup3r-secret", RoleViewer); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := us.Authenticate("nobody", "Sup3r-secret"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("unknown user: %v", err)
+	}
+	if _, err := us.Authenticate("alice", "Sup3r-secret"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := us.Authenticate("alice", "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("attempt %d: %v", i, err)
+		}
+	}
+	if _, err := us.Authenticate("alice", "wrong"); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("third failure should lock: %v", err)
+	}
+	if _, err := us.Authenticate("alice", "Sup3r-secret"); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("correct password while locked: %v", err)
+	}
+
+	// the lockout is persisted
+	reloaded := NewUserStoreFile(path)
+	if u, _ := reloaded.GetUser("alice"); !u.Locked(time.Now()) {
+		t.Fatal("lockout not persisted")
+	}
+	if err := reloaded.Unlock("alice"); err != nil {
+		t.Fatal(err)
+	}
+	u, err := reloaded.Authenticate("alice", "Sup3r-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.LastLogin == nil || u.FailedLogins != 0 {
+		t.Fatalf("login state not updated: %+v", u)
+	}
+}
+
+func TestPlaintextPasswordsMigratedOnLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.json")
+	legacy := `[{"username":"bob","password":"hunter2","role":"admin","created_at":"2024-01-01T00:00:00Z"}]`
+	if err := os.WriteFile(path, []byte(legacy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	us := NewUserStoreFile(path)
+	if _, err := us.Authenticate("bob", "hunter2"); err != nil {
+		t.Fatalf("migrated user cannot log in: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "hunter2") {
+		t.Fatalf("plaintext password still on disk: %s", data)
+	}
+	var users []User
+	if err := json.Unmarshal(data, &users); err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || strings.Contains(string(data), `"password"`) || !IsPasswordHash(users[0].PasswordHash) || users[0].Role != RoleAdmin {
+		t.Fatalf("unexpected migrated file: %s", data)
+	}
+}
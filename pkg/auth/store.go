@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Store persists users, tokens and tenants. UserStore and TokenStore keep an
+// in-memory view on top of a Store and write every change through to it, so
+// the CLI and the dashboard see the same accounts whichever backend holds
+// them.
+//
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Users returns every user.
+	Users() ([]*User, error)
+	// PutUser creates or replaces the user with u.Username.
+	PutUser(u *User) error
+	// DeleteUser removes a user; removing a missing user is not an error.
+	DeleteUser(username string) error
+
+	// Tokens returns every token.
+	Tokens() ([]*Token, error)
+	// PutToken creates or replaces the token with t.Prefix.
+	PutToken(t *Token) error
+	// DeleteToken removes a token; removing a missing token is not an error.
+	DeleteToken(prefix string) error
+
+	// Tenants returns every tenant.
+	Tenants() ([]*Tenant, error)
+	// PutTenant creates or replaces the tenant with t.Name.
+	PutTenant(t *Tenant) error
+}
+
+// OpenStore opens the backend described by spec:
+//
+//	"" or "file"     users.json and tokens.json (usersFile, tokensFile),
+//	                 with tenants.json next to the users file
+//	"sqlite:<path>"  a SQLite database holding both
+//	"memory"         in-memory only, for tests and throwaway runs
+func OpenStore(spec, usersFile, tokensFile string) (Store, error) {
+	kind, arg, _ := strings.Cut(spec, ":")
+	switch kind {
+	case "", "file":
+		return NewFileStore(usersFile, tokensFile), nil
+	case "memory":
+		return NewMemoryStore(), nil
+	case "sqlite":
+		if arg == "" {
+			return nil, fmt.Errorf("auth store %q: missing database path (sqlite:<path>)", spec)
+		}
+		return OpenSQLiteStore(arg)
+	}
+	return nil, fmt.Errorf("unknown auth store %q (want file, sqlite:<path> or memory)", spec)
+}
+
+// MemoryStore keeps users, tokens and tenants in memory only.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	users   map[string]User
+	tokens  map[string]Token
+	tenants map[string]Tenant
+}
+
+// NewMemoryStore returns an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{users: make(map[string]User), tokens: make(map[string]Token), tenants: make(map[string]Tenant)}
+}
+
+// Users returns copies of every user.
+func (m *MemoryStore) Users() ([]*User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]*User, 0, len(m.users))
+	for _, u := range m.users {
+		u := u
+		out = append(out, &u)
+	}
+	return out, nil
+}
+
+// PutUser stores a copy of u.
+func (m *MemoryStore) PutUser(u *User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users[u.Username] = *u
+	return nil
+}
+
+// DeleteUser removes a user.
+func (m *MemoryStore) DeleteUser(username string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.users, username)
+	return nil
+}
+
+// Tokens returns copies of every token.
+func (m *MemoryStore) Tokens() ([]*Token, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]*Token, 0, len(m.tokens))
+	for _, t := range m.tokens {
+		t := t
+		out = append(out, &t)
+	}
+	return out, nil
+}
+
+// PutToken stores a copy of t, without its full token value.
+func (m *MemoryStore) PutToken(t *Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := *t
+	c.Token = ""
+	m.tokens[t.Prefix] = c
+	return nil
+}
+
+// DeleteToken removes a token.
+func (m *MemoryStore) DeleteToken(prefix string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tokens, prefix)
+	return nil
+}
+
+// Tenants returns copies of every tenant.
+func (m *MemoryStore) Tenants() ([]*Tenant, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]*Tenant, 0, len(m.tenants))
+	for _, t := range m.tenants {
+		t := t
+		out = append(out, &t)
+	}
+	return out, nil
+}
+
+// PutTenant stores a copy of t.
+func (m *MemoryStore) PutTenant(t *Tenant) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tenants[t.Name] = *t
+	return nil
+}
@@ -0,0 +1,302 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// FileStore keeps users and tokens in two JSON files, the format missionctl
+// has always used, and tenants in a third. Every write rewrites the whole
+// file through a rename, so readers in other processes never see a partial
+// file.
+//
+// Files written by older versions are migrated when read: plaintext
+// passwords are hashed, cleartext tokens are hashed, and the per-user tokens
+// of the old `auth user add` format are moved into the tokens file.
+type FileStore struct {
+	UsersFile   string
+	TokensFile  string
+	TenantsFile string
+
+	mu sync.Mutex
+}
+
+// NewFileStore returns a store using the given files, defaulting to
+// users.json and tokens.json in the working directory. Tenants are kept in
+// tenants.json next to the users file.
+func NewFileStore(usersFile, tokensFile string) *FileStore {
+	if usersFile == "" {
+		usersFile = "users.json"
+	}
+	if tokensFile == "" {
+		tokensFile = "tokens.json"
+	}
+	return &FileStore{
+		UsersFile:   usersFile,
+		TokensFile:  tokensFile,
+		TenantsFile: filepath.Join(filepath.Dir(usersFile), "tenants.json"),
+	}
+}
+
+// fileUser is the on-disk form of a User, including fields only found in
+// files written by older versions.
+type fileUser struct {
+	User
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// fileToken is the on-disk form of a Token. Cleartext is only found in files
+// written before tokens were hashed.
+type fileToken struct {
+	Token
+	Cleartext string `json:"token,omitempty"`
+}
+
+// Users reads the users file, migrating legacy entries.
+func (s *FileStore) Users() ([]*User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var list []*fileUser
+	if err := readJSONFile(s.UsersFile, &list); err != nil {
+		return nil, err
+	}
+	var moved []*Token
+	migrated := 0
+	users := make([]*User, 0, len(list))
+	for _, fu := range list {
+		u := fu.User
+		if fu.Password != "" {
+			if IsPasswordHash(fu.Password) {
+				u.PasswordHash = fu.Password
+			} else {
+				hash, err := HashPassword(fu.Password)
+				if err != nil {
+					return nil, err
+				}
+				u.PasswordHash = hash
+			}
+			migrated++
+		}
+		if t := legacyToken(fu.Token, u.Username, "migrated", u.CreatedAt); t != nil {
+			moved = append(moved, t)
+			migrated++
+		}
+		users = append(users, &u)
+	}
+	if migrated == 0 {
+		return users, nil
+	}
+	if len(moved) > 0 {
+		tokens, err := s.tokensLocked()
+		if err != nil {
+			return nil, err
+		}
+		have := make(map[string]bool, len(tokens))
+		for _, t := range tokens {
+			have[t.Prefix] = true
+		}
+		for _, t := range moved {
+			if !have[t.Prefix] {
+				tokens = append(tokens, t)
+			}
+		}
+		if err := s.writeTokensLocked(tokens); err != nil {
+			return nil, err
+		}
+	}
+	log.Printf("FileStore: migrated %d legacy password(s)/token(s) in %s", migrated, s.UsersFile)
+	return users, s.writeUsersLocked(users)
+}
+
+// PutUser creates or replaces a user.
+func (s *FileStore) PutUser(u *User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var users []*User
+	if err := readJSONFile(s.UsersFile, &users); err != nil {
+		return err
+	}
+	users = replaceUser(users, u)
+	return s.writeUsersLocked(users)
+}
+
+// DeleteUser removes a user.
+func (s *FileStore) DeleteUser(username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var users []*User
+	if err := readJSONFile(s.UsersFile, &users); err != nil {
+		return err
+	}
+	kept := users[:0]
+	for _, u := range users {
+		if u.Username != username {
+			kept = append(kept, u)
+		}
+	}
+	return s.writeUsersLocked(kept)
+}
+
+// Tokens reads the tokens file, migrating cleartext tokens.
+func (s *FileStore) Tokens() ([]*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokensLocked()
+}
+
+func (s *FileStore) tokensLocked() ([]*Token, error) {
+	var list []*fileToken
+	if err := readJSONFile(s.TokensFile, &list); err != nil {
+		return nil, err
+	}
+	migrated := 0
+	tokens := make([]*Token, 0, len(list))
+	for _, ft := range list {
+		t := &ft.Token
+		if ft.Cleartext != "" {
+			if t = legacyToken(ft.Cleartext, ft.User, ft.Name, ft.CreatedAt); t == nil {
+				continue
+			}
+			t.ExpiresAt, t.Revoked = ft.ExpiresAt, ft.Revoked
+			migrated++
+		}
+		if t.Prefix != "" {
+			tokens = append(tokens, t)
+		}
+	}
+	if migrated > 0 {
+		log.Printf("FileStore: migrated %d cleartext token(s) in %s to hashed storage", migrated, s.TokensFile)
+		return tokens, s.writeTokensLocked(tokens)
+	}
+	return tokens, nil
+}
+
+// PutToken creates or replaces a token.
+func (s *FileStore) PutToken(t *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.tokensLocked()
+	if err != nil {
+		return err
+	}
+	for i, old := range tokens {
+		if old.Prefix == t.Prefix {
+			tokens[i] = t
+			return s.writeTokensLocked(tokens)
+		}
+	}
+	return s.writeTokensLocked(append(tokens, t))
+}
+
+// DeleteToken removes a token.
+func (s *FileStore) DeleteToken(prefix string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.tokensLocked()
+	if err != nil {
+		return err
+	}
+	kept := tokens[:0]
+	for _, t := range tokens {
+		if t.Prefix != prefix {
+			kept = append(kept, t)
+		}
+	}
+	return s.writeTokensLocked(kept)
+}
+
+// Tenants reads the tenants file.
+func (s *FileStore) Tenants() ([]*Tenant, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var tenants []*Tenant
+	return tenants, readJSONFile(s.TenantsFile, &tenants)
+}
+
+// PutTenant creates or replaces a tenant.
+func (s *FileStore) PutTenant(t *Tenant) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var tenants []*Tenant
+	if err := readJSONFile(s.TenantsFile, &tenants); err != nil {
+		return err
+	}
+	replaced := false
+	for i, old := range tenants {
+		if old.Name == t.Name {
+			tenants[i], replaced = t, true
+		}
+	}
+	if !replaced {
+		tenants = append(tenants, t)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].Name < tenants[j].Name })
+	return writeJSONFile(s.TenantsFile, tenants)
+}
+
+func (s *FileStore) writeUsersLocked(users []*User) error {
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	return writeJSONFile(s.UsersFile, users)
+}
+
+func (s *FileStore) writeTokensLocked(tokens []*Token) error {
+	sort.SliceStable(tokens, func(i, j int) bool { return tokens[i].CreatedAt.Before(tokens[j].CreatedAt) })
+	return writeJSONFile(s.TokensFile, tokens)
+}
+
+func replaceUser(users []*User, u *User) []*User {
+	for i, old := range users {
+		if old.Username == u.Username {
+			users[i] = u
+			return users
+		}
+	}
+	return append(users, u)
+}
+
+// readJSONFile decodes path into v; a missing or empty file leaves v alone.
+func readJSONFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+// writeJSONFile replaces path with the indented JSON of v via a temporary
+// file in the same directory.
+func writeJSONFile(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
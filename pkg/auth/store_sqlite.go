@@ -0,0 +1,224 @@
+package auth
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	// registers the "sqlite3" database/sql driver
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS users (
+	username      TEXT PRIMARY KEY,
+	password_hash TEXT NOT NULL DEFAULT '',
+	role          TEXT NOT NULL,
+	failed_logins INTEGER NOT NULL DEFAULT 0,
+	locked_until  TEXT,
+	last_login    TEXT,
+	created_at    TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS tenants (
+	name       TEXT PRIMARY KEY,
+	accounts   TEXT NOT NULL,
+	created_at TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS tokens (
+	prefix      TEXT PRIMARY KEY,
+	secret_hash TEXT NOT NULL,
+	legacy      INTEGER NOT NULL DEFAULT 0,
+	username    TEXT NOT NULL,
+	name        TEXT NOT NULL,
+	expires_at  TEXT,
+	revoked     INTEGER NOT NULL DEFAULT 0,
+	created_at  TEXT NOT NULL
+);`
+
+// SQLiteStore keeps users and tokens in a SQLite database, for installs
+// where several missionctl processes (CLI, dashboard) share one machine and
+// want row-level writes instead of rewriting JSON files.
+type SQLiteStore struct {
+	DB *sql.DB
+}
+
+// OpenSQLiteStore opens (creating if needed) the database at path.
+func OpenSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, fmt.Errorf("sqlite open error: %s", err)
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("sqlite schema error: %s", err)
+	}
+	// columns added after the first release
+	for _, table := range []string{"users", "tokens"} {
+		if err := addColumn(db, table, "tenant", "TEXT NOT NULL DEFAULT ''"); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("sqlite schema error: %s", err)
+		}
+	}
+	if err := addColumn(db, "users", "slack_id", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("sqlite schema error: %s", err)
+	}
+	return &SQLiteStore{DB: db}, nil
+}
+
+// addColumn adds column to table unless it is already there.
+func addColumn(db *sql.DB, table, column, decl string) error {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	_, err = db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, decl))
+	return err
+}
+
+// Close closes the database.
+func (s *SQLiteStore) Close() error { return s.DB.Close() }
+
+// Users returns every user.
+func (s *SQLiteStore) Users() ([]*User, error) {
+	rows, err := s.DB.Query(`SELECT username, password_hash, role, tenant, slack_id, failed_logins, locked_until, last_login, created_at FROM users ORDER BY username`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var users []*User
+	for rows.Next() {
+		var u User
+		var locked, last sql.NullString
+		var created string
+		if err := rows.Scan(&u.Username, &u.PasswordHash, &u.Role, &u.Tenant, &u.SlackID, &u.FailedLogins, &locked, &last, &created); err != nil {
+			return nil, err
+		}
+		u.LockedUntil, u.LastLogin = parseTimePtr(locked), parseTimePtr(last)
+		u.CreatedAt, _ = time.Parse(time.RFC3339Nano, created)
+		users = append(users, &u)
+	}
+	return users, rows.Err()
+}
+
+// PutUser creates or replaces a user.
+func (s *SQLiteStore) PutUser(u *User) error {
+	_, err := s.DB.Exec(`INSERT OR REPLACE INTO users (username, password_hash, role, tenant, slack_id, failed_logins, locked_until, last_login, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		u.Username, u.PasswordHash, string(u.Role), u.Tenant, u.SlackID, u.FailedLogins, formatTimePtr(u.LockedUntil), formatTimePtr(u.LastLogin), u.CreatedAt.Format(time.RFC3339Nano))
+	return err
+}
+
+// DeleteUser removes a user.
+func (s *SQLiteStore) DeleteUser(username string) error {
+	_, err := s.DB.Exec(`DELETE FROM users WHERE username = ?`, username)
+	return err
+}
+
+// Tokens returns every token.
+func (s *SQLiteStore) Tokens() ([]*Token, error) {
+	rows, err := s.DB.Query(`SELECT prefix, secret_hash, legacy, username, name, tenant, expires_at, revoked, created_at FROM tokens ORDER BY created_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var tokens []*Token
+	for rows.Next() {
+		var t Token
+		var expires sql.NullString
+		var created string
+		if err := rows.Scan(&t.Prefix, &t.SecretHash, &t.Legacy, &t.User, &t.Name, &t.Tenant, &expires, &t.Revoked, &created); err != nil {
+			return nil, err
+		}
+		t.ExpiresAt = parseTimePtr(expires)
+		t.CreatedAt, _ = time.Parse(time.RFC3339Nano, created)
+		tokens = append(tokens, &t)
+	}
+	return tokens, rows.Err()
+}
+
+// PutToken creates or replaces a token.
+func (s *SQLiteStore) PutToken(t *Token) error {
+	_, err := s.DB.Exec(`INSERT OR REPLACE INTO tokens (prefix, secret_hash, legacy, username, name, tenant, expires_at, revoked, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		t.Prefix, t.SecretHash, t.Legacy, t.User, t.Name, t.Tenant, formatTimePtr(t.ExpiresAt), t.Revoked, t.CreatedAt.Format(time.RFC3339Nano))
+	return err
+}
+
+// DeleteToken removes a token.
+func (s *SQLiteStore) DeleteToken(prefix string) error {
+	_, err := s.DB.Exec(`DELETE FROM tokens WHERE prefix = ?`, prefix)
+	return err
+}
+
+// Tenants returns every tenant. The account lists are stored as one JSON
+// object so new kinds of account need no schema change.
+func (s *SQLiteStore) Tenants() ([]*Tenant, error) {
+	rows, err := s.DB.Query(`SELECT name, accounts, created_at FROM tenants ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var tenants []*Tenant
+	for rows.Next() {
+		var t Tenant
+		var accounts, created string
+		if err := rows.Scan(&t.Name, &accounts, &created); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(accounts), &t); err != nil {
+			return nil, fmt.Errorf("tenant %s: %s", t.Name, err)
+		}
+		t.CreatedAt, _ = time.Parse(time.RFC3339Nano, created)
+		tenants = append(tenants, &t)
+	}
+	return tenants, rows.Err()
+}
+
+// PutTenant creates or replaces a tenant.
+func (s *SQLiteStore) PutTenant(t *Tenant) error {
+	accounts, err := json.Marshal(struct {
+		KubeContexts       []string `json:"kube_contexts,omitempty"`
+		AWSProfiles        []string `json:"aws_profiles,omitempty"`
+		GCPProjects        []string `json:"gcp_projects,omitempty"`
+		AzureSubscriptions []string `json:"azure_subscriptions,omitempty"`
+	}{t.KubeContexts, t.AWSProfiles, t.GCPProjects, t.AzureSubscriptions})
+	if err != nil {
+		return err
+	}
+	_, err = s.DB.Exec(`INSERT OR REPLACE INTO tenants (name, accounts, created_at) VALUES (?, ?, ?)`,
+		t.Name, string(accounts), t.CreatedAt.Format(time.RFC3339Nano))
+	return err
+}
+
+func formatTimePtr(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+func parseTimePtr(s sql.NullString) *time.Time {
+	if !s.Valid {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s.String)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,201 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStoreBackends(t *testing.T) {
+	backends := map[string]func(t *testing.T) Store{
+		"memory": func(t *testing.T) Store { return NewMemoryStore() },
+		"file": func(t *testing.T) Store {
+			dir := t.TempDir()
+			return NewFileStore(filepath.Join(dir, "users.json"), filepath.Join(dir, "tokens.json"))
+		},
+		"sqlite": func(t *testing.T) Store {
+			s, err := OpenStore("sqlite:"+filepath.Join(t.TempDir(), "auth.db"), "", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { s.(*SQLiteStore).Close() })
+			return s
+		},
+	}
+	for name, open := range backends {
+		t.Run(name, func(t *testing.T) {
+			s := open(t)
+			us := OpenUserStore(s)
+			if err := us.AddUser("alice", "Sup3r-secret", RoleOperator); err != nil {
+				t.Fatal(err)
+			}
+			if err := us.AddUser("bob", "", RoleViewer); err != nil {
+				t.Fatal(err)
+			}
+			if err := us.SetUserRole("bob", RoleAdmin); err != nil {
+				t.Fatal(err)
+			}
+			ts := OpenTokenStore(s)
+			tok, err := ts.GenerateToken("alice", "ci", time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// a second pair of stores on the same backend sees everything
+			us2, ts2 := OpenUserStore(s), OpenTokenStore(s)
+			if u, err := us2.Authenticate("alice", "Sup3r-secret"); err != nil || u.Role != RoleOperator {
+				t.Fatalf("Authenticate = %+v, %v", u, err)
+			}
+			if u, err := us2.GetUser("bob"); err != nil || u.Role != RoleAdmin {
+				t.Fatalf("GetUser(bob) = %+v, %v", u, err)
+			}
+			got, err := ts2.Validate(tok.Token)
+			if err != nil || got.User != "alice" || got.ExpiresAt == nil {
+				t.Fatalf("Validate = %+v, %v", got, err)
+			}
+
+			// users and tokens created after a store was opened are found on miss
+			if err := us2.AddUser("carol", "", RoleViewer); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := us.GetUser("carol"); err != nil {
+				t.Fatalf("user added elsewhere not found: %v", err)
+			}
+			later, err := ts2.GenerateToken("carol", "late", 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ts.Validate(later.Token); err != nil {
+				t.Fatalf("token issued elsewhere not accepted: %v", err)
+			}
+
+			// tenants, and the tenant of users and their tokens, persist
+			if err := OpenTenantStore(s).CreateTenant(&Tenant{Name: "acme", AWSProfiles: []string{"acme-*"}}); err != nil {
+				t.Fatal(err)
+			}
+			if err := us.SetUserTenant("carol", "acme"); err != nil {
+				t.Fatal(err)
+			}
+			acmeTok, err := ts.GenerateToken("carol", "acme", 0)
+			if err != nil || acmeTok.Tenant != "acme" {
+				t.Fatalf("token not issued in the user's tenant: %+v, %v", acmeTok, err)
+			}
+			if err := ts.SetTenant("alice", "acme"); err != nil {
+				t.Fatal(err)
+			}
+			tenant, err := OpenTenantStore(s).GetTenant("acme")
+			if err != nil || len(tenant.AWSProfiles) != 1 || tenant.CreatedAt.IsZero() {
+				t.Fatalf("GetTenant = %+v, %v", tenant, err)
+			}
+			if u, _ := OpenUserStore(s).GetUser("carol"); u.Tenant != "acme" {
+				t.Fatalf("user tenant not stored: %+v", u)
+			}
+			if got, _ := OpenTokenStore(s).Lookup(tok.Prefix); got.Tenant != "acme" {
+				t.Fatalf("SetTenant not stored: %+v", got)
+			}
+
+			// Slack users link to one user each
+			if err := us.SetUserSlackID("carol", "U0CAROL"); err != nil {
+				t.Fatal(err)
+			}
+			if err := us.SetUserSlackID("alice", "U0CAROL"); err == nil {
+				t.Fatal("a Slack user was linked twice")
+			}
+			if u, err := OpenUserStore(s).GetUserBySlackID("U0CAROL"); err != nil || u.Username != "carol" {
+				t.Fatalf("GetUserBySlackID = %+v, %v", u, err)
+			}
+			if _, err := us.GetUserBySlackID(""); err == nil {
+				t.Fatal("the empty Slack ID matched a user")
+			}
+
+			if err := ts.Revoke(tok.Prefix); err != nil {
+				t.Fatal(err)
+			}
+			if err := us.DeleteUser("bob"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := OpenTokenStore(s).Validate(tok.Token); err == nil {
+				t.Fatal("revoked token still valid")
+			}
+			if _, err := OpenUserStore(s).GetUser("bob"); err == nil {
+				t.Fatal("deleted user still present")
+			}
+		})
+	}
+}
+
+func TestOpenStoreSpecs(t *testing.T) {
+	if s, err := OpenStore("", "u.json", "t.json"); err != nil || s.(*FileStore).UsersFile != "u.json" {
+		t.Fatalf("default store = %#v, %v", s, err)
+	}
+	if _, err := OpenStore("memory", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, bad := range []string{"sqlite", "sqlite:", "postgres:db"} {
+		if _, err := OpenStore(bad, "", ""); err == nil {
+			t.Errorf("OpenStore(%q) should fail", bad)
+		}
+	}
+}
+
+func TestFileStoreMigratesAuthUserAddFormat(t *testing.T) {
+	dir := t.TempDir()
+	users, tokens := filepath.Join(dir, "users.json"), filepath.Join(dir, "tokens.json")
+	legacy := "fedcba9876543210fedcba9876543210"
+	data := `[{"username":"dave","role":"operator","token":"` + legacy + `","active":true,"created_at":"2024-01-01T00:00:00Z"}]`
+	if err := os.WriteFile(users, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s := NewFileStore(users, tokens)
+	if u, err := OpenUserStore(s).GetUser("dave"); err != nil || u.Role != RoleOperator {
+		t.Fatalf("GetUser = %+v, %v", u, err)
+	}
+	tok, err := OpenTokenStore(s).Validate(legacy)
+	if err != nil || tok.User != "dave" {
+		t.Fatalf("per-user token not migrated: %+v, %v", tok, err)
+	}
+	raw, _ := os.ReadFile(users)
+	if strings.Contains(string(raw), legacy) {
+		t.Fatalf("token left in users file: %s", raw)
+	}
+}
+
+func TestSQLiteStoreAddsTenantColumns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "auth.db")
+	old, err := OpenSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// recreate the tables as the first release wrote them
+	for _, stmt := range []string{
+		`DROP TABLE users`, `DROP TABLE tokens`,
+		`CREATE TABLE users (username TEXT PRIMARY KEY, password_hash TEXT NOT NULL DEFAULT '', role TEXT NOT NULL,
+			failed_logins INTEGER NOT NULL DEFAULT 0, locked_until TEXT, last_login TEXT, created_at TEXT NOT NULL)`,
+		`CREATE TABLE tokens (prefix TEXT PRIMARY KEY, secret_hash TEXT NOT NULL, legacy INTEGER NOT NULL DEFAULT 0,
+			username TEXT NOT NULL, name TEXT NOT NULL, expires_at TEXT, revoked INTEGER NOT NULL DEFAULT 0, created_at TEXT NOT NULL)`,
+		`INSERT INTO users (username, role, created_at) VALUES ('dave', 'viewer', '2024-01-01T00:00:00Z')`,
+	} {
+		if _, err := old.DB.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old.Close()
+
+	s, err := OpenSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	us := OpenUserStore(s)
+	if err := us.SetUserTenant("dave", "acme"); err != nil {
+		t.Fatal(err)
+	}
+	if err := us.SetUserSlackID("dave", "U0DAVE"); err != nil {
+		t.Fatal(err)
+	}
+	if u, err := OpenUserStore(s).GetUser("dave"); err != nil || u.Tenant != "acme" || u.SlackID != "U0DAVE" {
+		t.Fatalf("GetUser = %+v, %v", u, err)
+	}
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Tenant is an isolated group of users and tokens. Members may only reach
+// the cloud accounts listed here; users without a tenant are global and
+// unrestricted. Account entries are glob patterns (see authz.Glob).
+type Tenant struct {
+	Name               string    `json:"name"`
+	KubeContexts       []string  `json:"kube_contexts,omitempty"`
+	AWSProfiles        []string  `json:"aws_profiles,omitempty"`
+	GCPProjects        []string  `json:"gcp_projects,omitempty"`
+	AzureSubscriptions []string  `json:"azure_subscriptions,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// Accounts returns the accounts t may use with provider and what they are
+// called. scoped is false for providers tenants do not restrict.
+func (t *Tenant) Accounts(provider string) (kind string, allowed []string, scoped bool) {
+	switch provider {
+	case "k8s", "helm":
+		return "kube context", t.KubeContexts, true
+	case "aws":
+		return "AWS profile", t.AWSProfiles, true
+	case "gcp":
+		return "GCP project", t.GCPProjects, true
+	case "azure":
+		return "Azure subscription", t.AzureSubscriptions, true
+	}
+	return "", nil, false
+}
+
+var tenantNameRe = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// ValidTenantName reports whether name can be used for a tenant: lower
+// case letters, digits, - and _, starting with a letter or digit.
+func ValidTenantName(name string) bool {
+	return tenantNameRe.MatchString(name)
+}
+
+// TenantStore manages tenants, writing every change through to its Store.
+type TenantStore struct {
+	store   Store
+	mu      sync.RWMutex
+	tenants map[string]*Tenant
+}
+
+// OpenTenantStore creates a tenant store on top of s.
+func OpenTenantStore(s Store) *TenantStore {
+	ts := &TenantStore{store: s, tenants: make(map[string]*Tenant)}
+	if err := ts.load(); err != nil {
+		log.Printf("TenantStore: load: %v", err)
+	}
+	return ts
+}
+
+// load replaces the in-memory tenants with the store's.
+func (ts *TenantStore) load() error {
+	list, err := ts.store.Tenants()
+	if err != nil {
+		return err
+	}
+	tenants := make(map[string]*Tenant, len(list))
+	for _, t := range list {
+		tenants[t.Name] = t
+	}
+	ts.mu.Lock()
+	ts.tenants = tenants
+	ts.mu.Unlock()
+	return nil
+}
+
+// CreateTenant adds t. It fails if the name is invalid or taken.
+func (ts *TenantStore) CreateTenant(t *Tenant) error {
+	if !ValidTenantName(t.Name) {
+		return fmt.Errorf("invalid tenant name %q", t.Name)
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if _, ok := ts.tenants[t.Name]; ok {
+		return fmt.Errorf("tenant %s already exists", t.Name)
+	}
+	c := *t
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now()
+	}
+	if err := ts.store.PutTenant(&c); err != nil {
+		return err
+	}
+	ts.tenants[c.Name] = &c
+	return nil
+}
+
+// GetTenant returns a tenant, reloading once on a miss.
+func (ts *TenantStore) GetTenant(name string) (*Tenant, error) {
+	if t, ok := ts.lookup(name); ok {
+		return t, nil
+	}
+	if err := ts.load(); err == nil {
+		if t, ok := ts.lookup(name); ok {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("tenant %s: %w", name, os.ErrNotExist)
+}
+
+func (ts *TenantStore) lookup(name string) (*Tenant, bool) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	t, ok := ts.tenants[name]
+	return t, ok
+}
+
+// ListTenants returns all tenants sorted by name.
+func (ts *TenantStore) ListTenants() []*Tenant {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	out := make([]*Tenant, 0, len(ts.tenants))
+	for _, t := range ts.tenants {
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
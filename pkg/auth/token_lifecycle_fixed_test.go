@@ -50,7 +50,7 @@ func TestTokenLifecycleFixed(t *testing.T) {
         t.Fatal(err)
     }
     ts.mu.Lock()
-    if tt, ok := ts.tokens[t2.Token]; ok {
+    if tt, ok := ts.tokens[t2.Prefix]; ok {
         past := time.Now().Add(-1 * time.Hour)
         tt.ExpiresAt = &past
     }
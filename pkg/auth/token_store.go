@@ -1,525 +1,362 @@
 package auth
 
 import (
-    "crypto/rand"
-    "encoding/hex"
-    "encoding/json"
-    "errors"
-	type Role string
-	type User struct {
-	type UserStore struct {
-	package auth
-
-	import (
-		"crypto/rand"
-		"encoding/hex"
-		"encoding/json"
-		"errors"
-		"log"
-		"os"
-		"sync"
-		"time"
-	)
-
-	// Role defines simple RBAC roles
-	type Role string
-
-	const (
-		RoleViewer   Role = "viewer"
-		RoleOperator Role = "operator"
-		RoleAdmin    Role = "admin"
-	)
-
-	// User represents a local user for RBAC/auth
-	type User struct {
-		Username  string    `json:"username"`
-		Password  string    `json:"password,omitempty"`
-		Role      Role      `json:"role"`
-		CreatedAt time.Time `json:"created_at"`
-	}
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"log"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenPrefix starts the public part of every token issued by TokenStore.
+const TokenPrefix = "mc_"
+
+// Token represents an issued API token. Tokens have the form prefix.secret;
+// the prefix identifies the token and is safe to show and log, while only a
+// SHA-256 hash of the secret is kept.
+type Token struct {
+	// Token is the full prefix.secret value. It is only set on the Token
+	// returned by GenerateToken and Rotate and is never persisted.
+	Token      string `json:"-"`
+	Prefix     string `json:"prefix"`
+	SecretHash string `json:"secret_hash,omitempty"`
+	// Legacy marks tokens migrated from the old cleartext format; their
+	// prefix is the first 8 characters of the token.
+	Legacy bool   `json:"legacy,omitempty"`
+	User   string `json:"user"`
+	Name   string `json:"name"`
+	// Tenant is the tenant of User when the token was issued.
+	Tenant    string     `json:"tenant,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Revoked   bool       `json:"revoked"`
+	CreatedAt time.Time  `json:"created_at"`
+}
 
-	// UserStore manages users persisted to a JSON file.
-	type UserStore struct {
-		mu        sync.RWMutex
-		users     map[string]*User
-		usersFile string
-	}
+// legacyPrefixLen is how much of a migrated cleartext token becomes its prefix.
+const legacyPrefixLen = 8
 
-	// NewUserStore creates a user store backed by users.json in dir (or cwd if empty)
-	func NewUserStore(dir string) *UserStore {
-		us := &UserStore{users: make(map[string]*User)}
-		if dir == "" {
-			us.usersFile = "users.json"
-		} else {
-			us.usersFile = dir + "/users.json"
-		}
-		_ = us.load()
-		return us
-	}
+var errTokenNotFound = errors.New("token not found")
 
-	func (us *UserStore) load() error {
-		us.mu.Lock()
-		defer us.mu.Unlock()
-		f, err := os.Open(us.usersFile)
-		if err != nil {
-			if errors.Is(err, os.ErrNotExist) {
-				return nil
-			}
-			return err
-		}
-		defer f.Close()
-		var list []*User
-		dec := json.NewDecoder(f)
-		if err := dec.Decode(&list); err != nil {
-			return err
-		}
-		for _, u := range list {
-			us.users[u.Username] = u
-		}
+// legacyToken turns a cleartext token from an old file into a hashed Token,
+// or returns nil if there is nothing to migrate.
+func legacyToken(cleartext, user, name string, created time.Time) *Token {
+	if len(cleartext) < legacyPrefixLen {
 		return nil
 	}
-
-	func (us *UserStore) save() error {
-		us.mu.RLock()
-		list := make([]*User, 0, len(us.users))
-		for _, u := range us.users {
-			list = append(list, u)
-		}
-		us.mu.RUnlock()
-
-		f, err := os.Create(us.usersFile)
-		if err != nil {
-			return err
-		}
-		defer f.Close()
-		enc := json.NewEncoder(f)
-		enc.SetIndent("", "  ")
-		return enc.Encode(list)
-	}
-
-	// AddUser adds a user with password and role
-	func (us *UserStore) AddUser(username, password string, role Role) error {
-		us.mu.Lock()
-		defer us.mu.Unlock()
-		if _, ok := us.users[username]; ok {
-			return nil
-		}
-		u := &User{Username: username, Password: password, Role: role, CreatedAt: time.Now()}
-		us.users[username] = u
-		return us.save()
-	}
-
-	// GetUser returns a user or error
-	func (us *UserStore) GetUser(username string) (*User, error) {
-		us.mu.RLock()
-		defer us.mu.RUnlock()
-		if u, ok := us.users[username]; ok {
-			return u, nil
-		}
-		return nil, os.ErrNotExist
-	}
-
-	// ListUsers returns all users
-	func (us *UserStore) ListUsers() []*User {
-		us.mu.RLock()
-		defer us.mu.RUnlock()
-		out := make([]*User, 0, len(us.users))
-		for _, u := range us.users {
-			out = append(out, u)
-		}
-		return out
-	}
-
-	// DeleteUser removes a user
-	func (us *UserStore) DeleteUser(username string) error {
-		us.mu.Lock()
-		defer us.mu.Unlock()
-		delete(us.users, username)
-		return us.save()
-	}
-
-	// SetUserRole updates user's role.
-	func (us *UserStore) SetUserRole(username string, role Role) error {
-		us.mu.Lock()
-		defer us.mu.Unlock()
-		u, ok := us.users[username]
-		if !ok {
-			return errors.New("user not found")
-		}
-		u.Role = role
-		return us.save()
-	}
-
-	// Authenticate validates username/password and returns the user.
-	func (us *UserStore) Authenticate(username, password string) (*User, error) {
-		us.mu.RLock()
-		defer us.mu.RUnlock()
-		u, ok := us.users[username]
-		if !ok {
-			return nil, errors.New("invalid credentials")
-		}
-		if u.Password != password {
-			return nil, errors.New("invalid credentials")
-		}
-		return u, nil
+	return &Token{
+		Prefix:     cleartext[:legacyPrefixLen],
+		SecretHash: hashSecret(cleartext),
+		Legacy:     true,
+		User:       user,
+		Name:       name,
+		CreatedAt:  created,
 	}
+}
 
-	// Token represents an issued API token
-	type Token struct {
-		Token     string     `json:"token"`
-		User      string     `json:"user"`
-		Name      string     `json:"name"`
-		ExpiresAt *time.Time `json:"expires_at,omitempty"`
-		Revoked   bool       `json:"revoked"`
-		CreatedAt time.Time  `json:"created_at"`
-	}
+// TokenStore manages tokens, keyed by prefix, writing every change through
+// to its Store.
+type TokenStore struct {
+	store    Store
+	mu       sync.RWMutex
+	tokens   map[string]*Token
+	stopChan chan struct{}
+}
 
-	// TokenStore manages tokens persisted to tokens.json
-	type TokenStore struct {
-		mu         sync.RWMutex
-		tokens     map[string]*Token
-		tokensFile string
-		stopChan   chan struct{}
+// NewTokenStore creates a token store backed by tokensFile (tokens.json if
+// empty). dir is ignored and kept for compatibility.
+func NewTokenStore(dir, tokensFile string) *TokenStore {
+	if tokensFile == "" {
+		tokensFile = "tokens.json"
 	}
+	return OpenTokenStore(NewFileStore(filepath.Join(filepath.Dir(tokensFile), "users.json"), tokensFile))
+}
 
-	// NewTokenStore creates a token store backed by tokens.json in dir (or cwd if empty)
-	func NewTokenStore(dir, tokensFile string) *TokenStore {
-		ts := &TokenStore{tokens: make(map[string]*Token), stopChan: make(chan struct{})}
-		if tokensFile == "" {
-			ts.tokensFile = "tokens.json"
-		} else {
-			ts.tokensFile = tokensFile
-		}
-		_ = ts.load()
-		return ts
+// OpenTokenStore creates a token store on top of s.
+func OpenTokenStore(s Store) *TokenStore {
+	ts := &TokenStore{store: s, tokens: make(map[string]*Token), stopChan: make(chan struct{})}
+	if err := ts.load(); err != nil {
+		log.Printf("TokenStore: load: %v", err)
 	}
+	return ts
+}
 
-	func (ts *TokenStore) load() error {
-		ts.mu.Lock()
-		defer ts.mu.Unlock()
-		f, err := os.Open(ts.tokensFile)
-		if err != nil {
-			if errors.Is(err, os.ErrNotExist) {
-				return nil
-			}
-			return err
-		}
-		defer f.Close()
-		var list []*Token
-		dec := json.NewDecoder(f)
-		if err := dec.Decode(&list); err != nil {
-			return err
-		}
-		for _, t := range list {
-			ts.tokens[t.Token] = t
-		}
-		return nil
+// load replaces the in-memory tokens with the store's.
+func (ts *TokenStore) load() error {
+	list, err := ts.store.Tokens()
+	if err != nil {
+		return err
 	}
-
-	func (ts *TokenStore) persistList() error {
-		ts.mu.RLock()
-		copyList := make([]*Token, 0, len(ts.tokens))
-		for _, v := range ts.tokens {
-			copyList = append(copyList, v)
-		}
-		ts.mu.RUnlock()
-
-		f, err := os.Create(ts.tokensFile)
-		if err != nil {
-			return err
-		}
-		defer f.Close()
-		enc := json.NewEncoder(f)
-		enc.SetIndent("", "  ")
-		return enc.Encode(copyList)
+	tokens := make(map[string]*Token, len(list))
+	for _, t := range list {
+		tokens[t.Prefix] = t
 	}
+	ts.mu.Lock()
+	ts.tokens = tokens
+	ts.mu.Unlock()
+	return nil
+}
 
-	// GenerateToken issues a new token. ttl is a time.Duration (0 = no expiry).
-	func (ts *TokenStore) GenerateToken(username, name string, ttl time.Duration) (*Token, error) {
-		tokStr, err := genToken(16)
+// newTokenLocked issues and stores a token for user in tenant; the caller
+// must hold ts.mu.
+func (ts *TokenStore) newTokenLocked(username, name, tenant string, ttl time.Duration) (*Token, error) {
+	var prefix string
+	for {
+		id, err := genToken(6)
 		if err != nil {
 			return nil, err
 		}
-		var exp *time.Time
-		if ttl > 0 {
-			t := time.Now().Add(ttl)
-			exp = &t
+		prefix = TokenPrefix + id
+		if _, taken := ts.tokens[prefix]; !taken {
+			break
 		}
-		tr := &Token{Token: tokStr, User: username, Name: name, ExpiresAt: exp, Revoked: false, CreatedAt: time.Now()}
-		ts.mu.Lock()
-		ts.tokens[tr.Token] = tr
-		ts.mu.Unlock()
-		log.Printf("TokenStore: generated token for user=%s name=%s token=%s", username, name, tr.Token)
-		_ = ts.persistList()
-		return tr, nil
 	}
-
-	// ListTokens returns all tokens.
-	func (ts *TokenStore) ListTokens() []*Token {
-		ts.mu.RLock()
-		defer ts.mu.RUnlock()
-		out := make([]*Token, 0, len(ts.tokens))
-		for _, t := range ts.tokens {
-			out = append(out, t)
-		}
-		return out
+	secret, err := genToken(32)
+	if err != nil {
+		return nil, err
 	}
-
-	// Validate checks a token and returns it.
-	func (ts *TokenStore) Validate(token string) (*Token, error) {
-		ts.mu.RLock()
-		defer ts.mu.RUnlock()
-		t, ok := ts.tokens[token]
-		if !ok || t.Revoked {
-			return nil, errors.New("token not found")
-		}
-		if t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt) {
-			return nil, errors.New("token expired")
-		}
-		log.Printf("TokenStore: validate: token ok: %s", token)
-		return t, nil
+	var exp *time.Time
+	if ttl > 0 {
+		t := time.Now().Add(ttl)
+		exp = &t
 	}
-
-	// Rotate replaces an existing token with a new one.
-	func (ts *TokenStore) Rotate(oldToken string, ttl time.Duration) (*Token, error) {
-		ts.mu.Lock()
-		defer ts.mu.Unlock()
-		tr, ok := ts.tokens[oldToken]
-		if !ok {
-			return nil, errors.New("token not found")
-		}
-		newTok, err := genToken(16)
-		if err != nil {
-			return nil, err
-		}
-		var exp *time.Time
-		if ttl > 0 {
-			t := time.Now().Add(ttl)
-			exp = &t
-		}
-		newRec := &Token{Token: newTok, User: tr.User, Name: tr.Name, ExpiresAt: exp, Revoked: false, CreatedAt: time.Now()}
-		ts.tokens[newTok] = newRec
-		// revoke old token
-		tr.Revoked = true
-		delete(ts.tokens, oldToken)
-		log.Printf("TokenStore: rotated token %s -> %s", oldToken, newTok)
-		_ = ts.persistList()
-		return newRec, nil
+	stored := &Token{Prefix: prefix, SecretHash: hashSecret(secret), User: username, Name: name, Tenant: tenant, ExpiresAt: exp, CreatedAt: time.Now()}
+	if err := ts.store.PutToken(stored); err != nil {
+		return nil, err
 	}
+	ts.tokens[prefix] = stored
+	issued := *stored
+	issued.Token = prefix + "." + secret
+	return &issued, nil
+}
 
-	// Revoke marks a token revoked and persists.
-	func (ts *TokenStore) Revoke(token string) error {
-		ts.mu.Lock()
-		defer ts.mu.Unlock()
-		if t, ok := ts.tokens[token]; ok {
-			t.Revoked = true
-			delete(ts.tokens, token)
-			log.Printf("TokenStore: revoked token %s", token)
-			return ts.persistList()
-		}
-		return nil
+// GenerateToken issues a new token in the user's tenant. ttl is a
+// time.Duration (0 = no expiry). The returned Token is the only place the
+// full token appears.
+func (ts *TokenStore) GenerateToken(username, name string, ttl time.Duration) (*Token, error) {
+	tenant, err := ts.userTenant(username)
+	if err != nil {
+		return nil, err
 	}
-
-	// StartWatcher starts a background goroutine to periodically reload tokens from disk.
-	func (ts *TokenStore) StartWatcher() {
-		go func() {
-			ticker := time.NewTicker(5 * time.Second)
-			defer ticker.Stop()
-			for {
-				select {
-				case <-ticker.C:
-					_ = ts.load()
-				case <-ts.stopChan:
-					return
-				}
-			}
-		}()
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	tr, err := ts.newTokenLocked(username, name, tenant, ttl)
+	if err != nil {
+		return nil, err
 	}
+	log.Printf("TokenStore: generated token for user=%s name=%s prefix=%s", username, name, tr.Prefix)
+	return tr, nil
+}
 
-	// StopWatcher signals the background watcher to stop.
-	func (ts *TokenStore) StopWatcher() {
-		select {
-		case <-ts.stopChan:
-			return
-		default:
-			close(ts.stopChan)
-		}
+// userTenant returns the tenant of username, "" for global or unknown users.
+func (ts *TokenStore) userTenant(username string) (string, error) {
+	users, err := ts.store.Users()
+	if err != nil {
+		return "", err
 	}
-
-	// genToken produces a hex token string of n bytes
-	func genToken(n int) (string, error) {
-		if n <= 0 {
-			n = 16
-		}
-		b := make([]byte, n)
-		if _, err := rand.Read(b); err != nil {
-			return "", err
+	for _, u := range users {
+		if u.Username == username {
+			return u.Tenant, nil
 		}
-		return hex.EncodeToString(b), nil
 	}
-		return nil, err
-	}
-	var exp *time.Time
-	if ttl > 0 {
-		t := time.Now().Add(ttl)
-		exp = &t
+	return "", nil
+}
+
+// SetTenant moves every token of username into tenant, for when the user
+// is assigned to another tenant.
+func (ts *TokenStore) SetTenant(username, tenant string) error {
+	if err := ts.load(); err != nil {
+		return err
 	}
-	tr := &Token{Token: tokStr, User: username, Name: name, ExpiresAt: exp, Revoked: false}
 	ts.mu.Lock()
-	ts.tokens[tr.Token] = tr
-	ts.mu.Unlock()
-	log.Printf("TokenStore: generated token for user=%s name=%s token=%s", username, name, tr.Token)
-	_ = ts.persistList()
-	return tr, nil
+	defer ts.mu.Unlock()
+	for prefix, t := range ts.tokens {
+		if t.User != username || t.Tenant == tenant {
+			continue
+		}
+		c := *t
+		c.Tenant = tenant
+		if err := ts.store.PutToken(&c); err != nil {
+			return err
+		}
+		ts.tokens[prefix] = &c
+	}
+	return nil
 }
 
 // ListTokens returns all tokens.
 func (ts *TokenStore) ListTokens() []*Token {
 	ts.mu.RLock()
 	defer ts.mu.RUnlock()
-
-
-		ts.mu.RLock()
-		defer ts.mu.RUnlock()
-		out := make([]*Token, 0, len(ts.tokens))
-		for _, t := range ts.tokens {
-			out = append(out, t)
-		}
-		return out
+	out := make([]*Token, 0, len(ts.tokens))
+	for _, t := range ts.tokens {
+		out = append(out, t)
 	}
+	return out
+}
 
-	// Validate checks a token and returns it.
-	func (ts *TokenStore) Validate(token string) (*Token, error) {
-		ts.mu.RLock()
-		defer ts.mu.RUnlock()
-		t, ok := ts.tokens[token]
-		if !ok || t.Revoked {
-			return nil, errors.New("token not found")
-		}
-		if t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt) {
-			return nil, errors.New("token expired")
-		}
-		log.Printf("TokenStore: validate: token ok: %s", token)
-		return t, nil
+// Validate checks a full token and returns it. A token that is not known
+// yet triggers a reload from the store, so tokens issued by another process
+// (the CLI while the dashboard runs) are accepted right away.
+func (ts *TokenStore) Validate(token string) (*Token, error) {
+	prefix, secret, ok := splitToken(token)
+	if !ok {
+		return nil, errTokenNotFound
 	}
-
-	// Rotate replaces an existing token with a new one.
-	func (ts *TokenStore) Rotate(oldToken string, ttl time.Duration) (*Token, error) {
-		ts.mu.Lock()
-		defer ts.mu.Unlock()
-		tr, ok := ts.tokens[oldToken]
-		if !ok {
-			return nil, errors.New("token not found")
-		}
-		newTok, err := genToken(16)
-		if err != nil {
-			return nil, err
-		}
-		var exp *time.Time
-		if ttl > 0 {
-			t := time.Now().Add(ttl)
-			exp = &t
+	t, err := ts.check(prefix, secret)
+	if errors.Is(err, errTokenNotFound) {
+		if lerr := ts.load(); lerr == nil {
+			t, err = ts.check(prefix, secret)
 		}
-		newRec := &Token{Token: newTok, User: tr.User, Name: tr.Name, ExpiresAt: exp, Revoked: false}
-		ts.tokens[newTok] = newRec
-		// revoke old token
-		tr.Revoked = true
-		delete(ts.tokens, oldToken)
-		log.Printf("TokenStore: rotated token %s -> %s", oldToken, newTok)
-		_ = ts.persistList()
-		return newRec, nil
 	}
-
-	// Revoke marks a token revoked and persists.
-	func (ts *TokenStore) Revoke(token string) error {
-		ts.mu.Lock()
-		defer ts.mu.Unlock()
-		if t, ok := ts.tokens[token]; ok {
-			t.Revoked = true
-			delete(ts.tokens, token)
-			log.Printf("TokenStore: revoked token %s", token)
-			return ts.persistList()
-		}
-		return nil
+	if err != nil {
+		return nil, err
 	}
+	log.Printf("TokenStore: validate: token ok: prefix=%s", prefix)
+	return t, nil
+}
 
-	// StartWatcher starts a background goroutine to periodically reload tokens from disk.
-	func (ts *TokenStore) StartWatcher() {
-		go func() {
-			ticker := time.NewTicker(5 * time.Second)
-			defer ticker.Stop()
-			for {
-				select {
-				case <-ticker.C:
-					_ = ts.load()
-				case <-ts.stopChan:
-					return
-				}
-			}
-		}()
+func (ts *TokenStore) check(prefix, secret string) (*Token, error) {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	t, ok := ts.tokens[prefix]
+	if !ok || t.Revoked || !secretMatches(t, secret) {
+		return nil, errTokenNotFound
 	}
-
-	// StopWatcher signals the background watcher to stop.
-	func (ts *TokenStore) StopWatcher() {
-		close(ts.stopChan)
+	if t.ExpiresAt != nil && time.Now().After(*t.ExpiresAt) {
+		return nil, errors.New("token expired")
 	}
+	return t, nil
+}
 
+// Lookup finds a token by its prefix, for managing tokens whose secret is
+// not at hand. A full token is accepted too, and its secret is checked.
+func (ts *TokenStore) Lookup(ref string) (*Token, error) {
+	prefix, secret, full := splitToken(ref)
+	if !full {
+		prefix = ref
 	}
-	log.Printf("TokenStore: validate: token ok: %s", token)
-	return tr, nil
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+	t, ok := ts.tokens[prefix]
+	if !ok || (full && !secretMatches(t, secret)) {
+		return nil, errTokenNotFound
+	}
+	return t, nil
 }
 
-func (ts *TokenStore) Rotate(oldToken string, ttl time.Duration) (*TokenRecord, error) {
-	ts.mu.Lock()
-	tr, ok := ts.tokens[oldToken]
-	if !ok {
-		ts.mu.Unlock()
-		log.Printf("TokenStore: rotate: old token not found: %s", oldToken)
-		return nil, errors.New("token not found")
-	}
-	// generate new
-	newTok, err := genToken(16)
+// Rotate replaces an existing token, given by prefix or full token, with a
+// new one for the same user and name.
+func (ts *TokenStore) Rotate(ref string, ttl time.Duration) (*Token, error) {
+	old, err := ts.Lookup(ref)
 	if err != nil {
 		return nil, err
 	}
-	var exp *time.Time
-	if ttlSeconds > 0 {
-		t := time.Now().Add(time.Duration(ttlSeconds) * time.Second)
-		exp = &t
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.tokens[old.Prefix] != old {
+		return nil, errTokenNotFound
 	}
-	newRec := &TokenRecord{Token: newTok, Name: tr.Name, Username: tr.Username, User: tr.User, ExpiresAt: exp}
-	ts.tokens[newTok] = newRec
-	delete(ts.tokens, oldToken)
-	// make a snapshot and release lock before persisting
-	list := make([]*TokenRecord, 0, len(ts.tokens))
-	for _, v := range ts.tokens {
-		list = append(list, v)
+	newRec, err := ts.newTokenLocked(old.User, old.Name, old.Tenant, ttl)
+	if err != nil {
+		return nil, err
 	}
-	ts.mu.Unlock()
-	log.Printf("TokenStore: rotated token %s -> %s", oldToken, newTok)
-	if err := ts.persistList(list); err != nil {
-		log.Printf("TokenStore: persist error after rotate: %v", err)
+	// revoke old token
+	if err := ts.store.DeleteToken(old.Prefix); err != nil {
+		return nil, err
 	}
+	old.Revoked = true
+	delete(ts.tokens, old.Prefix)
+	log.Printf("TokenStore: rotated token %s -> %s", old.Prefix, newRec.Prefix)
 	return newRec, nil
 }
 
-func (ts *TokenStore) Revoke(token string) error {
-	ts.mu.Lock()
-	if _, ok := ts.tokens[token]; !ok {
-		ts.mu.Unlock()
+// Revoke marks a token, given by prefix or full token, revoked and persists.
+func (ts *TokenStore) Revoke(ref string) error {
+	t, err := ts.Lookup(ref)
+	if err != nil {
 		return nil
 	}
-	delete(ts.tokens, token)
-	// snapshot and unlock before persisting
-	list := make([]*TokenRecord, 0, len(ts.tokens))
-	for _, v := range ts.tokens {
-		list = append(list, v)
-	}
-	ts.mu.Unlock()
-	log.Printf("TokenStore: revoked token %s", token)
-	if err := ts.persistList(list); err != nil {
-		log.Printf("TokenStore: persist error after revoke: %v", err)
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if err := ts.store.DeleteToken(t.Prefix); err != nil {
 		return err
 	}
+	t.Revoked = true
+	delete(ts.tokens, t.Prefix)
+	log.Printf("TokenStore: revoked token %s", t.Prefix)
 	return nil
 }
+
+// splitToken splits a full token into prefix and secret. Tokens from before
+// the prefix.secret format are all secret, prefixed by their own start.
+func splitToken(token string) (prefix, secret string, ok bool) {
+	if p, s, found := strings.Cut(token, "."); found {
+		return p, s, p != "" && s != ""
+	}
+	if isLegacyToken(token) {
+		return token[:legacyPrefixLen], token, true
+	}
+	return "", "", false
+}
+
+// isLegacyToken reports whether s looks like a cleartext token issued
+// before the prefix.secret format (32 hex characters).
+func isLegacyToken(s string) bool {
+	if len(s) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func secretMatches(t *Token, secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(t.SecretHash)) == 1
+}
+
+// StartWatcher starts a background goroutine to periodically reload tokens from the store.
+func (ts *TokenStore) StartWatcher() {
+	go func() {
+		ticker := time.NewTicker(5 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				_ = ts.load()
+			case <-ts.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// StopWatcher signals the background watcher to stop.
+func (ts *TokenStore) StopWatcher() {
+	select {
+	case <-ts.stopChan:
+		return
+	default:
+		close(ts.stopChan)
+	}
+}
+
+// genToken produces a hex token string of n bytes
+func genToken(n int) (string, error) {
+	if n <= 0 {
+		n = 16
+	}
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
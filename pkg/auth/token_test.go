@@ -2,6 +2,8 @@ package auth
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -47,7 +49,7 @@ func TestTokenLifecycle(t *testing.T) {
 		t.Fatal(err)
 	}
 	ts.mu.Lock()
-	if tt, ok := ts.tokens[t2.Token]; ok {
+	if tt, ok := ts.tokens[t2.Prefix]; ok {
 		past := time.Now().Add(-1 * time.Hour)
 		tt.ExpiresAt = &past
 	}
@@ -56,3 +58,71 @@ func TestTokenLifecycle(t *testing.T) {
 		t.Fatalf("expired token should not validate")
 	}
 }
+
+func TestTokensStoredHashed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	ts := NewTokenStore("", path)
+	tok, err := ts.GenerateToken("u1", "ci", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix, secret, ok := strings.Cut(tok.Token, ".")
+	if !ok || prefix != tok.Prefix || !strings.HasPrefix(prefix, TokenPrefix) || len(secret) != 64 {
+		t.Fatalf("unexpected token format %q (prefix %q)", tok.Token, tok.Prefix)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), secret) || !strings.Contains(string(data), prefix) {
+		t.Fatalf("tokens file should hold the prefix and no secret: %s", data)
+	}
+
+	// a fresh store validates from the hash alone
+	other := NewTokenStore("", path)
+	if got, err := other.Validate(tok.Token); err != nil || got.User != "u1" || got.Token != "" {
+		t.Fatalf("Validate = %+v, %v", got, err)
+	}
+	if _, err := other.Validate(prefix + "." + strings.Repeat("0", 64)); err == nil {
+		t.Fatal("wrong secret validated")
+	}
+	if _, err := other.Validate(prefix); err == nil {
+		t.Fatal("prefix alone validated")
+	}
+	if got, err := other.Lookup(prefix); err != nil || got.Name != "ci" {
+		t.Fatalf("Lookup by prefix = %+v, %v", got, err)
+	}
+	if err := other.Revoke(prefix); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewTokenStore("", path).Validate(tok.Token); err == nil {
+		t.Fatal("token revoked by prefix still validates")
+	}
+}
+
+func TestLegacyTokensMigrated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	legacy := "0123456789abcdef0123456789abcdef"
+	data := `[{"token":"` + legacy + `","user":"u1","name":"old","revoked":false,"created_at":"2024-01-01T00:00:00Z"}]`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	ts := NewTokenStore("", path)
+	got, err := ts.Validate(legacy)
+	if err != nil {
+		t.Fatalf("legacy token rejected after migration: %v", err)
+	}
+	if got.Prefix != legacy[:8] || !got.Legacy {
+		t.Fatalf("unexpected migrated token %+v", got)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), legacy) {
+		t.Fatalf("cleartext token still on disk: %s", raw)
+	}
+	if _, err := NewTokenStore("", path).Validate(legacy); err != nil {
+		t.Fatalf("migrated file not readable: %v", err)
+	}
+}
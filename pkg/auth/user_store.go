@@ -0,0 +1,327 @@
+// Package auth holds missionctl's users, API tokens, roles and tenants.
+// UserStore, TokenStore and TenantStore are the API the CLI and the
+// dashboard use; all persist through a Store (JSON files, SQLite or memory).
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Role defines simple RBAC roles
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"
+	RoleOperator Role = "operator"
+	RoleAdmin    Role = "admin"
+)
+
+// roleLevel orders the roles; unknown roles rank below viewer.
+var roleLevel = map[Role]int{
+	RoleViewer:   10,
+	RoleOperator: 20,
+	RoleAdmin:    30,
+}
+
+// AtLeast reports whether r grants everything min does.
+func (r Role) AtLeast(min Role) bool {
+	return roleLevel[r] >= roleLevel[min]
+}
+
+// Valid reports whether r is one of the known roles.
+func (r Role) Valid() bool {
+	_, ok := roleLevel[r]
+	return ok
+}
+
+// User represents a local user for RBAC/auth
+type User struct {
+	Username     string     `json:"username"`
+	PasswordHash string     `json:"password_hash,omitempty"`
+	Role         Role       `json:"role"`
+	Tenant       string     `json:"tenant,omitempty"`
+	SlackID      string     `json:"slack_id,omitempty"`
+	FailedLogins int        `json:"failed_logins,omitempty"`
+	LockedUntil  *time.Time `json:"locked_until,omitempty"`
+	LastLogin    *time.Time `json:"last_login,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// Locked reports whether the user is locked out at time now.
+func (u *User) Locked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
+// UserStore manages users, writing every change through to its Store.
+type UserStore struct {
+	// Policy is what passwords chosen by people must satisfy. AddUser and
+	// SetPassword do not enforce it so that tooling can seed accounts;
+	// callers taking a password from a person check it first.
+	Policy PasswordPolicy
+	// Lockout controls locking accounts after failed logins.
+	Lockout LockoutPolicy
+
+	store Store
+	mu    sync.RWMutex
+	users map[string]*User
+}
+
+// NewUserStore creates a user store backed by users.json in dir (or cwd if empty)
+func NewUserStore(dir string) *UserStore {
+	if dir == "" {
+		return NewUserStoreFile("users.json")
+	}
+	return NewUserStoreFile(filepath.Join(dir, "users.json"))
+}
+
+// NewUserStoreFile creates a user store backed by the given JSON file. Tokens
+// migrated from old users files go to tokens.json next to it.
+func NewUserStoreFile(path string) *UserStore {
+	return OpenUserStore(NewFileStore(path, filepath.Join(filepath.Dir(path), "tokens.json")))
+}
+
+// OpenUserStore creates a user store on top of s.
+func OpenUserStore(s Store) *UserStore {
+	us := &UserStore{
+		Policy:  DefaultPasswordPolicy,
+		Lockout: DefaultLockoutPolicy,
+		store:   s,
+		users:   make(map[string]*User),
+	}
+	if err := us.load(); err != nil {
+		log.Printf("UserStore: load: %v", err)
+	}
+	return us
+}
+
+// load replaces the in-memory users with the store's.
+func (us *UserStore) load() error {
+	list, err := us.store.Users()
+	if err != nil {
+		return err
+	}
+	users := make(map[string]*User, len(list))
+	for _, u := range list {
+		users[u.Username] = u
+	}
+	us.mu.Lock()
+	us.users = users
+	us.mu.Unlock()
+	return nil
+}
+
+// AddUser adds a user with password and role. The password is stored as an
+// argon2id hash; an empty password creates a user that cannot log in.
+func (us *UserStore) AddUser(username, password string, role Role) error {
+	var hash string
+	if password != "" {
+		var err error
+		if hash, err = HashPassword(password); err != nil {
+			return err
+		}
+	}
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	if _, ok := us.users[username]; ok {
+		return nil
+	}
+	u := &User{Username: username, PasswordHash: hash, Role: role, CreatedAt: time.Now()}
+	if err := us.store.PutUser(u); err != nil {
+		return err
+	}
+	us.users[username] = u
+	return nil
+}
+
+// SetPassword replaces a user's password and clears any lockout.
+func (us *UserStore) SetPassword(username, password string) error {
+	hash, err := HashPassword(password)
+	if err != nil {
+		return err
+	}
+	return us.update(username, func(u *User) {
+		u.PasswordHash = hash
+		u.FailedLogins, u.LockedUntil = 0, nil
+	})
+}
+
+// Unlock clears a user's failed logins and lockout.
+func (us *UserStore) Unlock(username string) error {
+	return us.update(username, func(u *User) {
+		u.FailedLogins, u.LockedUntil = 0, nil
+	})
+}
+
+// SetUserRole updates user's role.
+func (us *UserStore) SetUserRole(username string, role Role) error {
+	return us.update(username, func(u *User) { u.Role = role })
+}
+
+// SetUserTenant moves a user into tenant; "" makes the user global.
+func (us *UserStore) SetUserTenant(username, tenant string) error {
+	return us.update(username, func(u *User) { u.Tenant = tenant })
+}
+
+// SetUserSlackID links a user to the Slack user with id, for slash
+// commands; "" unlinks them. A Slack user links to one user only.
+func (us *UserStore) SetUserSlackID(username, id string) error {
+	if id != "" {
+		if err := us.load(); err != nil {
+			return err
+		}
+		if u, err := us.GetUserBySlackID(id); err == nil && u.Username != username {
+			return fmt.Errorf("slack user %s is already linked to %s", id, u.Username)
+		}
+	}
+	return us.update(username, func(u *User) { u.SlackID = id })
+}
+
+// GetUserBySlackID returns the user linked to the Slack user with id. Like
+// GetUser it reloads once when no user matches.
+func (us *UserStore) GetUserBySlackID(id string) (*User, error) {
+	find := func() *User {
+		us.mu.RLock()
+		defer us.mu.RUnlock()
+		for _, u := range us.users {
+			if id != "" && u.SlackID == id {
+				return u
+			}
+		}
+		return nil
+	}
+	if u := find(); u != nil {
+		return u, nil
+	}
+	if err := us.load(); err == nil {
+		if u := find(); u != nil {
+			return u, nil
+		}
+	}
+	return nil, os.ErrNotExist
+}
+
+// update applies fn to a copy of the user and stores it.
+func (us *UserStore) update(username string, fn func(u *User)) error {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	return us.updateLocked(username, fn)
+}
+
+func (us *UserStore) updateLocked(username string, fn func(u *User)) error {
+	old, ok := us.users[username]
+	if !ok {
+		return errors.New("user not found")
+	}
+	u := *old
+	fn(&u)
+	if err := us.store.PutUser(&u); err != nil {
+		return err
+	}
+	us.users[username] = &u
+	return nil
+}
+
+// GetUser returns a user or error. A user that is not known yet triggers a
+// reload, so users created by another process are seen right away.
+func (us *UserStore) GetUser(username string) (*User, error) {
+	if u, ok := us.lookup(username); ok {
+		return u, nil
+	}
+	if err := us.load(); err == nil {
+		if u, ok := us.lookup(username); ok {
+			return u, nil
+		}
+	}
+	return nil, os.ErrNotExist
+}
+
+func (us *UserStore) lookup(username string) (*User, bool) {
+	us.mu.RLock()
+	defer us.mu.RUnlock()
+	u, ok := us.users[username]
+	return u, ok
+}
+
+// ListUsers returns all users
+func (us *UserStore) ListUsers() []*User {
+	us.mu.RLock()
+	defer us.mu.RUnlock()
+	out := make([]*User, 0, len(us.users))
+	for _, u := range us.users {
+		out = append(out, u)
+	}
+	return out
+}
+
+// DeleteUser removes a user
+func (us *UserStore) DeleteUser(username string) error {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	if err := us.store.DeleteUser(username); err != nil {
+		return err
+	}
+	delete(us.users, username)
+	return nil
+}
+
+// Authenticate validates username/password and returns the user. Failed
+// attempts are counted per user and lock the account once Lockout.MaxFailures
+// is reached; a successful login resets the count. It returns
+// ErrInvalidCredentials or an error wrapping ErrAccountLocked.
+func (us *UserStore) Authenticate(username, password string) (*User, error) {
+	// pick up lockouts and password changes made by other processes
+	if err := us.load(); err != nil {
+		return nil, err
+	}
+	us.mu.Lock()
+	defer us.mu.Unlock()
+	now := time.Now()
+	u, ok := us.users[username]
+	if !ok || u.PasswordHash == "" {
+		_, _ = VerifyPassword(dummyHash(), password)
+		return nil, ErrInvalidCredentials
+	}
+	if u.Locked(now) {
+		return nil, fmt.Errorf("%w until %s", ErrAccountLocked, u.LockedUntil.Format(time.RFC3339))
+	}
+	match, err := VerifyPassword(u.PasswordHash, password)
+	if err != nil {
+		return nil, fmt.Errorf("password check error: %s", err)
+	}
+	if !match {
+		var locked bool
+		err := us.updateLocked(username, func(u *User) {
+			u.FailedLogins++
+			if us.Lockout.MaxFailures > 0 && u.FailedLogins >= us.Lockout.MaxFailures {
+				until := now.Add(us.Lockout.Duration)
+				u.LockedUntil, u.FailedLogins, locked = &until, 0, true
+			}
+		})
+		if err != nil {
+			return nil, err
+		}
+		if locked {
+			return nil, fmt.Errorf("%w until %s", ErrAccountLocked, us.users[username].LockedUntil.Format(time.RFC3339))
+		}
+		return nil, ErrInvalidCredentials
+	}
+	err = us.updateLocked(username, func(u *User) {
+		if needsRehash(u.PasswordHash) {
+			if hash, err := HashPassword(password); err == nil {
+				u.PasswordHash = hash
+			}
+		}
+		u.FailedLogins, u.LockedUntil = 0, nil
+		u.LastLogin = &now
+	})
+	if err != nil {
+		return nil, err
+	}
+	return us.users[username], nil
+}
@@ -0,0 +1,160 @@
+package authz
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+)
+
+const stagingPolicy = `
+rules:
+  - name: prod-read-only
+    effect: deny
+    roles: [operator]
+    verbs: ["@write"]
+    environments: ["prod*"]
+  - name: staging-scale
+    effect: allow
+    roles: [viewer]
+    verbs: [scale]
+    resources: ["k8s/deployments"]
+    namespaces: [staging]
+`
+
+func TestEngineStagingVersusProd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(stagingPolicy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := NewEngine(p)
+	scale := func(role authpkg.Role, ns, env string) Request {
+		return Request{User: "u", Role: role, Verb: "scale", Resource: "k8s/deployments", Provider: "k8s",
+			Namespace: ns, Environment: env, MinRole: authpkg.RoleOperator}
+	}
+	cases := []struct {
+		name string
+		req  Request
+		want bool
+		rule string
+	}{
+		{"operator scales staging", scale(authpkg.RoleOperator, "staging", "staging"), true, ""},
+		{"operator cannot scale prod", scale(authpkg.RoleOperator, "web", "prod-eu"), false, `"prod-read-only"`},
+		{"operator still views prod", Request{Role: authpkg.RoleOperator, Verb: "list", Resource: "k8s/pods", Environment: "prod", MinRole: authpkg.RoleViewer}, true, ""},
+		{"viewer granted staging scale", scale(authpkg.RoleViewer, "staging", "staging"), true, `"staging-scale"`},
+		{"viewer cannot scale elsewhere", scale(authpkg.RoleViewer, "web", "staging"), false, ""},
+		{"admin unaffected by operator deny", scale(authpkg.RoleAdmin, "web", "prod"), true, ""},
+	}
+	for _, c := range cases {
+		d := e.Authorize(c.req)
+		if d.Allowed != c.want || d.Rule != c.rule {
+			t.Errorf("%s: got %+v, want allowed=%v rule=%s", c.name, d, c.want, c.rule)
+		}
+	}
+}
+
+func TestEngineDefaultDeny(t *testing.T) {
+	e := NewEngine(Policy{DefaultDeny: true, Rules: []Rule{{Effect: Allow, Users: []string{"ci"}, Verbs: []string{VerbRead}}}})
+	if d := e.Authorize(Request{User: "root", Role: authpkg.RoleAdmin, Verb: "list", Resource: "aws/ec2", MinRole: authpkg.RoleViewer}); d.Allowed {
+		t.Fatalf("admin allowed without a rule under default_deny: %+v", d)
+	}
+	if d := e.Authorize(Request{User: "ci", Role: authpkg.RoleViewer, Verb: "list", Resource: "aws/ec2", MinRole: authpkg.RoleViewer}); !d.Allowed {
+		t.Fatalf("ci denied a read: %+v", d)
+	}
+	if d := e.Authorize(Request{User: "ci", Role: authpkg.RoleViewer, Verb: "stop", Resource: "aws/ec2", MinRole: authpkg.RoleOperator}); d.Allowed {
+		t.Fatalf("ci allowed a write: %+v", d)
+	}
+}
+
+func TestEngineRoleFallbackReason(t *testing.T) {
+	d := NewEngine(Policy{}).Authorize(Request{User: "bob", Role: authpkg.RoleViewer, Verb: "scale", Resource: "k8s/deployments", Namespace: "web", MinRole: authpkg.RoleOperator})
+	if d.Allowed || !strings.Contains(d.Reason, "requires role operator") || !strings.Contains(d.Reason, "namespace=web") {
+		t.Fatalf("unexpected decision %+v", d)
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	bad := []Policy{
+		{Rules: []Rule{{Effect: "maybe"}}},
+		{Rules: []Rule{{Effect: Allow, Roles: []authpkg.Role{"root"}}}},
+		{Rules: []Rule{{Effect: Deny, Verbs: []string{"@everything"}}}},
+	}
+	for _, p := range bad {
+		if err := p.Validate(); err == nil {
+			t.Errorf("Validate(%+v) should fail", p)
+		}
+	}
+	path := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(path, []byte(`{"rules":[{"effect":"allow","verbs":["get"]}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if p, err := LoadPolicy(path); err != nil || len(p.Rules) != 1 {
+		t.Fatalf("LoadPolicy(json) = %+v, %v", p, err)
+	}
+	if p, err := LoadPolicyIfExists(filepath.Join(t.TempDir(), "missing.yaml")); err != nil || len(p.Rules) != 0 {
+		t.Fatalf("LoadPolicyIfExists(missing) = %+v, %v", p, err)
+	}
+}
+
+func TestGlob(t *testing.T) {
+	cases := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"*", "k8s/deployments", true},
+		{"k8s/*", "k8s/deployments", true},
+		{"k8s/*", "aws/ec2", false},
+		{"prod-*", "prod-eu", true},
+		{"prod-?u", "prod-eu", true},
+		{"prod", "prod-eu", false},
+		{"*-eu", "prod-eu", true},
+		{"", "", true},
+	}
+	for _, c := range cases {
+		if got := Glob(c.pattern, c.s); got != c.want {
+			t.Errorf("Glob(%q, %q) = %v", c.pattern, c.s, got)
+		}
+	}
+}
+
+func TestEngineTenantIsolation(t *testing.T) {
+	e := NewEngine(Policy{Rules: []Rule{{Effect: Allow, Users: []string{"eve"}}}})
+	tenants := map[string]*authpkg.Tenant{
+		"acme": {Name: "acme", KubeContexts: []string{"acme-*"}, AWSProfiles: []string{"acme"}},
+	}
+	e.UseTenants(func(name string) (*authpkg.Tenant, error) {
+		if t, ok := tenants[name]; ok {
+			return t, nil
+		}
+		return nil, os.ErrNotExist
+	})
+	req := func(user, tenant, provider, account string) Request {
+		return Request{User: user, Role: authpkg.RoleAdmin, Tenant: tenant, Verb: "list", Resource: provider + "/x",
+			Provider: provider, Account: account, MinRole: authpkg.RoleViewer}
+	}
+	cases := []struct {
+		name string
+		req  Request
+		want bool
+	}{
+		{"own kube context", req("ann", "acme", "k8s", "acme-prod"), true},
+		{"other kube context", req("ann", "acme", "k8s", "beta-prod"), false},
+		{"no account selected", req("ann", "acme", "aws", ""), false},
+		{"provider without accounts", req("ann", "acme", "gcp", "p-1"), false},
+		{"unscoped provider", req("ann", "acme", "docker", ""), true},
+		{"unknown tenant", req("ann", "gone", "docker", ""), false},
+		{"allow rule cannot escape tenant", req("eve", "acme", "k8s", "beta-prod"), false},
+		{"global user", req("root", "", "k8s", "beta-prod"), true},
+	}
+	for _, c := range cases {
+		if d := e.Authorize(c.req); d.Allowed != c.want {
+			t.Errorf("%s: got %+v", c.name, d)
+		}
+	}
+}
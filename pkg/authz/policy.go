@@ -0,0 +1,210 @@
+package authz
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+	"github.com/yourusername/devops-mission-control/pkg/yamlite"
+)
+
+// Effect is what a matching rule does.
+type Effect string
+
+const (
+	Allow Effect = "allow"
+	Deny  Effect = "deny"
+)
+
+// Verb groups usable in Rule.Verbs. They match on the minimum role the
+// action declares, so a rule can say "read only" without listing every
+// read verb of every provider.
+const (
+	VerbRead  = "@read"  // actions a viewer may run
+	VerbWrite = "@write" // actions that need operator or more
+	VerbAdmin = "@admin" // actions that need admin
+)
+
+// Rule matches requests and allows or denies them. Every list is optional;
+// an empty list matches anything. Resources and scope values are glob
+// patterns where * matches any run of characters (including /) and ?
+// matches one, so "k8s/*" covers every Kubernetes resource and "prod-*"
+// every prod environment.
+type Rule struct {
+	Name         string         `json:"name,omitempty"`
+	Effect       Effect         `json:"effect"`
+	Users        []string       `json:"users,omitempty"`
+	Roles        []authpkg.Role `json:"roles,omitempty"`
+	Tenants      []string       `json:"tenants,omitempty"`
+	Verbs        []string       `json:"verbs,omitempty"`
+	Resources    []string       `json:"resources,omitempty"`
+	Providers    []string       `json:"providers,omitempty"`
+	Namespaces   []string       `json:"namespaces,omitempty"`
+	Accounts     []string       `json:"accounts,omitempty"`
+	Environments []string       `json:"environments,omitempty"`
+}
+
+// Policy is an ordered set of rules.
+type Policy struct {
+	// DefaultDeny denies actions no rule allows instead of falling back to
+	// the built-in role hierarchy.
+	DefaultDeny bool   `json:"default_deny,omitempty"`
+	Rules       []Rule `json:"rules"`
+}
+
+// Matches reports whether the rule applies to r.
+func (rule *Rule) Matches(r Request) bool {
+	if len(rule.Roles) > 0 && !containsRole(rule.Roles, r.Role) {
+		return false
+	}
+	if len(rule.Verbs) > 0 && !matchVerb(rule.Verbs, r) {
+		return false
+	}
+	return matchAny(rule.Users, r.User) &&
+		matchAny(rule.Tenants, r.Tenant) &&
+		matchAny(rule.Resources, r.Resource) &&
+		matchAny(rule.Providers, r.Provider) &&
+		matchAny(rule.Namespaces, r.Namespace) &&
+		matchAny(rule.Accounts, r.Account) &&
+		matchAny(rule.Environments, r.Environment)
+}
+
+// label names the rule in decisions: its name, else its 1-based position.
+func (rule *Rule) label(i int) string {
+	if rule.Name != "" {
+		return fmt.Sprintf("%q", rule.Name)
+	}
+	return fmt.Sprintf("#%d", i+1)
+}
+
+// Validate checks effects, roles and patterns.
+func (p Policy) Validate() error {
+	for i, rule := range p.Rules {
+		if rule.Effect != Allow && rule.Effect != Deny {
+			return fmt.Errorf("rule %s: effect must be allow or deny, got %q", rule.label(i), rule.Effect)
+		}
+		for _, role := range rule.Roles {
+			if !role.Valid() {
+				return fmt.Errorf("rule %s: unknown role %q", rule.label(i), role)
+			}
+		}
+		for _, v := range rule.Verbs {
+			if strings.HasPrefix(v, "@") && v != VerbRead && v != VerbWrite && v != VerbAdmin {
+				return fmt.Errorf("rule %s: unknown verb group %q (use %s, %s or %s)", rule.label(i), v, VerbRead, VerbWrite, VerbAdmin)
+			}
+		}
+	}
+	return nil
+}
+
+// LoadPolicy reads a policy from a YAML or JSON file (chosen by extension).
+func LoadPolicy(path string) (Policy, error) {
+	var p Policy
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return p, err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &p)
+	} else {
+		err = yamlite.Unmarshal(data, &p)
+	}
+	if err != nil {
+		return p, fmt.Errorf("invalid policy %s: %w", path, err)
+	}
+	if err := p.Validate(); err != nil {
+		return p, fmt.Errorf("invalid policy %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// LoadPolicyIfExists is LoadPolicy, except a missing file yields the empty
+// policy.
+func LoadPolicyIfExists(path string) (Policy, error) {
+	p, err := LoadPolicy(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Policy{}, nil
+	}
+	return p, err
+}
+
+func containsRole(roles []authpkg.Role, r authpkg.Role) bool {
+	for _, role := range roles {
+		if role == r {
+			return true
+		}
+	}
+	return false
+}
+
+func matchVerb(verbs []string, r Request) bool {
+	for _, v := range verbs {
+		switch v {
+		case VerbRead:
+			if !r.MinRole.AtLeast(authpkg.RoleOperator) {
+				return true
+			}
+		case VerbWrite:
+			if r.MinRole.AtLeast(authpkg.RoleOperator) {
+				return true
+			}
+		case VerbAdmin:
+			if r.MinRole.AtLeast(authpkg.RoleAdmin) {
+				return true
+			}
+		default:
+			if Glob(v, r.Verb) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchAny(patterns []string, s string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if Glob(p, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Glob reports whether s matches pattern, where * matches any run of
+// characters and ? exactly one. Unlike path.Match, * also crosses /.
+func Glob(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			pattern = strings.TrimLeft(pattern, "*")
+			if pattern == "" {
+				return true
+			}
+			for i := 0; i <= len(s); i++ {
+				if Glob(pattern, s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if s == "" {
+				return false
+			}
+			s = s[1:]
+		default:
+			if s == "" || s[0] != pattern[0] {
+				return false
+			}
+			s = s[1:]
+		}
+		pattern = pattern[1:]
+	}
+	return s == ""
+}
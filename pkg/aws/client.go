@@ -1,24 +1,42 @@
 package aws
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"os/exec"
 	"strings"
+
+	"github.com/yourusername/devops-mission-control/pkg/executor"
 )
 
 // Client wraps AWS CLI operations
 type Client struct {
 	Region  string
 	Profile string
+	// Exec runs the aws CLI; defaults to the local OS executor.
+	Exec executor.Executor
+}
+
+// Option configures the AWS client.
+type Option func(*Client)
+
+// WithExecutor sets the executor used to run the aws CLI.
+func WithExecutor(e executor.Executor) Option {
+	return func(c *Client) { c.Exec = e }
 }
 
 // NewClient creates a new AWS client
-func NewClient(region, profile string) *Client {
-	return &Client{
+func NewClient(region, profile string, opts ...Option) *Client {
+	c := &Client{
 		Region:  region,
 		Profile: profile,
 	}
+	for _, o := range opts {
+		o(c)
+	}
+	if c.Exec == nil {
+		c.Exec = executor.New()
+	}
+	return c
 }
 
 // execAWS runs an AWS CLI command and returns output
@@ -32,15 +50,11 @@ func (c *Client) execAWS(service string, args ...string) (string, error) {
 	}
 	cmdArgs = append(cmdArgs, args...)
 
-	cmd := exec.Command("aws", cmdArgs...)
-	var out, errOut bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &errOut
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("aws error: %s", errOut.String())
+	res, err := c.Exec.Run(context.Background(), executor.Command{Name: "aws", Args: cmdArgs})
+	if err != nil {
+		return "", fmt.Errorf("aws error: %s", executor.ErrorText(res, err))
 	}
-	return strings.TrimSpace(out.String()), nil
+	return strings.TrimSpace(string(res.Stdout)), nil
 }
 
 // GetCallerIdentity returns current AWS account info
@@ -51,12 +65,16 @@ func (c *Client) GetCallerIdentity() (string, error) {
 // EC2 Operations
 
 // ListEC2Instances lists all EC2 instances
-func (c *Client) ListEC2Instances(running bool) (string, error) {
-	args := []string{"describe-instances", "--query", "Reservations[*].Instances[*].[InstanceId,InstanceType,State.Name,PrivateIpAddress,PublicIpAddress]", "--output", "table"}
+func (c *Client) ListEC2Instances(running bool) ([]Instance, error) {
+	args := []string{"describe-instances", "--query", instanceQuery, "--output", "json"}
 	if running {
-		args = []string{"describe-instances", "--filters", "Name=instance-state-name,Values=running", "--query", "Reservations[*].Instances[*].[InstanceId,InstanceType,State.Name,PrivateIpAddress,PublicIpAddress]", "--output", "table"}
+		args = []string{"describe-instances", "--filters", "Name=instance-state-name,Values=running", "--query", instanceQuery, "--output", "json"}
+	}
+	out, err := c.execAWS("ec2", args...)
+	if err != nil {
+		return nil, err
 	}
-	return c.execAWS("ec2", args...)
+	return decodeList[Instance](out, "instances")
 }
 
 // StopEC2Instance stops an EC2 instance
@@ -82,8 +100,12 @@ func (c *Client) DescribeEC2Instance(instanceID string) (string, error) {
 // S3 Operations
 
 // ListS3Buckets lists all S3 buckets
-func (c *Client) ListS3Buckets() (string, error) {
-	return c.execAWS("s3", "ls")
+func (c *Client) ListS3Buckets() ([]Bucket, error) {
+	out, err := c.execAWS("s3api", "list-buckets", "--query", bucketQuery, "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	return decodeList[Bucket](out, "buckets")
 }
 
 // ListS3BucketContents lists contents of an S3 bucket
@@ -117,8 +139,12 @@ func (c *Client) SyncS3(localPath, s3Path string, delete bool) (string, error) {
 // RDS Operations
 
 // ListRDSInstances lists all RDS instances
-func (c *Client) ListRDSInstances() (string, error) {
-	return c.execAWS("rds", "describe-db-instances", "--query", "DBInstances[*].[DBInstanceIdentifier,DBInstanceClass,Engine,DBInstanceStatus]", "--output", "table")
+func (c *Client) ListRDSInstances() ([]DBInstance, error) {
+	out, err := c.execAWS("rds", "describe-db-instances", "--query", dbQuery, "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	return decodeList[DBInstance](out, "db instances")
 }
 
 // DescribeRDSInstance gets details about an RDS instance
@@ -171,13 +197,21 @@ func (c *Client) FindUnusedResources() (map[string][]string, error) {
 // SecurityOperations
 
 // ListSecurityGroups lists all security groups
-func (c *Client) ListSecurityGroups() (string, error) {
-	return c.execAWS("ec2", "describe-security-groups", "--query", "SecurityGroups[*].[GroupId,GroupName,VpcId]", "--output", "table")
+func (c *Client) ListSecurityGroups() ([]SecurityGroup, error) {
+	out, err := c.execAWS("ec2", "describe-security-groups", "--query", sgQuery, "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	return decodeList[SecurityGroup](out, "security groups")
 }
 
 // ListVPCs lists all VPCs
-func (c *Client) ListVPCs() (string, error) {
-	return c.execAWS("ec2", "describe-vpcs", "--query", "Vpcs[*].[VpcId,CidrBlock,State]", "--output", "table")
+func (c *Client) ListVPCs() ([]VPC, error) {
+	out, err := c.execAWS("ec2", "describe-vpcs", "--query", vpcQuery, "--output", "json")
+	if err != nil {
+		return nil, err
+	}
+	return decodeList[VPC](out, "vpcs")
 }
 
 // GetAWSAccountInfo gets general account information
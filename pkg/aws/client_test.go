@@ -2,6 +2,8 @@ package aws
 
 import (
 	"testing"
+
+	"github.com/yourusername/devops-mission-control/pkg/executor"
 )
 
 func TestNewClient(t *testing.T) {
@@ -79,3 +81,39 @@ func TestListVPCs(t *testing.T) {
 		t.Logf("aws cli not available: %v", err)
 	}
 }
+
+func TestExecAWSPassesProfileAndRegion(t *testing.T) {
+	fake := executor.NewFake()
+	fake.On("aws", "s3api", "--profile", "prod", "--region", "eu-west-1", "list-buckets", "--query", bucketQuery, "--output", "json").
+		Return(`[{"name":"bucket-a","created_at":"2024-01-01T00:00:00+00:00"}]`)
+
+	client := NewClient("eu-west-1", "prod", WithExecutor(fake))
+	buckets, err := client.ListS3Buckets()
+	if err != nil {
+		t.Fatalf("ListS3Buckets: %v", err)
+	}
+	if len(buckets) != 1 || buckets[0].Name != "bucket-a" || buckets[0].CreatedAt.Year() != 2024 {
+		t.Fatalf("unexpected buckets %+v", buckets)
+	}
+}
+
+func TestListEC2InstancesDecodesQueryResult(t *testing.T) {
+	fake := executor.NewFake()
+	fake.On("aws", "ec2", "describe-instances", "--filters", "Name=instance-state-name,Values=running", "--query", instanceQuery, "--output", "json").
+		Return(`[{"id":"i-123","name":"web","type":"t3.micro","state":"running","private_ip":"10.0.0.5","public_ip":null,"availability_zone":"us-east-1a","launch_time":"2024-02-03T04:05:06+00:00"}]`)
+
+	client := NewClient("", "", WithExecutor(fake))
+	instances, err := client.ListEC2Instances(true)
+	if err != nil {
+		t.Fatalf("ListEC2Instances: %v", err)
+	}
+	if len(instances) != 1 || instances[0].ID != "i-123" || instances[0].Name != "web" || instances[0].PublicIP != "" {
+		t.Fatalf("unexpected instances %+v", instances)
+	}
+
+	fake.On("aws", "ec2", "describe-vpcs", "--query", vpcQuery, "--output", "json").Return("null")
+	vpcs, err := client.ListVPCs()
+	if err != nil || vpcs == nil || len(vpcs) != 0 {
+		t.Fatalf("expected empty vpc list, got %+v (%v)", vpcs, err)
+	}
+}
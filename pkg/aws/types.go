@@ -0,0 +1,77 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Instance is a summary of an EC2 instance.
+type Instance struct {
+	ID               string    `json:"id"`
+	Name             string    `json:"name,omitempty"`
+	Type             string    `json:"type"`
+	State            string    `json:"state"`
+	PrivateIP        string    `json:"private_ip,omitempty"`
+	PublicIP         string    `json:"public_ip,omitempty"`
+	AvailabilityZone string    `json:"availability_zone,omitempty"`
+	LaunchTime       time.Time `json:"launch_time"`
+}
+
+// Bucket is an S3 bucket.
+type Bucket struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DBInstance is a summary of an RDS instance.
+type DBInstance struct {
+	ID            string `json:"id"`
+	Class         string `json:"class"`
+	Engine        string `json:"engine"`
+	EngineVersion string `json:"engine_version,omitempty"`
+	Status        string `json:"status"`
+	Endpoint      string `json:"endpoint,omitempty"`
+}
+
+// SecurityGroup is an EC2 security group.
+type SecurityGroup struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	VpcID       string `json:"vpc_id,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// VPC is a virtual private cloud.
+type VPC struct {
+	ID        string `json:"id"`
+	CIDR      string `json:"cidr"`
+	State     string `json:"state"`
+	IsDefault bool   `json:"is_default"`
+}
+
+// JMESPath queries that reshape the AWS CLI responses into the structs above,
+// so `--output json` can be decoded directly.
+const (
+	instanceQuery = "Reservations[].Instances[].{id:InstanceId,name:Tags[?Key=='Name']|[0].Value,type:InstanceType,state:State.Name,private_ip:PrivateIpAddress,public_ip:PublicIpAddress,availability_zone:Placement.AvailabilityZone,launch_time:LaunchTime}"
+	bucketQuery   = "Buckets[].{name:Name,created_at:CreationDate}"
+	dbQuery       = "DBInstances[].{id:DBInstanceIdentifier,class:DBInstanceClass,engine:Engine,engine_version:EngineVersion,status:DBInstanceStatus,endpoint:Endpoint.Address}"
+	sgQuery       = "SecurityGroups[].{id:GroupId,name:GroupName,vpc_id:VpcId,description:Description}"
+	vpcQuery      = "Vpcs[].{id:VpcId,cidr:CidrBlock,state:State,is_default:IsDefault}"
+)
+
+// decodeList decodes a JSON array printed by the AWS CLI; "null" (an empty
+// query result) decodes to an empty list.
+func decodeList[T any](data, what string) ([]T, error) {
+	out := []T{}
+	if data == "" || data == "null" {
+		return out, nil
+	}
+	if err := json.Unmarshal([]byte(data), &out); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", what, err)
+	}
+	if out == nil {
+		out = []T{}
+	}
+	return out, nil
+}
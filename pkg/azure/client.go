@@ -1,62 +1,78 @@
 package azure
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"os/exec"
 	"strings"
+
+	"github.com/yourusername/devops-mission-control/pkg/executor"
 )
 
 // Client wraps Azure operations
 type Client struct {
 	Subscription  string
 	ResourceGroup string
+	// Exec runs az; defaults to the local OS executor.
+	Exec executor.Executor
+}
+
+// Option configures the Azure client.
+type Option func(*Client)
+
+// WithExecutor sets the executor used to run az.
+func WithExecutor(e executor.Executor) Option {
+	return func(c *Client) { c.Exec = e }
 }
 
 // NewClient creates a new Azure client
-func NewClient(subscription, resourceGroup string) *Client {
-	if subscription == "" {
-		subscription = getDefaultSubscription()
-	}
-	return &Client{
+func NewClient(subscription, resourceGroup string, opts ...Option) *Client {
+	c := &Client{
 		Subscription:  subscription,
 		ResourceGroup: resourceGroup,
 	}
+	for _, o := range opts {
+		o(c)
+	}
+	if c.Exec == nil {
+		c.Exec = executor.New()
+	}
+	if c.Subscription == "" {
+		c.Subscription = getDefaultSubscription(c.Exec)
+	}
+	return c
 }
 
 // execAZ runs an az command and returns output
 func (c *Client) execAZ(args ...string) (string, error) {
 	fullArgs := append([]string{"--subscription=" + c.Subscription}, args...)
 
-	cmd := exec.Command("az", fullArgs...)
-	var out, errOut bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &errOut
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("az error: %s", errOut.String())
+	res, err := c.Exec.Run(context.Background(), executor.Command{Name: "az", Args: fullArgs})
+	if err != nil {
+		return "", fmt.Errorf("az error: %s", executor.ErrorText(res, err))
 	}
-	return strings.TrimSpace(out.String()), nil
+	return strings.TrimSpace(string(res.Stdout)), nil
 }
 
 // getDefaultSubscription gets the default Azure subscription
-func getDefaultSubscription() string {
-	cmd := exec.Command("az", "account", "show", "--query=id", "-o=tsv")
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	if err := cmd.Run(); err != nil {
+func getDefaultSubscription(e executor.Executor) string {
+	res, err := e.Run(context.Background(), executor.Command{Name: "az", Args: []string{"account", "show", "--query=id", "-o=tsv"}})
+	if err != nil {
 		return ""
 	}
-	return strings.TrimSpace(out.String())
+	return strings.TrimSpace(string(res.Stdout))
 }
 
 // VM operations
-func (c *Client) ListVMs() (string, error) {
+func (c *Client) ListVMs() ([]VM, error) {
 	args := []string{"vm", "list"}
 	if c.ResourceGroup != "" {
 		args = append(args, "--resource-group="+c.ResourceGroup)
 	}
-	return c.execAZ(append(args, "-d")...)
+	out, err := c.execAZ(queryArgs(append(args, "-d"), vmQuery)...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeList[VM](out, "vms")
 }
 
 func (c *Client) StartVM(name string) (string, error) {
@@ -84,12 +100,16 @@ func (c *Client) DeleteVM(name string) (string, error) {
 }
 
 // Storage Account operations
-func (c *Client) ListStorageAccounts() (string, error) {
+func (c *Client) ListStorageAccounts() ([]StorageAccount, error) {
 	args := []string{"storage", "account", "list"}
 	if c.ResourceGroup != "" {
 		args = append(args, "--resource-group="+c.ResourceGroup)
 	}
-	return c.execAZ(args...)
+	out, err := c.execAZ(queryArgs(args, storageQuery)...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeList[StorageAccount](out, "storage accounts")
 }
 
 func (c *Client) ListStorageContainers(accountName string) (string, error) {
@@ -109,12 +129,16 @@ func (c *Client) GetStorageAccountKeys(name string) (string, error) {
 }
 
 // Database operations
-func (c *Client) ListDatabases() (string, error) {
+func (c *Client) ListDatabases() ([]Database, error) {
 	args := []string{"sql", "db", "list"}
 	if c.ResourceGroup != "" {
 		args = append(args, "--resource-group="+c.ResourceGroup)
 	}
-	return c.execAZ(args...)
+	out, err := c.execAZ(queryArgs(args, dbQuery)...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeList[Database](out, "databases")
 }
 
 func (c *Client) ListSQLServers() (string, error) {
@@ -126,12 +150,16 @@ func (c *Client) ListSQLServers() (string, error) {
 }
 
 // App Service operations
-func (c *Client) ListAppServices() (string, error) {
+func (c *Client) ListAppServices() ([]AppService, error) {
 	args := []string{"appservice", "list"}
 	if c.ResourceGroup != "" {
 		args = append(args, "--resource-group="+c.ResourceGroup)
 	}
-	return c.execAZ(args...)
+	out, err := c.execAZ(queryArgs(args, appQuery)...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeList[AppService](out, "app services")
 }
 
 func (c *Client) GetAppServiceConfig(name string) (string, error) {
@@ -143,8 +171,12 @@ func (c *Client) GetAppServiceConfig(name string) (string, error) {
 }
 
 // Resource Group operations
-func (c *Client) ListResourceGroups() (string, error) {
-	return c.execAZ("group", "list")
+func (c *Client) ListResourceGroups() ([]ResourceGroup, error) {
+	out, err := c.execAZ(queryArgs([]string{"group", "list"}, groupQuery)...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeList[ResourceGroup](out, "resource groups")
 }
 
 func (c *Client) GetResourceGroupInfo(name string) (string, error) {
@@ -152,12 +184,16 @@ func (c *Client) GetResourceGroupInfo(name string) (string, error) {
 }
 
 // Network operations
-func (c *Client) ListNetworks() (string, error) {
+func (c *Client) ListNetworks() ([]VNet, error) {
 	args := []string{"network", "vnet", "list"}
 	if c.ResourceGroup != "" {
 		args = append(args, "--resource-group="+c.ResourceGroup)
 	}
-	return c.execAZ(args...)
+	out, err := c.execAZ(queryArgs(args, vnetQuery)...)
+	if err != nil {
+		return nil, err
+	}
+	return decodeList[VNet](out, "networks")
 }
 
 func (c *Client) ListNetworkSecurityGroups() (string, error) {
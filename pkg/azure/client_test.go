@@ -2,6 +2,8 @@ package azure
 
 import (
 	"testing"
+
+	"github.com/yourusername/devops-mission-control/pkg/executor"
 )
 
 func TestNewClient(t *testing.T) {
@@ -61,3 +63,17 @@ func TestGetAccountInfo(t *testing.T) {
 		t.Logf("az cli not available: %v", err)
 	}
 }
+
+func TestListResourceGroupsUsesQuery(t *testing.T) {
+	fake := executor.NewFake()
+	fake.On("az", "--subscription=sub-1", "group", "list", "--query", groupQuery, "-o", "json").
+		Return(`[{"name":"rg-prod","location":"westeurope","state":"Succeeded"}]`)
+
+	groups, err := NewClient("sub-1", "", WithExecutor(fake)).ListResourceGroups()
+	if err != nil {
+		t.Fatalf("ListResourceGroups: %v", err)
+	}
+	if len(groups) != 1 || groups[0] != (ResourceGroup{Name: "rg-prod", Location: "westeurope", State: "Succeeded"}) {
+		t.Fatalf("unexpected groups %+v", groups)
+	}
+}
@@ -0,0 +1,89 @@
+package azure
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// VM is a summary of a virtual machine.
+type VM struct {
+	Name          string `json:"name"`
+	ResourceGroup string `json:"resource_group"`
+	Location      string `json:"location"`
+	Size          string `json:"size"`
+	PowerState    string `json:"power_state,omitempty"`
+	PrivateIPs    string `json:"private_ips,omitempty"`
+	PublicIPs     string `json:"public_ips,omitempty"`
+}
+
+// StorageAccount is a summary of a storage account.
+type StorageAccount struct {
+	Name          string `json:"name"`
+	ResourceGroup string `json:"resource_group"`
+	Location      string `json:"location"`
+	Kind          string `json:"kind"`
+	SKU           string `json:"sku"`
+}
+
+// Database is a summary of an Azure SQL database.
+type Database struct {
+	Name          string `json:"name"`
+	ResourceGroup string `json:"resource_group"`
+	Location      string `json:"location"`
+	Status        string `json:"status"`
+	SKU           string `json:"sku,omitempty"`
+}
+
+// AppService is a summary of an App Service app.
+type AppService struct {
+	Name          string `json:"name"`
+	ResourceGroup string `json:"resource_group"`
+	Location      string `json:"location"`
+	State         string `json:"state"`
+	Host          string `json:"host,omitempty"`
+}
+
+// ResourceGroup is a resource group.
+type ResourceGroup struct {
+	Name     string `json:"name"`
+	Location string `json:"location"`
+	State    string `json:"state"`
+}
+
+// VNet is a summary of a virtual network.
+type VNet struct {
+	Name            string   `json:"name"`
+	ResourceGroup   string   `json:"resource_group"`
+	Location        string   `json:"location"`
+	AddressPrefixes []string `json:"address_prefixes,omitempty"`
+}
+
+// JMESPath queries that reshape the az responses into the structs above.
+const (
+	vmQuery      = "[].{name:name,resource_group:resourceGroup,location:location,size:hardwareProfile.vmSize,power_state:powerState,private_ips:privateIps,public_ips:publicIps}"
+	storageQuery = "[].{name:name,resource_group:resourceGroup,location:location,kind:kind,sku:sku.name}"
+	dbQuery      = "[].{name:name,resource_group:resourceGroup,location:location,status:status,sku:currentServiceObjectiveName}"
+	appQuery     = "[].{name:name,resource_group:resourceGroup,location:location,state:state,host:defaultHostName}"
+	groupQuery   = "[].{name:name,location:location,state:properties.provisioningState}"
+	vnetQuery    = "[].{name:name,resource_group:resourceGroup,location:location,address_prefixes:addressSpace.addressPrefixes}"
+)
+
+// queryArgs asks az for JSON shaped by query.
+func queryArgs(args []string, query string) []string {
+	return append(args, "--query", query, "-o", "json")
+}
+
+// decodeList decodes a JSON array printed by az.
+func decodeList[T any](data, what string) ([]T, error) {
+	out := []T{}
+	if data == "" || data == "null" {
+		return out, nil
+	}
+	if err := json.Unmarshal([]byte(data), &out); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", what, err)
+	}
+	if out == nil {
+		out = []T{}
+	}
+	return out, nil
+}
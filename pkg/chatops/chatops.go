@@ -0,0 +1,321 @@
+// Package chatops runs missionctl commands for Slack slash commands. A
+// command runs as the missionctl user its Slack user is linked to, through
+// the missionctl binary itself, so the usual RBAC, policy and tenant checks
+// apply; only the allowed commands can be run this way.
+package chatops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/yourusername/devops-mission-control/pkg/audit"
+	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+	"github.com/yourusername/devops-mission-control/pkg/executor"
+	"github.com/yourusername/devops-mission-control/pkg/slack"
+)
+
+// DefaultCommands are the commands allowed when the bot is not given a
+// list: read-only ones.
+var DefaultCommands = []string{
+	"k8s pods list",
+	"k8s deployments list",
+	"k8s services list",
+	"k8s nodes list",
+	"k8s health",
+	"helm list",
+	"observe alerts list",
+	"observe events list",
+	"observe metrics list",
+}
+
+// DefaultTimeout bounds how long a command may run.
+const DefaultTimeout = 2 * time.Minute
+
+// MaxOutput is how much output is posted back; Slack truncates longer
+// sections.
+const MaxOutput = 2900
+
+// reservedFlag returns the flag in a that would let a Slack user run a
+// command as someone else or with another config: --actor, --token and
+// --config (-c, also inside combined short flags), and "--", after which
+// the --actor the bot adds would be an argument. Combined short flags are
+// read against flags, the flags of the command; without them any -c
+// inside is refused.
+func reservedFlag(a string, flags *pflag.FlagSet) string {
+	for _, f := range []string{"--actor", "--token", "--config"} {
+		if a == f || strings.HasPrefix(a, f+"=") {
+			return f
+		}
+	}
+	if a == "--" {
+		return a
+	}
+	if len(a) < 2 || a[0] != '-' || a[1] == '-' {
+		return ""
+	}
+	if flags == nil {
+		if strings.ContainsRune(a[1:], 'c') {
+			return "-c"
+		}
+		return ""
+	}
+	for i := 1; i < len(a); i++ {
+		f := flags.ShorthandLookup(a[i : i+1])
+		if f == nil {
+			// the command refuses an unknown flag
+			return ""
+		}
+		if f.Name == "config" {
+			return "-c"
+		}
+		if f.NoOptDefVal == "" {
+			// the rest of a is the flag's value
+			return ""
+		}
+	}
+	return ""
+}
+
+// Bot answers slash commands.
+type Bot struct {
+	Users *authpkg.UserStore
+	// Allowed are the command paths that may run; an entry allows its
+	// subcommands too ("k8s pods" allows "k8s pods list").
+	Allowed []string
+	// Resolve returns the path of the command args name, e.g. "k8s pods
+	// list" for [k8s pods list prod -n web], or an error for an unknown
+	// command.
+	Resolve func(args []string) (string, error)
+	// Flags, when set, returns the flags of the command args name,
+	// inherited ones included, so combined short flags can be read.
+	Flags func(args []string) *pflag.FlagSet
+	// Binary is the missionctl executable; BaseArgs come before the
+	// command's own arguments.
+	Binary   string
+	BaseArgs []string
+	Executor executor.Executor
+	Timeout  time.Duration
+	// Slack posts the results to the command's response URL.
+	Slack *slack.Client
+
+	wg sync.WaitGroup
+}
+
+// NewBot returns a bot allowing DefaultCommands, run through the binary
+// with resolve naming them.
+func NewBot(users *authpkg.UserStore, binary string, resolve func([]string) (string, error), client *slack.Client) *Bot {
+	return &Bot{
+		Users:    users,
+		Allowed:  DefaultCommands,
+		Resolve:  resolve,
+		Binary:   binary,
+		Executor: executor.New(),
+		Timeout:  DefaultTimeout,
+		Slack:    client,
+	}
+}
+
+// Handle checks sc and returns the immediate answer to it. An accepted
+// command runs in the background and posts its output to sc.ResponseURL;
+// a refused one is explained to its user only. Every command, run or
+// refused, is audited as chatops.command with the Slack user and channel.
+func (b *Bot) Handle(sc slack.SlashCommand) slack.Message {
+	text := strings.TrimSpace(sc.Text)
+	if text == "" || text == "help" {
+		return ephemeral(b.usage(sc.Command))
+	}
+	u, err := b.Users.GetUserBySlackID(sc.UserID)
+	if err != nil {
+		b.record(sc, nil, text, map[string]any{"allowed": false, "reason": "unlinked slack user"})
+		return ephemeral(fmt.Sprintf("⚠️ Your Slack account is not linked to a missionctl user. Ask an admin to run `missionctl user link-slack <user> %s`.", sc.UserID))
+	}
+	args, path, err := b.parse(text)
+	if err != nil {
+		b.record(sc, u, text, map[string]any{"allowed": false, "reason": err.Error()})
+		return ephemeral("⚠️ " + err.Error())
+	}
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.run(sc, u, text, path, args)
+	}()
+	return ephemeral(fmt.Sprintf("⏳ Running `%s` as %s...", text, u.Username))
+}
+
+// Wait blocks until the commands started by Handle have posted their
+// results.
+func (b *Bot) Wait() {
+	b.wg.Wait()
+}
+
+// parse splits text into arguments and checks the command they name is
+// allowed.
+func (b *Bot) parse(text string) ([]string, string, error) {
+	args, err := SplitArgs(text)
+	if err != nil {
+		return nil, "", err
+	}
+	var flags *pflag.FlagSet
+	if b.Flags != nil {
+		flags = b.Flags(args)
+	}
+	for _, a := range args {
+		if f := reservedFlag(a, flags); f != "" {
+			return nil, "", fmt.Errorf("`%s` cannot be used from Slack", f)
+		}
+	}
+	path, err := b.Resolve(args)
+	if err != nil {
+		return nil, "", err
+	}
+	if !b.allowed(path) {
+		return nil, "", fmt.Errorf("`%s` cannot be run from Slack", path)
+	}
+	return args, path, nil
+}
+
+func (b *Bot) allowed(path string) bool {
+	for _, a := range b.Allowed {
+		a = strings.Join(strings.Fields(a), " ")
+		if a != "" && (path == a || strings.HasPrefix(path, a+" ")) {
+			return true
+		}
+	}
+	return false
+}
+
+// run executes the command as u and posts the outcome.
+func (b *Bot) run(sc slack.SlashCommand, u *authpkg.User, text, path string, args []string) {
+	timeout := b.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	argv := append(append(append([]string{}, b.BaseArgs...), args...), "--actor", u.Username)
+	start := time.Now()
+	res, err := b.Executor.Run(ctx, executor.Command{Name: b.Binary, Args: argv})
+	took := time.Since(start)
+
+	details := map[string]any{"allowed": true, "command": path, "duration_ms": took.Milliseconds()}
+	status := "✅"
+	out := ""
+	if res != nil {
+		details["exit_code"] = res.ExitCode
+		out = strings.TrimSpace(string(res.Stdout) + "\n" + string(res.Stderr))
+	}
+	if err != nil {
+		status = "❌"
+		details["error"] = err.Error()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			out = strings.TrimSpace(out + "\ntimed out after " + timeout.String())
+		}
+	}
+	b.record(sc, u, text, details)
+
+	if out == "" {
+		out = "(no output)"
+	}
+	if len(out) > MaxOutput {
+		out = out[:MaxOutput] + "\n… output truncated"
+	}
+	invoked := strings.TrimSpace(sc.Command + " " + text)
+	msg := slack.Message{
+		ResponseType: "in_channel",
+		Text:         fmt.Sprintf("%s %s", status, invoked),
+		Blocks: []slack.Block{
+			slack.Section(fmt.Sprintf("%s <@%s> ran `%s`", status, sc.UserID, invoked)),
+			slack.Section("```" + out + "```"),
+			slack.Context(fmt.Sprintf("as %s · %s", u.Username, took.Round(time.Millisecond))),
+		},
+	}
+	if perr := b.post(sc, msg); perr != nil {
+		fmt.Fprintf(os.Stderr, "chatops: failed to post result of %q: %v\n", invoked, perr)
+	}
+}
+
+// post answers through the response URL, else in the channel with the
+// Web API.
+func (b *Bot) post(sc slack.SlashCommand, msg slack.Message) error {
+	if b.Slack == nil {
+		return fmt.Errorf("no slack client")
+	}
+	if sc.ResponseURL != "" {
+		return b.Slack.Respond(sc.ResponseURL, msg)
+	}
+	msg.Channel = sc.ChannelID
+	_, err := b.Slack.PostMessage(msg)
+	return err
+}
+
+// record audits an invocation by the Slack user of sc, as the missionctl
+// user u when it is linked.
+func (b *Bot) record(sc slack.SlashCommand, u *authpkg.User, text string, details map[string]any) {
+	details["slack_user"] = sc.UserID
+	details["slack_user_name"] = sc.UserName
+	details["channel"] = sc.ChannelID
+	details["channel_name"] = sc.ChannelName
+	tenant, actor := "", "slack:"+sc.UserID
+	if u != nil {
+		tenant, actor = u.Tenant, u.Username
+	}
+	if err := audit.Record(tenant, "chatops.command", actor, text, details); err != nil {
+		fmt.Fprintf(os.Stderr, "audit record failed: %v\n", err)
+	}
+}
+
+func (b *Bot) usage(command string) string {
+	if command == "" {
+		command = "/missionctl"
+	}
+	var lines []string
+	for _, a := range b.Allowed {
+		lines = append(lines, "• `"+command+" "+a+"`")
+	}
+	return "Commands you can run from Slack, with their usual arguments and flags:\n" + strings.Join(lines, "\n")
+}
+
+func ephemeral(text string) slack.Message {
+	return slack.Message{ResponseType: "ephemeral", Text: text}
+}
+
+// SplitArgs splits a command line on spaces, keeping quoted strings
+// together.
+func SplitArgs(s string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	var quote rune
+	inArg := false
+	for _, r := range s {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			cur.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in command")
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
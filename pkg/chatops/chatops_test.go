@@ -0,0 +1,202 @@
+package chatops
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/yourusername/devops-mission-control/pkg/audit"
+	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+	"github.com/yourusername/devops-mission-control/pkg/executor"
+	"github.com/yourusername/devops-mission-control/pkg/slack"
+)
+
+// resolve names the command of args as missionctl would for the few
+// commands the test knows.
+func resolve(args []string) (string, error) {
+	for _, path := range []string{"k8s pods list", "k8s pods delete", "observe alerts list"} {
+		n := len(strings.Fields(path))
+		if len(args) >= n && strings.Join(args[:n], " ") == path {
+			return path, nil
+		}
+	}
+	return "", os.ErrNotExist
+}
+
+// inTempDir runs the test in a temporary directory, where the audit log
+// and users file go.
+func inTempDir(t *testing.T) {
+	dir := t.TempDir()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(cwd) })
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBot(t *testing.T) {
+	inTempDir(t)
+	users := authpkg.NewUserStore("")
+	if err := users.AddUser("ann", "", authpkg.RoleViewer); err != nil {
+		t.Fatal(err)
+	}
+	if err := users.SetUserTenant("ann", "acme"); err != nil {
+		t.Fatal(err)
+	}
+	if err := users.SetUserSlackID("ann", "U0ANN"); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var posted []map[string]any
+	responses := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		_ = json.Unmarshal(raw, &body)
+		mu.Lock()
+		posted = append(posted, body)
+		mu.Unlock()
+	}))
+	defer responses.Close()
+
+	fake := executor.NewFake()
+	fake.On("/bin/missionctl", "--config", "/etc/mc.yaml", "k8s", "pods", "list", "prod", "--actor", "ann").Return("NAME   STATUS\nweb-1  Running\n")
+	fake.On("/bin/missionctl", "--config", "/etc/mc.yaml", "observe", "alerts", "list", "--actor", "ann").Fail("forbidden: role viewer", 1)
+	b := NewBot(users, "/bin/missionctl", resolve, slack.NewClient("", ""))
+	b.Allowed = []string{"k8s pods list", "observe alerts"}
+	b.BaseArgs = []string{"--config", "/etc/mc.yaml"}
+	b.Executor = fake
+	cmd := func(user, text string) slack.Message {
+		return b.Handle(slack.SlashCommand{Command: "/missionctl", Text: text, UserID: user, UserName: "ann.s", ChannelID: "C0OPS", ChannelName: "ops", ResponseURL: responses.URL})
+	}
+
+	if m := cmd("U0ANN", "k8s pods list prod"); m.ResponseType != "ephemeral" || !strings.Contains(m.Text, "Running") {
+		t.Fatalf("ack %+v", m)
+	}
+	b.Wait()
+	if len(posted) != 1 || posted[0]["response_type"] != "in_channel" || !strings.Contains(posted[0]["text"].(string), "✅") {
+		t.Fatalf("posted %+v", posted)
+	}
+	if blocks, _ := json.Marshal(posted[0]["blocks"]); !strings.Contains(string(blocks), "web-1  Running") {
+		t.Fatalf("output not posted: %s", blocks)
+	}
+
+	// failures are posted too; RBAC is the command's own
+	cmd("U0ANN", "observe alerts list")
+	b.Wait()
+	if len(posted) != 2 || !strings.Contains(posted[1]["text"].(string), "❌") {
+		t.Fatalf("failure %+v", posted)
+	}
+
+	// refusals answer the user alone and run nothing
+	for text, want := range map[string]string{
+		"k8s pods delete web-1":             "cannot be run",
+		"k8s pods list prod --actor root":   "--actor",
+		"k8s pods list --token=mc_x":        "--token",
+		"k8s pods list -vc /tmp/other.yaml": "-c",
+		"k8s pods list -- prod":             "--",
+		"kubectl get pods":                  "file does not exist",
+		`k8s pods list "prod`:               "unterminated",
+	} {
+		if m := cmd("U0ANN", text); m.ResponseType != "ephemeral" || !strings.Contains(m.Text, want) {
+			t.Errorf("%q: %+v", text, m)
+		}
+	}
+	if m := cmd("U0BOB", "k8s pods list"); !strings.Contains(m.Text, "not linked") || !strings.Contains(m.Text, "U0BOB") {
+		t.Errorf("unlinked user: %+v", m)
+	}
+	if m := cmd("U0ANN", "help"); !strings.Contains(m.Text, "`/missionctl observe alerts`") {
+		t.Errorf("help: %+v", m)
+	}
+	b.Wait()
+	if calls := fake.Calls(); len(calls) != 2 || len(posted) != 2 {
+		t.Fatalf("ran %d commands, posted %d", len(calls), len(posted))
+	}
+
+	entries, _, err := audit.Page("", "", 100, func(e audit.Entry) bool { return e.Action == "chatops.command" })
+	if err != nil || len(entries) != 10 {
+		t.Fatalf("audited %d invocations: %v", len(entries), err)
+	}
+	if e := entries[0]; e.Actor != "ann" || e.TenantID != "acme" || e.Target != "k8s pods list prod" || e.Details["slack_user"] != "U0ANN" ||
+		e.Details["channel"] != "C0OPS" || e.Details["allowed"] != true || e.Details["exit_code"] != float64(0) {
+		t.Fatalf("run entry %+v", e)
+	}
+	if e := entries[len(entries)-1]; e.Actor != "slack:U0BOB" || e.Details["allowed"] != false {
+		t.Fatalf("unlinked entry %+v", e)
+	}
+}
+
+// hang is an executor whose commands run until they are cancelled.
+type hang struct{}
+
+func (hang) Run(ctx context.Context, cmd executor.Command) (*executor.Result, error) {
+	<-ctx.Done()
+	return &executor.Result{ExitCode: -1}, ctx.Err()
+}
+
+func TestBotTimeout(t *testing.T) {
+	inTempDir(t)
+	users := authpkg.NewUserStore("")
+	_ = users.AddUser("ann", "", authpkg.RoleViewer)
+	_ = users.SetUserSlackID("ann", "U0ANN")
+	got := make(chan map[string]any, 1)
+	responses := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		got <- body
+	}))
+	defer responses.Close()
+	b := NewBot(users, "missionctl", func([]string) (string, error) { return "k8s pods list", nil }, slack.NewClient("", ""))
+	b.Executor = hang{}
+	b.Timeout = 50 * time.Millisecond
+	b.Handle(slack.SlashCommand{Text: "k8s pods list", UserID: "U0ANN", ResponseURL: responses.URL})
+	b.Wait()
+	if blocks, _ := json.Marshal((<-got)["blocks"]); !strings.Contains(string(blocks), "timed out after 50ms") {
+		t.Fatalf("timeout not reported: %s", blocks)
+	}
+}
+
+func TestSplitArgs(t *testing.T) {
+	args, err := SplitArgs(`k8s pods list  -n "my ns" --selector='app=web tier'`)
+	if err != nil || strings.Join(args, "|") != "k8s|pods|list|-n|my ns|--selector=app=web tier" {
+		t.Fatalf("%q: %v", args, err)
+	}
+}
+
+func TestReservedFlag(t *testing.T) {
+	flags := pflag.NewFlagSet("list", pflag.ContinueOnError)
+	flags.BoolP("verbose", "v", false, "")
+	flags.BoolP("all", "a", false, "")
+	flags.StringP("config", "c", "", "")
+	flags.StringP("namespace", "n", "", "")
+	for a, want := range map[string]string{
+		"-vc":        "-c",
+		"-avc":       "-c",
+		"-c":         "-c",
+		"-c=x.yaml":  "-c",
+		"-ncache":    "",
+		"-nc":        "",
+		"-va":        "",
+		"-5":         "",
+		"--config=x": "--config",
+		"cache":      "",
+	} {
+		if got := reservedFlag(a, flags); got != want {
+			t.Errorf("reservedFlag(%q) = %q, want %q", a, got, want)
+		}
+	}
+	if got := reservedFlag("-nc", nil); got != "-c" {
+		t.Errorf("without flags, -nc = %q", got)
+	}
+}
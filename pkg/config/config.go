@@ -0,0 +1,296 @@
+// Package config loads the missionctl config file: a set of defaults plus
+// named profiles (for example prod-eu or staging) that pre-fill namespaces,
+// cloud accounts, the dashboard address and the auth/audit file locations.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/devops-mission-control/pkg/yamlite"
+)
+
+// Environment variables read by missionctl. Every profile key can also be
+// overridden with MISSIONCTL_<KEY>, e.g. MISSIONCTL_AWS_REGION.
+const (
+	EnvConfig  = "MISSIONCTL_CONFIG"
+	EnvProfile = "MISSIONCTL_PROFILE"
+	envPrefix  = "MISSIONCTL_"
+)
+
+// Profile holds one set of settings. Empty fields are unset.
+type Profile struct {
+	Namespace                  string `json:"namespace,omitempty"`
+	KubeContext                string `json:"kube_context,omitempty"`
+	AWSRegion                  string `json:"aws_region,omitempty"`
+	AWSProfile                 string `json:"aws_profile,omitempty"`
+	GCPProject                 string `json:"gcp_project,omitempty"`
+	GCPRegion                  string `json:"gcp_region,omitempty"`
+	AzureSubscription          string `json:"azure_subscription,omitempty"`
+	AzureResourceGroup         string `json:"azure_resource_group,omitempty"`
+	TerraformWorkdir           string `json:"terraform_workdir,omitempty"`
+	DashboardAddr              string `json:"dashboard_addr,omitempty"`
+	Environment                string `json:"environment,omitempty"`
+	AuthStore                  string `json:"auth_store,omitempty"`
+	UsersFile                  string `json:"users_file,omitempty"`
+	TokensFile                 string `json:"tokens_file,omitempty"`
+	AuditFile                  string `json:"audit_file,omitempty"`
+	AuditSigningKey            string `json:"audit_signing_key,omitempty"`
+	AuditMaxSize               string `json:"audit_max_size,omitempty"`
+	AuditMaxAge                string `json:"audit_max_age,omitempty"`
+	AuditRetention             string `json:"audit_retention,omitempty"`
+	AuditSinks                 string `json:"audit_sinks,omitempty"`
+	AuditBuffer                string `json:"audit_buffer,omitempty"`
+	MetricsStore               string `json:"metrics_store,omitempty"`
+	MetricsRemoteWrite         string `json:"metrics_remote_write,omitempty"`
+	MetricsRemoteWriteInterval string `json:"metrics_remote_write_interval,omitempty"`
+	PluginsDir                 string `json:"plugins_dir,omitempty"`
+	PolicyFile                 string `json:"policy_file,omitempty"`
+	AlertRulesFile             string `json:"alert_rules_file,omitempty"`
+	NotifyRoutesFile           string `json:"notify_routes_file,omitempty"`
+	SilencesFile               string `json:"silences_file,omitempty"`
+	SlackBotToken              string `json:"slack_bot_token,omitempty"`
+	SlackSigningSecret         string `json:"slack_signing_secret,omitempty"`
+	SlackApprovalChannel       string `json:"slack_approval_channel,omitempty"`
+	SlackApprovers             string `json:"slack_approvers,omitempty"`
+	SlackApprovalTimeout       string `json:"slack_approval_timeout,omitempty"`
+	ApprovalsDir               string `json:"approvals_dir,omitempty"`
+	SlackCommands              string `json:"slack_commands,omitempty"`
+	SlackTemplatesDir          string `json:"slack_templates_dir,omitempty"`
+	DashboardURL               string `json:"dashboard_url,omitempty"`
+}
+
+// fields maps each config key to the field that stores it.
+func (p *Profile) fields() map[string]*string {
+	return map[string]*string{
+		"namespace":                     &p.Namespace,
+		"kube_context":                  &p.KubeContext,
+		"aws_region":                    &p.AWSRegion,
+		"aws_profile":                   &p.AWSProfile,
+		"gcp_project":                   &p.GCPProject,
+		"gcp_region":                    &p.GCPRegion,
+		"azure_subscription":            &p.AzureSubscription,
+		"azure_resource_group":          &p.AzureResourceGroup,
+		"terraform_workdir":             &p.TerraformWorkdir,
+		"dashboard_addr":                &p.DashboardAddr,
+		"environment":                   &p.Environment,
+		"auth_store":                    &p.AuthStore,
+		"users_file":                    &p.UsersFile,
+		"tokens_file":                   &p.TokensFile,
+		"audit_file":                    &p.AuditFile,
+		"audit_signing_key":             &p.AuditSigningKey,
+		"audit_max_size":                &p.AuditMaxSize,
+		"audit_max_age":                 &p.AuditMaxAge,
+		"audit_retention":               &p.AuditRetention,
+		"audit_sinks":                   &p.AuditSinks,
+		"audit_buffer":                  &p.AuditBuffer,
+		"metrics_store":                 &p.MetricsStore,
+		"metrics_remote_write":          &p.MetricsRemoteWrite,
+		"metrics_remote_write_interval": &p.MetricsRemoteWriteInterval,
+		"plugins_dir":                   &p.PluginsDir,
+		"policy_file":                   &p.PolicyFile,
+		"alert_rules_file":              &p.AlertRulesFile,
+		"notify_routes_file":            &p.NotifyRoutesFile,
+		"silences_file":                 &p.SilencesFile,
+		"slack_bot_token":               &p.SlackBotToken,
+		"slack_signing_secret":          &p.SlackSigningSecret,
+		"slack_approval_channel":        &p.SlackApprovalChannel,
+		"slack_approvers":               &p.SlackApprovers,
+		"slack_approval_timeout":        &p.SlackApprovalTimeout,
+		"approvals_dir":                 &p.ApprovalsDir,
+		"slack_commands":                &p.SlackCommands,
+		"slack_templates_dir":           &p.SlackTemplatesDir,
+		"dashboard_url":                 &p.DashboardURL,
+	}
+}
+
+// Keys returns every supported config key in sorted order.
+func Keys() []string {
+	var p Profile
+	keys := make([]string, 0, len(p.fields()))
+	for k := range p.fields() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Get returns the value of key, or "" when it is unset or unknown.
+func (p Profile) Get(key string) string {
+	if f, ok := p.fields()[key]; ok {
+		return *f
+	}
+	return ""
+}
+
+// Set assigns value to key. An empty value unsets it.
+func (p *Profile) Set(key, value string) error {
+	f, ok := p.fields()[key]
+	if !ok {
+		return fmt.Errorf("unknown config key %q (valid keys: %s)", key, strings.Join(Keys(), ", "))
+	}
+	*f = value
+	return nil
+}
+
+// Config is the on-disk config file.
+type Config struct {
+	CurrentProfile string             `json:"current_profile,omitempty"`
+	Defaults       Profile            `json:"defaults"`
+	Profiles       map[string]Profile `json:"profiles,omitempty"`
+}
+
+// Setting is a resolved config value together with where it came from.
+type Setting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// configDir returns $XDG_CONFIG_HOME/missionctl, else ~/.config/missionctl.
+func configDir() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return filepath.Join(".config", "missionctl")
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "missionctl")
+}
+
+// DefaultPluginsDir is where `plugin install` puts plugins unless plugins_dir
+// says otherwise.
+func DefaultPluginsDir() string {
+	return filepath.Join(configDir(), "plugins")
+}
+
+// DefaultPolicyPath is the authorization policy read when policy_file is
+// not set. It is optional.
+func DefaultPolicyPath() string {
+	return filepath.Join(configDir(), "policy.yaml")
+}
+
+// DefaultAlertRulesPath is the alert rules file read when alert_rules_file
+// is not set. It is optional.
+func DefaultAlertRulesPath() string {
+	return filepath.Join(configDir(), "alerts.yaml")
+}
+
+// DefaultNotifyRoutesPath is the notification routes file read when
+// notify_routes_file is not set. It is optional.
+func DefaultNotifyRoutesPath() string {
+	return filepath.Join(configDir(), "notify.yaml")
+}
+
+// DefaultSilencesPath is where silences are kept unless silences_file says
+// otherwise.
+func DefaultSilencesPath() string {
+	return filepath.Join(configDir(), "silences.json")
+}
+
+// DefaultApprovalsDir is where approval requests are kept unless
+// approvals_dir says otherwise.
+func DefaultApprovalsDir() string {
+	return filepath.Join(configDir(), "approvals")
+}
+
+// DefaultSlackTemplatesDir is where the Slack message templates are read
+// from unless slack_templates_dir says otherwise. It is optional.
+func DefaultSlackTemplatesDir() string {
+	return filepath.Join(configDir(), "slack-templates")
+}
+
+// DefaultPath returns the config file location: $MISSIONCTL_CONFIG, else
+// $XDG_CONFIG_HOME/missionctl/config.yaml, else ~/.config/missionctl/config.yaml.
+func DefaultPath() string {
+	if p := os.Getenv(EnvConfig); p != "" {
+		return p
+	}
+	return filepath.Join(configDir(), "config.yaml")
+}
+
+// Load reads the config file at path. A missing file yields an empty config.
+func Load(path string) (*Config, error) {
+	cfg := &Config{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, err
+	}
+	if err := yamlite.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save writes the config to path, creating its directory if needed.
+func (c *Config) Save(path string) error {
+	data, err := yamlite.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// ActiveProfile returns the profile selected by $MISSIONCTL_PROFILE, falling
+// back to current_profile. It may be empty.
+func (c *Config) ActiveProfile() string {
+	if p := os.Getenv(EnvProfile); p != "" {
+		return p
+	}
+	return c.CurrentProfile
+}
+
+// Settings resolves every key for the named profile. Later sources win:
+// defaults, then the profile, then MISSIONCTL_<KEY> environment variables.
+// Command-line flags are applied on top by the caller.
+func (c *Config) Settings(profile string) ([]Setting, error) {
+	var prof Profile
+	if profile != "" {
+		p, ok := c.Profiles[profile]
+		if !ok {
+			return nil, fmt.Errorf("profile %q not found in config", profile)
+		}
+		prof = p
+	}
+	var out []Setting
+	for _, key := range Keys() {
+		s := Setting{Key: key}
+		if v := c.Defaults.Get(key); v != "" {
+			s.Value, s.Source = v, "defaults"
+		}
+		if v := prof.Get(key); v != "" {
+			s.Value, s.Source = v, "profile "+profile
+		}
+		env := envPrefix + strings.ToUpper(key)
+		if v := os.Getenv(env); v != "" {
+			s.Value, s.Source = v, "env "+env
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// Resolve returns the effective settings for the named profile as a Profile.
+func (c *Config) Resolve(profile string) (Profile, error) {
+	settings, err := c.Settings(profile)
+	if err != nil {
+		return Profile{}, err
+	}
+	var p Profile
+	for _, s := range settings {
+		_ = p.Set(s.Key, s.Value)
+	}
+	return p, nil
+}
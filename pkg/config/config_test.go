@@ -0,0 +1,97 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadMissingFileIsEmpty(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "nope.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.CurrentProfile != "" || len(cfg.Profiles) != 0 {
+		t.Fatalf("expected empty config, got %+v", cfg)
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missionctl", "config.yaml")
+	cfg := &Config{
+		CurrentProfile: "prod-eu",
+		Defaults:       Profile{Namespace: "default", DashboardAddr: "localhost:8080"},
+		Profiles: map[string]Profile{
+			"prod-eu": {KubeContext: "prod-eu-1", AWSRegion: "eu-west-1", AuditFile: "/var/log/missionctl/audit.jsonl"},
+			"staging": {Namespace: "staging"},
+		},
+	}
+	if err := cfg.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.CurrentProfile != "prod-eu" || got.Defaults != cfg.Defaults || got.Profiles["prod-eu"] != cfg.Profiles["prod-eu"] {
+		t.Fatalf("round trip mismatch: %+v", got)
+	}
+}
+
+func TestSettingsPrecedence(t *testing.T) {
+	cfg := &Config{
+		Defaults: Profile{Namespace: "default", AWSRegion: "us-east-1", GCPProject: "shared"},
+		Profiles: map[string]Profile{"prod-eu": {AWSRegion: "eu-west-1", GCPProject: "prod"}},
+	}
+	t.Setenv("MISSIONCTL_GCP_PROJECT", "override")
+
+	p, err := cfg.Resolve("prod-eu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Namespace != "default" || p.AWSRegion != "eu-west-1" || p.GCPProject != "override" {
+		t.Fatalf("unexpected resolution: %+v", p)
+	}
+	settings, _ := cfg.Settings("prod-eu")
+	sources := map[string]string{}
+	for _, s := range settings {
+		sources[s.Key] = s.Source
+	}
+	if sources["namespace"] != "defaults" || sources["aws_region"] != "profile prod-eu" || sources["gcp_project"] != "env MISSIONCTL_GCP_PROJECT" {
+		t.Fatalf("unexpected sources: %v", sources)
+	}
+	if _, err := cfg.Resolve("missing"); err == nil {
+		t.Fatal("expected error for unknown profile")
+	}
+}
+
+func TestActiveProfileAndPathFromEnv(t *testing.T) {
+	cfg := &Config{CurrentProfile: "staging"}
+	t.Setenv(EnvProfile, "")
+	if cfg.ActiveProfile() != "staging" {
+		t.Fatalf("expected current_profile, got %q", cfg.ActiveProfile())
+	}
+	t.Setenv(EnvProfile, "prod-eu")
+	if cfg.ActiveProfile() != "prod-eu" {
+		t.Fatalf("expected env profile, got %q", cfg.ActiveProfile())
+	}
+
+	t.Setenv(EnvConfig, "")
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+	if got := DefaultPath(); got != filepath.Join("/tmp/xdg", "missionctl", "config.yaml") {
+		t.Fatalf("unexpected path %q", got)
+	}
+	t.Setenv(EnvConfig, "/etc/missionctl.yaml")
+	if got := DefaultPath(); got != "/etc/missionctl.yaml" {
+		t.Fatalf("unexpected path %q", got)
+	}
+}
+
+func TestSetRejectsUnknownKey(t *testing.T) {
+	var p Profile
+	if err := p.Set("aws_region", "eu-west-1"); err != nil || p.AWSRegion != "eu-west-1" {
+		t.Fatalf("set failed: %v %+v", err, p)
+	}
+	if err := p.Set("colour", "blue"); err == nil {
+		t.Fatal("expected error for unknown key")
+	}
+}
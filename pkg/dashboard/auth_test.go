@@ -1,12 +1,25 @@
 package dashboard
 
 import (
+	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/yourusername/devops-mission-control/pkg/approval"
+	"github.com/yourusername/devops-mission-control/pkg/audit"
 	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+	"github.com/yourusername/devops-mission-control/pkg/authz"
+	"github.com/yourusername/devops-mission-control/pkg/chatops"
+	"github.com/yourusername/devops-mission-control/pkg/metrics"
+	"github.com/yourusername/devops-mission-control/pkg/slack"
 )
 
 func TestAuthMiddleware_AllowsViewerWithToken(t *testing.T) {
@@ -90,3 +103,333 @@ func TestAuthMiddleware_RejectsLowerRole(t *testing.T) {
 		t.Fatalf("expected 403, got %d", rr.Code)
 	}
 }
+
+func TestAuthMiddleware_AppliesPolicy(t *testing.T) {
+	dir := t.TempDir()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if cerr := os.Chdir(cwd); cerr != nil {
+			t.Fatalf("failed to chdir back: %v", cerr)
+		}
+	})
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	us := authpkg.NewUserStore("")
+	if err := us.AddUser("carol", "", authpkg.RoleOperator); err != nil {
+		t.Fatal(err)
+	}
+	httpUserStore = us
+	UsePolicy(authz.NewEngine(authz.Policy{Rules: []authz.Rule{{
+		Effect: authz.Deny, Verbs: []string{"create"}, Resources: []string{"dashboard/alerts"}, Environments: []string{"prod"},
+	}}}), "prod")
+	t.Cleanup(func() { httpAuthz, httpEnvironment = newHTTPAuthz(), "" })
+
+	handler := authMethodMiddleware(authpkg.RoleViewer, authpkg.RoleOperator, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	})
+	for _, c := range []struct {
+		method, path string
+		want         int
+	}{
+		{"POST", "/api/alerts", http.StatusForbidden},
+		{"GET", "/api/alerts", 200},
+		{"POST", "/api/events", 200},
+	} {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(c.method, c.path, nil)
+		req.Header.Set("X-Actor", "carol")
+		handler.ServeHTTP(rr, req)
+		if rr.Code != c.want {
+			t.Errorf("%s %s: got %d, want %d", c.method, c.path, rr.Code, c.want)
+		}
+	}
+}
+
+func TestAuthMiddleware_TenantScoping(t *testing.T) {
+	dir := t.TempDir()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if cerr := os.Chdir(cwd); cerr != nil {
+			t.Fatalf("failed to chdir back: %v", cerr)
+		}
+	})
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	store := authpkg.NewMemoryStore()
+	UseAuthStore(store)
+	t.Cleanup(func() { httpTokenStore.StopWatcher() })
+	if err := httpTenantStore.CreateTenant(&authpkg.Tenant{Name: "acme"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := httpUserStore.AddUser("ann", "", authpkg.RoleViewer); err != nil {
+		t.Fatal(err)
+	}
+	tok, err := httpTokenStore.GenerateToken("ann", "t", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := httpUserStore.SetUserTenant("ann", "acme"); err != nil {
+		t.Fatal(err)
+	}
+
+	ms := metrics.NewMetricsStore(10)
+	ms.RecordMetric("cpu", 1, "%", map[string]string{"tenant": "acme"})
+	ms.RecordMetric("cpu", 2, "%", map[string]string{"tenant": "beta"})
+	ms.RecordMetric("cpu", 3, "%", nil)
+	d := NewDashboard("", ms)
+	handler := authMiddleware(authpkg.RoleViewer, d.handleMetrics)
+
+	get := func() *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/api/metrics", nil)
+		req.Header.Set("Authorization", "Bearer "+tok.Token)
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+	// the token was issued before ann joined acme
+	if rr := get(); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("token from before the tenant change accepted: %d", rr.Code)
+	}
+	if err := httpTokenStore.SetTenant("ann", "acme"); err != nil {
+		t.Fatal(err)
+	}
+	rr := get()
+	var got []metrics.Metric
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("%d %s: %v", rr.Code, rr.Body.String(), err)
+	}
+	if len(got) != 1 || got[0].Value != 1 {
+		t.Fatalf("ann saw %+v", got)
+	}
+}
+
+func TestHandleAudit_Pages(t *testing.T) {
+	dir := t.TempDir()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if cerr := os.Chdir(cwd); cerr != nil {
+			t.Fatalf("failed to chdir back: %v", cerr)
+		}
+	})
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	d := NewDashboard("", metrics.NewMetricsStore(10))
+	get := func(query string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		d.handleAudit(rr, httptest.NewRequest("GET", "/api/audit"+query, nil))
+		return rr
+	}
+	if rr := get(""); rr.Code != http.StatusOK || rr.Body.String() != "[]\n" {
+		t.Fatalf("empty log: %d %q", rr.Code, rr.Body.String())
+	}
+	for i := 0; i < 5; i++ {
+		if err := audit.Record("", "test.action", "bob", "", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rr := get("?limit=2&after=2")
+	var page []audit.Entry
+	if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil {
+		t.Fatalf("%d %s: %v", rr.Code, rr.Body.String(), err)
+	}
+	if len(page) != 2 || page[0].Seq != 3 || rr.Header().Get("X-Next-Cursor") != "4" {
+		t.Fatalf("page %+v, next %q", page, rr.Header().Get("X-Next-Cursor"))
+	}
+	if rr := get("?after=bogus"); rr.Code != http.StatusBadRequest {
+		t.Fatalf("bad cursor: %d", rr.Code)
+	}
+	if err := audit.Record("", "other.action", "ann", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	rr = get("?q=actor!=bob&q=action=other.*&since=1h")
+	if err := json.Unmarshal(rr.Body.Bytes(), &page); err != nil || len(page) != 1 || page[0].Actor != "ann" {
+		t.Fatalf("query: %d %s", rr.Code, rr.Body.String())
+	}
+	if rr := get("?q=who=bob"); rr.Code != http.StatusBadRequest {
+		t.Fatalf("bad query term: %d", rr.Code)
+	}
+}
+
+func TestHandleMetricsQuery(t *testing.T) {
+	ms := metrics.NewMetricsStore(10)
+	for i := 1; i <= 4; i++ {
+		host := "web-1"
+		if i%2 == 0 {
+			host = "web-2"
+		}
+		if err := ms.RecordMetric("cpu", float64(i), "%", map[string]string{"host": host}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d := NewDashboard("", ms)
+	get := func(query string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		d.handleMetricsQuery(rr, httptest.NewRequest("GET", "/api/metrics/query"+query, nil))
+		return rr
+	}
+	rr := get("?name=cpu&from=5m&agg=max&by=host&tag=host=web-*")
+	var series []metrics.Series
+	if err := json.Unmarshal(rr.Body.Bytes(), &series); err != nil {
+		t.Fatalf("%d %s: %v", rr.Code, rr.Body.String(), err)
+	}
+	if len(series) != 2 || series[0].Tags["host"] != "web-1" || series[0].Points[0].Value != 3 || series[1].Points[0].Value != 4 {
+		t.Fatalf("series: %+v", series)
+	}
+	for _, bad := range []string{"", "?name=cpu&agg=median", "?name=cpu&from=yesterday", "?name=cpu&step=-1m", "?name=cpu&tag=host"} {
+		if rr := get(bad); rr.Code != http.StatusBadRequest {
+			t.Errorf("%q: %d %s", bad, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestHandlePrometheus(t *testing.T) {
+	ms := metrics.NewMetricsStore(10)
+	if err := ms.RecordMetric("cpu", 1, "%", map[string]string{"tenant": "acme"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ms.RecordMetric("cpu", 2, "%", map[string]string{"tenant": "beta"}); err != nil {
+		t.Fatal(err)
+	}
+	countDenial("forbidden")
+	d := NewDashboard("", ms)
+	get := func(tenant, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/metrics", nil)
+		req.Header.Set("Accept", accept)
+		req = req.WithContext(context.WithValue(req.Context(), tenantCtxKey{}, tenant))
+		rr := httptest.NewRecorder()
+		d.handlePrometheus(rr, req)
+		return rr
+	}
+
+	rr := get("", "text/plain")
+	body := rr.Body.String()
+	if rr.Header().Get("Content-Type") != metrics.ContentTypeText ||
+		!strings.Contains(body, `missionctl_cpu_percent{tenant="beta"} 2`) ||
+		!strings.Contains(body, `missionctl_auth_denials_total{reason="forbidden"}`) ||
+		!strings.Contains(body, `missionctl_audit_records_total{result="ok"}`) {
+		t.Fatalf("global scrape:\n%s", body)
+	}
+
+	rr = get("acme", "application/openmetrics-text;version=1.0.0,text/plain;q=0.5")
+	body = rr.Body.String()
+	if rr.Header().Get("Content-Type") != metrics.ContentTypeOpenMetrics || !strings.HasSuffix(body, "# EOF\n") ||
+		!strings.Contains(body, `tenant="acme"`) || strings.Contains(body, "beta") || strings.Contains(body, "auth_denials") {
+		t.Fatalf("tenant scrape:\n%s", body)
+	}
+}
+
+func TestHandleSlackInteraction(t *testing.T) {
+	dir := t.TempDir()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if cerr := os.Chdir(cwd); cerr != nil {
+			t.Fatalf("failed to chdir back: %v", cerr)
+		}
+	})
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	var updates []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		updates = append(updates, r.URL.Path+" "+string(body))
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer api.Close()
+	gate := approval.NewGate(approval.NewStore("approvals"), slack.NewClient("", "#ops", slack.WithBotToken("xoxb-test"), slack.WithAPIURL(api.URL)), "#ops")
+	req, err := gate.Store.Create(approval.Request{Command: "terraform apply", Actor: "ann", Tenant: "acme", ExpiresAt: time.Now().Add(time.Hour), Channel: "C1", TS: "1.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := NewDashboard("", metrics.NewMetricsStore(10))
+	d.UseApprovals(gate, "s3cret")
+
+	post := func(secret, action string) *httptest.ResponseRecorder {
+		payload := `{"type":"block_actions","user":{"id":"U9","username":"lead"},"channel":{"id":"C1"},"actions":[{"action_id":"` + action + `","value":"` + req.ID + `"}]}`
+		body := "payload=" + url.QueryEscape(payload)
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		r := httptest.NewRequest("POST", "/api/slack/interactions", strings.NewReader(body))
+		r.Header.Set("X-Slack-Request-Timestamp", ts)
+		r.Header.Set("X-Slack-Signature", slack.Sign(secret, ts, []byte(body)))
+		rr := httptest.NewRecorder()
+		d.handleSlackInteraction(rr, r)
+		return rr
+	}
+	if rr := post("forged", approval.ActionApprove); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("forged signature: %d", rr.Code)
+	}
+	if got, _ := gate.Store.Get(req.ID); got.Status != approval.StatusPending {
+		t.Fatalf("a forged click decided: %+v", got)
+	}
+	if rr := post("s3cret", approval.ActionApprove); rr.Code != http.StatusOK {
+		t.Fatalf("%d %s", rr.Code, rr.Body.String())
+	}
+	if got, _ := gate.Store.Get(req.ID); got.Status != approval.StatusApproved || got.DecidedBy != "U9" {
+		t.Fatalf("request %+v", got)
+	}
+	if len(updates) != 1 || !strings.HasPrefix(updates[0], "/chat.update ") || !strings.Contains(updates[0], `Approved by \u003c@U9\u003e`) {
+		t.Fatalf("updates %q", updates)
+	}
+	entries, _, err := audit.Page("", "", 100, func(e audit.Entry) bool { return strings.HasPrefix(e.Action, "approval.") })
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("audit %+v: %v", entries, err)
+	}
+	if e := entries[0]; e.Action != "approval.approve" || e.TenantID != "acme" || e.Target != "terraform apply" ||
+		e.Details["slack_user"] != "U9" || e.Details["channel"] != "C1" || e.Details["requested_by"] != "ann" {
+		t.Fatalf("audit entry %+v", e)
+	}
+}
+
+func TestHandleSlackCommand(t *testing.T) {
+	dir := t.TempDir()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if cerr := os.Chdir(cwd); cerr != nil {
+			t.Fatalf("failed to chdir back: %v", cerr)
+		}
+	})
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	bot := chatops.NewBot(authpkg.NewUserStore(""), "missionctl", func([]string) (string, error) { return "k8s pods list", nil }, slack.NewClient("", ""))
+	d := NewDashboard("", metrics.NewMetricsStore(10))
+	d.UseChatOps(bot, "s3cret")
+	post := func(secret string) *httptest.ResponseRecorder {
+		body := url.Values{"command": {"/missionctl"}, "text": {"k8s pods list"}, "user_id": {"U0NOBODY"}, "channel_id": {"C1"}}.Encode()
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		r := httptest.NewRequest("POST", "/api/slack/commands", strings.NewReader(body))
+		r.Header.Set("X-Slack-Request-Timestamp", ts)
+		r.Header.Set("X-Slack-Signature", slack.Sign(secret, ts, []byte(body)))
+		rr := httptest.NewRecorder()
+		d.handleSlackCommand(rr, r)
+		return rr
+	}
+	if rr := post("forged"); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("forged signature: %d", rr.Code)
+	}
+	rr := post("s3cret")
+	var msg slack.Message
+	if err := json.Unmarshal(rr.Body.Bytes(), &msg); err != nil || rr.Code != http.StatusOK || msg.ResponseType != "ephemeral" || !strings.Contains(msg.Text, "not linked") {
+		t.Fatalf("%d %s: %v", rr.Code, rr.Body.String(), err)
+	}
+}
@@ -1,19 +1,29 @@
 package dashboard
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/yourusername/devops-mission-control/pkg/alerting"
+	"github.com/yourusername/devops-mission-control/pkg/approval"
 	"github.com/yourusername/devops-mission-control/pkg/audit"
 	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+	"github.com/yourusername/devops-mission-control/pkg/authz"
+	"github.com/yourusername/devops-mission-control/pkg/chatops"
 	"github.com/yourusername/devops-mission-control/pkg/metrics"
+	"github.com/yourusername/devops-mission-control/pkg/notify"
+	"github.com/yourusername/devops-mission-control/pkg/slack"
 )
 
 // Dashboard serves the missionctl web dashboard
@@ -24,6 +34,12 @@ type Dashboard struct {
 	isRunning    bool
 	stopChan     chan bool
 	refreshRate  time.Duration
+	remoteWrite  *metrics.RemoteWriter
+	alertEngine  *alerting.Engine
+	notifier     *notify.Dispatcher
+	approvals    *approval.Gate
+	chatops      *chatops.Bot
+	slackSecret  string
 }
 
 // NewDashboard creates a new dashboard
@@ -36,24 +52,136 @@ func NewDashboard(addr string, store *metrics.MetricsStore) *Dashboard {
 	}
 }
 
+// UseRemoteWrite makes the dashboard push its metrics through rw while it
+// runs. rw sends the process counters of /metrics unless it has an Extra.
+func (d *Dashboard) UseRemoteWrite(rw *metrics.RemoteWriter) {
+	if rw.Extra == nil {
+		rw.Extra = processFamilies
+	}
+	d.remoteWrite = rw
+}
+
+// UseAlertEngine makes the dashboard evaluate e's rules while it runs.
+func (d *Dashboard) UseAlertEngine(e *alerting.Engine) {
+	d.alertEngine = e
+}
+
+// UseNotifier makes the dashboard send alert notifications through n while
+// it runs.
+func (d *Dashboard) UseNotifier(n *notify.Dispatcher) {
+	d.notifier = n
+}
+
+// UseApprovals serves /api/slack/interactions, where Slack posts the
+// Approve / Reject clicks on g's requests signed with signingSecret.
+func (d *Dashboard) UseApprovals(g *approval.Gate, signingSecret string) {
+	d.approvals, d.slackSecret = g, signingSecret
+}
+
+// UseChatOps serves /api/slack/commands, where Slack posts the slash
+// commands b runs, signed with signingSecret.
+func (d *Dashboard) UseChatOps(b *chatops.Bot, signingSecret string) {
+	d.chatops, d.slackSecret = b, signingSecret
+}
+
 // auth resources for HTTP handlers (use same backing files)
 var (
-	httpTokenStore *authpkg.TokenStore
-	httpUserStore  = authpkg.NewUserStore("")
+	httpTokenStore  *authpkg.TokenStore
+	httpUserStore   = authpkg.NewUserStore("")
+	httpTenantStore = authpkg.OpenTenantStore(authpkg.NewFileStore("", ""))
+)
+
+// UseAuthStore points the HTTP handlers at the given auth backend instead of
+// users.json and tokens.json in the working directory, so the dashboard
+// sees the same users, tokens and tenants as the CLI.
+func UseAuthStore(s authpkg.Store) {
+	httpUserStore = authpkg.OpenUserStore(s)
+	httpTokenStore = authpkg.OpenTokenStore(s)
+	httpTenantStore = authpkg.OpenTenantStore(s)
+	httpTokenStore.StartWatcher()
+}
+
+// policy engine for HTTP handlers; without UsePolicy only roles apply
+var (
+	httpAuthz       = newHTTPAuthz()
+	httpEnvironment string
 )
 
-// role hierarchy for simple checks
-var roleLevel = map[authpkg.Role]int{
-	authpkg.RoleViewer:   10,
-	authpkg.RoleOperator: 20,
-	authpkg.RoleAdmin:    30,
+func newHTTPAuthz() *authz.Engine {
+	e := authz.NewEngine(authz.Policy{})
+	e.UseTenants(func(name string) (*authpkg.Tenant, error) { return httpTenantStore.GetTenant(name) })
+	return e
+}
+
+// tenantCtxKey carries the caller's tenant from authMiddleware to handlers.
+type tenantCtxKey struct{}
+
+// requestTenant returns the tenant of the authenticated caller, "" for
+// global users.
+func requestTenant(r *http.Request) string {
+	t, _ := r.Context().Value(tenantCtxKey{}).(string)
+	return t
+}
+
+// storeFor returns the metrics a caller may see: everything for global
+// users, their tenant's items otherwise.
+func (d *Dashboard) storeFor(r *http.Request) *metrics.MetricsStore {
+	if t := requestTenant(r); t != "" {
+		return d.metricsStore.ForTenant(t)
+	}
+	return d.metricsStore
+}
+
+// UsePolicy makes the HTTP handlers authorize through e, the engine the CLI
+// uses, with requests scoped to environment. e must be able to look up
+// tenants (see authz.Engine.UseTenants).
+func UsePolicy(e *authz.Engine, environment string) {
+	httpAuthz, httpEnvironment = e, environment
+}
+
+// methodVerbs maps HTTP methods to the verbs policy rules match on.
+var methodVerbs = map[string]string{
+	http.MethodGet:    "get",
+	http.MethodHead:   "get",
+	http.MethodPost:   "create",
+	http.MethodPut:    "update",
+	http.MethodPatch:  "update",
+	http.MethodDelete: "delete",
+}
+
+// httpRequest describes r for the policy engine: the verb comes from the
+// method and the resource is dashboard/<path without /api/>, e.g. GET
+// /api/alerts is get on dashboard/alerts. A namespace query parameter
+// scopes the request.
+func httpRequest(r *http.Request, minRole authpkg.Role) authz.Request {
+	verb, ok := methodVerbs[r.Method]
+	if !ok {
+		verb = strings.ToLower(r.Method)
+	}
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/"), "/")
+	if path == "" {
+		path = "ui"
+	}
+	return authz.Request{
+		Verb:        verb,
+		Resource:    "dashboard/" + path,
+		Provider:    "dashboard",
+		Namespace:   r.URL.Query().Get("namespace"),
+		Environment: httpEnvironment,
+		MinRole:     minRole,
+	}
 }
 
-// authMiddleware wraps handlers and enforces a minimum role. It accepts either
-// an Authorization: Bearer <token> header or X-Actor: <username> custom header.
+// authMiddleware wraps handlers and authorizes them through the policy
+// engine, with minRole as the role needed when no policy rule matches. It
+// accepts either an Authorization: Bearer <token> header or X-Actor:
+// <username> custom header. A user in a tenant only gets its tenant's data,
+// and a token is only accepted while its user is still in the tenant it
+// was issued for.
 func authMiddleware(minRole authpkg.Role, h http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		actor := ""
+		var tok *authpkg.Token
 		// check Authorization header first
 		authHeader := r.Header.Get("Authorization")
 		if authHeader != "" {
@@ -61,11 +189,13 @@ func authMiddleware(minRole authpkg.Role, h http.HandlerFunc) http.HandlerFunc {
 			if strings.HasPrefix(authHeader, "Bearer ") {
 				token := strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
 				if token != "" {
-					tok, err := httpTokenStore.Validate(token)
+					var err error
+					tok, err = httpTokenStore.Validate(token)
 					if err != nil {
 						if rerr := audit.Record("", "auth.check", "", r.URL.Path, map[string]any{"allowed": false, "reason": "invalid token"}); rerr != nil {
 							fmt.Fprintf(os.Stderr, "audit record failed: %v\n", rerr)
 						}
+						countDenial("invalid_token")
 						http.Error(w, "invalid token", http.StatusUnauthorized)
 						return
 					}
@@ -81,6 +211,7 @@ func authMiddleware(minRole authpkg.Role, h http.HandlerFunc) http.HandlerFunc {
 			if err := audit.Record("", "auth.check", "", r.URL.Path, map[string]any{"allowed": false, "reason": "no actor"}); err != nil {
 				fmt.Fprintf(os.Stderr, "audit record failed: %v\n", err)
 			}
+			countDenial("no_actor")
 			http.Error(w, "missing actor or token", http.StatusUnauthorized)
 			return
 		}
@@ -89,24 +220,41 @@ func authMiddleware(minRole authpkg.Role, h http.HandlerFunc) http.HandlerFunc {
 			if rerr := audit.Record("", "auth.check", actor, r.URL.Path, map[string]any{"allowed": false, "reason": "actor not found"}); rerr != nil {
 				fmt.Fprintf(os.Stderr, "audit record failed: %v\n", rerr)
 			}
+			countDenial("unknown_actor")
 			http.Error(w, "actor not found", http.StatusUnauthorized)
 			return
 		}
-		if roleLevel[u.Role] < roleLevel[minRole] {
-			if rerr := audit.Record("", "auth.check", actor, r.URL.Path, map[string]any{"allowed": false, "required": minRole, "have": u.Role}); rerr != nil {
+		if tok != nil && tok.Tenant != u.Tenant {
+			if rerr := audit.Record(u.Tenant, "auth.check", actor, r.URL.Path, map[string]any{"allowed": false, "reason": "token tenant mismatch", "token_tenant": tok.Tenant}); rerr != nil {
+				fmt.Fprintf(os.Stderr, "audit record failed: %v\n", rerr)
+			}
+			countDenial("tenant_mismatch")
+			http.Error(w, "token not valid for this tenant", http.StatusUnauthorized)
+			return
+		}
+		req := httpRequest(r, minRole)
+		req.User, req.Role, req.Tenant = u.Username, u.Role, u.Tenant
+		if d := httpAuthz.Authorize(req); !d.Allowed {
+			if rerr := audit.Record(u.Tenant, "auth.check", actor, r.URL.Path, map[string]any{"allowed": false, "required": minRole, "have": u.Role, "reason": d.Reason}); rerr != nil {
 				fmt.Fprintf(os.Stderr, "audit record failed: %v\n", rerr)
 			}
+			countDenial("forbidden")
 			http.Error(w, "forbidden", http.StatusForbidden)
 			return
 		}
 		// allowed
-		if err := audit.Record("", "auth.check", actor, r.URL.Path, map[string]any{"allowed": true, "have": u.Role}); err != nil {
+		if err := audit.Record(u.Tenant, "auth.check", actor, r.URL.Path, map[string]any{"allowed": true, "have": u.Role}); err != nil {
 			fmt.Fprintf(os.Stderr, "audit record failed: %v\n", err)
 		}
-		h(w, r)
+		h(w, r.WithContext(context.WithValue(r.Context(), tenantCtxKey{}, u.Tenant)))
 	}
 }
 
+// countDenial counts a request authMiddleware turned away.
+func countDenial(reason string) {
+	metrics.Counters.Inc(metrics.Namespace+"_auth_denials_total", "Dashboard requests denied by authentication or authorization, by reason.", map[string]string{"reason": reason})
+}
+
 // authMethodMiddleware enforces different minimum roles depending on HTTP method.
 // GET/HEAD/OPTIONS use `viewRole`, while POST/PUT/DELETE use `postRole`.
 func authMethodMiddleware(viewRole, postRole authpkg.Role, h http.HandlerFunc) http.HandlerFunc {
@@ -155,12 +303,22 @@ func (d *Dashboard) Start() error {
 
 	// API endpoints (viewer for reads, operator for creates/changes)
 	mux.HandleFunc("/api/metrics", authMethodMiddleware(authpkg.RoleViewer, authpkg.RoleOperator, d.handleMetrics))
+	mux.HandleFunc("/api/metrics/query", authMiddleware(authpkg.RoleViewer, d.handleMetricsQuery))
 	mux.HandleFunc("/api/events", authMethodMiddleware(authpkg.RoleViewer, authpkg.RoleOperator, d.handleEvents))
 	mux.HandleFunc("/api/alerts", authMethodMiddleware(authpkg.RoleViewer, authpkg.RoleOperator, d.handleAlerts))
 	// audit is sensitive: admin only
 	mux.HandleFunc("/api/audit", authMiddleware(authpkg.RoleAdmin, d.handleAudit))
 	mux.HandleFunc("/api/stats", authMethodMiddleware(authpkg.RoleViewer, authpkg.RoleOperator, d.handleStats))
 	mux.HandleFunc("/api/health", authMiddleware(authpkg.RoleViewer, d.handleHealth))
+	// Prometheus scrape endpoint
+	mux.HandleFunc("/metrics", authMiddleware(authpkg.RoleViewer, d.handlePrometheus))
+	// Slack signs its requests instead of sending a missionctl token
+	if d.approvals != nil {
+		mux.HandleFunc("/api/slack/interactions", d.handleSlackInteraction)
+	}
+	if d.chatops != nil {
+		mux.HandleFunc("/api/slack/commands", d.handleSlackCommand)
+	}
 
 	// Web UI (require viewer)
 	mux.HandleFunc("/", authMiddleware(authpkg.RoleViewer, d.handleDashboard))
@@ -215,6 +373,43 @@ func (d *Dashboard) Start() error {
 		errCh <- server.Serve(ln)
 	}()
 
+	// writes compact the store as they go; the ticker ages out old points
+	// while nothing is written. Stopping waits for the last remote write.
+	done := make(chan struct{})
+	var bg sync.WaitGroup
+	defer bg.Wait()
+	defer close(done)
+	bg.Add(1)
+	go func() {
+		defer bg.Done()
+		d.compactLoop(done)
+	}()
+	if d.remoteWrite != nil {
+		bg.Add(1)
+		go func() {
+			defer bg.Done()
+			d.remoteWrite.Run(done)
+		}()
+	}
+	if d.alertEngine != nil {
+		bg.Add(1)
+		go func() {
+			defer bg.Done()
+			d.alertEngine.Run(done)
+		}()
+	}
+	if d.notifier != nil {
+		bg.Add(1)
+		go func() {
+			defer bg.Done()
+			d.notifier.Run(done)
+		}()
+	}
+	if d.chatops != nil {
+		// let running slash commands post their results
+		defer d.chatops.Wait()
+	}
+
 	fmt.Printf("✅ Dashboard started at http://%s\n", d.addr)
 
 	select {
@@ -297,7 +492,7 @@ func (d *Dashboard) handleJS(w http.ResponseWriter, r *http.Request) {
 // handleMetrics returns metrics as JSON
 func (d *Dashboard) handleMetrics(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	metrics := d.metricsStore.GetMetrics()
+	metrics := d.storeFor(r).GetMetrics()
 	if err := json.NewEncoder(w).Encode(metrics); err != nil {
 		http.Error(w, "failed to encode metrics", http.StatusInternalServerError)
 		if rerr := audit.Record("", "dashboard.error", "", d.addr, map[string]any{"error": err.Error()}); rerr != nil {
@@ -310,7 +505,7 @@ func (d *Dashboard) handleMetrics(w http.ResponseWriter, r *http.Request) {
 // handleEvents returns events as JSON
 func (d *Dashboard) handleEvents(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	events := d.metricsStore.GetEvents()
+	events := d.storeFor(r).GetEvents()
 	if err := json.NewEncoder(w).Encode(events); err != nil {
 		http.Error(w, "failed to encode events", http.StatusInternalServerError)
 		if rerr := audit.Record("", "dashboard.error", "", d.addr, map[string]any{"error": err.Error()}); rerr != nil {
@@ -323,7 +518,7 @@ func (d *Dashboard) handleEvents(w http.ResponseWriter, r *http.Request) {
 // handleAlerts returns alerts as JSON
 func (d *Dashboard) handleAlerts(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	alerts := d.metricsStore.GetAlerts()
+	alerts := d.storeFor(r).GetAlerts()
 	if err := json.NewEncoder(w).Encode(alerts); err != nil {
 		http.Error(w, "failed to encode alerts", http.StatusInternalServerError)
 		if rerr := audit.Record("", "dashboard.error", "", d.addr, map[string]any{"error": err.Error()}); rerr != nil {
@@ -336,7 +531,7 @@ func (d *Dashboard) handleAlerts(w http.ResponseWriter, r *http.Request) {
 // handleStats returns summary statistics
 func (d *Dashboard) handleStats(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	stats := d.metricsStore.GetStats()
+	stats := d.storeFor(r).GetStats()
 	if err := json.NewEncoder(w).Encode(stats); err != nil {
 		http.Error(w, "failed to encode stats", http.StatusInternalServerError)
 		if rerr := audit.Record("", "dashboard.error", "", d.addr, map[string]any{"error": err.Error()}); rerr != nil {
@@ -363,14 +558,167 @@ func (d *Dashboard) handleHealth(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// handleAudit returns audit log entries as JSON
-func (d *Dashboard) handleAudit(w http.ResponseWriter, r *http.Request) {
+// handlePrometheus serves the metrics in the Prometheus text format, or
+// OpenMetrics when the scraper accepts it. Tenant callers get their
+// tenant's metrics only; global callers also get the process counters.
+func (d *Dashboard) handlePrometheus(w http.ResponseWriter, r *http.Request) {
+	families := d.storeFor(r).Families()
+	if requestTenant(r) == "" {
+		families = append(families, processFamilies()...)
+	}
+	write, contentType := metrics.WriteText, metrics.ContentTypeText
+	if strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text") {
+		write, contentType = metrics.WriteOpenMetrics, metrics.ContentTypeOpenMetrics
+	}
+	w.Header().Set("Content-Type", contentType)
+	if err := write(w, families); err != nil {
+		fmt.Fprintf(os.Stderr, "write response failed: %v\n", err)
+	}
+}
+
+// processFamilies are the counters of this process: audit records and
+// whatever has been counted in metrics.Counters.
+func processFamilies() []metrics.Family {
+	st := audit.Stats()
+	records := metrics.Family{
+		Name: metrics.Namespace + "_audit_records_total",
+		Help: "Audit entries recorded by this process, by result.",
+		Type: metrics.TypeCounter,
+		Samples: []metrics.Sample{
+			{Labels: map[string]string{"result": "ok"}, Value: float64(st.Recorded)},
+			{Labels: map[string]string{"result": "failed"}, Value: float64(st.Failed)},
+		},
+	}
+	return append([]metrics.Family{records}, metrics.Counters.Families()...)
+}
+
+// compactLoop compacts the metrics store every rollup interval until done
+// is closed.
+func (d *Dashboard) compactLoop(done <-chan struct{}) {
+	interval := d.metricsStore.Rollup.Interval
+	if interval <= 0 {
+		interval = metrics.DefaultRollup.Interval
+	}
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-tick.C:
+			if err := d.metricsStore.Compact(); err != nil {
+				log.Printf("metrics compaction failed: %v", err)
+			}
+		}
+	}
+}
+
+// handleMetricsQuery runs a time-series query and returns its series as
+// JSON. ?name= is the metric; ?from= and ?to= bound the range (default the
+// last hour); ?agg= and ?step= aggregate it; ?tag= (repeatable) takes
+// key=glob or key!=glob filters and ?by= (repeatable or comma-separated)
+// the tags to group by.
+func (d *Dashboard) handleMetricsQuery(w http.ResponseWriter, r *http.Request) {
+	q, err := metricsQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	series, err := d.storeFor(r).Query(q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
-	entries, err := audit.ReadEntries("")
+	if err := json.NewEncoder(w).Encode(series); err != nil {
+		http.Error(w, "failed to encode series", http.StatusInternalServerError)
+		if rerr := audit.Record("", "dashboard.error", "", d.addr, map[string]any{"error": err.Error()}); rerr != nil {
+			fmt.Fprintf(os.Stderr, "audit record failed: %v\n", rerr)
+		}
+		return
+	}
+}
+
+// metricsQuery reads the query of an /api/metrics/query request.
+func metricsQuery(r *http.Request) (metrics.Query, error) {
+	params := r.URL.Query()
+	q := metrics.Query{Name: params.Get("name"), Agg: params.Get("agg")}
+	now := time.Now()
+	for param, field := range map[string]*time.Time{"from": &q.From, "to": &q.To} {
+		if v := params.Get(param); v != "" {
+			t, err := audit.ParseTime(v, now)
+			if err != nil {
+				return q, err
+			}
+			*field = t
+		}
+	}
+	if v := params.Get("step"); v != "" {
+		step, err := audit.ParseDuration(v)
+		if err != nil {
+			return q, err
+		}
+		q.Step = step
+	}
+	for _, v := range params["tag"] {
+		f, err := metrics.ParseTagFilter(v)
+		if err != nil {
+			return q, err
+		}
+		q.Filters = append(q.Filters, f)
+	}
+	for _, v := range params["by"] {
+		for _, k := range strings.Split(v, ",") {
+			if k = strings.TrimSpace(k); k != "" {
+				q.GroupBy = append(q.GroupBy, k)
+			}
+		}
+	}
+	return q, nil
+}
+
+// Page sizes of /api/audit.
+const (
+	defaultAuditLimit = 1000
+	maxAuditLimit     = 10000
+)
+
+// handleAudit returns one page of audit log entries as JSON, oldest first.
+// ?limit= sets the page size and ?after= takes the cursor from the
+// X-Next-Cursor header of the previous page, which is absent on the last.
+// ?q= (repeatable) takes the query terms of `missionctl audit list`, and
+// ?since= and ?until= its time bounds.
+func (d *Dashboard) handleAudit(w http.ResponseWriter, r *http.Request) {
+	limit := defaultAuditLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = min(n, maxAuditLimit)
+	}
+	q, err := auditQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	entries, next, err := audit.Page("", r.URL.Query().Get("after"), limit, q.Match)
+	if errors.Is(err, os.ErrNotExist) {
+		entries, err = []audit.Entry{}, nil
+	}
+	if errors.Is(err, audit.ErrInvalidCursor) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, "failed to read audit", http.StatusInternalServerError)
 		return
 	}
+	w.Header().Set("Content-Type", "application/json")
+	if next != "" {
+		w.Header().Set("X-Next-Cursor", next)
+	}
 	if err := json.NewEncoder(w).Encode(entries); err != nil {
 		http.Error(w, "failed to encode audit entries", http.StatusInternalServerError)
 		if rerr := audit.Record("", "dashboard.error", "", d.addr, map[string]any{"error": err.Error()}); rerr != nil {
@@ -380,6 +728,126 @@ func (d *Dashboard) handleAudit(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// auditQuery reads the query of an /api/audit request, confined to the
+// caller's tenant.
+func auditQuery(r *http.Request) (audit.Query, error) {
+	var q audit.Query
+	now := time.Now()
+	for param, field := range map[string]*time.Time{"since": &q.Since, "until": &q.Until} {
+		if v := r.URL.Query().Get(param); v != "" {
+			t, err := audit.ParseTime(v, now)
+			if err != nil {
+				return q, err
+			}
+			*field = t
+		}
+	}
+	if t := requestTenant(r); t != "" {
+		q.Terms = append(q.Terms, audit.Term{Field: "tenant", Pattern: t})
+	}
+	for _, v := range r.URL.Query()["q"] {
+		t, err := audit.ParseTerm(v)
+		if err != nil {
+			return q, err
+		}
+		q.Terms = append(q.Terms, t)
+	}
+	return q, nil
+}
+
+// handleSlackInteraction records the Approve / Reject clicks Slack posts
+// for approval requests, audits them and redraws the request message. A
+// refused click is explained to its user alone.
+func (d *Dashboard) handleSlackInteraction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+	if err := slack.VerifyRequest(d.slackSecret, r.Header, body, time.Now()); err != nil {
+		if rerr := audit.Record("", "auth.check", "", r.URL.Path, map[string]any{"allowed": false, "reason": err.Error()}); rerr != nil {
+			fmt.Fprintf(os.Stderr, "audit record failed: %v\n", rerr)
+		}
+		countDenial("invalid_signature")
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	in, err := slack.ParseInteraction(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, a := range in.Actions {
+		if a.ActionID != approval.ActionApprove && a.ActionID != approval.ActionReject {
+			continue
+		}
+		approve := a.ActionID == approval.ActionApprove
+		req, err := d.approvals.Decide(a.Value, in.User, approve)
+		action := "approval.reject"
+		if approve {
+			action = "approval.approve"
+		}
+		details := map[string]any{"request": a.Value, "slack_user": in.User.ID, "slack_user_name": in.User.Username, "channel": in.Channel.ID, "allowed": err == nil}
+		if req.Actor != "" {
+			details["requested_by"] = req.Actor
+		}
+		if err != nil {
+			details["error"] = err.Error()
+		}
+		if rerr := audit.Record(req.Tenant, action, "slack:"+in.User.ID, req.Command, details); rerr != nil {
+			fmt.Fprintf(os.Stderr, "audit record failed: %v\n", rerr)
+		}
+		if err != nil {
+			if in.ResponseURL != "" {
+				if rerr := d.approvals.Slack.Respond(in.ResponseURL, slack.Message{ResponseType: "ephemeral", Text: "⚠️ " + err.Error()}); rerr != nil {
+					log.Printf("slack response failed: %v", rerr)
+				}
+			}
+			continue
+		}
+		if uerr := d.approvals.Update(req); uerr != nil {
+			log.Printf("failed to update approval message %s: %v", req.ID, uerr)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleSlackCommand answers the slash commands Slack posts. Slack wants
+// an answer within three seconds, so commands run in the background and
+// post their output to the response URL.
+func (d *Dashboard) handleSlackCommand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+	if err := slack.VerifyRequest(d.slackSecret, r.Header, body, time.Now()); err != nil {
+		if rerr := audit.Record("", "auth.check", "", r.URL.Path, map[string]any{"allowed": false, "reason": err.Error()}); rerr != nil {
+			fmt.Fprintf(os.Stderr, "audit record failed: %v\n", rerr)
+		}
+		countDenial("invalid_signature")
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	sc, err := slack.ParseSlashCommand(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(d.chatops.Handle(sc)); err != nil {
+		log.Printf("failed to answer slack command: %v", err)
+	}
+}
+
 const dashboardHTML = `
 <!DOCTYPE html>
 <html>
@@ -415,14 +883,14 @@ const dashboardHTML = `
 		</div>
 
 		<div class="content-grid">
-			<section class="widget alerts-widget">
+			<section class="widget alerts-widget" id="alerts">
 				<h2>⚠️ Active Alerts</h2>
 				<div id="alertsList" class="list">
 					<p class="loading">Loading...</p>
 				</div>
 			</section>
 
-			<section class="widget events-widget">
+			<section class="widget events-widget" id="events">
 				<h2>📊 Recent Events</h2>
 				<div id="eventsList" class="list">
 					<p class="loading">Loading...</p>
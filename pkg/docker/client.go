@@ -1,46 +1,81 @@
 package docker
 
 import (
-	"bytes"
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
+
+	"github.com/yourusername/devops-mission-control/pkg/executor"
 )
 
 // Client wraps Docker operations
-type Client struct{}
+type Client struct {
+	// Exec runs docker; defaults to the local OS executor.
+	Exec executor.Executor
+}
+
+// Option configures the Docker client.
+type Option func(*Client)
+
+// WithExecutor sets the executor used to run docker.
+func WithExecutor(e executor.Executor) Option {
+	return func(c *Client) { c.Exec = e }
+}
 
 // NewClient creates a new Docker client
-func NewClient() *Client {
-	return &Client{}
+func NewClient(opts ...Option) *Client {
+	c := &Client{}
+	for _, o := range opts {
+		o(c)
+	}
+	if c.Exec == nil {
+		c.Exec = executor.New()
+	}
+	return c
 }
 
 // execDocker runs a docker command and returns output
 func (c *Client) execDocker(args ...string) (string, error) {
-	cmd := exec.Command("docker", args...)
-	var out, errOut bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &errOut
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("docker error: %s", errOut.String())
+	res, err := c.Exec.Run(context.Background(), executor.Command{Name: "docker", Args: args})
+	if err != nil {
+		return "", fmt.Errorf("docker error: %s", executor.ErrorText(res, err))
 	}
-	return strings.TrimSpace(out.String()), nil
+	return strings.TrimSpace(string(res.Stdout)), nil
 }
 
-// ListContainers lists all containers (running and stopped)
-func (c *Client) ListContainers(running bool) (string, error) {
-	args := []string{"ps", "-a", "--format", "table {{.Names}}\t{{.Status}}\t{{.Ports}}"}
+// runDockerAttached runs docker wired to the terminal (logs -f, exec -it, compose, build)
+func (c *Client) runDockerAttached(args ...string) error {
+	_, err := c.Exec.Run(context.Background(), executor.Command{
+		Name:   "docker",
+		Args:   args,
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	})
+	return err
+}
+
+// ListContainers lists containers; only running ones when running is set
+func (c *Client) ListContainers(running bool) ([]Container, error) {
+	args := []string{"ps", "-a", "--format", jsonLines}
 	if running {
-		args = []string{"ps", "--format", "table {{.Names}}\t{{.Status}}\t{{.Ports}}"}
+		args = []string{"ps", "--format", jsonLines}
 	}
-	return c.execDocker(args...)
+	out, err := c.execDocker(args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseContainers(out)
 }
 
 // ListImages lists all Docker images
-func (c *Client) ListImages() (string, error) {
-	return c.execDocker("images", "--format", "table {{.Repository}}\t{{.Tag}}\t{{.Size}}\t{{.CreatedAt}}")
+func (c *Client) ListImages() ([]Image, error) {
+	out, err := c.execDocker("images", "--format", jsonLines)
+	if err != nil {
+		return nil, err
+	}
+	return parseImages(out)
 }
 
 // StopContainer stops a running container
@@ -64,12 +99,7 @@ func (c *Client) GetContainerLogs(containerID string, follow bool) error {
 		args = append(args, "-f")
 	}
 
-	cmd := exec.Command("docker", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-
-	return cmd.Run()
+	return c.runDockerAttached(args...)
 }
 
 // ExecInContainer executes a command in a container (interactive)
@@ -77,17 +107,16 @@ func (c *Client) ExecInContainer(containerID string, command []string) error {
 	args := []string{"exec", "-it", containerID}
 	args = append(args, command...)
 
-	cmd := exec.Command("docker", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-
-	return cmd.Run()
+	return c.runDockerAttached(args...)
 }
 
 // GetContainerStats retrieves container resource usage stats
-func (c *Client) GetContainerStats() (string, error) {
-	return c.execDocker("stats", "--no-stream", "--format", "table {{.Container}}\t{{.CPUPerc}}\t{{.MemUsage}}\t{{.MemPerc}}")
+func (c *Client) GetContainerStats() ([]ContainerStats, error) {
+	out, err := c.execDocker("stats", "--no-stream", "--format", jsonLines)
+	if err != nil {
+		return nil, err
+	}
+	return parseStats(out)
 }
 
 // GetContainerInspect retrieves detailed info about a container
@@ -116,22 +145,12 @@ func (c *Client) ComposeUp(detach bool) error {
 		args = append(args, "-d")
 	}
 
-	cmd := exec.Command("docker", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-
-	return cmd.Run()
+	return c.runDockerAttached(args...)
 }
 
 // ComposeDown stops Docker Compose services
 func (c *Client) ComposeDown() error {
-	cmd := exec.Command("docker", "compose", "down")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-
-	return cmd.Run()
+	return c.runDockerAttached("compose", "down")
 }
 
 // ComposeLogs streams Docker Compose logs
@@ -141,12 +160,7 @@ func (c *Client) ComposeLogs(follow bool) error {
 		args = append(args, "-f")
 	}
 
-	cmd := exec.Command("docker", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-
-	return cmd.Run()
+	return c.runDockerAttached(args...)
 }
 
 // ComposeStatus shows Docker Compose service status
@@ -163,22 +177,12 @@ func (c *Client) BuildImage(dockerfile, tag, context string) error {
 		args = append(args, ".")
 	}
 
-	cmd := exec.Command("docker", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-
-	return cmd.Run()
+	return c.runDockerAttached(args...)
 }
 
 // PullImage pulls an image from registry
 func (c *Client) PullImage(image string) error {
-	cmd := exec.Command("docker", "pull", image)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-
-	return cmd.Run()
+	return c.runDockerAttached("pull", image)
 }
 
 // RemoveImage removes a Docker image
@@ -2,6 +2,8 @@ package docker
 
 import (
 	"testing"
+
+	"github.com/yourusername/devops-mission-control/pkg/executor"
 )
 
 func TestNewClient(t *testing.T) {
@@ -42,3 +44,18 @@ func TestGetContainerStats(t *testing.T) {
 		t.Logf("docker not available or no containers: %v", err)
 	}
 }
+
+func TestListContainersParsesJSONLines(t *testing.T) {
+	fake := executor.NewFake()
+	fake.On("docker", "ps", "--format", "{{json .}}").Return(
+		`{"ID":"abc123","Names":"api","Image":"api:1.2","State":"running","Status":"Up 2 hours","Ports":"0.0.0.0:8080->80/tcp","RunningFor":"2 hours ago"}` + "\n" +
+			`{"ID":"def456","Names":"db","Image":"postgres:16","State":"running","Status":"Up 3 hours","Ports":"","RunningFor":"3 hours ago"}`)
+
+	containers, err := NewClient(WithExecutor(fake)).ListContainers(true)
+	if err != nil {
+		t.Fatalf("ListContainers: %v", err)
+	}
+	if len(containers) != 2 || containers[0].Name != "api" || containers[0].Ports != "0.0.0.0:8080->80/tcp" || containers[1].Image != "postgres:16" {
+		t.Fatalf("unexpected containers %+v", containers)
+	}
+}
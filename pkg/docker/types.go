@@ -0,0 +1,114 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Container is a row of `docker ps`.
+type Container struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Image   string `json:"image"`
+	State   string `json:"state"`
+	Status  string `json:"status"`
+	Ports   string `json:"ports,omitempty"`
+	Created string `json:"created"`
+}
+
+// Image is a row of `docker images`.
+type Image struct {
+	ID         string `json:"id"`
+	Repository string `json:"repository"`
+	Tag        string `json:"tag"`
+	Size       string `json:"size"`
+	Created    string `json:"created"`
+}
+
+// ContainerStats is a row of `docker stats --no-stream`.
+type ContainerStats struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	CPUPerc  string `json:"cpu_percent"`
+	MemUsage string `json:"mem_usage"`
+	MemPerc  string `json:"mem_percent"`
+	NetIO    string `json:"net_io"`
+	BlockIO  string `json:"block_io"`
+}
+
+// jsonLines is the --format value that makes docker print one JSON object per row.
+const jsonLines = "{{json .}}"
+
+// decodeLines decodes docker's one-object-per-line output.
+func decodeLines[T any](data, what string) ([]T, error) {
+	var rows []T
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var row T
+		if err := json.Unmarshal([]byte(line), &row); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", what, err)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func parseContainers(data string) ([]Container, error) {
+	rows, err := decodeLines[struct {
+		ID, Names, Image, State, Status, Ports, RunningFor string
+	}](data, "containers")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Container, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, Container{
+			ID:      row.ID,
+			Name:    row.Names,
+			Image:   row.Image,
+			State:   row.State,
+			Status:  row.Status,
+			Ports:   row.Ports,
+			Created: row.RunningFor,
+		})
+	}
+	return out, nil
+}
+
+func parseImages(data string) ([]Image, error) {
+	rows, err := decodeLines[struct {
+		ID, Repository, Tag, Size, CreatedSince string
+	}](data, "images")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Image, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, Image{
+			ID:         row.ID,
+			Repository: row.Repository,
+			Tag:        row.Tag,
+			Size:       row.Size,
+			Created:    row.CreatedSince,
+		})
+	}
+	return out, nil
+}
+
+func parseStats(data string) ([]ContainerStats, error) {
+	rows, err := decodeLines[struct {
+		ID, Name, CPUPerc, MemUsage, MemPerc, NetIO, BlockIO string
+	}](data, "stats")
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ContainerStats, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, ContainerStats(row))
+	}
+	return out, nil
+}
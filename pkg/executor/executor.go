@@ -0,0 +1,107 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Command describes a single invocation of an external tool (kubectl, aws, ...).
+type Command struct {
+	Name string
+	Args []string
+	// Dir is the working directory; empty means the current directory.
+	Dir string
+	// Env holds extra KEY=VALUE pairs appended to the current environment.
+	Env []string
+	// Stdin, when set, is connected to the process standard input.
+	Stdin io.Reader
+	// Stdout and Stderr, when set, receive output as it is produced instead of
+	// the Result, so long sessions are not held in memory. An *os.File is
+	// handed to the process as-is, keeping a terminal a terminal.
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// String renders the command line for logs and error messages.
+func (c Command) String() string {
+	return strings.TrimSpace(c.Name + " " + strings.Join(c.Args, " "))
+}
+
+// Result holds the captured output of a finished command.
+type Result struct {
+	Stdout   []byte
+	Stderr   []byte
+	ExitCode int
+}
+
+// Executor runs external commands. Every tool client accepts one so tests can
+// swap in a Fake instead of shelling out to real binaries.
+type Executor interface {
+	Run(ctx context.Context, cmd Command) (*Result, error)
+}
+
+// OSExecutor runs commands on the local machine via os/exec.
+type OSExecutor struct{}
+
+// New returns the default executor backed by os/exec.
+func New() Executor {
+	return OSExecutor{}
+}
+
+// Run executes cmd and waits for it to finish. The returned Result is never nil.
+func (OSExecutor) Run(ctx context.Context, cmd Command) (*Result, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	c := exec.CommandContext(ctx, cmd.Name, cmd.Args...)
+	c.Dir = cmd.Dir
+	if len(cmd.Env) > 0 {
+		c.Env = append(os.Environ(), cmd.Env...)
+	}
+	c.Stdin = cmd.Stdin
+
+	var out, errOut bytes.Buffer
+	c.Stdout = outputWriter(&out, cmd.Stdout)
+	c.Stderr = outputWriter(&errOut, cmd.Stderr)
+
+	err := c.Run()
+	res := &Result{Stdout: out.Bytes(), Stderr: errOut.Bytes()}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			res.ExitCode = exitErr.ExitCode()
+		} else {
+			res.ExitCode = -1
+		}
+		return res, err
+	}
+	return res, nil
+}
+
+// outputWriter is where a stream of the process goes: w when the caller
+// supplied one, otherwise buf for the Result.
+func outputWriter(buf *bytes.Buffer, w io.Writer) io.Writer {
+	if w == nil {
+		return buf
+	}
+	return w
+}
+
+// ErrorText returns the most useful description of a failed run: the command's
+// stderr when present, otherwise the error returned by Run.
+func ErrorText(res *Result, err error) string {
+	if res != nil {
+		if s := strings.TrimSpace(string(res.Stderr)); s != "" {
+			return s
+		}
+	}
+	if err != nil {
+		return err.Error()
+	}
+	return ""
+}
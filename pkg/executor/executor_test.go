@@ -0,0 +1,107 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOSExecutorStreamsOrCaptures(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	var streamed bytes.Buffer
+	res, err := New().Run(context.Background(), Command{
+		Name:   "sh",
+		Args:   []string{"-c", "echo $GREETING; cat"},
+		Env:    []string{"GREETING=hello"},
+		Stdin:  strings.NewReader("from-stdin"),
+		Stdout: &streamed,
+	})
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if got := streamed.String(); got != "hello\nfrom-stdin" {
+		t.Fatalf("unexpected streamed stdout %q", got)
+	}
+	if len(res.Stdout) != 0 {
+		t.Fatalf("streamed output was also captured: %q", res.Stdout)
+	}
+
+	res, err = New().Run(context.Background(), Command{Name: "sh", Args: []string{"-c", "echo captured"}})
+	if err != nil || string(res.Stdout) != "captured\n" {
+		t.Fatalf("unexpected captured stdout %q: %v", res.Stdout, err)
+	}
+}
+
+func TestOSExecutorExitCode(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	res, err := New().Run(context.Background(), Command{Name: "sh", Args: []string{"-c", "echo boom >&2; exit 3"}})
+	if err == nil {
+		t.Fatal("expected error for non-zero exit")
+	}
+	if res.ExitCode != 3 {
+		t.Fatalf("expected exit code 3, got %d", res.ExitCode)
+	}
+	if ErrorText(res, err) != "boom" {
+		t.Fatalf("unexpected error text %q", ErrorText(res, err))
+	}
+}
+
+func TestFakeServesInOrderAndRecordsCalls(t *testing.T) {
+	f := NewFake()
+	f.On("kubectl", "get", "pods").Return("first")
+	f.On("kubectl", "get", "pods").Return("second")
+	f.On("kubectl", "delete", "pod", "x").Fail("not found", 1)
+
+	for _, want := range []string{"first", "second", "second"} {
+		res, err := f.Run(context.Background(), Command{Name: "kubectl", Args: []string{"get", "pods"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(res.Stdout) != want {
+			t.Fatalf("expected %q, got %q", want, res.Stdout)
+		}
+	}
+	res, err := f.Run(context.Background(), Command{Name: "kubectl", Args: []string{"delete", "pod", "x"}})
+	if err == nil || res.ExitCode != 1 || string(res.Stderr) != "not found" {
+		t.Fatalf("expected failure, got res=%+v err=%v", res, err)
+	}
+	if _, err := f.Run(context.Background(), Command{Name: "aws"}); err == nil {
+		t.Fatal("expected error for unregistered command")
+	}
+	if n := len(f.Calls()); n != 5 {
+		t.Fatalf("expected 5 recorded calls, got %d", n)
+	}
+}
+
+func TestRecorderRoundTrip(t *testing.T) {
+	inner := NewFake()
+	inner.On("gcloud", "config", "list").Return("project = demo")
+
+	rec := NewRecorder(inner)
+	if _, err := rec.Run(context.Background(), Command{Name: "gcloud", Args: []string{"config", "list"}}); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := rec.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	replay, err := LoadFake(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := replay.Run(context.Background(), Command{Name: "gcloud", Args: []string{"config", "list"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(res.Stdout) != "project = demo" {
+		t.Fatalf("unexpected replayed stdout %q", res.Stdout)
+	}
+}
@@ -0,0 +1,191 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Interaction is a recorded command together with the output it produced.
+// A list of interactions (a "cassette") can be saved to disk by a Recorder
+// and replayed later by a Fake.
+type Interaction struct {
+	Name     string   `json:"name"`
+	Args     []string `json:"args"`
+	Stdout   string   `json:"stdout,omitempty"`
+	Stderr   string   `json:"stderr,omitempty"`
+	ExitCode int      `json:"exit_code,omitempty"`
+}
+
+func (i Interaction) key() string {
+	return i.Name + "\x00" + strings.Join(i.Args, "\x00")
+}
+
+// Return sets the stdout served for this interaction.
+func (i *Interaction) Return(stdout string) *Interaction {
+	i.Stdout = stdout
+	return i
+}
+
+// Fail makes the interaction exit with the given stderr and exit code.
+func (i *Interaction) Fail(stderr string, exitCode int) *Interaction {
+	i.Stderr = stderr
+	if exitCode == 0 {
+		exitCode = 1
+	}
+	i.ExitCode = exitCode
+	return i
+}
+
+// Fake is an Executor that serves canned responses and records every call.
+// Responses registered for the same command line are served in order; the
+// last one is repeated once the queue is exhausted.
+type Fake struct {
+	mu        sync.Mutex
+	responses map[string][]*Interaction
+	served    map[string]int
+	calls     []Command
+}
+
+// NewFake returns an empty Fake.
+func NewFake() *Fake {
+	return &Fake{
+		responses: make(map[string][]*Interaction),
+		served:    make(map[string]int),
+	}
+}
+
+// LoadFake builds a Fake from a cassette file written by Recorder.Save.
+func LoadFake(path string) (*Fake, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var list []Interaction
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %w", path, err)
+	}
+	f := NewFake()
+	for _, i := range list {
+		f.Add(i)
+	}
+	return f, nil
+}
+
+// On registers a response for the exact command line name+args and returns it
+// so the caller can chain Return or Fail.
+func (f *Fake) On(name string, args ...string) *Interaction {
+	i := &Interaction{Name: name, Args: args}
+	f.mu.Lock()
+	f.responses[i.key()] = append(f.responses[i.key()], i)
+	f.mu.Unlock()
+	return i
+}
+
+// Add registers a prepared interaction.
+func (f *Fake) Add(i Interaction) {
+	cp := i
+	f.mu.Lock()
+	f.responses[cp.key()] = append(f.responses[cp.key()], &cp)
+	f.mu.Unlock()
+}
+
+// Calls returns the commands run so far, in order.
+func (f *Fake) Calls() []Command {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Command{}, f.calls...)
+}
+
+// Run serves the canned response for cmd. Commands with no registered response
+// fail with exit code 127, like a missing binary would.
+func (f *Fake) Run(ctx context.Context, cmd Command) (*Result, error) {
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return &Result{ExitCode: -1}, err
+		}
+	}
+	key := Interaction{Name: cmd.Name, Args: cmd.Args}.key()
+
+	f.mu.Lock()
+	f.calls = append(f.calls, cmd)
+	queue := f.responses[key]
+	var resp *Interaction
+	if len(queue) > 0 {
+		n := f.served[key]
+		if n >= len(queue) {
+			n = len(queue) - 1
+		}
+		resp = queue[n]
+		f.served[key] = n + 1
+	}
+	f.mu.Unlock()
+
+	if resp == nil {
+		msg := "no canned response for: " + cmd.String()
+		return &Result{Stderr: []byte(msg), ExitCode: 127}, fmt.Errorf("executor: %s", msg)
+	}
+
+	res := &Result{Stdout: []byte(resp.Stdout), Stderr: []byte(resp.Stderr), ExitCode: resp.ExitCode}
+	if cmd.Stdout != nil && resp.Stdout != "" {
+		_, _ = cmd.Stdout.Write(res.Stdout)
+	}
+	if cmd.Stderr != nil && resp.Stderr != "" {
+		_, _ = cmd.Stderr.Write(res.Stderr)
+	}
+	if resp.ExitCode != 0 {
+		return res, fmt.Errorf("exit status %d", resp.ExitCode)
+	}
+	return res, nil
+}
+
+// Recorder wraps another Executor and records every interaction so real tool
+// output can be captured once and replayed with LoadFake.
+type Recorder struct {
+	Next Executor
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder records the commands run through next.
+func NewRecorder(next Executor) *Recorder {
+	if next == nil {
+		next = New()
+	}
+	return &Recorder{Next: next}
+}
+
+// Run delegates to the wrapped executor and records the outcome.
+func (r *Recorder) Run(ctx context.Context, cmd Command) (*Result, error) {
+	res, err := r.Next.Run(ctx, cmd)
+	i := Interaction{Name: cmd.Name, Args: append([]string{}, cmd.Args...)}
+	if res != nil {
+		i.Stdout = string(res.Stdout)
+		i.Stderr = string(res.Stderr)
+		i.ExitCode = res.ExitCode
+	}
+	r.mu.Lock()
+	r.interactions = append(r.interactions, i)
+	r.mu.Unlock()
+	return res, err
+}
+
+// Interactions returns everything recorded so far.
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction{}, r.interactions...)
+}
+
+// Save writes the recorded interactions to path as a JSON cassette.
+func (r *Recorder) Save(path string) error {
+	data, err := json.MarshalIndent(r.Interactions(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -1,30 +1,48 @@
 package gcp
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"os/exec"
 	"strings"
+
+	"github.com/yourusername/devops-mission-control/pkg/executor"
 )
 
 // Client wraps Google Cloud operations
 type Client struct {
 	Project string
 	Region  string
+	// Exec runs gcloud; defaults to the local OS executor.
+	Exec executor.Executor
+}
+
+// Option configures the GCP client.
+type Option func(*Client)
+
+// WithExecutor sets the executor used to run gcloud.
+func WithExecutor(e executor.Executor) Option {
+	return func(c *Client) { c.Exec = e }
 }
 
 // NewClient creates a new GCP client
-func NewClient(project, region string) *Client {
-	if project == "" {
-		project = getDefaultProject()
-	}
-	if region == "" {
-		region = "us-central1"
-	}
-	return &Client{
+func NewClient(project, region string, opts ...Option) *Client {
+	c := &Client{
 		Project: project,
 		Region:  region,
 	}
+	for _, o := range opts {
+		o(c)
+	}
+	if c.Exec == nil {
+		c.Exec = executor.New()
+	}
+	if c.Project == "" {
+		c.Project = getDefaultProject(c.Exec)
+	}
+	if c.Region == "" {
+		c.Region = "us-central1"
+	}
+	return c
 }
 
 // execGcloud runs a gcloud command and returns output
@@ -33,31 +51,29 @@ func (c *Client) execGcloud(args ...string) (string, error) {
 	fullArgs := []string{"--project=" + c.Project}
 	fullArgs = append(fullArgs, args...)
 
-	cmd := exec.Command("gcloud", fullArgs...)
-	var out, errOut bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &errOut
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("gcloud error: %s", errOut.String())
+	res, err := c.Exec.Run(context.Background(), executor.Command{Name: "gcloud", Args: fullArgs})
+	if err != nil {
+		return "", fmt.Errorf("gcloud error: %s", executor.ErrorText(res, err))
 	}
-	return strings.TrimSpace(out.String()), nil
+	return strings.TrimSpace(string(res.Stdout)), nil
 }
 
 // getDefaultProject gets the default GCP project
-func getDefaultProject() string {
-	cmd := exec.Command("gcloud", "config", "get-value", "project")
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	if err := cmd.Run(); err != nil {
+func getDefaultProject(e executor.Executor) string {
+	res, err := e.Run(context.Background(), executor.Command{Name: "gcloud", Args: []string{"config", "get-value", "project"}})
+	if err != nil {
 		return ""
 	}
-	return strings.TrimSpace(out.String())
+	return strings.TrimSpace(string(res.Stdout))
 }
 
 // Compute Engine operations
-func (c *Client) ListInstances() (string, error) {
-	return c.execGcloud("compute", "instances", "list", "--format=table(name,zone,machineType,status)")
+func (c *Client) ListInstances() ([]Instance, error) {
+	out, err := c.execGcloud("compute", "instances", "list", "--format=json")
+	if err != nil {
+		return nil, err
+	}
+	return parseInstances(out)
 }
 
 func (c *Client) DescribeInstance(name string) (string, error) {
@@ -81,8 +97,12 @@ func (c *Client) ListImages() (string, error) {
 }
 
 // Cloud Storage operations
-func (c *Client) ListBuckets() (string, error) {
-	return c.execGcloud("storage", "buckets", "list", "--format=table(name,location,storageClass)")
+func (c *Client) ListBuckets() ([]Bucket, error) {
+	out, err := c.execGcloud("storage", "buckets", "list", "--format=json")
+	if err != nil {
+		return nil, err
+	}
+	return parseBuckets(out)
 }
 
 func (c *Client) ListBucketContents(bucket string) (string, error) {
@@ -105,8 +125,12 @@ func (c *Client) GetBucketSize(bucket string) (string, error) {
 }
 
 // Cloud SQL operations
-func (c *Client) ListSQLInstances() (string, error) {
-	return c.execGcloud("sql", "instances", "list", "--format=table(name,databaseVersion,state,ipAddresses[0].ipAddress)")
+func (c *Client) ListSQLInstances() ([]SQLInstance, error) {
+	out, err := c.execGcloud("sql", "instances", "list", "--format=json")
+	if err != nil {
+		return nil, err
+	}
+	return parseSQLInstances(out)
 }
 
 func (c *Client) DescribeSQLInstance(name string) (string, error) {
@@ -122,8 +146,12 @@ func (c *Client) StopSQLInstance(name string) (string, error) {
 }
 
 // Cloud Run operations
-func (c *Client) ListCloudRunServices() (string, error) {
-	return c.execGcloud("run", "services", "list", "--region="+c.Region, "--format=table(metadata.name,status.url,status.conditions[0].lastTransitionTime)")
+func (c *Client) ListCloudRunServices() ([]RunService, error) {
+	out, err := c.execGcloud("run", "services", "list", "--region="+c.Region, "--format=json")
+	if err != nil {
+		return nil, err
+	}
+	return parseRunServices(out)
 }
 
 func (c *Client) DescribeCloudRunService(name string) (string, error) {
@@ -140,8 +168,12 @@ func (c *Client) GetDatasetSize(dataset string) (string, error) {
 }
 
 // IAM operations
-func (c *Client) ListServiceAccounts() (string, error) {
-	return c.execGcloud("iam", "service-accounts", "list", "--format=table(email,displayName,disabled)")
+func (c *Client) ListServiceAccounts() ([]ServiceAccount, error) {
+	out, err := c.execGcloud("iam", "service-accounts", "list", "--format=json")
+	if err != nil {
+		return nil, err
+	}
+	return parseServiceAccounts(out)
 }
 
 func (c *Client) ListIAMBindings() (string, error) {
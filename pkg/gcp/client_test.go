@@ -2,6 +2,8 @@ package gcp
 
 import (
 	"testing"
+
+	"github.com/yourusername/devops-mission-control/pkg/executor"
 )
 
 func TestNewClient(t *testing.T) {
@@ -68,3 +70,23 @@ func TestGetProjectInfo(t *testing.T) {
 		t.Logf("gcloud not available: %v", err)
 	}
 }
+
+func TestListInstancesParsesJSON(t *testing.T) {
+	fake := executor.NewFake()
+	fake.On("gcloud", "--project=demo", "compute", "instances", "list", "--format=json").Return(`[{
+		"name": "vm-1",
+		"zone": "https://www.googleapis.com/compute/v1/projects/demo/zones/us-central1-a",
+		"machineType": "https://www.googleapis.com/compute/v1/projects/demo/zones/us-central1-a/machineTypes/e2-small",
+		"status": "RUNNING",
+		"networkInterfaces": [{"networkIP": "10.128.0.2", "accessConfigs": [{"natIP": "34.1.2.3"}]}]
+	}]`)
+
+	instances, err := NewClient("demo", "", WithExecutor(fake)).ListInstances()
+	if err != nil {
+		t.Fatalf("ListInstances: %v", err)
+	}
+	want := Instance{Name: "vm-1", Zone: "us-central1-a", MachineType: "e2-small", Status: "RUNNING", InternalIP: "10.128.0.2", ExternalIP: "34.1.2.3"}
+	if len(instances) != 1 || instances[0] != want {
+		t.Fatalf("unexpected instances %+v", instances)
+	}
+}
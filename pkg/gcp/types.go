@@ -0,0 +1,211 @@
+package gcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+)
+
+// Instance is a summary of a Compute Engine instance.
+type Instance struct {
+	Name        string    `json:"name"`
+	Zone        string    `json:"zone"`
+	MachineType string    `json:"machine_type"`
+	Status      string    `json:"status"`
+	InternalIP  string    `json:"internal_ip,omitempty"`
+	ExternalIP  string    `json:"external_ip,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Bucket is a Cloud Storage bucket.
+type Bucket struct {
+	Name         string    `json:"name"`
+	Location     string    `json:"location"`
+	StorageClass string    `json:"storage_class"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// SQLInstance is a summary of a Cloud SQL instance.
+type SQLInstance struct {
+	Name            string `json:"name"`
+	DatabaseVersion string `json:"database_version"`
+	Region          string `json:"region"`
+	Tier            string `json:"tier,omitempty"`
+	State           string `json:"state"`
+	IP              string `json:"ip,omitempty"`
+}
+
+// RunService is a Cloud Run service.
+type RunService struct {
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	Ready     string    `json:"ready"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ServiceAccount is an IAM service account.
+type ServiceAccount struct {
+	Email       string `json:"email"`
+	DisplayName string `json:"display_name,omitempty"`
+	Disabled    bool   `json:"disabled"`
+}
+
+func decode(data, what string, v any) error {
+	if data == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(data), v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", what, err)
+	}
+	return nil
+}
+
+// parseInstances converts `gcloud compute instances list --format=json`.
+func parseInstances(data string) ([]Instance, error) {
+	var items []struct {
+		Name              string    `json:"name"`
+		Zone              string    `json:"zone"`
+		MachineType       string    `json:"machineType"`
+		Status            string    `json:"status"`
+		CreationTimestamp time.Time `json:"creationTimestamp"`
+		NetworkInterfaces []struct {
+			NetworkIP     string `json:"networkIP"`
+			AccessConfigs []struct {
+				NatIP string `json:"natIP"`
+			} `json:"accessConfigs"`
+		} `json:"networkInterfaces"`
+	}
+	if err := decode(data, "instances", &items); err != nil {
+		return nil, err
+	}
+	out := make([]Instance, 0, len(items))
+	for _, item := range items {
+		inst := Instance{
+			Name:        item.Name,
+			Zone:        path.Base(item.Zone),
+			MachineType: path.Base(item.MachineType),
+			Status:      item.Status,
+			CreatedAt:   item.CreationTimestamp,
+		}
+		if len(item.NetworkInterfaces) > 0 {
+			nic := item.NetworkInterfaces[0]
+			inst.InternalIP = nic.NetworkIP
+			if len(nic.AccessConfigs) > 0 {
+				inst.ExternalIP = nic.AccessConfigs[0].NatIP
+			}
+		}
+		out = append(out, inst)
+	}
+	return out, nil
+}
+
+// parseBuckets converts `gcloud storage buckets list --format=json`.
+func parseBuckets(data string) ([]Bucket, error) {
+	var items []struct {
+		Name                string    `json:"name"`
+		Location            string    `json:"location"`
+		DefaultStorageClass string    `json:"default_storage_class"`
+		CreationTime        time.Time `json:"creation_time"`
+	}
+	if err := decode(data, "buckets", &items); err != nil {
+		return nil, err
+	}
+	out := make([]Bucket, 0, len(items))
+	for _, item := range items {
+		out = append(out, Bucket{
+			Name:         item.Name,
+			Location:     item.Location,
+			StorageClass: item.DefaultStorageClass,
+			CreatedAt:    item.CreationTime,
+		})
+	}
+	return out, nil
+}
+
+// parseSQLInstances converts `gcloud sql instances list --format=json`.
+func parseSQLInstances(data string) ([]SQLInstance, error) {
+	var items []struct {
+		Name            string `json:"name"`
+		DatabaseVersion string `json:"databaseVersion"`
+		Region          string `json:"region"`
+		State           string `json:"state"`
+		Settings        struct {
+			Tier string `json:"tier"`
+		} `json:"settings"`
+		IPAddresses []struct {
+			IPAddress string `json:"ipAddress"`
+		} `json:"ipAddresses"`
+	}
+	if err := decode(data, "sql instances", &items); err != nil {
+		return nil, err
+	}
+	out := make([]SQLInstance, 0, len(items))
+	for _, item := range items {
+		inst := SQLInstance{
+			Name:            item.Name,
+			DatabaseVersion: item.DatabaseVersion,
+			Region:          item.Region,
+			Tier:            item.Settings.Tier,
+			State:           item.State,
+		}
+		if len(item.IPAddresses) > 0 {
+			inst.IP = item.IPAddresses[0].IPAddress
+		}
+		out = append(out, inst)
+	}
+	return out, nil
+}
+
+// parseRunServices converts `gcloud run services list --format=json`.
+func parseRunServices(data string) ([]RunService, error) {
+	var items []struct {
+		Metadata struct {
+			Name              string    `json:"name"`
+			CreationTimestamp time.Time `json:"creationTimestamp"`
+		} `json:"metadata"`
+		Status struct {
+			URL        string `json:"url"`
+			Conditions []struct {
+				Type   string `json:"type"`
+				Status string `json:"status"`
+			} `json:"conditions"`
+		} `json:"status"`
+	}
+	if err := decode(data, "run services", &items); err != nil {
+		return nil, err
+	}
+	out := make([]RunService, 0, len(items))
+	for _, item := range items {
+		svc := RunService{
+			Name:      item.Metadata.Name,
+			URL:       item.Status.URL,
+			Ready:     "Unknown",
+			CreatedAt: item.Metadata.CreationTimestamp,
+		}
+		for _, cond := range item.Status.Conditions {
+			if cond.Type == "Ready" {
+				svc.Ready = cond.Status
+			}
+		}
+		out = append(out, svc)
+	}
+	return out, nil
+}
+
+// parseServiceAccounts converts `gcloud iam service-accounts list --format=json`.
+func parseServiceAccounts(data string) ([]ServiceAccount, error) {
+	var items []struct {
+		Email       string `json:"email"`
+		DisplayName string `json:"displayName"`
+		Disabled    bool   `json:"disabled"`
+	}
+	if err := decode(data, "service accounts", &items); err != nil {
+		return nil, err
+	}
+	out := make([]ServiceAccount, 0, len(items))
+	for _, item := range items {
+		out = append(out, ServiceAccount(item))
+	}
+	return out, nil
+}
@@ -1,32 +1,47 @@
 package git
 
 import (
-	"bytes"
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
+
+	"github.com/yourusername/devops-mission-control/pkg/executor"
 )
 
 // Client wraps Git operations
-type Client struct{}
+type Client struct {
+	// Exec runs git; defaults to the local OS executor.
+	Exec executor.Executor
+}
+
+// Option configures the Git client.
+type Option func(*Client)
+
+// WithExecutor sets the executor used to run git.
+func WithExecutor(e executor.Executor) Option {
+	return func(c *Client) { c.Exec = e }
+}
 
 // NewClient creates a new Git client
-func NewClient() *Client {
-	return &Client{}
+func NewClient(opts ...Option) *Client {
+	c := &Client{}
+	for _, o := range opts {
+		o(c)
+	}
+	if c.Exec == nil {
+		c.Exec = executor.New()
+	}
+	return c
 }
 
 // execGit runs a git command and returns output
 func (c *Client) execGit(args ...string) (string, error) {
-	cmd := exec.Command("git", args...)
-	var out, errOut bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &errOut
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("git error: %s", errOut.String())
+	res, err := c.Exec.Run(context.Background(), executor.Command{Name: "git", Args: args})
+	if err != nil {
+		return "", fmt.Errorf("git error: %s", executor.ErrorText(res, err))
 	}
-	return strings.TrimSpace(out.String()), nil
+	return strings.TrimSpace(string(res.Stdout)), nil
 }
 
 // GetStatus returns repository status
@@ -181,11 +196,14 @@ func (c *Client) UndoLastCommit() (string, error) {
 
 // RevertCommit reverts a commit
 func (c *Client) RevertCommit(commitSHA string) error {
-	cmd := exec.Command("git", "revert", commitSHA, "--no-edit")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-	return cmd.Run()
+	_, err := c.Exec.Run(context.Background(), executor.Command{
+		Name:   "git",
+		Args:   []string{"revert", commitSHA, "--no-edit"},
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	})
+	return err
 }
 
 // CleanWorktree cleans untracked files
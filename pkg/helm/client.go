@@ -1,45 +1,63 @@
 package helm
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"os/exec"
 	"strings"
+
+	"github.com/yourusername/devops-mission-control/pkg/executor"
 )
 
 // Client wraps Helm operations
 type Client struct {
 	Namespace string
+	// Exec runs helm; defaults to the local OS executor.
+	Exec executor.Executor
+}
+
+// Option configures the Helm client.
+type Option func(*Client)
+
+// WithExecutor sets the executor used to run helm.
+func WithExecutor(e executor.Executor) Option {
+	return func(c *Client) { c.Exec = e }
 }
 
 // NewClient creates a new Helm client
-func NewClient(namespace string) *Client {
+func NewClient(namespace string, opts ...Option) *Client {
 	if namespace == "" {
 		namespace = "default"
 	}
-	return &Client{
+	c := &Client{
 		Namespace: namespace,
 	}
+	for _, o := range opts {
+		o(c)
+	}
+	if c.Exec == nil {
+		c.Exec = executor.New()
+	}
+	return c
 }
 
 // execHelm runs a helm command and returns output
 func (c *Client) execHelm(args ...string) (string, error) {
 	fullArgs := append([]string{"-n", c.Namespace}, args...)
 
-	cmd := exec.Command("helm", fullArgs...)
-	var out, errOut bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &errOut
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("helm error: %s", errOut.String())
+	res, err := c.Exec.Run(context.Background(), executor.Command{Name: "helm", Args: fullArgs})
+	if err != nil {
+		return "", fmt.Errorf("helm error: %s", executor.ErrorText(res, err))
 	}
-	return strings.TrimSpace(out.String()), nil
+	return strings.TrimSpace(string(res.Stdout)), nil
 }
 
 // Repository operations
-func (c *Client) RepoList() (string, error) {
-	return c.execHelm("repo", "list")
+func (c *Client) RepoList() ([]Repo, error) {
+	out, err := c.execHelm("repo", "list", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	return decodeList[Repo](out, "repositories")
 }
 
 func (c *Client) RepoAdd(name, url string) (string, error) {
@@ -55,8 +73,12 @@ func (c *Client) RepoUpdate() (string, error) {
 }
 
 // Chart operations
-func (c *Client) SearchChart(name string) (string, error) {
-	return c.execHelm("search", "hub", name)
+func (c *Client) SearchChart(name string) ([]Chart, error) {
+	out, err := c.execHelm("search", "hub", name, "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	return decodeList[Chart](out, "charts")
 }
 
 func (c *Client) ShowChart(chart string) (string, error) {
@@ -68,8 +90,12 @@ func (c *Client) ShowValues(chart string) (string, error) {
 }
 
 // Release operations
-func (c *Client) ListReleases() (string, error) {
-	return c.execHelm("list")
+func (c *Client) ListReleases() ([]Release, error) {
+	out, err := c.execHelm("list", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	return decodeList[Release](out, "releases")
 }
 
 func (c *Client) GetRelease(name string) (string, error) {
@@ -105,8 +131,12 @@ func (c *Client) RollbackRelease(name string, revision int) (string, error) {
 }
 
 // Release history
-func (c *Client) GetReleaseHistory(name string) (string, error) {
-	return c.execHelm("history", name)
+func (c *Client) GetReleaseHistory(name string) ([]Revision, error) {
+	out, err := c.execHelm("history", name, "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	return decodeList[Revision](out, "history")
 }
 
 // Template operations
@@ -2,6 +2,8 @@ package helm
 
 import (
 	"testing"
+
+	"github.com/yourusername/devops-mission-control/pkg/executor"
 )
 
 func TestNewClient(t *testing.T) {
@@ -57,3 +59,17 @@ func TestListPlugins(t *testing.T) {
 		t.Logf("helm not available: %v", err)
 	}
 }
+
+func TestListReleasesParsesJSON(t *testing.T) {
+	fake := executor.NewFake()
+	fake.On("helm", "-n", "web", "list", "-o", "json").
+		Return(`[{"name":"api","namespace":"web","revision":"3","updated":"2024-01-02 03:04:05 +0000 UTC","status":"deployed","chart":"api-1.4.0","app_version":"1.4.0"}]`)
+
+	releases, err := NewClient("web", WithExecutor(fake)).ListReleases()
+	if err != nil {
+		t.Fatalf("ListReleases: %v", err)
+	}
+	if len(releases) != 1 || releases[0].Name != "api" || releases[0].Revision != "3" || releases[0].Chart != "api-1.4.0" {
+		t.Fatalf("unexpected releases %+v", releases)
+	}
+}
@@ -0,0 +1,57 @@
+package helm
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Release is a row of `helm list`.
+type Release struct {
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+	Revision   string `json:"revision"`
+	Updated    string `json:"updated"`
+	Status     string `json:"status"`
+	Chart      string `json:"chart"`
+	AppVersion string `json:"app_version"`
+}
+
+// Repo is a configured chart repository.
+type Repo struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// Revision is a row of `helm history`.
+type Revision struct {
+	Revision    int    `json:"revision"`
+	Updated     string `json:"updated"`
+	Status      string `json:"status"`
+	Chart       string `json:"chart"`
+	AppVersion  string `json:"app_version"`
+	Description string `json:"description"`
+}
+
+// Chart is a search result from Artifact Hub.
+type Chart struct {
+	URL         string `json:"url"`
+	Version     string `json:"version"`
+	AppVersion  string `json:"app_version"`
+	Description string `json:"description"`
+}
+
+// helm prints the same snake_case keys the structs above use, so -o json
+// output decodes directly.
+func decodeList[T any](data, what string) ([]T, error) {
+	out := []T{}
+	if data == "" || data == "null" {
+		return out, nil
+	}
+	if err := json.Unmarshal([]byte(data), &out); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", what, err)
+	}
+	if out == nil {
+		out = []T{}
+	}
+	return out, nil
+}
@@ -1,28 +1,46 @@
 package k8s
 
 import (
-	"bytes"
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
+
+	"github.com/yourusername/devops-mission-control/pkg/executor"
 )
 
 // Client wraps kubectl operations
 type Client struct {
 	Namespace string
 	Context   string
+	// Exec runs kubectl; defaults to the local OS executor.
+	Exec executor.Executor
+}
+
+// Option configures the Kubernetes client.
+type Option func(*Client)
+
+// WithExecutor sets the executor used to run kubectl.
+func WithExecutor(e executor.Executor) Option {
+	return func(c *Client) { c.Exec = e }
 }
 
 // NewClient creates a new Kubernetes client
-func NewClient(namespace, context string) *Client {
+func NewClient(namespace, context string, opts ...Option) *Client {
 	if namespace == "" {
 		namespace = "default"
 	}
-	return &Client{
+	c := &Client{
 		Namespace: namespace,
 		Context:   context,
 	}
+	for _, o := range opts {
+		o(c)
+	}
+	if c.Exec == nil {
+		c.Exec = executor.New()
+	}
+	return c
 }
 
 // execKubectl runs a kubectl command and returns output
@@ -31,23 +49,38 @@ func (c *Client) execKubectl(args ...string) (string, error) {
 		args = append([]string{"--context", c.Context}, args...)
 	}
 
-	cmd := exec.Command("kubectl", args...)
-	var out, errOut bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &errOut
+	res, err := c.Exec.Run(context.Background(), executor.Command{Name: "kubectl", Args: args})
+	if err != nil {
+		return "", fmt.Errorf("kubectl error: %s", executor.ErrorText(res, err))
+	}
+	return strings.TrimSpace(string(res.Stdout)), nil
+}
 
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("kubectl error: %s", errOut.String())
+// runKubectlAttached runs kubectl wired to the terminal (logs -f, exec -it, port-forward)
+func (c *Client) runKubectlAttached(args ...string) error {
+	if c.Context != "" {
+		args = append([]string{"--context", c.Context}, args...)
 	}
-	return strings.TrimSpace(out.String()), nil
+	_, err := c.Exec.Run(context.Background(), executor.Command{
+		Name:   "kubectl",
+		Args:   args,
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	})
+	return err
 }
 
 // ListPods lists pods in a namespace
-func (c *Client) ListPods(namespace string) (string, error) {
+func (c *Client) ListPods(namespace string) ([]Pod, error) {
 	if namespace == "" {
 		namespace = c.Namespace
 	}
-	return c.execKubectl("get", "pods", "-n", namespace, "-o", "wide")
+	out, err := c.execKubectl("get", "pods", "-n", namespace, "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	return parsePods(out)
 }
 
 // GetPodLogs retrieves logs from a pod
@@ -60,33 +93,40 @@ func (c *Client) GetPodLogs(podName, namespace string, follow bool) error {
 		args = append(args, "-f")
 	}
 
-	cmd := exec.Command("kubectl", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-
-	return cmd.Run()
+	return c.runKubectlAttached(args...)
 }
 
 // ListDeployments lists deployments in a namespace
-func (c *Client) ListDeployments(namespace string) (string, error) {
+func (c *Client) ListDeployments(namespace string) ([]Deployment, error) {
 	if namespace == "" {
 		namespace = c.Namespace
 	}
-	return c.execKubectl("get", "deployments", "-n", namespace, "-o", "wide")
+	out, err := c.execKubectl("get", "deployments", "-n", namespace, "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	return parseDeployments(out)
 }
 
 // ListServices lists services in a namespace
-func (c *Client) ListServices(namespace string) (string, error) {
+func (c *Client) ListServices(namespace string) ([]Service, error) {
 	if namespace == "" {
 		namespace = c.Namespace
 	}
-	return c.execKubectl("get", "services", "-n", namespace, "-o", "wide")
+	out, err := c.execKubectl("get", "services", "-n", namespace, "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	return parseServices(out)
 }
 
 // ListNodes lists all cluster nodes
-func (c *Client) ListNodes() (string, error) {
-	return c.execKubectl("get", "nodes", "-o", "wide")
+func (c *Client) ListNodes() ([]Node, error) {
+	out, err := c.execKubectl("get", "nodes", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	return parseNodes(out)
 }
 
 // GetCurrentContext returns the current cluster context
@@ -145,11 +185,15 @@ func (c *Client) GetEvents(namespace string) (string, error) {
 }
 
 // GetPodsByLabel gets pods matching a label selector
-func (c *Client) GetPodsByLabel(selector, namespace string) (string, error) {
+func (c *Client) GetPodsByLabel(selector, namespace string) ([]Pod, error) {
 	if namespace == "" {
 		namespace = c.Namespace
 	}
-	return c.execKubectl("get", "pods", "-n", namespace, "-l", selector, "-o", "wide")
+	out, err := c.execKubectl("get", "pods", "-n", namespace, "-l", selector, "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	return parsePods(out)
 }
 
 // ExecInPod executes a command in a pod (interactive)
@@ -165,12 +209,7 @@ func (c *Client) ExecInPod(podName, namespace, container string, command []strin
 	args = append(args, "--")
 	args = append(args, command...)
 
-	cmd := exec.Command("kubectl", args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-
-	return cmd.Run()
+	return c.runKubectlAttached(args...)
 }
 
 // PortForward forwards a local port to a pod
@@ -179,12 +218,7 @@ func (c *Client) PortForward(podName, namespace, ports string) error {
 		namespace = c.Namespace
 	}
 
-	cmd := exec.Command("kubectl", "port-forward", podName, ports, "-n", namespace)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-
-	return cmd.Run()
+	return c.runKubectlAttached("port-forward", podName, ports, "-n", namespace)
 }
 
 // GetClusterInfo gets cluster information
@@ -2,6 +2,9 @@ package k8s
 
 import (
 	"testing"
+	"time"
+
+	"github.com/yourusername/devops-mission-control/pkg/executor"
 )
 
 func TestNewClient(t *testing.T) {
@@ -73,3 +76,98 @@ func TestCheckClusterHealth(t *testing.T) {
 		t.Error("health map should not be nil")
 	}
 }
+
+const podsJSON = `{"items":[{
+  "metadata":{"name":"api-0","namespace":"web","creationTimestamp":"2024-01-02T03:04:05Z",
+    "ownerReferences":[{"kind":"StatefulSet","name":"api","controller":true}]},
+  "spec":{"nodeName":"node-a","containers":[{"name":"api","image":"api:1.2"},{"name":"proxy","image":"envoy:1.30"}]},
+  "status":{"phase":"Running","podIP":"10.0.0.7","containerStatuses":[
+    {"name":"api","ready":true,"restartCount":2,"state":{"running":{}}},
+    {"name":"proxy","ready":false,"restartCount":1,"state":{"waiting":{"reason":"CrashLoopBackOff"}}}]}
+}]}`
+
+func TestListPodsWithFakeExecutor(t *testing.T) {
+	fake := executor.NewFake()
+	fake.On("kubectl", "--context", "prod", "get", "pods", "-n", "web", "-o", "json").Return(podsJSON)
+
+	client := NewClient("web", "prod", WithExecutor(fake))
+	pods, err := client.ListPods("")
+	if err != nil {
+		t.Fatalf("ListPods: %v", err)
+	}
+	if len(pods) != 1 {
+		t.Fatalf("expected 1 pod, got %d", len(pods))
+	}
+	p := pods[0]
+	if p.Name != "api-0" || p.Namespace != "web" || p.Node != "node-a" || p.IP != "10.0.0.7" {
+		t.Fatalf("unexpected pod %+v", p)
+	}
+	if p.Ready != "1/2" || p.Restarts != 3 || p.Status != "CrashLoopBackOff" {
+		t.Fatalf("unexpected pod status %+v", p)
+	}
+	if p.Owner != "StatefulSet/api" || len(p.Images) != 2 || p.Images[1] != "envoy:1.30" {
+		t.Fatalf("unexpected pod owner/images %+v", p)
+	}
+	if !p.CreatedAt.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Fatalf("unexpected creation time %v", p.CreatedAt)
+	}
+	if calls := fake.Calls(); len(calls) != 1 || calls[0].Name != "kubectl" {
+		t.Fatalf("unexpected calls %+v", calls)
+	}
+}
+
+func TestListDeploymentsServicesNodesWithFakeExecutor(t *testing.T) {
+	fake := executor.NewFake()
+	fake.On("kubectl", "get", "deployments", "-n", "default", "-o", "json").Return(`{"items":[{
+		"metadata":{"name":"web","namespace":"default"},
+		"spec":{"replicas":3,"selector":{"matchLabels":{"app":"web"}},"template":{"spec":{"containers":[{"name":"web","image":"nginx:1.25"}]}}},
+		"status":{"readyReplicas":2,"updatedReplicas":3,"availableReplicas":2}}]}`)
+	fake.On("kubectl", "get", "services", "-n", "default", "-o", "json").Return(`{"items":[{
+		"metadata":{"name":"web","namespace":"default"},
+		"spec":{"type":"NodePort","clusterIP":"10.96.0.10","ports":[{"port":80,"nodePort":30080,"protocol":"TCP"}]}}]}`)
+	fake.On("kubectl", "get", "nodes", "-o", "json").Return(`{"items":[{
+		"metadata":{"name":"node-a","labels":{"node-role.kubernetes.io/control-plane":""}},
+		"spec":{"unschedulable":true},
+		"status":{"conditions":[{"type":"Ready","status":"True"}],
+		  "addresses":[{"type":"InternalIP","address":"192.168.1.10"}],
+		  "nodeInfo":{"kubeletVersion":"v1.29.1","containerRuntimeVersion":"containerd://1.7.2"}}}]}`)
+
+	client := NewClient("", "", WithExecutor(fake))
+	deps, err := client.ListDeployments("")
+	if err != nil {
+		t.Fatalf("ListDeployments: %v", err)
+	}
+	if len(deps) != 1 || deps[0].Replicas != 3 || deps[0].Ready != 2 || deps[0].Selector["app"] != "web" || deps[0].Images[0] != "nginx:1.25" {
+		t.Fatalf("unexpected deployments %+v", deps)
+	}
+
+	svcs, err := client.ListServices("")
+	if err != nil {
+		t.Fatalf("ListServices: %v", err)
+	}
+	if len(svcs) != 1 || svcs[0].Type != "NodePort" || len(svcs[0].Ports) != 1 || svcs[0].Ports[0] != "80:30080/TCP" {
+		t.Fatalf("unexpected services %+v", svcs)
+	}
+
+	nodes, err := client.ListNodes()
+	if err != nil {
+		t.Fatalf("ListNodes: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].Status != "Ready,SchedulingDisabled" || nodes[0].InternalIP != "192.168.1.10" {
+		t.Fatalf("unexpected nodes %+v", nodes)
+	}
+	if len(nodes[0].Roles) != 1 || nodes[0].Roles[0] != "control-plane" {
+		t.Fatalf("unexpected node roles %+v", nodes[0].Roles)
+	}
+}
+
+func TestExecKubectlSurfacesStderr(t *testing.T) {
+	fake := executor.NewFake()
+	fake.On("kubectl", "delete", "pod", "ghost", "-n", "default").Fail(`pods "ghost" not found`, 1)
+
+	client := NewClient("", "", WithExecutor(fake))
+	_, err := client.DeletePod("ghost", "")
+	if err == nil || err.Error() != `kubectl error: pods "ghost" not found` {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,385 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Pod is a typed summary of a Kubernetes pod.
+type Pod struct {
+	Name      string    `json:"name"`
+	Namespace string    `json:"namespace"`
+	Status    string    `json:"status"`
+	Ready     string    `json:"ready"`
+	Restarts  int       `json:"restarts"`
+	IP        string    `json:"ip,omitempty"`
+	Node      string    `json:"node,omitempty"`
+	Images    []string  `json:"images,omitempty"`
+	Owner     string    `json:"owner,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Deployment is a typed summary of a Kubernetes deployment.
+type Deployment struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Replicas  int               `json:"replicas"`
+	Ready     int               `json:"ready"`
+	UpToDate  int               `json:"up_to_date"`
+	Available int               `json:"available"`
+	Images    []string          `json:"images,omitempty"`
+	Selector  map[string]string `json:"selector,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// Service is a typed summary of a Kubernetes service.
+type Service struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Type        string            `json:"type"`
+	ClusterIP   string            `json:"cluster_ip,omitempty"`
+	ExternalIPs []string          `json:"external_ips,omitempty"`
+	Ports       []string          `json:"ports,omitempty"`
+	Selector    map[string]string `json:"selector,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// Node is a typed summary of a Kubernetes node.
+type Node struct {
+	Name             string    `json:"name"`
+	Status           string    `json:"status"`
+	Roles            []string  `json:"roles,omitempty"`
+	Version          string    `json:"version"`
+	InternalIP       string    `json:"internal_ip,omitempty"`
+	OSImage          string    `json:"os_image,omitempty"`
+	KernelVersion    string    `json:"kernel_version,omitempty"`
+	ContainerRuntime string    `json:"container_runtime,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// Age returns how long ago the pod was created.
+func (p Pod) Age() time.Duration { return sinceCreated(p.CreatedAt) }
+
+// Age returns how long ago the deployment was created.
+func (d Deployment) Age() time.Duration { return sinceCreated(d.CreatedAt) }
+
+// Age returns how long ago the service was created.
+func (s Service) Age() time.Duration { return sinceCreated(s.CreatedAt) }
+
+// Age returns how long ago the node joined the cluster.
+func (n Node) Age() time.Duration { return sinceCreated(n.CreatedAt) }
+
+func sinceCreated(t time.Time) time.Duration {
+	if t.IsZero() {
+		return 0
+	}
+	return time.Since(t)
+}
+
+// The structs below mirror the subset of the Kubernetes API objects that
+// `kubectl get -o json` returns and that we need to build the summaries.
+
+type objectMeta struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace"`
+	Labels            map[string]string `json:"labels"`
+	CreationTimestamp time.Time         `json:"creationTimestamp"`
+	DeletionTimestamp *time.Time        `json:"deletionTimestamp"`
+	OwnerReferences   []struct {
+		Kind       string `json:"kind"`
+		Name       string `json:"name"`
+		Controller bool   `json:"controller"`
+	} `json:"ownerReferences"`
+}
+
+type container struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+}
+
+type containerState struct {
+	Waiting *struct {
+		Reason string `json:"reason"`
+	} `json:"waiting"`
+	Terminated *struct {
+		Reason   string `json:"reason"`
+		ExitCode int    `json:"exitCode"`
+	} `json:"terminated"`
+}
+
+type podList struct {
+	Items []struct {
+		Metadata objectMeta `json:"metadata"`
+		Spec     struct {
+			NodeName   string      `json:"nodeName"`
+			Containers []container `json:"containers"`
+		} `json:"spec"`
+		Status struct {
+			Phase             string `json:"phase"`
+			Reason            string `json:"reason"`
+			PodIP             string `json:"podIP"`
+			ContainerStatuses []struct {
+				Name         string         `json:"name"`
+				Ready        bool           `json:"ready"`
+				RestartCount int            `json:"restartCount"`
+				State        containerState `json:"state"`
+			} `json:"containerStatuses"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+type deploymentList struct {
+	Items []struct {
+		Metadata objectMeta `json:"metadata"`
+		Spec     struct {
+			Replicas *int `json:"replicas"`
+			Selector struct {
+				MatchLabels map[string]string `json:"matchLabels"`
+			} `json:"selector"`
+			Template struct {
+				Spec struct {
+					Containers []container `json:"containers"`
+				} `json:"spec"`
+			} `json:"template"`
+		} `json:"spec"`
+		Status struct {
+			ReadyReplicas     int `json:"readyReplicas"`
+			UpdatedReplicas   int `json:"updatedReplicas"`
+			AvailableReplicas int `json:"availableReplicas"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+type serviceList struct {
+	Items []struct {
+		Metadata objectMeta `json:"metadata"`
+		Spec     struct {
+			Type        string            `json:"type"`
+			ClusterIP   string            `json:"clusterIP"`
+			ExternalIPs []string          `json:"externalIPs"`
+			Selector    map[string]string `json:"selector"`
+			Ports       []struct {
+				Port       int    `json:"port"`
+				NodePort   int    `json:"nodePort"`
+				Protocol   string `json:"protocol"`
+				TargetPort any    `json:"targetPort"`
+			} `json:"ports"`
+		} `json:"spec"`
+		Status struct {
+			LoadBalancer struct {
+				Ingress []struct {
+					IP       string `json:"ip"`
+					Hostname string `json:"hostname"`
+				} `json:"ingress"`
+			} `json:"loadBalancer"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+type nodeList struct {
+	Items []struct {
+		Metadata objectMeta `json:"metadata"`
+		Spec     struct {
+			Unschedulable bool `json:"unschedulable"`
+		} `json:"spec"`
+		Status struct {
+			Conditions []struct {
+				Type   string `json:"type"`
+				Status string `json:"status"`
+			} `json:"conditions"`
+			Addresses []struct {
+				Type    string `json:"type"`
+				Address string `json:"address"`
+			} `json:"addresses"`
+			NodeInfo struct {
+				KubeletVersion          string `json:"kubeletVersion"`
+				OSImage                 string `json:"osImage"`
+				KernelVersion           string `json:"kernelVersion"`
+				ContainerRuntimeVersion string `json:"containerRuntimeVersion"`
+			} `json:"nodeInfo"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// parsePods converts `kubectl get pods -o json` output to Pod summaries.
+func parsePods(data string) ([]Pod, error) {
+	var list podList
+	if err := json.Unmarshal([]byte(data), &list); err != nil {
+		return nil, fmt.Errorf("failed to parse pods: %w", err)
+	}
+	pods := make([]Pod, 0, len(list.Items))
+	for _, item := range list.Items {
+		p := Pod{
+			Name:      item.Metadata.Name,
+			Namespace: item.Metadata.Namespace,
+			IP:        item.Status.PodIP,
+			Node:      item.Spec.NodeName,
+			Images:    images(item.Spec.Containers),
+			Owner:     owner(item.Metadata),
+			CreatedAt: item.Metadata.CreationTimestamp,
+		}
+
+		// status follows kubectl: a container-level reason beats the pod phase
+		status := item.Status.Phase
+		if item.Status.Reason != "" {
+			status = item.Status.Reason
+		}
+		ready := 0
+		for _, cs := range item.Status.ContainerStatuses {
+			p.Restarts += cs.RestartCount
+			if cs.Ready {
+				ready++
+			}
+			switch {
+			case cs.State.Waiting != nil && cs.State.Waiting.Reason != "":
+				status = cs.State.Waiting.Reason
+			case cs.State.Terminated != nil && cs.State.Terminated.Reason != "":
+				status = cs.State.Terminated.Reason
+			case cs.State.Terminated != nil:
+				status = fmt.Sprintf("ExitCode:%d", cs.State.Terminated.ExitCode)
+			}
+		}
+		if item.Metadata.DeletionTimestamp != nil {
+			status = "Terminating"
+		}
+		p.Status = status
+		p.Ready = fmt.Sprintf("%d/%d", ready, len(item.Spec.Containers))
+		pods = append(pods, p)
+	}
+	return pods, nil
+}
+
+// parseDeployments converts `kubectl get deployments -o json` output.
+func parseDeployments(data string) ([]Deployment, error) {
+	var list deploymentList
+	if err := json.Unmarshal([]byte(data), &list); err != nil {
+		return nil, fmt.Errorf("failed to parse deployments: %w", err)
+	}
+	out := make([]Deployment, 0, len(list.Items))
+	for _, item := range list.Items {
+		replicas := 1
+		if item.Spec.Replicas != nil {
+			replicas = *item.Spec.Replicas
+		}
+		out = append(out, Deployment{
+			Name:      item.Metadata.Name,
+			Namespace: item.Metadata.Namespace,
+			Replicas:  replicas,
+			Ready:     item.Status.ReadyReplicas,
+			UpToDate:  item.Status.UpdatedReplicas,
+			Available: item.Status.AvailableReplicas,
+			Images:    images(item.Spec.Template.Spec.Containers),
+			Selector:  item.Spec.Selector.MatchLabels,
+			CreatedAt: item.Metadata.CreationTimestamp,
+		})
+	}
+	return out, nil
+}
+
+// parseServices converts `kubectl get services -o json` output.
+func parseServices(data string) ([]Service, error) {
+	var list serviceList
+	if err := json.Unmarshal([]byte(data), &list); err != nil {
+		return nil, fmt.Errorf("failed to parse services: %w", err)
+	}
+	out := make([]Service, 0, len(list.Items))
+	for _, item := range list.Items {
+		svc := Service{
+			Name:        item.Metadata.Name,
+			Namespace:   item.Metadata.Namespace,
+			Type:        item.Spec.Type,
+			ClusterIP:   item.Spec.ClusterIP,
+			ExternalIPs: append([]string{}, item.Spec.ExternalIPs...),
+			Selector:    item.Spec.Selector,
+			CreatedAt:   item.Metadata.CreationTimestamp,
+		}
+		for _, ing := range item.Status.LoadBalancer.Ingress {
+			if ing.IP != "" {
+				svc.ExternalIPs = append(svc.ExternalIPs, ing.IP)
+			} else if ing.Hostname != "" {
+				svc.ExternalIPs = append(svc.ExternalIPs, ing.Hostname)
+			}
+		}
+		for _, port := range item.Spec.Ports {
+			s := fmt.Sprintf("%d", port.Port)
+			if port.NodePort != 0 {
+				s += fmt.Sprintf(":%d", port.NodePort)
+			}
+			if port.Protocol != "" {
+				s += "/" + port.Protocol
+			}
+			svc.Ports = append(svc.Ports, s)
+		}
+		out = append(out, svc)
+	}
+	return out, nil
+}
+
+// parseNodes converts `kubectl get nodes -o json` output.
+func parseNodes(data string) ([]Node, error) {
+	var list nodeList
+	if err := json.Unmarshal([]byte(data), &list); err != nil {
+		return nil, fmt.Errorf("failed to parse nodes: %w", err)
+	}
+	out := make([]Node, 0, len(list.Items))
+	for _, item := range list.Items {
+		n := Node{
+			Name:             item.Metadata.Name,
+			Status:           "Unknown",
+			Version:          item.Status.NodeInfo.KubeletVersion,
+			OSImage:          item.Status.NodeInfo.OSImage,
+			KernelVersion:    item.Status.NodeInfo.KernelVersion,
+			ContainerRuntime: item.Status.NodeInfo.ContainerRuntimeVersion,
+			CreatedAt:        item.Metadata.CreationTimestamp,
+		}
+		for _, cond := range item.Status.Conditions {
+			if cond.Type == "Ready" {
+				if cond.Status == "True" {
+					n.Status = "Ready"
+				} else {
+					n.Status = "NotReady"
+				}
+			}
+		}
+		if item.Spec.Unschedulable {
+			n.Status += ",SchedulingDisabled"
+		}
+		for _, addr := range item.Status.Addresses {
+			if addr.Type == "InternalIP" {
+				n.InternalIP = addr.Address
+			}
+		}
+		for label := range item.Metadata.Labels {
+			if role, ok := strings.CutPrefix(label, "node-role.kubernetes.io/"); ok && role != "" {
+				n.Roles = append(n.Roles, role)
+			}
+		}
+		sort.Strings(n.Roles)
+		out = append(out, n)
+	}
+	return out, nil
+}
+
+func images(containers []container) []string {
+	out := make([]string, 0, len(containers))
+	for _, c := range containers {
+		out = append(out, c.Image)
+	}
+	return out
+}
+
+// owner returns the controlling owner as Kind/name, e.g. "ReplicaSet/api-7d9f".
+func owner(meta objectMeta) string {
+	for _, ref := range meta.OwnerReferences {
+		if ref.Controller {
+			return ref.Kind + "/" + ref.Name
+		}
+	}
+	if len(meta.OwnerReferences) > 0 {
+		return meta.OwnerReferences[0].Kind + "/" + meta.OwnerReferences[0].Name
+	}
+	return ""
+}
@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"fmt"
+	"os"
 	"sync"
 	"time"
 )
@@ -41,16 +42,28 @@ type Alert struct {
 	Metadata   map[string]string `json:"metadata,omitempty"`
 }
 
-// MetricsStore holds all collected metrics and events
+// MetricsStore holds all collected metrics and events. With a Backend
+// (see OpenStore) it is a view of the newest MaxSize items of each kind in
+// the backend: writes go through to it, and reads reload the view once
+// another process has written.
+//
+// Metrics holds raw points only; older points are rolled up into buckets
+// according to Rollup and are reached through Query.
 type MetricsStore struct {
 	mu      sync.RWMutex
 	Metrics []Metric
 	Events  []Event
 	Alerts  []Alert
 	MaxSize int // Maximum number of items to store
+	Rollup  RollupPolicy
+
+	buckets   []Bucket
+	backend   Backend
+	version   int64
+	compacted time.Time
 }
 
-// NewMetricsStore creates a new metrics store
+// NewMetricsStore creates a new in-memory metrics store
 func NewMetricsStore(maxSize int) *MetricsStore {
 	if maxSize <= 0 {
 		maxSize = 10000
@@ -60,11 +73,103 @@ func NewMetricsStore(maxSize int) *MetricsStore {
 		Events:  make([]Event, 0, maxSize),
 		Alerts:  make([]Alert, 0, maxSize),
 		MaxSize: maxSize,
+		Rollup:  DefaultRollup,
+	}
+}
+
+// NewPersistentStore creates a store that writes through to b, loaded with
+// what b already holds.
+func NewPersistentStore(b Backend, maxSize int) (*MetricsStore, error) {
+	s := NewMetricsStore(maxSize)
+	s.backend = b
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.reloadLocked(); err != nil {
+		return nil, fmt.Errorf("metrics store load error: %s", err)
+	}
+	return s, nil
+}
+
+// Close closes the backend, if any.
+func (s *MetricsStore) Close() error {
+	if s.backend == nil {
+		return nil
+	}
+	return s.backend.Close()
+}
+
+// refresh reloads the view when another process has written to the
+// backend since the last load.
+func (s *MetricsStore) refresh() {
+	if s.backend == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, err := s.backend.Version(); err == nil && v == s.version {
+		return
+	}
+	if err := s.reloadLocked(); err != nil {
+		fmt.Fprintf(os.Stderr, "metrics store reload failed: %v\n", err)
+	}
+}
+
+func (s *MetricsStore) reloadLocked() error {
+	v, err := s.backend.Version()
+	if err != nil {
+		return err
+	}
+	d, err := s.backend.Load(s.MaxSize)
+	if err != nil {
+		return err
+	}
+	s.Metrics, s.Events, s.Alerts, s.buckets, s.version = d.Metrics, d.Events, d.Alerts, d.Buckets, v
+	return nil
+}
+
+// Compact rolls up raw points that are old enough and drops the oldest
+// items beyond MaxSize. Writes compact as they go; a long-running process
+// that may go quiet calls Compact on a timer so old points still age out.
+func (s *MetricsStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.compactLocked(time.Now())
+}
+
+// compactLocked compacts at most once per Rollup.Interval, unless the
+// store is over MaxSize.
+func (s *MetricsStore) compactLocked(now time.Time) error {
+	if s.backend != nil {
+		changed, err := s.backend.Compact(s.Rollup, s.MaxSize, now)
+		if err != nil {
+			return fmt.Errorf("metrics store error: %s", err)
+		}
+		if changed {
+			if err := s.reloadLocked(); err != nil {
+				return fmt.Errorf("metrics store error: %s", err)
+			}
+		}
+		return nil
+	}
+
+	if len(s.Events) > s.MaxSize {
+		s.Events = s.Events[len(s.Events)-s.MaxSize:]
+	}
+	if len(s.Alerts) > s.MaxSize {
+		s.Alerts = s.Alerts[len(s.Alerts)-s.MaxSize:]
+	}
+	if len(s.Metrics) <= s.MaxSize && now.Sub(s.compacted) < s.Rollup.Interval {
+		return nil
 	}
+	res := rollup(s.Metrics, s.buckets, s.Rollup, s.MaxSize, now)
+	s.Metrics = s.Metrics[res.drop:]
+	s.buckets = res.apply(s.buckets)
+	s.compacted = now
+	return nil
 }
 
 // RecordMetric adds a metric to the store
-func (s *MetricsStore) RecordMetric(name string, value float64, unit string, tags map[string]string) {
+func (s *MetricsStore) RecordMetric(name string, value float64, unit string, tags map[string]string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -76,35 +181,49 @@ func (s *MetricsStore) RecordMetric(name string, value float64, unit string, tag
 		Tags:      tags,
 	}
 
-	s.Metrics = append(s.Metrics, metric)
-	if len(s.Metrics) > s.MaxSize {
-		s.Metrics = s.Metrics[len(s.Metrics)-s.MaxSize:]
+	if s.backend != nil {
+		if err := s.backend.AddMetric(metric); err != nil {
+			return fmt.Errorf("metrics store error: %s", err)
+		}
 	}
+	s.Metrics = append(s.Metrics, metric)
+	return s.compactLocked(time.Now())
 }
 
 // RecordEvent adds an event to the store
-func (s *MetricsStore) RecordEvent(eventType, message, status string, duration time.Duration, metadata map[string]string) {
+func (s *MetricsStore) RecordEvent(eventType, message, status string, duration time.Duration, metadata map[string]string) error {
+	return s.AddEvent(Event{
+		Type:     eventType,
+		Message:  message,
+		Status:   status,
+		Duration: duration,
+		Metadata: metadata,
+	})
+}
+
+// AddEvent stores e, filling in its ID and timestamp when they are empty.
+func (s *MetricsStore) AddEvent(e Event) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	event := Event{
-		ID:        fmt.Sprintf("evt_%d", time.Now().UnixNano()),
-		Type:      eventType,
-		Message:   message,
-		Status:    status,
-		Timestamp: time.Now(),
-		Duration:  duration,
-		Metadata:  metadata,
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	if e.ID == "" {
+		e.ID = fmt.Sprintf("evt_%d", e.Timestamp.UnixNano())
 	}
 
-	s.Events = append(s.Events, event)
-	if len(s.Events) > s.MaxSize {
-		s.Events = s.Events[len(s.Events)-s.MaxSize:]
+	if s.backend != nil {
+		if err := s.backend.AddEvent(e); err != nil {
+			return fmt.Errorf("metrics store error: %s", err)
+		}
 	}
+	s.Events = append(s.Events, e)
+	return s.compactLocked(time.Now())
 }
 
 // CreateAlert creates a new alert
-func (s *MetricsStore) CreateAlert(name, severity, message string, metadata map[string]string) {
+func (s *MetricsStore) CreateAlert(name, severity, message string, metadata map[string]string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -118,22 +237,33 @@ func (s *MetricsStore) CreateAlert(name, severity, message string, metadata map[
 		Metadata:  metadata,
 	}
 
-	s.Alerts = append(s.Alerts, alert)
-	if len(s.Alerts) > s.MaxSize {
-		s.Alerts = s.Alerts[len(s.Alerts)-s.MaxSize:]
+	if s.backend != nil {
+		if err := s.backend.PutAlert(alert); err != nil {
+			return fmt.Errorf("metrics store error: %s", err)
+		}
 	}
+	s.Alerts = append(s.Alerts, alert)
+	return s.compactLocked(time.Now())
 }
 
 // ResolveAlert marks an alert as resolved
 func (s *MetricsStore) ResolveAlert(alertID string) error {
+	s.refresh()
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	for i := range s.Alerts {
 		if s.Alerts[i].ID == alertID {
-			s.Alerts[i].Resolved = true
+			a := s.Alerts[i]
+			a.Resolved = true
 			now := time.Now()
-			s.Alerts[i].ResolvedAt = &now
+			a.ResolvedAt = &now
+			if s.backend != nil {
+				if err := s.backend.PutAlert(a); err != nil {
+					return fmt.Errorf("metrics store error: %s", err)
+				}
+			}
+			s.Alerts[i] = a
 			return nil
 		}
 	}
@@ -142,6 +272,7 @@ func (s *MetricsStore) ResolveAlert(alertID string) error {
 
 // GetMetrics returns all metrics
 func (s *MetricsStore) GetMetrics() []Metric {
+	s.refresh()
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	return append([]Metric{}, s.Metrics...)
@@ -149,6 +280,7 @@ func (s *MetricsStore) GetMetrics() []Metric {
 
 // GetEvents returns all events
 func (s *MetricsStore) GetEvents() []Event {
+	s.refresh()
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	return append([]Event{}, s.Events...)
@@ -156,6 +288,7 @@ func (s *MetricsStore) GetEvents() []Event {
 
 // GetAlerts returns all alerts
 func (s *MetricsStore) GetAlerts() []Alert {
+	s.refresh()
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 	return append([]Alert{}, s.Alerts...)
@@ -163,6 +296,7 @@ func (s *MetricsStore) GetAlerts() []Alert {
 
 // GetActiveAlerts returns only unresolved alerts
 func (s *MetricsStore) GetActiveAlerts() []Alert {
+	s.refresh()
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -177,6 +311,7 @@ func (s *MetricsStore) GetActiveAlerts() []Alert {
 
 // GetMetricsByName returns metrics with a specific name
 func (s *MetricsStore) GetMetricsByName(name string) []Metric {
+	s.refresh()
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -191,6 +326,7 @@ func (s *MetricsStore) GetMetricsByName(name string) []Metric {
 
 // GetEventsByType returns events of a specific type
 func (s *MetricsStore) GetEventsByType(eventType string) []Event {
+	s.refresh()
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -203,17 +339,60 @@ func (s *MetricsStore) GetEventsByType(eventType string) []Event {
 	return result
 }
 
+// TenantKey is the tag, label or metadata key that puts a metric, event or
+// alert in a tenant. Items without it are global.
+const TenantKey = "tenant"
+
+// ForTenant returns a snapshot holding only the items of tenant, so a
+// tenant's dashboard never shows another tenant's data.
+func (s *MetricsStore) ForTenant(tenant string) *MetricsStore {
+	s.refresh()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := &MetricsStore{MaxSize: s.MaxSize, Rollup: s.Rollup}
+	for _, m := range s.Metrics {
+		if m.Tags[TenantKey] == tenant || m.Labels[TenantKey] == tenant {
+			out.Metrics = append(out.Metrics, m)
+		}
+	}
+	for _, b := range s.buckets {
+		if b.Tags[TenantKey] == tenant {
+			out.buckets = append(out.buckets, b)
+		}
+	}
+	for _, e := range s.Events {
+		if e.Metadata[TenantKey] == tenant {
+			out.Events = append(out.Events, e)
+		}
+	}
+	for _, a := range s.Alerts {
+		if a.Metadata[TenantKey] == tenant {
+			out.Alerts = append(out.Alerts, a)
+		}
+	}
+	return out
+}
+
 // Clear removes all data from the store
-func (s *MetricsStore) Clear() {
+func (s *MetricsStore) Clear() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if s.backend != nil {
+		if err := s.backend.Clear(); err != nil {
+			return fmt.Errorf("metrics store error: %s", err)
+		}
+	}
 	s.Metrics = []Metric{}
 	s.Events = []Event{}
 	s.Alerts = []Alert{}
+	s.buckets = nil
+	return nil
 }
 
 // GetStats returns summary statistics
 func (s *MetricsStore) GetStats() map[string]interface{} {
+	s.refresh()
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
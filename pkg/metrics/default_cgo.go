@@ -0,0 +1,6 @@
+//go:build cgo
+
+package metrics
+
+// DefaultStore is the backend used when metrics_store is not set.
+const DefaultStore = "sqlite:metrics.db"
@@ -0,0 +1,7 @@
+//go:build !cgo
+
+package metrics
+
+// DefaultStore is the backend used when metrics_store is not set. The
+// SQLite driver needs cgo, so builds without it keep metrics in memory.
+const DefaultStore = "memory"
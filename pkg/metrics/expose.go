@@ -0,0 +1,304 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Prometheus metric types.
+const (
+	TypeGauge   = "gauge"
+	TypeCounter = "counter"
+)
+
+// Content types of the exposition formats.
+const (
+	ContentTypeText        = "text/plain; version=0.0.4; charset=utf-8"
+	ContentTypeOpenMetrics = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// Namespace prefixes every exported metric name.
+const Namespace = "missionctl"
+
+// Family is a Prometheus metric family. Counter names end in _total.
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Unit    string
+	Samples []Sample
+}
+
+// Sample is one labelled value of a family; a zero Timestamp means now.
+type Sample struct {
+	Labels    map[string]string
+	Value     float64
+	Timestamp time.Time
+}
+
+// unitNames maps units as recorded to Prometheus base-unit suffixes.
+var unitNames = map[string]string{
+	"%":  "percent",
+	"s":  "seconds",
+	"ms": "milliseconds",
+	"B":  "bytes",
+}
+
+// promUnit returns the name suffix for unit, "" if it has none.
+func promUnit(unit string) string {
+	if u, ok := unitNames[unit]; ok {
+		return u
+	}
+	return strings.Trim(sanitize(strings.ToLower(unit), false), "_")
+}
+
+// sanitize replaces what Prometheus does not allow in metric names (colon
+// included) or, with label, label names.
+func sanitize(s string, label bool) string {
+	var b strings.Builder
+	for i, r := range s {
+		ok := r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9' || !label && r == ':'
+		if ok {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// metricName is the exported name of a stored metric: namespaced,
+// sanitized and suffixed with its unit.
+func metricName(name, unit string) string {
+	n := Namespace + "_" + sanitize(name, false)
+	if u := promUnit(unit); u != "" && !strings.HasSuffix(n, "_"+u) {
+		n += "_" + u
+	}
+	return n
+}
+
+// Families exports the store: the latest value of every series of the
+// stored metrics as gauges with their tags and labels as labels, the events
+// by type and status and the active alerts by severity. Event counts are
+// those of the events the store holds, so trimming shows up as a counter
+// reset.
+func (s *MetricsStore) Families() []Family {
+	s.refresh()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	byName := map[string]*Family{}
+	latest := map[string]int{} // family name + series -> sample index
+	for _, m := range s.Metrics {
+		name := metricName(m.Name, m.Unit)
+		f, ok := byName[name]
+		if !ok {
+			f = &Family{Name: name, Help: fmt.Sprintf("Recorded metric %s.", m.Name), Type: TypeGauge, Unit: promUnit(m.Unit)}
+			byName[name] = f
+		}
+		labels := promLabels(seriesTags(m))
+		key := name + "\x00" + tagsKey(labels)
+		sm := Sample{Labels: labels, Value: m.Value, Timestamp: m.Timestamp}
+		if i, ok := latest[key]; ok {
+			f.Samples[i] = sm
+			continue
+		}
+		latest[key] = len(f.Samples)
+		f.Samples = append(f.Samples, sm)
+	}
+	out := make([]Family, 0, len(byName)+2)
+	for _, f := range byName {
+		out = append(out, *f)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return append(out, s.statusFamiliesLocked()...)
+}
+
+// statusFamiliesLocked counts the events and active alerts.
+func (s *MetricsStore) statusFamiliesLocked() []Family {
+	events := map[[2]string]int{}
+	for _, e := range s.Events {
+		events[[2]string{e.Type, e.Status}]++
+	}
+	ev := Family{Name: Namespace + "_events_total", Help: "Events in the store by type and status.", Type: TypeCounter}
+	for k, n := range events {
+		ev.Samples = append(ev.Samples, Sample{Labels: map[string]string{"type": k[0], "status": k[1]}, Value: float64(n)})
+	}
+	active := map[string]int{}
+	for _, a := range s.Alerts {
+		if !a.Resolved {
+			active[a.Severity]++
+		}
+	}
+	al := Family{Name: Namespace + "_alerts_active", Help: "Unresolved alerts by severity.", Type: TypeGauge}
+	for sev, n := range active {
+		al.Samples = append(al.Samples, Sample{Labels: map[string]string{"severity": sev}, Value: float64(n)})
+	}
+	return []Family{ev, al}
+}
+
+// promLabels turns tags into valid, distinct label names, dropping the
+// reserved __ prefix.
+func promLabels(tags map[string]string) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(tags))
+	for k, v := range tags {
+		name := sanitize(k, true)
+		if strings.HasPrefix(name, "__") || name == "" {
+			continue
+		}
+		out[name] = v
+	}
+	return out
+}
+
+// CounterSet holds process-wide counters, such as auth denials, exported
+// next to the store's metrics.
+type CounterSet struct {
+	mu       sync.Mutex
+	families map[string]*Family
+	index    map[string]int // name + labels -> sample
+}
+
+// Counters is the process's CounterSet.
+var Counters = &CounterSet{}
+
+// Inc adds one to the counter name (ending in _total) with labels,
+// registering it with help on first use.
+func (c *CounterSet) Inc(name, help string, labels map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.families == nil {
+		c.families, c.index = map[string]*Family{}, map[string]int{}
+	}
+	f, ok := c.families[name]
+	if !ok {
+		f = &Family{Name: name, Help: help, Type: TypeCounter}
+		c.families[name] = f
+	}
+	key := name + "\x00" + tagsKey(labels)
+	if i, ok := c.index[key]; ok {
+		f.Samples[i].Value++
+		return
+	}
+	c.index[key] = len(f.Samples)
+	f.Samples = append(f.Samples, Sample{Labels: labels, Value: 1})
+}
+
+// Families returns a copy of the counters.
+func (c *CounterSet) Families() []Family {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make([]Family, 0, len(c.families))
+	for _, f := range c.families {
+		cp := *f
+		cp.Samples = append([]Sample{}, f.Samples...)
+		out = append(out, cp)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// WriteText writes families in the Prometheus text format (version 0.0.4).
+func WriteText(w io.Writer, families []Family) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		if len(f.Samples) == 0 {
+			continue
+		}
+		fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", f.Name, escapeHelp(f.Help), f.Name, f.Type)
+		for _, sm := range sortedSamples(f.Samples) {
+			writeSample(bw, f.Name, sm)
+			if !sm.Timestamp.IsZero() {
+				fmt.Fprintf(bw, " %d", sm.Timestamp.UnixMilli())
+			}
+			bw.WriteByte('\n')
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteOpenMetrics writes families in the OpenMetrics 1.0 text format.
+func WriteOpenMetrics(w io.Writer, families []Family) error {
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		if len(f.Samples) == 0 {
+			continue
+		}
+		name := f.Name
+		if f.Type == TypeCounter {
+			name = strings.TrimSuffix(name, "_total")
+		}
+		fmt.Fprintf(bw, "# TYPE %s %s\n", name, f.Type)
+		if f.Unit != "" && strings.HasSuffix(name, "_"+f.Unit) {
+			fmt.Fprintf(bw, "# UNIT %s %s\n", name, f.Unit)
+		}
+		fmt.Fprintf(bw, "# HELP %s %s\n", name, escapeValue(f.Help))
+		for _, sm := range sortedSamples(f.Samples) {
+			writeSample(bw, f.Name, sm)
+			if !sm.Timestamp.IsZero() {
+				fmt.Fprintf(bw, " %s", strconv.FormatFloat(float64(sm.Timestamp.UnixMilli())/1000, 'f', -1, 64))
+			}
+			bw.WriteByte('\n')
+		}
+	}
+	bw.WriteString("# EOF\n")
+	return bw.Flush()
+}
+
+func writeSample(w *bufio.Writer, name string, sm Sample) {
+	w.WriteString(name)
+	if len(sm.Labels) > 0 {
+		keys := make([]string, 0, len(sm.Labels))
+		for k := range sm.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		w.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, `%s="%s"`, k, escapeValue(sm.Labels[k]))
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatValue(sm.Value))
+}
+
+// sortedSamples orders samples by their labels so output is stable.
+func sortedSamples(samples []Sample) []Sample {
+	out := append([]Sample{}, samples...)
+	sort.SliceStable(out, func(i, j int) bool { return tagsKey(out[i].Labels) < tagsKey(out[j].Labels) })
+	return out
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	valueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeValue(s string) string { return valueEscaper.Replace(s) }
@@ -0,0 +1,186 @@
+package metrics
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+)
+
+func TestExposition(t *testing.T) {
+	s := NewMetricsStore(100)
+	at := time.UnixMilli(1700000000000)
+	s.Metrics = append(s.Metrics,
+		Metric{Name: "cpu", Value: 10, Unit: "%", Timestamp: at, Tags: map[string]string{"host": "web-1", "k8s.io/app": `say "hi"`}},
+		Metric{Name: "cpu", Value: 20, Unit: "%", Timestamp: at.Add(time.Second), Tags: map[string]string{"host": "web-1", "k8s.io/app": `say "hi"`}},
+		Metric{Name: "deploy.time", Value: 1.5, Unit: "s", Timestamp: at, Labels: map[string]string{"__name__": "x", "env": "prod"}})
+	s.Events = append(s.Events, Event{Type: "command", Status: "failure"}, Event{Type: "command", Status: "failure"}, Event{Type: "deployment", Status: "success"})
+	s.Alerts = append(s.Alerts, Alert{Severity: "critical"}, Alert{Severity: "critical", Resolved: true}, Alert{Severity: "warning"})
+
+	var text strings.Builder
+	if err := WriteText(&text, s.Families()); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# TYPE missionctl_cpu_percent gauge\n" +
+			`missionctl_cpu_percent{host="web-1",k8s_io_app="say \"hi\""} 20 1700000001000` + "\n",
+		`missionctl_deploy_time_seconds{env="prod"} 1.5 1700000000000`,
+		"# TYPE missionctl_events_total counter\n",
+		`missionctl_events_total{status="failure",type="command"} 2`,
+		`missionctl_alerts_active{severity="critical"} 1`,
+	} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("text format lacks %q:\n%s", want, text.String())
+		}
+	}
+	if strings.Contains(text.String(), "# EOF") || strings.Contains(text.String(), `__name__`) {
+		t.Errorf("text format:\n%s", text.String())
+	}
+
+	var om strings.Builder
+	if err := WriteOpenMetrics(&om, s.Families()); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# TYPE missionctl_cpu_percent gauge\n# UNIT missionctl_cpu_percent percent\n",
+		`missionctl_deploy_time_seconds{env="prod"} 1.5 1700000000` + "\n",
+		"# TYPE missionctl_events counter\n",
+		`missionctl_events_total{status="success",type="deployment"} 1`,
+	} {
+		if !strings.Contains(om.String(), want) {
+			t.Errorf("OpenMetrics lacks %q:\n%s", want, om.String())
+		}
+	}
+	if !strings.HasSuffix(om.String(), "# EOF\n") {
+		t.Errorf("OpenMetrics must end with # EOF:\n%s", om.String())
+	}
+
+	var c CounterSet
+	c.Inc("missionctl_denials_total", "Denials.", map[string]string{"reason": "forbidden"})
+	c.Inc("missionctl_denials_total", "Denials.", map[string]string{"reason": "forbidden"})
+	c.Inc("missionctl_denials_total", "Denials.", map[string]string{"reason": "no_actor"})
+	if f := c.Families(); len(f) != 1 || len(f[0].Samples) != 2 || f[0].Samples[0].Value != 2 {
+		t.Fatalf("counters: %+v", f)
+	}
+}
+
+func TestRemoteWrite(t *testing.T) {
+	var mu sync.Mutex
+	var got []map[string][]float64 // per request: series -> values
+	fail := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if fail {
+			fail = false
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Content-Encoding") != "snappy" || r.Header.Get("X-Prometheus-Remote-Write-Version") != "0.1.0" {
+			t.Errorf("headers: %v", r.Header)
+		}
+		body, _ := io.ReadAll(r.Body)
+		raw, err := snappy.Decode(nil, body)
+		if err != nil {
+			t.Errorf("snappy: %v", err)
+		}
+		got = append(got, decodeWriteRequest(t, raw))
+	}))
+	defer srv.Close()
+
+	s := NewMetricsStore(100)
+	if err := s.RecordMetric("cpu", 1, "%", map[string]string{"host": "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RecordMetric("cpu", 2, "%", map[string]string{"host": "a"}); err != nil {
+		t.Fatal(err)
+	}
+	s.Events = append(s.Events, Event{Type: "command", Status: "success"})
+	rw := NewRemoteWriter(srv.URL, s)
+	rw.RetryDelay = time.Millisecond
+	rw.MaxSamples = 2
+	rw.Extra = func() []Family {
+		return []Family{{Name: "missionctl_extra_total", Type: TypeCounter, Samples: []Sample{{Value: 7}}}}
+	}
+	// the 503 is retried
+	if err := rw.Push(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RecordMetric("cpu", 3, "%", map[string]string{"host": "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rw.Push(); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	// each push sends the new points, the events and the extra counter in
+	// requests of 2 samples
+	if len(got) != 4 {
+		t.Fatalf("requests: %+v", got)
+	}
+	cpu := `__name__=missionctl_cpu_percent,host=a`
+	if v := got[0][cpu]; len(v) != 2 || v[0] != 1 || v[1] != 2 {
+		t.Fatalf("first request: %+v", got[0])
+	}
+	if got[1]["__name__=missionctl_events_total,status=success,type=command"][0] != 1 || got[1]["__name__=missionctl_extra_total"][0] != 7 {
+		t.Fatalf("counters: %+v", got[1])
+	}
+	// the second push only has the new point
+	if v := append(got[2][cpu], got[3][cpu]...); len(v) != 1 || v[0] != 3 {
+		t.Fatalf("second push sent %v", v)
+	}
+}
+
+// decodeWriteRequest decodes the series of a WriteRequest as
+// "name=value,..." of their sorted labels -> sample values.
+func decodeWriteRequest(t *testing.T, b []byte) map[string][]float64 {
+	t.Helper()
+	out := map[string][]float64{}
+	for _, ts := range fields(t, b, 1) {
+		var labels []string
+		for _, l := range fields(t, ts, 1) {
+			kv := fields(t, l, 1, 2)
+			labels = append(labels, string(kv[0])+"="+string(kv[1]))
+		}
+		key := strings.Join(labels, ",")
+		for _, sm := range fields(t, ts, 2) {
+			if len(sm) < 9 || sm[0] != 1<<3|1 {
+				t.Fatalf("sample %x", sm)
+			}
+			out[key] = append(out[key], math.Float64frombits(binary.LittleEndian.Uint64(sm[1:9])))
+		}
+	}
+	return out
+}
+
+// fields returns the length-delimited fields of b with the given numbers,
+// in order.
+func fields(t *testing.T, b []byte, nums ...int) [][]byte {
+	t.Helper()
+	var out [][]byte
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 || tag&7 != 2 {
+			t.Fatalf("unexpected tag %d in %x", tag, b)
+		}
+		b = b[n:]
+		size, n := binary.Uvarint(b)
+		data := b[n : n+int(size)]
+		b = b[n+int(size):]
+		for _, want := range nums {
+			if int(tag>>3) == want {
+				out = append(out, data)
+			}
+		}
+	}
+	return out
+}
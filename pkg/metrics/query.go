@@ -0,0 +1,296 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Aggregations accepted by Query.Agg.
+const (
+	AggAvg   = "avg"
+	AggMin   = "min"
+	AggMax   = "max"
+	AggSum   = "sum"
+	AggCount = "count"
+	AggP50   = "p50"
+	AggP95   = "p95"
+	AggP99   = "p99"
+	AggRate  = "rate"
+)
+
+// Aggregations lists the aggregations for help text.
+const Aggregations = "avg|min|max|sum|count|p50|p95|p99|rate"
+
+// maxQueryPoints bounds the windows of one series.
+const maxQueryPoints = 11000
+
+// Query selects one metric over [From, To), optionally filtered and grouped
+// by tags, and aggregates it over Step windows, From rounded down to a
+// multiple of Step. Without Agg and Step it
+// returns the points as stored; older ones come back as the averages of
+// their rollup buckets.
+type Query struct {
+	Name    string
+	From    time.Time
+	To      time.Time
+	Filters []TagFilter
+	// GroupBy splits the result into one series per value of these tags;
+	// without it all matching points form one series.
+	GroupBy []string
+	Agg     string
+	Step    time.Duration
+}
+
+// TagFilter compares a tag with a glob pattern (path.Match syntax). A
+// missing tag matches the empty pattern.
+type TagFilter struct {
+	Key     string
+	Pattern string
+	Negate  bool
+}
+
+// ParseTagFilter parses key=pattern or key!=pattern.
+func ParseTagFilter(s string) (TagFilter, error) {
+	i := strings.Index(s, "=")
+	if i <= 0 {
+		return TagFilter{}, fmt.Errorf("invalid tag filter %q (expected key=value or key!=value)", s)
+	}
+	f := TagFilter{Key: s[:i], Pattern: s[i+1:]}
+	if strings.HasSuffix(f.Key, "!") {
+		f.Key, f.Negate = strings.TrimSuffix(f.Key, "!"), true
+	}
+	if f.Key == "" {
+		return TagFilter{}, fmt.Errorf("invalid tag filter %q (expected key=value or key!=value)", s)
+	}
+	if _, err := path.Match(f.Pattern, ""); err != nil {
+		return TagFilter{}, fmt.Errorf("invalid tag filter %q: %s", s, err)
+	}
+	return f, nil
+}
+
+// Match reports whether tags satisfy the filter.
+func (f TagFilter) Match(tags map[string]string) bool {
+	ok, _ := path.Match(f.Pattern, tags[f.Key])
+	return ok != f.Negate
+}
+
+// Point is one value of a series.
+type Point struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// Series is the result of a query for one group.
+type Series struct {
+	Name   string            `json:"name"`
+	Tags   map[string]string `json:"tags,omitempty"`
+	Points []Point           `json:"points"`
+}
+
+// validate fills in defaults and checks q.
+func (q *Query) validate(now time.Time) error {
+	if q.Name == "" {
+		return fmt.Errorf("query needs a metric name")
+	}
+	if q.To.IsZero() {
+		q.To = now
+	}
+	if q.From.IsZero() {
+		q.From = q.To.Add(-time.Hour)
+	}
+	if !q.From.Before(q.To) {
+		return fmt.Errorf("query range is empty: from %s is not before to %s", q.From.Format(time.RFC3339), q.To.Format(time.RFC3339))
+	}
+	switch q.Agg {
+	case "", AggAvg, AggMin, AggMax, AggSum, AggCount, AggP50, AggP95, AggP99, AggRate:
+	default:
+		return fmt.Errorf("unknown aggregation %q (expected %s)", q.Agg, Aggregations)
+	}
+	if q.Step < 0 {
+		return fmt.Errorf("step must be positive")
+	}
+	if q.Step > 0 {
+		if q.Agg == "" {
+			q.Agg = AggAvg
+		}
+		// align windows to the clock so repeated queries line up
+		q.From = q.From.Truncate(q.Step)
+		if n := q.To.Sub(q.From) / q.Step; n > maxQueryPoints {
+			return fmt.Errorf("step %s gives %d points per series (max %d)", q.Step, n, maxQueryPoints)
+		}
+	}
+	return nil
+}
+
+// sample is a raw point (Count 1) or a rollup bucket.
+type sample struct {
+	t time.Time
+	b Bucket
+}
+
+// Query runs q over the raw points and rollup buckets of the store.
+func (s *MetricsStore) Query(q Query) ([]Series, error) {
+	if err := q.validate(time.Now()); err != nil {
+		return nil, err
+	}
+	s.refresh()
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	groups := map[string]*[]sample{}
+	var order []string
+	collect := func(tags map[string]string, sm sample) {
+		if sm.t.Before(q.From) || !sm.t.Before(q.To) {
+			return
+		}
+		for _, f := range q.Filters {
+			if !f.Match(tags) {
+				return
+			}
+		}
+		key := groupKey(tags, q.GroupBy)
+		g, ok := groups[key]
+		if !ok {
+			g = &[]sample{}
+			groups[key] = g
+			order = append(order, key)
+		}
+		*g = append(*g, sm)
+	}
+	for _, b := range s.buckets {
+		if b.Name == q.Name {
+			collect(b.Tags, sample{t: b.Start, b: b})
+		}
+	}
+	for _, m := range s.Metrics {
+		if m.Name == q.Name {
+			v := m.Value
+			collect(seriesTags(m), sample{t: m.Timestamp, b: Bucket{Count: 1, Sum: v, Min: v, Max: v, First: v, Last: v}})
+		}
+	}
+
+	sort.Strings(order)
+	out := make([]Series, 0, len(order))
+	for _, key := range order {
+		samples := *groups[key]
+		sort.SliceStable(samples, func(i, j int) bool { return samples[i].t.Before(samples[j].t) })
+		series := Series{Name: q.Name, Tags: groupTags(key, q.GroupBy), Points: []Point{}}
+		switch {
+		case q.Agg == "":
+			for _, sm := range samples {
+				series.Points = append(series.Points, Point{Time: sm.t, Value: sm.b.Sum / float64(sm.b.Count)})
+			}
+		case q.Step == 0:
+			series.Points = append(series.Points, Point{Time: q.From, Value: aggregate(q.Agg, samples, q.To.Sub(q.From))})
+		default:
+			for i := 0; i < len(samples); {
+				start := q.From.Add(samples[i].t.Sub(q.From) / q.Step * q.Step)
+				j := i
+				for j < len(samples) && samples[j].t.Before(start.Add(q.Step)) {
+					j++
+				}
+				series.Points = append(series.Points, Point{Time: start, Value: aggregate(q.Agg, samples[i:j], q.Step)})
+				i = j
+			}
+		}
+		out = append(out, series)
+	}
+	return out, nil
+}
+
+// groupKey renders the group-by tag values of tags.
+func groupKey(tags map[string]string, by []string) string {
+	values := make([]string, len(by))
+	for i, k := range by {
+		values[i] = tags[k]
+	}
+	return strings.Join(values, "\x00")
+}
+
+func groupTags(key string, by []string) map[string]string {
+	if len(by) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(by))
+	for i, v := range strings.Split(key, "\x00") {
+		out[by[i]] = v
+	}
+	return out
+}
+
+// aggregate reduces samples, oldest first, that cover window.
+func aggregate(agg string, samples []sample, window time.Duration) float64 {
+	var total Bucket
+	for _, sm := range samples {
+		total.merge(sm.b)
+	}
+	switch agg {
+	case AggAvg:
+		return total.Sum / float64(total.Count)
+	case AggMin:
+		return total.Min
+	case AggMax:
+		return total.Max
+	case AggSum:
+		return total.Sum
+	case AggCount:
+		return float64(total.Count)
+	case AggP50:
+		return percentile(samples, 0.50)
+	case AggP95:
+		return percentile(samples, 0.95)
+	case AggP99:
+		return percentile(samples, 0.99)
+	case AggRate:
+		return increase(samples) / window.Seconds()
+	}
+	return math.NaN()
+}
+
+// percentile is the nearest-rank percentile of the samples, each bucket
+// counting as its average Count times; over rolled-up data it is an
+// estimate.
+func percentile(samples []sample, p float64) float64 {
+	type weighted struct {
+		v float64
+		n int
+	}
+	values := make([]weighted, 0, len(samples))
+	total := 0
+	for _, sm := range samples {
+		values = append(values, weighted{sm.b.Sum / float64(sm.b.Count), sm.b.Count})
+		total += sm.b.Count
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].v < values[j].v })
+	rank := int(math.Ceil(p * float64(total)))
+	seen := 0
+	for _, w := range values {
+		seen += w.n
+		if seen >= rank {
+			return w.v
+		}
+	}
+	return values[len(values)-1].v
+}
+
+// increase treats the samples as a counter and sums how much it grew,
+// counting a drop as a reset to zero.
+func increase(samples []sample) float64 {
+	var inc float64
+	for i, sm := range samples {
+		inc += sm.b.Last - sm.b.First
+		if i == 0 {
+			continue
+		}
+		if d := sm.b.First - samples[i-1].b.Last; d >= 0 {
+			inc += d
+		} else {
+			inc += sm.b.First
+		}
+	}
+	return inc
+}
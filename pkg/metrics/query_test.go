@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestQuery(t *testing.T) {
+	s := NewMetricsStore(100)
+	from := time.Now().Add(-15 * time.Minute).Truncate(5 * time.Minute)
+	for i := 0; i < 10; i++ {
+		at := from.Add(time.Duration(i) * time.Minute)
+		s.Metrics = append(s.Metrics,
+			Metric{Name: "cpu", Value: float64(i + 1), Timestamp: at, Tags: map[string]string{"host": "web-1"}},
+			Metric{Name: "cpu", Value: 100, Timestamp: at, Labels: map[string]string{"host": "db-1"}},
+			Metric{Name: "mem", Value: 50, Timestamp: at})
+	}
+	to := from.Add(10 * time.Minute)
+	web := TagFilter{Key: "host", Pattern: "web-*"}
+
+	series, err := s.Query(Query{Name: "cpu", From: from, To: to, Filters: []TagFilter{web}, Agg: AggAvg, Step: 5 * time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 1 || len(series[0].Points) != 2 || series[0].Points[0].Value != 3 || series[0].Points[1].Value != 8 ||
+		!series[0].Points[1].Time.Equal(from.Add(5*time.Minute)) {
+		t.Fatalf("avg over 5m steps: %+v", series)
+	}
+
+	for agg, want := range map[string]float64{AggMax: 10, AggMin: 1, AggSum: 55, AggCount: 10, AggP50: 5, AggP95: 10} {
+		series, err := s.Query(Query{Name: "cpu", From: from, To: to, Filters: []TagFilter{web}, Agg: agg})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := series[0].Points[0].Value; got != want {
+			t.Errorf("%s = %v, want %v", agg, got, want)
+		}
+	}
+
+	series, err = s.Query(Query{Name: "cpu", From: from, To: to, GroupBy: []string{"host"}, Agg: AggMax})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 2 || series[0].Tags["host"] != "db-1" || series[0].Points[0].Value != 100 || series[1].Points[0].Value != 10 {
+		t.Fatalf("group by host: %+v", series)
+	}
+
+	neg, _ := ParseTagFilter("host!=db-*")
+	series, _ = s.Query(Query{Name: "cpu", From: from, To: from.Add(2 * time.Minute), Filters: []TagFilter{neg}})
+	if len(series) != 1 || len(series[0].Points) != 2 || series[0].Points[1].Value != 2 {
+		t.Fatalf("raw points: %+v", series)
+	}
+
+	for _, q := range []Query{{}, {Name: "cpu", Agg: "median"}, {Name: "cpu", From: to, To: from}, {Name: "cpu", Step: time.Nanosecond}} {
+		if _, err := s.Query(q); err == nil {
+			t.Errorf("%+v should be rejected", q)
+		}
+	}
+	for _, bad := range []string{"host", "=web", "host=[", "!=web"} {
+		if _, err := ParseTagFilter(bad); err == nil {
+			t.Errorf("tag filter %q should be rejected", bad)
+		}
+	}
+}
+
+func TestQueryRate(t *testing.T) {
+	s := NewMetricsStore(100)
+	from := time.Now().Add(-time.Hour).Truncate(time.Minute)
+	// a counter that resets after 20
+	for i, v := range []float64{0, 10, 20, 5, 15} {
+		s.Metrics = append(s.Metrics, Metric{Name: "requests", Value: v, Timestamp: from.Add(time.Duration(i) * time.Minute)})
+	}
+	series, err := s.Query(Query{Name: "requests", From: from, To: from.Add(5 * time.Minute), Agg: AggRate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := series[0].Points[0].Value, 35.0/300; math.Abs(got-want) > 1e-9 {
+		t.Fatalf("rate = %v, want %v", got, want)
+	}
+}
+
+func TestRollup(t *testing.T) {
+	memory := NewMetricsStore(100)
+	b, err := OpenSQLiteBackend(filepath.Join(t.TempDir(), "metrics.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	persistent, err := NewPersistentStore(b, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer persistent.Close()
+
+	now := time.Now()
+	minute := now.Add(-90 * time.Minute).Truncate(time.Minute)
+	hour := now.Add(-30 * time.Hour).Truncate(time.Hour)
+	points := []Metric{
+		{Name: "cpu", Value: 7, Timestamp: now.Add(-40 * 24 * time.Hour)},
+		{Name: "cpu", Value: 4, Timestamp: hour.Add(time.Minute)},
+		{Name: "cpu", Value: 6, Timestamp: hour.Add(30 * time.Minute)},
+		{Name: "cpu", Value: 1, Timestamp: minute.Add(time.Second)},
+		{Name: "cpu", Value: 3, Timestamp: minute.Add(2 * time.Second)},
+		{Name: "cpu", Value: 9, Timestamp: now.Add(-time.Minute)},
+	}
+	memory.Metrics = append(memory.Metrics, points...)
+	for _, m := range points {
+		if err := b.AddMetric(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for name, s := range map[string]*MetricsStore{"memory": memory, "sqlite": persistent} {
+		if err := s.Compact(); err != nil {
+			t.Fatal(err)
+		}
+		if len(s.Metrics) != 1 || s.Metrics[0].Value != 9 {
+			t.Fatalf("%s: only the newest point should stay raw: %+v", name, s.Metrics)
+		}
+		if len(s.buckets) != 2 || s.buckets[0].Resolution != time.Hour || s.buckets[1].Resolution != time.Minute {
+			t.Fatalf("%s: buckets: %+v", name, s.buckets)
+		}
+
+		series, err := s.Query(Query{Name: "cpu", From: now.Add(-60 * 24 * time.Hour), To: now})
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := series[0].Points
+		if len(p) != 3 || !p[0].Time.Equal(hour) || p[0].Value != 5 || !p[1].Time.Equal(minute) || p[1].Value != 2 || p[2].Value != 9 {
+			t.Fatalf("%s: points: %+v", name, p)
+		}
+		series, _ = s.Query(Query{Name: "cpu", From: now.Add(-60 * 24 * time.Hour), To: now, Agg: AggCount})
+		if got := series[0].Points[0].Value; got != 5 {
+			t.Fatalf("%s: count = %v, want 5 (the 40-day-old point is past retention)", name, got)
+		}
+	}
+
+	// compacting again changes nothing
+	if changed, err := b.Compact(DefaultRollup, 100, now.Add(2*time.Minute)); err != nil || changed {
+		t.Fatalf("second compaction: changed=%v err=%v", changed, err)
+	}
+}
@@ -0,0 +1,259 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+)
+
+// RemoteWriter pushes a store to a Prometheus remote-write endpoint: every
+// raw point recorded since the last push, with its own timestamp, and the
+// event and alert counts of Families plus whatever Extra returns, stamped
+// with the time of the push. Network errors, 429 and 5xx responses are
+// retried with exponential backoff and the points are resent on the next
+// push; other failures drop them. Credentials in the URL are sent as basic
+// auth.
+type RemoteWriter struct {
+	URL   string
+	Store *MetricsStore
+	// Extra returns families to send with every push, e.g. Counters.Families.
+	Extra    func() []Family
+	Interval time.Duration
+	// MaxSamples bounds the samples of one request.
+	MaxSamples int
+	Retries    int
+	RetryDelay time.Duration
+	Client     *http.Client
+
+	mu   sync.Mutex
+	sent time.Time // timestamp of the newest raw point pushed
+}
+
+// NewRemoteWriter returns a writer pushing store to url every 30 seconds
+// in requests of up to 2000 samples, with three retries.
+func NewRemoteWriter(url string, store *MetricsStore) *RemoteWriter {
+	return &RemoteWriter{
+		URL:        url,
+		Store:      store,
+		Interval:   30 * time.Second,
+		MaxSamples: 2000,
+		Retries:    3,
+		RetryDelay: 500 * time.Millisecond,
+		Client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Run pushes every Interval until done is closed, then pushes once more.
+func (rw *RemoteWriter) Run(done <-chan struct{}) {
+	t := time.NewTicker(rw.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-done:
+			if err := rw.Push(); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+			}
+			return
+		case <-t.C:
+			if err := rw.Push(); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+			}
+		}
+	}
+}
+
+// series is one remote-write time series; labels include __name__.
+type series struct {
+	labels  map[string]string
+	samples []Sample
+}
+
+// Push sends what was recorded since the last push.
+func (rw *RemoteWriter) Push() error {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	now := time.Now()
+	byKey := map[string]*series{}
+	var order []string
+	add := func(name string, sm Sample) {
+		labels := map[string]string{"__name__": name}
+		for k, v := range sm.Labels {
+			labels[k] = v
+		}
+		key := tagsKey(labels)
+		s, ok := byKey[key]
+		if !ok {
+			s = &series{labels: labels}
+			byKey[key] = s
+			order = append(order, key)
+		}
+		if sm.Timestamp.IsZero() {
+			sm.Timestamp = now
+		}
+		s.samples = append(s.samples, sm)
+	}
+
+	newest := rw.sent
+	rw.Store.refresh()
+	rw.Store.mu.RLock()
+	for _, m := range rw.Store.Metrics {
+		if !m.Timestamp.After(rw.sent) {
+			continue
+		}
+		add(metricName(m.Name, m.Unit), Sample{Labels: promLabels(seriesTags(m)), Value: m.Value, Timestamp: m.Timestamp})
+		if m.Timestamp.After(newest) {
+			newest = m.Timestamp
+		}
+	}
+	families := rw.Store.statusFamiliesLocked()
+	rw.Store.mu.RUnlock()
+	if rw.Extra != nil {
+		families = append(families, rw.Extra()...)
+	}
+	for _, f := range families {
+		for _, sm := range f.Samples {
+			add(f.Name, sm)
+		}
+	}
+
+	all := make([]series, 0, len(order))
+	for _, key := range order {
+		s := byKey[key]
+		// remote write wants each series in time order
+		sort.SliceStable(s.samples, func(i, j int) bool { return s.samples[i].Timestamp.Before(s.samples[j].Timestamp) })
+		all = append(all, *s)
+	}
+	for _, batch := range splitSeries(all, rw.MaxSamples) {
+		retry, err := rw.send(batch)
+		if err != nil {
+			if !retry {
+				rw.sent = newest
+			}
+			return err
+		}
+	}
+	rw.sent = newest
+	return nil
+}
+
+// splitSeries cuts all into requests of at most max samples.
+func splitSeries(all []series, max int) [][]series {
+	if max <= 0 {
+		return [][]series{all}
+	}
+	var out [][]series
+	var cur []series
+	n := 0
+	for _, s := range all {
+		for len(s.samples) > 0 {
+			take := min(len(s.samples), max-n)
+			cur = append(cur, series{labels: s.labels, samples: s.samples[:take]})
+			s.samples = s.samples[take:]
+			if n += take; n == max {
+				out, cur, n = append(out, cur), nil, 0
+			}
+		}
+	}
+	if len(cur) > 0 {
+		out = append(out, cur)
+	}
+	return out
+}
+
+// send posts one request with retries and reports whether the failure, if
+// any, was worth retrying.
+func (rw *RemoteWriter) send(batch []series) (retry bool, err error) {
+	body := snappy.Encode(nil, encodeWriteRequest(batch))
+	delay := rw.RetryDelay
+	for attempt := 0; ; attempt++ {
+		retry, err = rw.post(body)
+		if err == nil {
+			return false, nil
+		}
+		if !retry || attempt >= rw.Retries {
+			return retry, fmt.Errorf("metrics remote write error: %s (%d series dropped)", err, len(batch))
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// post sends body once and reports whether a failure is worth retrying.
+func (rw *RemoteWriter) post(body []byte) (retry bool, err error) {
+	req, err := http.NewRequest(http.MethodPost, rw.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	req.Header.Set("User-Agent", "missionctl-remote-write")
+	client := rw.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("%s returned %s", req.URL.Redacted(), resp.Status)
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+// encodeWriteRequest encodes a prometheus.WriteRequest:
+//
+//	WriteRequest { repeated TimeSeries timeseries = 1; }
+//	TimeSeries   { repeated Label labels = 1; repeated Sample samples = 2; }
+//	Label        { string name = 1; string value = 2; }
+//	Sample       { double value = 1; int64 timestamp = 2; } // ms
+//
+// Labels are sorted by name, as receivers require.
+func encodeWriteRequest(batch []series) []byte {
+	var req []byte
+	for _, s := range batch {
+		names := make([]string, 0, len(s.labels))
+		for k := range s.labels {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		var ts []byte
+		for _, k := range names {
+			var l []byte
+			l = appendBytes(l, 1, []byte(k))
+			l = appendBytes(l, 2, []byte(s.labels[k]))
+			ts = appendBytes(ts, 1, l)
+		}
+		for _, sm := range s.samples {
+			var b []byte
+			b = binary.AppendUvarint(b, 1<<3|1) // fixed64
+			b = binary.LittleEndian.AppendUint64(b, math.Float64bits(sm.Value))
+			b = binary.AppendUvarint(b, 2<<3|0) // varint
+			b = binary.AppendUvarint(b, uint64(sm.Timestamp.UnixMilli()))
+			ts = appendBytes(ts, 2, b)
+		}
+		req = appendBytes(req, 1, ts)
+	}
+	return req
+}
+
+// appendBytes appends a length-delimited protobuf field.
+func appendBytes(b []byte, field int, data []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
@@ -0,0 +1,224 @@
+package metrics
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// Bucket summarises the points of one series over Resolution from Start.
+// Buckets replace raw points once they are old enough (see RollupPolicy).
+type Bucket struct {
+	Name       string            `json:"name"`
+	Tags       map[string]string `json:"tags,omitempty"`
+	Start      time.Time         `json:"start"`
+	Resolution time.Duration     `json:"resolution"`
+	Count      int               `json:"count"`
+	Sum        float64           `json:"sum"`
+	Min        float64           `json:"min"`
+	Max        float64           `json:"max"`
+	First      float64           `json:"first"`
+	Last       float64           `json:"last"`
+}
+
+// Tier keeps data older than After at Resolution only.
+type Tier struct {
+	After      time.Duration
+	Resolution time.Duration
+}
+
+// RollupPolicy says how raw points are rolled up into coarser buckets.
+type RollupPolicy struct {
+	// Tiers, in increasing After and Resolution. Raw points older than the
+	// first tier's After become buckets of its Resolution, and so on.
+	Tiers []Tier
+	// Retention drops buckets older than this; 0 keeps them.
+	Retention time.Duration
+	// Interval is how often a store compacts at most, unless it holds more
+	// than MaxSize raw points.
+	Interval time.Duration
+}
+
+// DefaultRollup keeps raw points for an hour, minutes for a day and hours
+// for 30 days.
+var DefaultRollup = RollupPolicy{
+	Tiers:     []Tier{{After: time.Hour, Resolution: time.Minute}, {After: 24 * time.Hour, Resolution: time.Hour}},
+	Retention: 30 * 24 * time.Hour,
+	Interval:  time.Minute,
+}
+
+// seriesTags identifies the series of m: its tags and labels together.
+func seriesTags(m Metric) map[string]string {
+	if len(m.Labels) == 0 {
+		return m.Tags
+	}
+	out := make(map[string]string, len(m.Tags)+len(m.Labels))
+	for k, v := range m.Labels {
+		out[k] = v
+	}
+	for k, v := range m.Tags {
+		out[k] = v
+	}
+	return out
+}
+
+// tagsKey renders tags canonically; json.Marshal sorts map keys.
+func tagsKey(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(tags)
+	return string(b)
+}
+
+type bucketKey struct {
+	name, tags string
+	res        time.Duration
+	start      int64
+}
+
+func keyOf(b *Bucket) bucketKey {
+	return bucketKey{b.Name, tagsKey(b.Tags), b.Resolution, b.Start.UnixNano()}
+}
+
+// add merges a point taken at the end of the bucket so far.
+func (b *Bucket) add(v float64) {
+	if b.Count == 0 {
+		b.Min, b.Max, b.First = v, v, v
+	}
+	b.Count++
+	b.Sum += v
+	b.Min = min(b.Min, v)
+	b.Max = max(b.Max, v)
+	b.Last = v
+}
+
+// merge folds in a later bucket.
+func (b *Bucket) merge(o Bucket) {
+	if o.Count == 0 {
+		return
+	}
+	if b.Count == 0 {
+		b.Min, b.Max, b.First = o.Min, o.Max, o.First
+	}
+	b.Count += o.Count
+	b.Sum += o.Sum
+	b.Min = min(b.Min, o.Min)
+	b.Max = max(b.Max, o.Max)
+	b.Last = o.Last
+}
+
+// rollupResult says what a compaction changes.
+type rollupResult struct {
+	// drop is how many raw points, from the oldest, were rolled up.
+	drop int
+	// put are new or changed buckets; del are buckets to remove.
+	put, del []Bucket
+}
+
+func (r rollupResult) changed() bool {
+	return r.drop > 0 || len(r.put) > 0 || len(r.del) > 0
+}
+
+// rollup applies p at now to points (oldest first) and buckets: points
+// older than the first tier, and the oldest beyond maxRaw, go into buckets,
+// buckets older than each further tier are merged into it and buckets past
+// the retention are dropped.
+func rollup(points []Metric, buckets []Bucket, p RollupPolicy, maxRaw int, now time.Time) rollupResult {
+	var res rollupResult
+	if len(p.Tiers) == 0 {
+		if maxRaw > 0 && len(points) > maxRaw {
+			res.drop = len(points) - maxRaw
+		}
+		return res
+	}
+	cutoff := now.Add(-p.Tiers[0].After)
+	for i, m := range points {
+		if m.Timestamp.Before(cutoff) {
+			res.drop = i + 1
+		}
+	}
+	if maxRaw > 0 && len(points)-res.drop > maxRaw {
+		res.drop = len(points) - maxRaw
+	}
+
+	index := make(map[bucketKey]*Bucket, len(buckets))
+	stored := make(map[bucketKey]bool, len(buckets))
+	for i := range buckets {
+		b := buckets[i]
+		k := keyOf(&b)
+		index[k], stored[k] = &b, true
+	}
+	changed := map[bucketKey]bool{}
+	target := func(name string, tags map[string]string, res time.Duration, t time.Time) *Bucket {
+		nb := Bucket{Name: name, Tags: tags, Resolution: res, Start: t.Truncate(res)}
+		k := keyOf(&nb)
+		if b, ok := index[k]; ok {
+			changed[k] = true
+			return b
+		}
+		index[k], changed[k] = &nb, true
+		return &nb
+	}
+
+	for _, m := range points[:res.drop] {
+		target(m.Name, seriesTags(m), p.Tiers[0].Resolution, m.Timestamp).add(m.Value)
+	}
+	for i := 1; i < len(p.Tiers); i++ {
+		from, to := p.Tiers[i-1].Resolution, p.Tiers[i]
+		cutoff := now.Add(-to.After)
+		var old []*Bucket
+		for _, b := range index {
+			if b.Resolution == from && b.Start.Before(cutoff) {
+				old = append(old, b)
+			}
+		}
+		sort.Slice(old, func(a, b int) bool { return old[a].Start.Before(old[b].Start) })
+		for _, b := range old {
+			target(b.Name, b.Tags, to.Resolution, b.Start).merge(*b)
+			k := keyOf(b)
+			delete(index, k)
+			delete(changed, k)
+			if stored[k] {
+				res.del = append(res.del, *b)
+			}
+		}
+	}
+	if p.Retention > 0 {
+		expired := now.Add(-p.Retention)
+		for k, b := range index {
+			if b.Start.Before(expired) {
+				delete(index, k)
+				delete(changed, k)
+				if stored[k] {
+					res.del = append(res.del, *b)
+				}
+			}
+		}
+	}
+	for k := range changed {
+		res.put = append(res.put, *index[k])
+	}
+	sort.Slice(res.put, func(a, b int) bool { return res.put[a].Start.Before(res.put[b].Start) })
+	return res
+}
+
+// apply returns buckets with res.put and res.del applied, oldest first.
+func (r rollupResult) apply(buckets []Bucket) []Bucket {
+	gone := make(map[bucketKey]bool, len(r.del)+len(r.put))
+	for i := range r.del {
+		gone[keyOf(&r.del[i])] = true
+	}
+	for i := range r.put {
+		gone[keyOf(&r.put[i])] = true
+	}
+	out := make([]Bucket, 0, len(buckets)+len(r.put))
+	for i := range buckets {
+		if !gone[keyOf(&buckets[i])] {
+			out = append(out, buckets[i])
+		}
+	}
+	out = append(out, r.put...)
+	sort.SliceStable(out, func(a, b int) bool { return out[a].Start.Before(out[b].Start) })
+	return out
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Backend persists metrics, events and alerts. A MetricsStore keeps an
+// in-memory view on top of a Backend and writes every change through to
+// it, so the CLI and the dashboard see the same data.
+//
+// Implementations must be safe for concurrent use.
+type Backend interface {
+	// Load returns the newest max metrics, events and alerts and every
+	// rollup bucket, oldest first.
+	Load(max int) (Data, error)
+	// AddMetric appends a metric.
+	AddMetric(m Metric) error
+	// AddEvent appends an event.
+	AddEvent(e Event) error
+	// PutAlert creates or replaces the alert with a.ID.
+	PutAlert(a Alert) error
+	// Compact rolls raw metrics up according to p and drops all but the
+	// newest max events and alerts. It does nothing when the backend was
+	// compacted less than p.Interval ago and holds at most max items of
+	// each kind, and reports whether anything changed.
+	Compact(p RollupPolicy, max int, now time.Time) (bool, error)
+	// Clear removes everything.
+	Clear() error
+	// Version changes whenever another process writes to the backend, so
+	// the store only reloads when there is something new.
+	Version() (int64, error)
+	Close() error
+}
+
+// Data is what a backend holds.
+type Data struct {
+	Metrics []Metric
+	Events  []Event
+	Alerts  []Alert
+	Buckets []Bucket
+}
+
+// OpenStore opens a store of up to maxSize items of each kind on the
+// backend described by spec:
+//
+//	""               DefaultStore
+//	"sqlite"         metrics.db in the working directory
+//	"sqlite:<path>"  a SQLite database (needs a cgo build)
+//	"memory"         in-memory only, lost when the process exits
+func OpenStore(spec string, maxSize int) (*MetricsStore, error) {
+	if spec == "" {
+		spec = DefaultStore
+	}
+	if spec == "sqlite" {
+		spec = "sqlite:metrics.db"
+	}
+	kind, arg, _ := strings.Cut(spec, ":")
+	switch kind {
+	case "memory":
+		return NewMetricsStore(maxSize), nil
+	case "sqlite":
+		if arg == "" {
+			return nil, fmt.Errorf("metrics store %q: missing database path (sqlite:<path>)", spec)
+		}
+		b, err := OpenSQLiteBackend(arg)
+		if err != nil {
+			return nil, err
+		}
+		s, err := NewPersistentStore(b, maxSize)
+		if err != nil {
+			b.Close()
+			return nil, err
+		}
+		return s, nil
+	}
+	return nil, fmt.Errorf("unknown metrics store %q (want sqlite:<path> or memory)", spec)
+}
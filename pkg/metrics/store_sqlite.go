@@ -0,0 +1,357 @@
+package metrics
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	// registers the "sqlite3" database/sql driver
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS metrics (
+	id        INTEGER PRIMARY KEY AUTOINCREMENT,
+	name      TEXT NOT NULL,
+	value     REAL NOT NULL,
+	unit      TEXT NOT NULL,
+	ts        INTEGER NOT NULL,
+	tags      TEXT,
+	labels    TEXT
+);
+CREATE INDEX IF NOT EXISTS metrics_name_ts ON metrics (name, ts);
+CREATE TABLE IF NOT EXISTS events (
+	id       INTEGER PRIMARY KEY AUTOINCREMENT,
+	event_id TEXT NOT NULL,
+	type     TEXT NOT NULL,
+	message  TEXT NOT NULL,
+	status   TEXT NOT NULL,
+	ts       INTEGER NOT NULL,
+	duration INTEGER NOT NULL DEFAULT 0,
+	metadata TEXT,
+	user     TEXT NOT NULL DEFAULT '',
+	resource TEXT NOT NULL DEFAULT ''
+);
+CREATE TABLE IF NOT EXISTS alerts (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	alert_id    TEXT NOT NULL UNIQUE,
+	name        TEXT NOT NULL,
+	severity    TEXT NOT NULL,
+	message     TEXT NOT NULL,
+	ts          INTEGER NOT NULL,
+	resolved    INTEGER NOT NULL DEFAULT 0,
+	resolved_at INTEGER,
+	metadata    TEXT
+);
+CREATE TABLE IF NOT EXISTS buckets (
+	name       TEXT NOT NULL,
+	tags       TEXT NOT NULL DEFAULT '',
+	resolution INTEGER NOT NULL,
+	start      INTEGER NOT NULL,
+	count      INTEGER NOT NULL,
+	sum        REAL NOT NULL,
+	min        REAL NOT NULL,
+	max        REAL NOT NULL,
+	first      REAL NOT NULL,
+	last       REAL NOT NULL,
+	PRIMARY KEY (name, tags, resolution, start)
+);
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value INTEGER NOT NULL
+);`
+
+// SQLiteBackend keeps metrics, events and alerts in a SQLite database that
+// the CLI and the dashboard open side by side. Timestamps are stored as
+// Unix nanoseconds.
+type SQLiteBackend struct {
+	DB *sql.DB
+}
+
+// OpenSQLiteBackend opens (creating if needed) the database at path.
+func OpenSQLiteBackend(path string) (*SQLiteBackend, error) {
+	// Immediate transactions take the write lock up front, so two processes
+	// compacting at once queue instead of failing to upgrade.
+	db, err := sql.Open("sqlite3", "file:"+path+"?_busy_timeout=5000&_journal_mode=WAL&_txlock=immediate")
+	if err != nil {
+		return nil, fmt.Errorf("sqlite open error: %s", err)
+	}
+	// PRAGMA data_version is per connection, so Version needs the same one
+	// every time.
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("sqlite schema error: %s", err)
+	}
+	return &SQLiteBackend{DB: db}, nil
+}
+
+// Close closes the database.
+func (b *SQLiteBackend) Close() error { return b.DB.Close() }
+
+// Version returns SQLite's data_version, which changes when another
+// connection commits.
+func (b *SQLiteBackend) Version() (int64, error) {
+	var v int64
+	err := b.DB.QueryRow(`PRAGMA data_version`).Scan(&v)
+	return v, err
+}
+
+// Load returns the newest max items of each kind and every bucket, oldest
+// first.
+func (b *SQLiteBackend) Load(max int) (Data, error) {
+	var d Data
+	var err error
+	if d.Metrics, err = b.loadMetrics(max); err != nil {
+		return d, err
+	}
+	if d.Events, err = b.loadEvents(max); err != nil {
+		return d, err
+	}
+	if d.Alerts, err = b.loadAlerts(max); err != nil {
+		return d, err
+	}
+	d.Buckets, err = loadBuckets(b.DB)
+	return d, err
+}
+
+func (b *SQLiteBackend) loadMetrics(max int) ([]Metric, error) {
+	rows, err := b.DB.Query(`SELECT name, value, unit, ts, tags, labels FROM
+		(SELECT * FROM metrics ORDER BY id DESC LIMIT ?) ORDER BY id`, max)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Metric{}
+	for rows.Next() {
+		var m Metric
+		var ts int64
+		var tags, labels sql.NullString
+		if err := rows.Scan(&m.Name, &m.Value, &m.Unit, &ts, &tags, &labels); err != nil {
+			return nil, err
+		}
+		m.Timestamp = time.Unix(0, ts)
+		m.Tags, m.Labels = decodeMap(tags), decodeMap(labels)
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
+func (b *SQLiteBackend) loadEvents(max int) ([]Event, error) {
+	rows, err := b.DB.Query(`SELECT event_id, type, message, status, ts, duration, metadata, user, resource FROM
+		(SELECT * FROM events ORDER BY id DESC LIMIT ?) ORDER BY id`, max)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Event{}
+	for rows.Next() {
+		var e Event
+		var ts, duration int64
+		var metadata sql.NullString
+		if err := rows.Scan(&e.ID, &e.Type, &e.Message, &e.Status, &ts, &duration, &metadata, &e.User, &e.Resource); err != nil {
+			return nil, err
+		}
+		e.Timestamp, e.Duration = time.Unix(0, ts), time.Duration(duration)
+		e.Metadata = decodeMap(metadata)
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (b *SQLiteBackend) loadAlerts(max int) ([]Alert, error) {
+	rows, err := b.DB.Query(`SELECT alert_id, name, severity, message, ts, resolved, resolved_at, metadata FROM
+		(SELECT * FROM alerts ORDER BY id DESC LIMIT ?) ORDER BY id`, max)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Alert{}
+	for rows.Next() {
+		var a Alert
+		var ts int64
+		var resolvedAt sql.NullInt64
+		var metadata sql.NullString
+		if err := rows.Scan(&a.ID, &a.Name, &a.Severity, &a.Message, &ts, &a.Resolved, &resolvedAt, &metadata); err != nil {
+			return nil, err
+		}
+		a.Timestamp = time.Unix(0, ts)
+		if resolvedAt.Valid {
+			t := time.Unix(0, resolvedAt.Int64)
+			a.ResolvedAt = &t
+		}
+		a.Metadata = decodeMap(metadata)
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// AddMetric appends a metric.
+func (b *SQLiteBackend) AddMetric(m Metric) error {
+	_, err := b.DB.Exec(`INSERT INTO metrics (name, value, unit, ts, tags, labels) VALUES (?, ?, ?, ?, ?, ?)`,
+		m.Name, m.Value, m.Unit, m.Timestamp.UnixNano(), encodeMap(m.Tags), encodeMap(m.Labels))
+	return err
+}
+
+// AddEvent appends an event.
+func (b *SQLiteBackend) AddEvent(e Event) error {
+	_, err := b.DB.Exec(`INSERT INTO events (event_id, type, message, status, ts, duration, metadata, user, resource) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		e.ID, e.Type, e.Message, e.Status, e.Timestamp.UnixNano(), int64(e.Duration), encodeMap(e.Metadata), e.User, e.Resource)
+	return err
+}
+
+// PutAlert creates or replaces an alert, keeping its place in the order.
+func (b *SQLiteBackend) PutAlert(a Alert) error {
+	var resolvedAt any
+	if a.ResolvedAt != nil {
+		resolvedAt = a.ResolvedAt.UnixNano()
+	}
+	_, err := b.DB.Exec(`INSERT INTO alerts (alert_id, name, severity, message, ts, resolved, resolved_at, metadata) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (alert_id) DO UPDATE SET name = excluded.name, severity = excluded.severity, message = excluded.message,
+		ts = excluded.ts, resolved = excluded.resolved, resolved_at = excluded.resolved_at, metadata = excluded.metadata`,
+		a.ID, a.Name, a.Severity, a.Message, a.Timestamp.UnixNano(), a.Resolved, resolvedAt, encodeMap(a.Metadata))
+	return err
+}
+
+// Compact rolls metrics up and trims events and alerts in one
+// transaction, so concurrent compactions never count a point twice.
+func (b *SQLiteBackend) Compact(p RollupPolicy, max int, now time.Time) (bool, error) {
+	tx, err := b.DB.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var last int64
+	if err := tx.QueryRow(`SELECT value FROM meta WHERE key = 'compacted_at'`).Scan(&last); err != nil && err != sql.ErrNoRows {
+		return false, err
+	}
+	if now.Sub(time.Unix(0, last)) < p.Interval {
+		var over bool
+		if err := tx.QueryRow(`SELECT (SELECT count(*) FROM metrics) > ?1 OR (SELECT count(*) FROM events) > ?1 OR (SELECT count(*) FROM alerts) > ?1`, max).Scan(&over); err != nil {
+			return false, err
+		}
+		if !over {
+			return false, nil
+		}
+	}
+
+	ids, points, err := rawMetrics(tx)
+	if err != nil {
+		return false, err
+	}
+	buckets, err := loadBuckets(tx)
+	if err != nil {
+		return false, err
+	}
+	res := rollup(points, buckets, p, max, now)
+	if res.drop > 0 {
+		if _, err := tx.Exec(`DELETE FROM metrics WHERE id <= ?`, ids[res.drop-1]); err != nil {
+			return false, err
+		}
+	}
+	for _, bk := range res.del {
+		if _, err := tx.Exec(`DELETE FROM buckets WHERE name = ? AND tags = ? AND resolution = ? AND start = ?`,
+			bk.Name, tagsKey(bk.Tags), int64(bk.Resolution), bk.Start.UnixNano()); err != nil {
+			return false, err
+		}
+	}
+	for _, bk := range res.put {
+		if _, err := tx.Exec(`INSERT OR REPLACE INTO buckets (name, tags, resolution, start, count, sum, min, max, first, last) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			bk.Name, tagsKey(bk.Tags), int64(bk.Resolution), bk.Start.UnixNano(), bk.Count, bk.Sum, bk.Min, bk.Max, bk.First, bk.Last); err != nil {
+			return false, err
+		}
+	}
+	trimmed := int64(0)
+	for _, table := range []string{"events", "alerts"} {
+		r, err := tx.Exec(fmt.Sprintf(`DELETE FROM %[1]s WHERE id <= (SELECT id FROM %[1]s ORDER BY id DESC LIMIT 1 OFFSET ?)`, table), max)
+		if err != nil {
+			return false, err
+		}
+		n, _ := r.RowsAffected()
+		trimmed += n
+	}
+	if _, err := tx.Exec(`INSERT OR REPLACE INTO meta (key, value) VALUES ('compacted_at', ?)`, now.UnixNano()); err != nil {
+		return false, err
+	}
+	return res.changed() || trimmed > 0, tx.Commit()
+}
+
+// querier is a *sql.DB or *sql.Tx.
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// rawMetrics returns every raw metric and its row id, oldest first.
+func rawMetrics(q querier) ([]int64, []Metric, error) {
+	rows, err := q.Query(`SELECT id, name, value, unit, ts, tags, labels FROM metrics ORDER BY id`)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+	var ids []int64
+	var out []Metric
+	for rows.Next() {
+		var id, ts int64
+		var m Metric
+		var tags, labels sql.NullString
+		if err := rows.Scan(&id, &m.Name, &m.Value, &m.Unit, &ts, &tags, &labels); err != nil {
+			return nil, nil, err
+		}
+		m.Timestamp = time.Unix(0, ts)
+		m.Tags, m.Labels = decodeMap(tags), decodeMap(labels)
+		ids, out = append(ids, id), append(out, m)
+	}
+	return ids, out, rows.Err()
+}
+
+func loadBuckets(q querier) ([]Bucket, error) {
+	rows, err := q.Query(`SELECT name, tags, resolution, start, count, sum, min, max, first, last FROM buckets ORDER BY start`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	out := []Bucket{}
+	for rows.Next() {
+		var bk Bucket
+		var tags string
+		var res, start int64
+		if err := rows.Scan(&bk.Name, &tags, &res, &start, &bk.Count, &bk.Sum, &bk.Min, &bk.Max, &bk.First, &bk.Last); err != nil {
+			return nil, err
+		}
+		bk.Tags = decodeMap(sql.NullString{String: tags, Valid: true})
+		bk.Resolution, bk.Start = time.Duration(res), time.Unix(0, start)
+		out = append(out, bk)
+	}
+	return out, rows.Err()
+}
+
+// Clear removes every row.
+func (b *SQLiteBackend) Clear() error {
+	_, err := b.DB.Exec(`DELETE FROM metrics; DELETE FROM events; DELETE FROM alerts; DELETE FROM buckets;`)
+	return err
+}
+
+// encodeMap stores a map as JSON, NULL when it is empty.
+func encodeMap(m map[string]string) any {
+	if len(m) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil
+	}
+	return string(b)
+}
+
+func decodeMap(s sql.NullString) map[string]string {
+	if !s.Valid || s.String == "" {
+		return nil
+	}
+	var m map[string]string
+	if err := json.Unmarshal([]byte(s.String), &m); err != nil {
+		return nil
+	}
+	return m
+}
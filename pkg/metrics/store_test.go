@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLiteStoreSharedBetweenProcesses(t *testing.T) {
+	spec := "sqlite:" + filepath.Join(t.TempDir(), "metrics.db")
+	cli, err := OpenStore(spec, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+	dash, err := OpenStore(spec, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dash.Close()
+
+	for i := 0; i < 5; i++ {
+		if err := cli.RecordMetric("cpu", float64(i), "%", map[string]string{"tenant": "acme"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cli.AddEvent(Event{Type: "command", Message: "k8s pods delete web-1", Status: "failure", User: "bob"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.CreateAlert("disk", "critical", "disk full", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// the other store sees the writes, trimmed to the newest MaxSize
+	m := dash.GetMetrics()
+	if len(m) != 3 || m[0].Value != 2 || m[2].Value != 4 || m[2].Tags["tenant"] != "acme" {
+		t.Fatalf("metrics: %+v", m)
+	}
+	if ev := dash.GetEvents(); len(ev) != 1 || ev[0].User != "bob" || ev[0].ID == "" {
+		t.Fatalf("events: %+v", ev)
+	}
+	alerts := dash.GetActiveAlerts()
+	if len(alerts) != 1 {
+		t.Fatalf("alerts: %+v", alerts)
+	}
+	if err := dash.ResolveAlert(alerts[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if a := cli.GetAlerts(); len(a) != 1 || !a[0].Resolved || a[0].ResolvedAt == nil {
+		t.Fatalf("resolve should reach the other store: %+v", a)
+	}
+
+	// and it all survives a restart
+	cli.Close()
+	again, err := OpenStore(spec, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer again.Close()
+	if stats := again.GetStats(); stats["total_metrics"] != 3 || stats["failed_ops"] != 1 || stats["active_alerts"] != 0 {
+		t.Fatalf("stats after reopen: %+v", stats)
+	}
+	if err := again.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if len(dash.GetMetrics()) != 0 {
+		t.Fatal("Clear should empty the backend")
+	}
+}
+
+func TestOpenStore(t *testing.T) {
+	s, err := OpenStore("memory", 0)
+	if err != nil || s.backend != nil || s.MaxSize != 10000 {
+		t.Fatalf("memory store: %+v %v", s, err)
+	}
+	for _, spec := range []string{"sqlite:", "bolt:/tmp/x"} {
+		if _, err := OpenStore(spec, 10); err == nil {
+			t.Fatalf("%q should be rejected", spec)
+		}
+	}
+}
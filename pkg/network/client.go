@@ -1,123 +1,137 @@
 package network
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"os/exec"
 	"strings"
+
+	"github.com/yourusername/devops-mission-control/pkg/executor"
 )
 
 // Client wraps network diagnostic operations
 type Client struct {
 	Timeout string
+	// Exec runs the diagnostic tools; defaults to the local OS executor.
+	Exec executor.Executor
+}
+
+// Option configures the Network client.
+type Option func(*Client)
+
+// WithExecutor sets the executor used to run diagnostic tools.
+func WithExecutor(e executor.Executor) Option {
+	return func(c *Client) { c.Exec = e }
 }
 
 // NewClient creates a new Network client
-func NewClient() *Client {
-	return &Client{
+func NewClient(opts ...Option) *Client {
+	c := &Client{
 		Timeout: "5",
 	}
+	for _, o := range opts {
+		o(c)
+	}
+	if c.Exec == nil {
+		c.Exec = executor.New()
+	}
+	return c
 }
 
 // execCommand runs a command and returns output
-func execCommand(name string, args ...string) (string, error) {
-	cmd := exec.Command(name, args...)
-	var out, errOut bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &errOut
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("command error: %s", errOut.String())
+func (c *Client) execCommand(name string, args ...string) (string, error) {
+	res, err := c.Exec.Run(context.Background(), executor.Command{Name: name, Args: args})
+	if err != nil {
+		return "", fmt.Errorf("command error: %s", executor.ErrorText(res, err))
 	}
-	return strings.TrimSpace(out.String()), nil
+	return strings.TrimSpace(string(res.Stdout)), nil
 }
 
 // Ping
 func (c *Client) Ping(host string) (string, error) {
 	// -c for Linux/Mac, -n for Windows
-	return execCommand("ping", "-c", c.Timeout, host)
+	return c.execCommand("ping", "-c", c.Timeout, host)
 }
 
 // Traceroute
 func (c *Client) Traceroute(host string) (string, error) {
 	// traceroute on Linux/Mac, tracert on Windows
-	return execCommand("traceroute", "-m", "30", host)
+	return c.execCommand("traceroute", "-m", "30", host)
 }
 
 // DNS lookup
 func (c *Client) Nslookup(host string) (string, error) {
-	return execCommand("nslookup", host)
+	return c.execCommand("nslookup", host)
 }
 
 // Dig (DNS information)
 func (c *Client) Dig(host string) (string, error) {
-	return execCommand("dig", host)
+	return c.execCommand("dig", host)
 }
 
 // Whois
 func (c *Client) Whois(domain string) (string, error) {
-	return execCommand("whois", domain)
+	return c.execCommand("whois", domain)
 }
 
 // Check port connectivity
 func (c *Client) CheckPort(host string, port string) (string, error) {
 	// Using nc (netcat) for port checking
-	return execCommand("nc", "-zv", "-w", c.Timeout, host, port)
+	return c.execCommand("nc", "-zv", "-w", c.Timeout, host, port)
 }
 
 // Get IP address
 func (c *Client) GetIP(host string) (string, error) {
-	return execCommand("getent", "hosts", host)
+	return c.execCommand("getent", "hosts", host)
 }
 
 // Network interfaces
 func (c *Client) GetNetworkInterfaces() (string, error) {
-	return execCommand("ifconfig")
+	return c.execCommand("ifconfig")
 }
 
 // IP route
 func (c *Client) GetRouteTable() (string, error) {
-	return execCommand("netstat", "-rn")
+	return c.execCommand("netstat", "-rn")
 }
 
 // DNS resolution with dig
 func (c *Client) ReverseLookup(ip string) (string, error) {
-	return execCommand("dig", "-x", ip)
+	return c.execCommand("dig", "-x", ip)
 }
 
 // Check MTU
 func (c *Client) CheckMTU(interface_name string) (string, error) {
-	return execCommand("ifconfig", interface_name)
+	return c.execCommand("ifconfig", interface_name)
 }
 
 // TCP connections
 func (c *Client) GetTCPConnections() (string, error) {
-	return execCommand("netstat", "-an")
+	return c.execCommand("netstat", "-an")
 }
 
 // Hostname and domain info
 func (c *Client) GetHostname() (string, error) {
-	return execCommand("hostname")
+	return c.execCommand("hostname")
 }
 
 func (c *Client) GetFQDN() (string, error) {
-	return execCommand("hostname", "-f")
+	return c.execCommand("hostname", "-f")
 }
 
 // DNS servers
 func (c *Client) GetDNSServers() (string, error) {
-	return execCommand("cat", "/etc/resolv.conf")
+	return c.execCommand("cat", "/etc/resolv.conf")
 }
 
 // Packet loss check
 func (c *Client) PacketLoss(host string, count string) (string, error) {
-	return execCommand("ping", "-c", count, host)
+	return c.execCommand("ping", "-c", count, host)
 }
 
 // Bandwidth test (requires iperf3)
 func (c *Client) SpeedTest(testServer string) (string, error) {
 	// Requires speedtest-cli to be installed
-	return execCommand("speedtest", "--simple")
+	return c.execCommand("speedtest", "--simple")
 }
 
 // SSL/TLS certificate check
@@ -125,15 +139,15 @@ func (c *Client) CheckSSLCertificate(host string, port string) (string, error) {
 	if port == "" {
 		port = "443"
 	}
-	return execCommand("openssl", "s_client", "-connect", host+":"+port, "-showcerts")
+	return c.execCommand("openssl", "s_client", "-connect", host+":"+port, "-showcerts")
 }
 
 // HTTP response check
 func (c *Client) HTTPHead(url string) (string, error) {
-	return execCommand("curl", "-I", url)
+	return c.execCommand("curl", "-I", url)
 }
 
 // DNS propagation check
 func (c *Client) CheckDNSPropagation(domain string) (string, error) {
-	return execCommand("dig", "+short", "NS", domain)
+	return c.execCommand("dig", "+short", "NS", domain)
 }
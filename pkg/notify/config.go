@@ -0,0 +1,427 @@
+// Package notify sends the alerts of the metrics store to receivers: Slack,
+// Microsoft Teams, generic webhooks, email and PagerDuty. Routes pick the
+// receivers of an alert by its labels, alerts of a receiver are grouped
+// into one notification that is repeated while they keep firing, and
+// silences and inhibition rules mute alerts nobody needs to hear about.
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/yourusername/devops-mission-control/pkg/alerting"
+	"github.com/yourusername/devops-mission-control/pkg/metrics"
+	"github.com/yourusername/devops-mission-control/pkg/slack"
+	"github.com/yourusername/devops-mission-control/pkg/yamlite"
+)
+
+// LabelAlertName is the label holding the name of an alert. Routes,
+// silences and inhibition rules match it, severity and the alert's
+// metadata (tenant included) as labels.
+const LabelAlertName = "alertname"
+
+// Labels returns the labels routes and silences match an alert on.
+func Labels(a metrics.Alert) map[string]string {
+	labels := make(map[string]string, len(a.Metadata)+2)
+	for k, v := range a.Metadata {
+		labels[k] = v
+	}
+	labels[LabelAlertName] = a.Name
+	labels["severity"] = a.Severity
+	return labels
+}
+
+// Defaults of the grouping and timing settings.
+const (
+	DefaultGroupWait      = 30 * time.Second
+	DefaultGroupInterval  = 5 * time.Minute
+	DefaultRepeatInterval = 4 * time.Hour
+)
+
+// Timing controls when the notification of a group is sent: GroupWait
+// after its first alert, so related alerts can join it, again GroupInterval
+// after the last one when alerts joined or resolved, and otherwise every
+// RepeatInterval while it keeps firing. GroupBy are the labels whose values
+// split a receiver's alerts into groups.
+type Timing struct {
+	GroupBy        []string          `json:"group_by,omitempty"`
+	GroupWait      alerting.Duration `json:"group_wait,omitempty"`
+	GroupInterval  alerting.Duration `json:"group_interval,omitempty"`
+	RepeatInterval alerting.Duration `json:"repeat_interval,omitempty"`
+}
+
+// inherit fills the unset settings of t from parent.
+func (t *Timing) inherit(parent Timing) {
+	if t.GroupBy == nil {
+		t.GroupBy = parent.GroupBy
+	}
+	if t.GroupWait == 0 {
+		t.GroupWait = parent.GroupWait
+	}
+	if t.GroupInterval == 0 {
+		t.GroupInterval = parent.GroupInterval
+	}
+	if t.RepeatInterval == 0 {
+		t.RepeatInterval = parent.RepeatInterval
+	}
+}
+
+// Receiver is where notifications go: exactly one of its destinations.
+type Receiver struct {
+	Name      string             `json:"name"`
+	Slack     *SlackReceiver     `json:"slack,omitempty"`
+	Teams     *TeamsReceiver     `json:"teams,omitempty"`
+	Webhook   *WebhookReceiver   `json:"webhook,omitempty"`
+	Email     *EmailReceiver     `json:"email,omitempty"`
+	PagerDuty *PagerDutyReceiver `json:"pagerduty,omitempty"`
+
+	template *template.Template
+}
+
+// SlackReceiver posts to an incoming webhook, or to Channel through the
+// Web API with a bot token.
+type SlackReceiver struct {
+	WebhookURL string `json:"webhook_url,omitempty"`
+	Channel    string `json:"channel,omitempty"`
+	// Token, or the environment variable TokenEnv names, is the bot token.
+	Token    string `json:"token,omitempty"`
+	TokenEnv string `json:"token_env,omitempty"`
+	// MaxRetries overrides the client's retries of failed sends, here and
+	// in the other receivers.
+	MaxRetries *int `json:"max_retries,omitempty"`
+}
+
+// TeamsReceiver posts cards to a Microsoft Teams webhook.
+type TeamsReceiver struct {
+	WebhookURL string `json:"webhook_url"`
+	MaxRetries *int   `json:"max_retries,omitempty"`
+}
+
+// WebhookReceiver posts the JSON Template renders (see Webhook) to URL
+// with Headers.
+type WebhookReceiver struct {
+	URL        string            `json:"url"`
+	Template   string            `json:"template,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	MaxRetries *int              `json:"max_retries,omitempty"`
+}
+
+// EmailReceiver mails through the SMTP server at SMTP (host:port).
+type EmailReceiver struct {
+	SMTP     string   `json:"smtp"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+	Username string   `json:"username,omitempty"`
+	// Password, or the environment variable PasswordEnv names, is the
+	// SMTP password.
+	Password    string `json:"password,omitempty"`
+	PasswordEnv string `json:"password_env,omitempty"`
+	MaxRetries  *int   `json:"max_retries,omitempty"`
+}
+
+// PagerDutyReceiver sends Events API v2 events to an integration.
+type PagerDutyReceiver struct {
+	RoutingKey string `json:"routing_key"`
+	// URL is the API host, DefaultPagerDutyURL by default.
+	URL        string `json:"url,omitempty"`
+	Source     string `json:"source,omitempty"`
+	MaxRetries *int   `json:"max_retries,omitempty"`
+}
+
+// validate checks that r has exactly one complete destination.
+func (r *Receiver) validate() error {
+	kinds := 0
+	for _, set := range []bool{r.Slack != nil, r.Teams != nil, r.Webhook != nil, r.Email != nil, r.PagerDuty != nil} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return fmt.Errorf("needs exactly one of slack, teams, webhook, email and pagerduty")
+	}
+	switch {
+	case r.Slack != nil && r.Slack.WebhookURL == "" && r.Slack.Token == "" && r.Slack.TokenEnv == "":
+		return fmt.Errorf("slack webhook_url or token is required")
+	case r.Slack != nil && r.Slack.WebhookURL == "" && r.Slack.Channel == "":
+		return fmt.Errorf("slack channel is required with a token")
+	case r.Teams != nil && r.Teams.WebhookURL == "":
+		return fmt.Errorf("teams webhook_url is required")
+	case r.Webhook != nil && r.Webhook.URL == "":
+		return fmt.Errorf("webhook url is required")
+	case r.Email != nil && (r.Email.SMTP == "" || r.Email.From == "" || len(r.Email.To) == 0):
+		return fmt.Errorf("email smtp, from and to are required")
+	case r.PagerDuty != nil && r.PagerDuty.RoutingKey == "":
+		return fmt.Errorf("pagerduty routing_key is required")
+	}
+	if r.Webhook != nil {
+		t, err := ParseWebhookTemplate(r.Webhook.Template)
+		if err != nil {
+			return err
+		}
+		r.template = t
+	}
+	return nil
+}
+
+// notifier returns the notifier of r; a Slack client also gets slackOpts.
+func (r Receiver) notifier(slackOpts []slack.Option) Notifier {
+	retries := func(n *int) []Option {
+		if n == nil {
+			return nil
+		}
+		return []Option{WithMaxRetries(*n)}
+	}
+	switch {
+	case r.Teams != nil:
+		return NewTeams(r.Teams.WebhookURL, retries(r.Teams.MaxRetries)...)
+	case r.Webhook != nil:
+		opts := retries(r.Webhook.MaxRetries)
+		for k, v := range r.Webhook.Headers {
+			opts = append(opts, WithHeader(k, os.ExpandEnv(v)))
+		}
+		return NewWebhook(r.Webhook.URL, r.template, opts...)
+	case r.Email != nil:
+		e := NewEmail(r.Email.SMTP, r.Email.From, r.Email.To)
+		e.Username, e.Password = r.Email.Username, r.Email.Password
+		if r.Email.PasswordEnv != "" {
+			e.Password = os.Getenv(r.Email.PasswordEnv)
+		}
+		if r.Email.MaxRetries != nil {
+			e.MaxRetries = *r.Email.MaxRetries
+		}
+		return e
+	case r.PagerDuty != nil:
+		p := NewPagerDuty(r.PagerDuty.RoutingKey, retries(r.PagerDuty.MaxRetries)...)
+		if r.PagerDuty.URL != "" {
+			p.URL = r.PagerDuty.URL
+		}
+		if r.PagerDuty.Source != "" {
+			p.Source = r.PagerDuty.Source
+		}
+		return p
+	}
+	opts := append([]slack.Option{}, slackOpts...)
+	if r.Slack.MaxRetries != nil {
+		opts = append(opts, slack.WithMaxRetries(*r.Slack.MaxRetries))
+	}
+	if token := r.Slack.Token; token != "" || r.Slack.TokenEnv != "" {
+		if r.Slack.TokenEnv != "" {
+			token = os.Getenv(r.Slack.TokenEnv)
+		}
+		opts = append(opts, slack.WithBotToken(token))
+	}
+	return slack.NewClient(r.Slack.WebhookURL, r.Slack.Channel, opts...)
+}
+
+// Route sends the alerts matching all of Match (key=glob or key!=glob over
+// Labels) to Receiver. Routes are tried in order and the first match wins,
+// unless it sets Continue.
+type Route struct {
+	Match    []string `json:"match,omitempty"`
+	Receiver string   `json:"receiver"`
+	Continue bool     `json:"continue,omitempty"`
+	Timing
+
+	matchers []metrics.TagFilter
+}
+
+// InhibitRule mutes the alerts matching Target while an alert matching
+// Source fires with the same values of the Equal labels, e.g. the warnings
+// of a host that already has a critical alert.
+type InhibitRule struct {
+	Source []string `json:"source"`
+	Target []string `json:"target"`
+	Equal  []string `json:"equal,omitempty"`
+
+	source, target []metrics.TagFilter
+}
+
+// Config is the content of a notification routes file. Alerts no route
+// matches go to Receiver, if set. Its Timing is the default of the routes.
+type Config struct {
+	Receiver string `json:"receiver,omitempty"`
+	Timing
+	Receivers []Receiver    `json:"receivers"`
+	Routes    []Route       `json:"routes,omitempty"`
+	Inhibit   []InhibitRule `json:"inhibit,omitempty"`
+}
+
+// Validate checks the config and fills in defaults.
+func (c *Config) Validate() error {
+	c.Timing.inherit(Timing{
+		GroupBy:        []string{LabelAlertName},
+		GroupWait:      alerting.Duration(DefaultGroupWait),
+		GroupInterval:  alerting.Duration(DefaultGroupInterval),
+		RepeatInterval: alerting.Duration(DefaultRepeatInterval),
+	})
+	if err := c.Timing.validate(); err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for i, r := range c.Receivers {
+		if r.Name == "" {
+			return fmt.Errorf("receiver #%d: name is required", i+1)
+		}
+		if names[r.Name] {
+			return fmt.Errorf("receiver %q: duplicate name", r.Name)
+		}
+		names[r.Name] = true
+		if err := c.Receivers[i].validate(); err != nil {
+			return fmt.Errorf("receiver %q: %w", r.Name, err)
+		}
+	}
+	if c.Receiver != "" && !names[c.Receiver] {
+		return fmt.Errorf("unknown receiver %q", c.Receiver)
+	}
+	for i := range c.Routes {
+		r := &c.Routes[i]
+		if !names[r.Receiver] {
+			return fmt.Errorf("route #%d: unknown receiver %q", i+1, r.Receiver)
+		}
+		r.Timing.inherit(c.Timing)
+		if err := r.Timing.validate(); err != nil {
+			return fmt.Errorf("route #%d: %w", i+1, err)
+		}
+		var err error
+		if r.matchers, err = parseMatchers(r.Match); err != nil {
+			return fmt.Errorf("route #%d: %w", i+1, err)
+		}
+	}
+	for i := range c.Inhibit {
+		in := &c.Inhibit[i]
+		if len(in.Source) == 0 || len(in.Target) == 0 {
+			return fmt.Errorf("inhibit rule #%d: source and target are required", i+1)
+		}
+		var err error
+		if in.source, err = parseMatchers(in.Source); err != nil {
+			return fmt.Errorf("inhibit rule #%d: %w", i+1, err)
+		}
+		if in.target, err = parseMatchers(in.Target); err != nil {
+			return fmt.Errorf("inhibit rule #%d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (t Timing) validate() error {
+	if t.GroupWait < 0 || t.GroupInterval <= 0 || t.RepeatInterval <= 0 {
+		return fmt.Errorf("group_wait, group_interval and repeat_interval must be positive")
+	}
+	return nil
+}
+
+// routes returns the routes of an alert with labels, the default route
+// when no route matches.
+func (c *Config) routes(labels map[string]string) []*Route {
+	var out []*Route
+	for i := range c.Routes {
+		r := &c.Routes[i]
+		if !matchAll(r.matchers, labels) {
+			continue
+		}
+		out = append(out, r)
+		if !r.Continue {
+			return out
+		}
+	}
+	if len(out) == 0 && c.Receiver != "" {
+		out = append(out, &Route{Receiver: c.Receiver, Timing: c.Timing})
+	}
+	return out
+}
+
+// inhibited reports whether an alert with labels is muted by another of
+// firing.
+func (c *Config) inhibited(labels map[string]string, firing []map[string]string) bool {
+	for _, in := range c.Inhibit {
+		if !matchAll(in.target, labels) {
+			continue
+		}
+	sources:
+		for _, other := range firing {
+			if !matchAll(in.source, other) || sameLabels(other, labels) {
+				continue
+			}
+			for _, k := range in.Equal {
+				if other[k] != labels[k] {
+					continue sources
+				}
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// sameLabels reports whether a and b are the labels of the same alert.
+func sameLabels(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// parseMatchers parses key=glob and key!=glob matchers.
+func parseMatchers(match []string) ([]metrics.TagFilter, error) {
+	out := make([]metrics.TagFilter, 0, len(match))
+	for _, m := range match {
+		f, err := metrics.ParseTagFilter(m)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+// matchAll reports whether labels satisfy every matcher.
+func matchAll(matchers []metrics.TagFilter, labels map[string]string) bool {
+	for _, f := range matchers {
+		if !f.Match(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadConfig reads a routes file in YAML or JSON (chosen by extension).
+func LoadConfig(path string) (Config, error) {
+	var c Config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &c)
+	} else {
+		err = yamlite.Unmarshal(data, &c)
+	}
+	if err != nil {
+		return c, fmt.Errorf("invalid notification routes %s: %w", path, err)
+	}
+	if err := c.Validate(); err != nil {
+		return c, fmt.Errorf("invalid notification routes %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// LoadConfigIfExists is LoadConfig, except a missing file routes nothing.
+func LoadConfigIfExists(path string) (Config, error) {
+	c, err := LoadConfig(path)
+	if errors.Is(err, os.ErrNotExist) {
+		c = Config{}
+		return c, c.Validate()
+	}
+	return c, err
+}
@@ -0,0 +1,363 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourusername/devops-mission-control/pkg/metrics"
+	"github.com/yourusername/devops-mission-control/pkg/slack"
+)
+
+// DefaultInterval is how often Run looks for alerts to notify.
+const DefaultInterval = 10 * time.Second
+
+// severityRank orders severities; the notification of a group has the
+// highest of its alerts.
+var severityRank = map[string]int{"info": 1, "warning": 2, "critical": 3}
+
+// SeverityResolved is the severity of the notification that a group's
+// alerts resolved.
+const SeverityResolved = "resolved"
+
+// MetaGroup is the metadata key of a notification that holds the labels
+// of its group, which stay the same for every notification of the group.
+const MetaGroup = "group"
+
+// Dispatcher routes the active alerts of a store to receivers. It works off
+// the store rather than a hook in CreateAlert, so the alerts every CLI run
+// and every rule engine creates are notified by the one dashboard that runs
+// it. Which alerts it has notified is kept in memory: after a restart the
+// active alerts are notified once more.
+type Dispatcher struct {
+	Store *metrics.MetricsStore
+	// Path is the routes file, reloaded by Run whenever it changes.
+	Path     string
+	Silences *SilenceStore
+	Interval time.Duration
+	// SlackOptions configure the clients of the Slack receivers, e.g.
+	// with message templates. Set them before the routes are loaded.
+	SlackOptions []slack.Option
+
+	mu      sync.Mutex
+	config  Config
+	senders map[string]Notifier
+	groups  map[string]*group
+	modTime time.Time
+	size    int64
+	exists  bool
+}
+
+// group is the alerts of one receiver that are notified together.
+type group struct {
+	receiver string
+	timing   Timing
+	labels   map[string]string
+	alerts   []metrics.Alert
+	notified map[string]bool // alert IDs of the last notification
+	created  time.Time
+	sent     time.Time
+}
+
+// NewDispatcher returns a dispatcher over store with the routes at path and
+// the silences of silences.
+func NewDispatcher(store *metrics.MetricsStore, path string, silences *SilenceStore) *Dispatcher {
+	d := &Dispatcher{Store: store, Path: path, Silences: silences, Interval: DefaultInterval, groups: map[string]*group{}}
+	d.setConfig(Config{})
+	return d
+}
+
+// Config returns the current routes.
+func (d *Dispatcher) Config() Config {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.config
+}
+
+// SetConfig replaces the routes after validating them.
+func (d *Dispatcher) SetConfig(c Config) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.setConfig(c)
+	return nil
+}
+
+func (d *Dispatcher) setConfig(c Config) {
+	d.config = c
+	d.senders = map[string]Notifier{}
+	for _, r := range c.Receivers {
+		d.senders[r.Name] = r.notifier(d.SlackOptions)
+	}
+}
+
+// Reload reads Path again if it changed since the last load and reports
+// whether the routes changed. A file that goes away routes nothing; a file
+// that does not load keeps the routes in place until it is fixed.
+func (d *Dispatcher) Reload() (bool, error) {
+	if d.Path == "" {
+		return false, nil
+	}
+	fi, err := os.Stat(d.Path)
+	exists := err == nil
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+	d.mu.Lock()
+	unchanged := exists == d.exists && (!exists || fi.ModTime().Equal(d.modTime) && fi.Size() == d.size)
+	d.mu.Unlock()
+	if unchanged {
+		return false, nil
+	}
+	c, err := LoadConfigIfExists(d.Path)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	// remember the version either way, so a broken file is reported once
+	d.exists = exists
+	if exists {
+		d.modTime, d.size = fi.ModTime(), fi.Size()
+	}
+	if err != nil {
+		return false, err
+	}
+	d.setConfig(c)
+	return true, nil
+}
+
+// Dispatch groups the active alerts that are neither silenced nor
+// inhibited by receiver and sends the notifications due at now. A group
+// whose alerts all resolved gets a last notification saying so. A failed
+// send is retried on the next call.
+func (d *Dispatcher) Dispatch(now time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var silences []Silence
+	if d.Silences != nil {
+		var err error
+		if silences, err = d.Silences.Active(now); err != nil {
+			return err
+		}
+	}
+	active := d.Store.GetActiveAlerts()
+	labels := make([]map[string]string, len(active))
+	for i, a := range active {
+		labels[i] = Labels(a)
+	}
+	current := map[string]*group{}
+	var order []string
+	for i, a := range active {
+		if silenced(silences, labels[i]) || d.config.inhibited(labels[i], labels) {
+			continue
+		}
+		for _, r := range d.config.routes(labels[i]) {
+			gl := map[string]string{}
+			for _, k := range r.GroupBy {
+				if v, ok := labels[i][k]; ok {
+					gl[k] = v
+				}
+			}
+			key := r.Receiver + "\x00" + groupKey(gl)
+			g, ok := current[key]
+			if !ok {
+				g = &group{receiver: r.Receiver, timing: r.Timing, labels: gl}
+				current[key] = g
+				order = append(order, key)
+			}
+			g.alerts = append(g.alerts, a)
+		}
+	}
+	for key := range d.groups {
+		if _, ok := current[key]; !ok {
+			order = append(order, key)
+		}
+	}
+
+	// the alerts that resolved since they were notified
+	resolved := map[string]metrics.Alert{}
+	stillActive := map[string]bool{}
+	for _, a := range active {
+		stillActive[a.ID] = true
+	}
+	for _, a := range d.Store.GetAlerts() {
+		if a.Resolved {
+			resolved[a.ID] = a
+		}
+	}
+
+	var errs []error
+	for _, key := range order {
+		g, cur := d.groups[key], current[key]
+		if g == nil {
+			cur.created = now
+			cur.notified = map[string]bool{}
+			d.groups[key] = cur
+			g = cur
+		} else if cur != nil {
+			g.alerts, g.timing = cur.alerts, cur.timing
+		} else {
+			g.alerts = nil
+		}
+		firing := map[string]bool{}
+		joined := false
+		for _, a := range g.alerts {
+			firing[a.ID] = true
+			if !g.notified[a.ID] {
+				joined = true
+			}
+		}
+		var gone []metrics.Alert
+		for id := range g.notified {
+			if firing[id] {
+				continue
+			}
+			if a, ok := resolved[id]; ok || !stillActive[id] {
+				if !ok {
+					// trimmed from the store
+					a = metrics.Alert{ID: id}
+				}
+				gone = append(gone, a)
+			}
+		}
+		sort.Slice(gone, func(i, j int) bool { return gone[i].ID < gone[j].ID })
+		if len(g.alerts) == 0 && len(gone) == 0 {
+			// everything resolved before the group was notified, or was
+			// silenced or inhibited afterwards
+			delete(d.groups, key)
+			continue
+		}
+
+		due := false
+		switch {
+		case g.sent.IsZero():
+			due = now.Sub(g.created) >= time.Duration(g.timing.GroupWait)
+		case joined || len(gone) > 0:
+			due = now.Sub(g.sent) >= time.Duration(g.timing.GroupInterval)
+		default:
+			due = now.Sub(g.sent) >= time.Duration(g.timing.RepeatInterval)
+		}
+		if !due {
+			continue
+		}
+		sender, ok := d.senders[g.receiver]
+		if !ok {
+			errs = append(errs, fmt.Errorf("receiver %q: not configured", g.receiver))
+			continue
+		}
+		name, severity, message, metadata := notification(g, gone)
+		if err := sender.SendAlert(name, severity, message, metadata); err != nil {
+			errs = append(errs, fmt.Errorf("receiver %q: %w", g.receiver, err))
+			continue
+		}
+		if len(g.alerts) == 0 {
+			delete(d.groups, key)
+			continue
+		}
+		g.sent = now
+		g.notified = firing
+	}
+	return errors.Join(errs...)
+}
+
+// notification renders the notification of g, which also reports the
+// alerts of gone as resolved.
+func notification(g *group, gone []metrics.Alert) (name, severity, message string, metadata map[string]string) {
+	metadata = map[string]string{}
+	for k, v := range g.labels {
+		// the name is shown anyway
+		if k != LabelAlertName {
+			metadata[k] = v
+		}
+	}
+	if len(g.labels) > 0 {
+		metadata[MetaGroup] = groupKey(g.labels)
+	}
+	alerts := g.alerts
+	if len(alerts) == 0 {
+		alerts, severity = gone, SeverityResolved
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].Timestamp.Before(alerts[j].Timestamp) })
+	for _, a := range alerts {
+		if severity != SeverityResolved && severityRank[a.Severity] >= severityRank[severity] {
+			severity = a.Severity
+		}
+	}
+	name = g.labels[LabelAlertName]
+	if name == "" {
+		name = alerts[0].Name
+		for _, a := range alerts[1:] {
+			if a.Name != name {
+				name = fmt.Sprintf("%d alerts", len(alerts))
+				break
+			}
+		}
+	}
+	if len(alerts) == 1 && len(gone) == 0 {
+		for k, v := range alerts[0].Metadata {
+			metadata[k] = v
+		}
+		return name, severity, alerts[0].Message, metadata
+	}
+	var lines []string
+	for _, a := range alerts {
+		lines = append(lines, alertLine(a))
+	}
+	if severity != SeverityResolved {
+		metadata["firing"] = fmt.Sprint(len(alerts))
+		for _, a := range gone {
+			lines = append(lines, "Resolved: "+alertLine(a))
+		}
+	}
+	if len(gone) > 0 {
+		metadata["resolved"] = fmt.Sprint(len(gone))
+	}
+	return name, severity, strings.Join(lines, "\n"), metadata
+}
+
+// alertLine describes a in a notification of several alerts.
+func alertLine(a metrics.Alert) string {
+	if a.Name == "" {
+		return "• " + a.ID
+	}
+	return fmt.Sprintf("• [%s] %s: %s", a.Severity, a.Name, a.Message)
+}
+
+// Run reloads the routes and dispatches every Interval until done is
+// closed.
+func (d *Dispatcher) Run(done <-chan struct{}) {
+	t := time.NewTicker(d.Interval)
+	defer t.Stop()
+	for {
+		if _, err := d.Reload(); err != nil {
+			fmt.Fprintf(os.Stderr, "notification routes reload failed: %v\n", err)
+		}
+		if err := d.Dispatch(time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "notification error: %v\n", err)
+		}
+		select {
+		case <-done:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// groupKey renders labels sorted as k=v pairs.
+func groupKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + labels[k]
+	}
+	return strings.Join(parts, ",")
+}
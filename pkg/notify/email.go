@@ -0,0 +1,102 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Email sends notifications as plain-text mail through an SMTP server.
+// With a Username it authenticates with PLAIN, which net/smtp only allows
+// over TLS (STARTTLS is used when the server offers it) or to localhost.
+type Email struct {
+	// Addr is the server's host:port.
+	Addr     string
+	From     string
+	To       []string
+	Username string
+	Password string
+	// MaxRetries and Backoff retry failed deliveries as HTTP does.
+	MaxRetries int
+	Backoff    time.Duration
+	// SendMail delivers a message; smtp.SendMail unless set for tests.
+	SendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+	// Sleep is used to wait between retries. Inject for testing.
+	Sleep func(d time.Duration)
+	formatter
+}
+
+// NewEmail returns an email notifier sending from from to to through the
+// SMTP server at addr.
+func NewEmail(addr, from string, to []string) *Email {
+	e := &Email{Addr: addr, From: from, To: to, MaxRetries: 2, Backoff: 500 * time.Millisecond, SendMail: smtp.SendMail}
+	e.formatter = formatter{e.Send}
+	return e
+}
+
+// Message returns the mail sent for n, headers included.
+func (e *Email) Message(n Notification) []byte {
+	subject := "[missionctl] " + n.Title
+	if n.Subject != "" && n.Kind != KindMetrics {
+		subject += ": " + n.Subject
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", e.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(e.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", n.Timestamp.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(n.Text, "\n", "\r\n") + "\r\n")
+	if len(n.Fields) > 0 {
+		b.WriteString("\r\n")
+	}
+	for _, f := range n.Fields {
+		fmt.Fprintf(&b, "%s: %s\r\n", f.Name, f.Value)
+	}
+	return []byte(b.String())
+}
+
+// Send mails n to every recipient.
+func (e *Email) Send(n Notification) error {
+	if e.Addr == "" || e.From == "" || len(e.To) == 0 {
+		return fmt.Errorf("email notifier needs an SMTP address, a sender and recipients")
+	}
+	var auth smtp.Auth
+	if e.Username != "" {
+		host, _, err := net.SplitHostPort(e.Addr)
+		if err != nil {
+			return fmt.Errorf("email notifier: invalid SMTP address %q", e.Addr)
+		}
+		auth = smtp.PlainAuth("", e.Username, e.Password, host)
+	}
+	send := e.SendMail
+	if send == nil {
+		send = smtp.SendMail
+	}
+	msg := e.Message(n)
+	var err error
+	for attempt := 0; attempt <= e.MaxRetries; attempt++ {
+		if attempt > 0 {
+			d := e.Backoff * (1 << (attempt - 1))
+			if e.Sleep != nil {
+				e.Sleep(d)
+			} else {
+				time.Sleep(d)
+			}
+		}
+		if err = send(e.Addr, auth, e.From, e.To, msg); err == nil {
+			return nil
+		}
+		// 5xx replies are permanent
+		var reply *textproto.Error
+		if errors.As(err, &reply) && reply.Code >= 500 {
+			break
+		}
+	}
+	return fmt.Errorf("failed to send email: %w", err)
+}
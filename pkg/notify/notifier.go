@@ -0,0 +1,326 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/yourusername/devops-mission-control/pkg/slack"
+)
+
+// Notifier delivers missionctl's notifications to one destination.
+// *slack.Client is one; Teams, Webhook, Email and PagerDuty are the
+// others.
+type Notifier interface {
+	SendAlert(alertName, severity, message string, metadata map[string]string) error
+	SendDeployment(app, status, version string, metadata map[string]string) error
+	SendOperationStatus(operation, status string, duration float64, metadata map[string]string) error
+	SendResourceStatus(resource, resourceType, status string, stats map[string]string) error
+	SendMetricsSummary(title string, metrics map[string]interface{}) error
+}
+
+var _ Notifier = (*slack.Client)(nil)
+
+// Kinds of notification.
+const (
+	KindAlert      = "alert"
+	KindDeployment = "deployment"
+	KindOperation  = "operation"
+	KindResource   = "resource"
+	KindMetrics    = "metrics"
+)
+
+// Levels of notification, which notifiers turn into colours and
+// severities.
+const (
+	LevelCritical = "critical"
+	LevelWarning  = "warning"
+	LevelInfo     = "info"
+	LevelOK       = "ok"
+)
+
+// Notification is what the Send methods of a Notifier describe, in a form
+// every destination can render. Subject is what the notification is about:
+// the alert, app, operation or resource.
+type Notification struct {
+	Kind      string    `json:"kind"`
+	Title     string    `json:"title"`
+	Subject   string    `json:"subject"`
+	Status    string    `json:"status"`
+	Level     string    `json:"level"`
+	Text      string    `json:"text"`
+	Fields    []Field   `json:"fields,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Field is a name and value shown with a notification.
+type Field struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Field returns the value of the field called name, or "".
+func (n Notification) Field(name string) string {
+	for _, f := range n.Fields {
+		if f.Name == name {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// sortedFields appends the entries of m to fields, sorted by key.
+func sortedFields(fields []Field, m map[string]string) []Field {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fields = append(fields, Field{k, m[k]})
+	}
+	return fields
+}
+
+// statusLevel is the level of a deployment or operation status.
+func statusLevel(status string) string {
+	switch status {
+	case "failed":
+		return LevelCritical
+	case "pending":
+		return LevelInfo
+	}
+	return LevelOK
+}
+
+// AlertNotification describes an alert; a severity of "resolved" says it
+// is over.
+func AlertNotification(alertName, severity, message string, metadata map[string]string) Notification {
+	n := Notification{Kind: KindAlert, Title: "⚠️ Alert Triggered", Subject: alertName, Status: severity, Level: LevelInfo, Text: message, Timestamp: time.Now()}
+	switch severity {
+	case "critical":
+		n.Level = LevelCritical
+	case "warning":
+		n.Level = LevelWarning
+	case SeverityResolved:
+		n.Title, n.Level = "✅ Alert Resolved", LevelOK
+	}
+	n.Fields = sortedFields([]Field{{"Severity", severity}, {"Alert", alertName}}, metadata)
+	return n
+}
+
+// DeploymentNotification describes a deployment.
+func DeploymentNotification(app, status, version string, metadata map[string]string) Notification {
+	emoji := map[string]string{LevelCritical: "❌", LevelInfo: "⏳", LevelOK: "✅"}[statusLevel(status)]
+	n := Notification{Kind: KindDeployment, Title: emoji + " Deployment Notification", Subject: app, Status: status, Level: statusLevel(status),
+		Text: fmt.Sprintf("%s deployment of %s", status, app), Timestamp: time.Now()}
+	n.Fields = sortedFields([]Field{{"App", app}, {"Status", status}, {"Version", version}}, metadata)
+	return n
+}
+
+// OperationNotification describes the status of an operation that took
+// duration seconds.
+func OperationNotification(operation, status string, duration float64, metadata map[string]string) Notification {
+	emoji := map[string]string{LevelCritical: "❌", LevelInfo: "⏳", LevelOK: "✅"}[statusLevel(status)]
+	n := Notification{Kind: KindOperation, Title: emoji + " Operation Status", Subject: operation, Status: status, Level: statusLevel(status),
+		Text: fmt.Sprintf("%s: %s", operation, status), Timestamp: time.Now()}
+	n.Fields = sortedFields([]Field{{"Operation", operation}, {"Status", status}, {"Duration", fmt.Sprintf("%.2f seconds", duration)}}, metadata)
+	return n
+}
+
+// ResourceNotification describes the status of a resource.
+func ResourceNotification(resource, resourceType, status string, stats map[string]string) Notification {
+	n := Notification{Kind: KindResource, Title: "📊 Resource Status", Subject: resource, Status: status, Level: LevelOK,
+		Text: fmt.Sprintf("%s is %s", resource, status), Timestamp: time.Now()}
+	switch status {
+	case "unhealthy", "error":
+		n.Level = LevelCritical
+	case "degraded", "warning":
+		n.Level = LevelWarning
+	}
+	n.Fields = sortedFields([]Field{{"Resource", resource}, {"Type", resourceType}, {"Status", status}}, stats)
+	return n
+}
+
+// MetricsNotification describes a metrics summary.
+func MetricsNotification(title string, metrics map[string]interface{}) Notification {
+	values := make(map[string]string, len(metrics))
+	for k, v := range metrics {
+		values[k] = fmt.Sprintf("%v", v)
+	}
+	return Notification{Kind: KindMetrics, Title: "📈 Metrics Summary", Subject: title, Level: LevelInfo, Text: title,
+		Fields: sortedFields(nil, values), Timestamp: time.Now()}
+}
+
+// formatter implements Notifier over a function that delivers
+// Notifications; the notifiers of this package embed it.
+type formatter struct {
+	send func(Notification) error
+}
+
+func (f formatter) SendAlert(alertName, severity, message string, metadata map[string]string) error {
+	return f.send(AlertNotification(alertName, severity, message, metadata))
+}
+
+func (f formatter) SendDeployment(app, status, version string, metadata map[string]string) error {
+	return f.send(DeploymentNotification(app, status, version, metadata))
+}
+
+func (f formatter) SendOperationStatus(operation, status string, duration float64, metadata map[string]string) error {
+	return f.send(OperationNotification(operation, status, duration, metadata))
+}
+
+func (f formatter) SendResourceStatus(resource, resourceType, status string, stats map[string]string) error {
+	return f.send(ResourceNotification(resource, resourceType, status, stats))
+}
+
+func (f formatter) SendMetricsSummary(title string, metrics map[string]interface{}) error {
+	return f.send(MetricsNotification(title, metrics))
+}
+
+// HTTP is the delivery plumbing shared by the notifiers that post JSON:
+// like the Slack client, it retries network errors, 429 and 5xx responses
+// with exponential backoff and logs every attempt.
+type HTTP struct {
+	Client *http.Client
+	// MaxRetries controls how many times to retry transient failures (0 = no retries)
+	MaxRetries int
+	// Backoff is the base backoff duration used for exponential backoff between retries
+	Backoff time.Duration
+	Logger  slack.Logger
+	// Sleep is used to wait between retries. Inject for testing.
+	Sleep func(d time.Duration)
+	// Header is added to every request.
+	Header http.Header
+}
+
+// Option configures the HTTP plumbing of a notifier.
+type Option func(*HTTP)
+
+// WithHTTPClient sets a custom HTTP client.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(h *HTTP) { h.Client = hc }
+}
+
+// WithMaxRetries sets number of retries for transient failures.
+func WithMaxRetries(n int) Option {
+	return func(h *HTTP) { h.MaxRetries = n }
+}
+
+// WithBackoff sets base backoff duration.
+func WithBackoff(d time.Duration) Option {
+	return func(h *HTTP) { h.Backoff = d }
+}
+
+// WithSleeper sets a custom sleep function (useful for tests).
+func WithSleeper(sleep func(time.Duration)) Option {
+	return func(h *HTTP) { h.Sleep = sleep }
+}
+
+// WithLogger sets the logger of delivery attempts.
+func WithLogger(l slack.Logger) Option {
+	return func(h *HTTP) { h.Logger = l }
+}
+
+// WithHeader adds a header to every request, e.g. Authorization.
+func WithHeader(key, value string) Option {
+	return func(h *HTTP) {
+		if h.Header == nil {
+			h.Header = http.Header{}
+		}
+		h.Header.Add(key, value)
+	}
+}
+
+// newHTTP returns the plumbing with the Slack client's defaults: two
+// retries, 500ms backoff and a 10s timeout.
+func newHTTP(opts []Option) HTTP {
+	h := HTTP{
+		Client:     &http.Client{Timeout: 10 * time.Second},
+		MaxRetries: 2,
+		Backoff:    500 * time.Millisecond,
+		Logger:     stdLogger{log.Default()},
+	}
+	for _, o := range opts {
+		o(&h)
+	}
+	if h.Client == nil {
+		h.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if h.Backoff <= 0 {
+		h.Backoff = 500 * time.Millisecond
+	}
+	if h.Logger == nil {
+		h.Logger = stdLogger{log.Default()}
+	}
+	return h
+}
+
+// PostJSON posts the JSON encoding of v to url and returns the response
+// body of the first 2xx answer.
+func (h *HTTP) PostJSON(url string, v any) ([]byte, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal notification: %w", err)
+	}
+	return h.Post(url, "application/json", payload)
+}
+
+// Post posts payload to url and returns the response body of the first
+// 2xx answer.
+func (h *HTTP) Post(url, contentType string, payload []byte) ([]byte, error) {
+	var lastErr error
+	for attempt := 0; attempt <= h.MaxRetries; attempt++ {
+		if attempt > 0 {
+			h.wait(h.Backoff * (1 << (attempt - 1)))
+		}
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("notification request error: %s", err)
+		}
+		req.Header.Set("Content-Type", contentType)
+		for k, vs := range h.Header {
+			for _, v := range vs {
+				req.Header.Add(k, v)
+			}
+		}
+		resp, err := h.Client.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to send notification: %w", err)
+			h.Logger.Errorf("notification attempt %d/%d failed: %v", attempt+1, h.MaxRetries+1, err)
+			continue
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		h.Logger.Infof("notification response attempt %d status=%d", attempt+1, resp.StatusCode)
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return body, nil
+		}
+		lastErr = fmt.Errorf("%s returned status %d: %s", req.URL.Redacted(), resp.StatusCode, body)
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return nil, lastErr
+		}
+	}
+	return nil, lastErr
+}
+
+func (h *HTTP) wait(d time.Duration) {
+	if h.Sleep != nil {
+		h.Sleep(d)
+	} else {
+		time.Sleep(d)
+	}
+}
+
+// stdLogger logs through the standard library logger, as the Slack
+// client does by default.
+type stdLogger struct{ l *log.Logger }
+
+func (s stdLogger) WithFields(map[string]interface{}) slack.Logger { return s }
+func (s stdLogger) Infof(format string, v ...interface{})          { s.l.Printf(format, v...) }
+func (s stdLogger) Errorf(format string, v ...interface{})         { s.l.Printf("ERROR: "+format, v...) }
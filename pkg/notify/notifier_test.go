@@ -0,0 +1,256 @@
+package notify
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// request is what a fake endpoint received.
+type request struct {
+	path   string
+	header http.Header
+	body   map[string]any
+}
+
+// endpoint answers with the given statuses in turn, then 200, and records
+// the requests.
+func endpoint(t *testing.T, statuses ...int) (*httptest.Server, func() []request) {
+	var mu sync.Mutex
+	var got []request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		raw, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		if err := json.Unmarshal(raw, &body); err != nil {
+			t.Errorf("%s: invalid JSON %s", r.URL.Path, raw)
+		}
+		got = append(got, request{r.URL.Path, r.Header, body})
+		if len(statuses) > 0 {
+			w.WriteHeader(statuses[0])
+			statuses = statuses[1:]
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []request {
+		mu.Lock()
+		defer mu.Unlock()
+		out := got
+		got = nil
+		return out
+	}
+}
+
+// path returns the value at the dotted path of v, indexing arrays by
+// number.
+func path(v any, p string) any {
+	for _, k := range strings.Split(p, ".") {
+		switch x := v.(type) {
+		case map[string]any:
+			v = x[k]
+		case []any:
+			i := int(k[0] - '0')
+			if i >= len(x) {
+				return nil
+			}
+			v = x[i]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+func noSleep(time.Duration) {}
+
+func TestTeams(t *testing.T) {
+	srv, got := endpoint(t, http.StatusServiceUnavailable)
+	var n Notifier = NewTeams(srv.URL, WithSleeper(noSleep), WithBackoff(time.Millisecond))
+	if err := n.SendAlert("disk-full", "critical", "disk 99% on db-1", map[string]string{"host": "db-1"}); err != nil {
+		t.Fatal(err)
+	}
+	reqs := got()
+	if len(reqs) != 2 {
+		t.Fatalf("the 503 should be retried: %d requests", len(reqs))
+	}
+	card := path(reqs[1].body, "attachments.0.content")
+	if path(card, "type") != "AdaptiveCard" || path(card, "body.0.text") != "⚠️ Alert Triggered" ||
+		path(card, "body.0.color") != "Attention" || path(card, "body.1.text") != "disk 99% on db-1" ||
+		path(card, "body.2.facts.2.title") != "host" || path(card, "body.2.facts.2.value") != "db-1" {
+		t.Fatalf("card %+v", card)
+	}
+	if err := n.SendDeployment("web", "success", "v1.2", nil); err != nil {
+		t.Fatal(err)
+	}
+	if card := path(got()[0].body, "attachments.0.content"); path(card, "body.0.color") != "Good" || path(card, "body.1.text") != "success deployment of web" {
+		t.Fatalf("deployment card %+v", card)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	srv, got := endpoint(t, http.StatusBadRequest)
+	tmpl, err := ParseWebhookTemplate(`{"text": {{json .Title}}, "summary": {{json .Text}}, "host": {{json (.Field "host")}}, "level": "{{.Level}}"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n Notifier = NewWebhook(srv.URL, tmpl, WithHeader("Authorization", "Bearer s3cret"), WithSleeper(noSleep))
+	// a 400 is not retried
+	if err := n.SendResourceStatus("db-1", "vm", "degraded", map[string]string{"host": "db-1"}); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Fatalf("want the 400, got %v", err)
+	}
+	if reqs := got(); len(reqs) != 1 {
+		t.Fatalf("%d requests", len(reqs))
+	}
+	if err := n.SendResourceStatus("db-1", "vm", "degraded", map[string]string{"host": "db-1"}); err != nil {
+		t.Fatal(err)
+	}
+	r := got()[0]
+	if r.header.Get("Authorization") != "Bearer s3cret" || r.body["text"] != "📊 Resource Status" ||
+		r.body["summary"] != "db-1 is degraded" || r.body["host"] != "db-1" || r.body["level"] != LevelWarning {
+		t.Fatalf("webhook got %+v %+v", r.header, r.body)
+	}
+
+	// the default template posts the notification itself
+	if err := NewWebhook(srv.URL, nil).SendMetricsSummary("daily", map[string]interface{}{"p95": 1.5}); err != nil {
+		t.Fatal(err)
+	}
+	if b := got()[0].body; b["kind"] != KindMetrics || path(b, "fields.0.name") != "p95" || path(b, "fields.0.value") != "1.5" {
+		t.Fatalf("default body %+v", b)
+	}
+
+	// a template that does not render JSON fails before sending
+	bad, _ := ParseWebhookTemplate(`{"text": {{.Text}}}`)
+	if err := NewWebhook(srv.URL, bad).SendAlert("x", "info", "not json", nil); err == nil {
+		t.Fatal("invalid JSON should fail")
+	}
+	if reqs := got(); len(reqs) != 0 {
+		t.Fatalf("sent %+v", reqs)
+	}
+}
+
+func TestPagerDuty(t *testing.T) {
+	srv, got := endpoint(t)
+	p := NewPagerDuty("R0UT1NG", WithSleeper(noSleep))
+	p.URL, p.Source = srv.URL, "mc-test"
+	group := map[string]string{MetaGroup: "alertname=disk-full,host=db-1"}
+	if err := p.SendAlert("disk-full", "critical", "disk 99%", group); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SendAlert("disk-full", SeverityResolved, "disk 99%", group); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SendOperationStatus("terraform apply", "failed", 12.5, nil); err != nil {
+		t.Fatal(err)
+	}
+	reqs := got()
+	if len(reqs) != 3 {
+		t.Fatalf("%d requests", len(reqs))
+	}
+	trigger, resolve, change := reqs[0], reqs[1], reqs[2]
+	if trigger.path != "/v2/enqueue" || trigger.body["routing_key"] != "R0UT1NG" || trigger.body["event_action"] != "trigger" ||
+		trigger.body["dedup_key"] != "missionctl/alertname=disk-full,host=db-1" || path(trigger.body, "payload.severity") != "critical" ||
+		path(trigger.body, "payload.source") != "mc-test" || path(trigger.body, "payload.summary") != "⚠️ Alert Triggered: disk 99%" {
+		t.Fatalf("trigger %+v", trigger.body)
+	}
+	if resolve.body["event_action"] != "resolve" || resolve.body["dedup_key"] != trigger.body["dedup_key"] || resolve.body["payload"] != nil {
+		t.Fatalf("resolve %+v", resolve.body)
+	}
+	if change.path != "/v2/change/enqueue" || path(change.body, "payload.custom_details.Duration") != "12.50 seconds" {
+		t.Fatalf("change %s %+v", change.path, change.body)
+	}
+}
+
+func TestEmail(t *testing.T) {
+	addr, mails := smtpServer(t)
+	e := NewEmail(addr, "missionctl@example.com", []string{"oncall@example.com", "ops@example.com"})
+	e.Sleep = noSleep
+	var n Notifier = e
+	if err := n.SendAlert("disk-full", "critical", "disk 99% on db-1", map[string]string{"host": "db-1"}); err != nil {
+		t.Fatal(err)
+	}
+	m := <-mails
+	if m.from != "missionctl@example.com" || strings.Join(m.to, ",") != "oncall@example.com,ops@example.com" {
+		t.Fatalf("envelope %+v", m)
+	}
+	for _, want := range []string{"To: oncall@example.com, ops@example.com\r\n", "Subject: =?utf-8?q?", "\r\n\r\ndisk 99% on db-1\r\n", "host: db-1\r\n"} {
+		if !strings.Contains(m.data, want) {
+			t.Errorf("mail lacks %q:\n%s", want, m.data)
+		}
+	}
+}
+
+// mail is a message the fake SMTP server accepted.
+type mail struct {
+	from string
+	to   []string
+	data string
+}
+
+// smtpServer accepts mail on a local port without authentication.
+func smtpServer(t *testing.T) (string, <-chan mail) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	mails := make(chan mail, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				reply := func(s string) { _, _ = io.WriteString(conn, s+"\r\n") }
+				reply("220 localhost ESMTP")
+				var m mail
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					cmd := strings.ToUpper(strings.TrimSpace(line))
+					switch {
+					case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+						reply("250 localhost")
+					case strings.HasPrefix(cmd, "MAIL FROM:"):
+						m.from = strings.Trim(strings.TrimSpace(line)[10:], "<>")
+						reply("250 OK")
+					case strings.HasPrefix(cmd, "RCPT TO:"):
+						m.to = append(m.to, strings.Trim(strings.TrimSpace(line)[8:], "<>"))
+						reply("250 OK")
+					case cmd == "DATA":
+						reply("354 go ahead")
+						var b strings.Builder
+						for {
+							l, err := r.ReadString('\n')
+							if err != nil || l == ".\r\n" {
+								break
+							}
+							b.WriteString(l)
+						}
+						m.data = b.String()
+						mails <- m
+						reply("250 OK")
+					case cmd == "QUIT":
+						reply("221 bye")
+						return
+					default:
+						reply("250 OK")
+					}
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String(), mails
+}
@@ -0,0 +1,287 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yourusername/devops-mission-control/pkg/metrics"
+	"github.com/yourusername/devops-mission-control/pkg/slack"
+)
+
+const routesYAML = `receiver: oncall
+group_wait: 30s
+group_interval: 5m
+repeat_interval: 1h
+receivers:
+  - name: oncall
+    slack:
+      webhook_url: URL/oncall
+      channel: "#oncall"
+      max_retries: 0
+  - name: acme
+    slack:
+      webhook_url: URL/acme
+routes:
+  - match: [tenant=acme]
+    receiver: acme
+    group_by: [tenant]
+    group_wait: 1s
+    continue: true
+  - match: [severity=critical]
+    receiver: oncall
+inhibit:
+  - source: [severity=critical]
+    target: [severity=warning]
+    equal: [host]
+`
+
+// posted is a message a fake Slack webhook received.
+type posted struct {
+	receiver string
+	msg      slack.Message
+}
+
+// slackServer records what receivers post, by the last element of the
+// webhook path.
+func slackServer(t *testing.T) (*httptest.Server, func() []posted) {
+	var mu sync.Mutex
+	var got []posted
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg slack.Message
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		mu.Lock()
+		got = append(got, posted{strings.TrimPrefix(r.URL.Path, "/"), msg})
+		mu.Unlock()
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []posted {
+		mu.Lock()
+		defer mu.Unlock()
+		out := got
+		got = nil
+		return out
+	}
+}
+
+func writeRoutes(t *testing.T, url string) string {
+	path := filepath.Join(t.TempDir(), "notify.yaml")
+	if err := os.WriteFile(path, []byte(strings.ReplaceAll(routesYAML, "URL", url)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	c, err := LoadConfig(writeRoutes(t, "http://127.0.0.1:9"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := c.Routes[0]
+	if time.Duration(r.GroupWait) != time.Second || time.Duration(r.RepeatInterval) != time.Hour ||
+		len(r.GroupBy) != 1 || r.GroupBy[0] != "tenant" || c.GroupBy[0] != LabelAlertName ||
+		c.Receivers[0].Slack.Channel != "#oncall" || *c.Receivers[0].Slack.MaxRetries != 0 {
+		t.Fatalf("loaded %+v", c)
+	}
+	if got := c.routes(map[string]string{"tenant": "acme", "severity": "critical"}); len(got) != 2 || got[0].Receiver != "acme" || got[1].Receiver != "oncall" {
+		t.Fatalf("continue should go on to the next route: %+v", got)
+	}
+	if got := c.routes(map[string]string{"tenant": "acme", "severity": "warning"}); len(got) != 1 || got[0].Receiver != "acme" {
+		t.Fatalf("a matched alert does not go to the default receiver: %+v", got)
+	}
+	if got := c.routes(map[string]string{"severity": "info"}); len(got) != 1 || got[0].Receiver != "oncall" {
+		t.Fatalf("default receiver: %+v", got)
+	}
+	for _, bad := range []string{
+		"receivers:\n  - name: x\n",
+		"receivers:\n  - name: x\n    teams: {webhook_url: u}\n    pagerduty: {routing_key: k}\n",
+		"receivers:\n  - name: x\n    email: {smtp: localhost:25, from: a@b}\n",
+		"receivers:\n  - name: x\n    slack: {token_env: SLACK_BOT_TOKEN}\n",
+		"receivers:\n  - name: x\n    webhook: {url: u, template: '{{.Nope'}\n",
+		"receiver: nobody\nreceivers: []\n",
+		"receivers: []\nroutes:\n  - receiver: nobody\n",
+		"receivers:\n  - name: x\n    slack:\n      webhook_url: u\nroutes:\n  - receiver: x\n    match: [severity]\n",
+		"receivers: []\ninhibit:\n  - source: [severity=critical]\n",
+		"receivers: []\nrepeat_interval: soon\n",
+	} {
+		path := filepath.Join(t.TempDir(), "notify.yaml")
+		if err := os.WriteFile(path, []byte(bad), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("%q should be rejected", bad)
+		}
+	}
+	if c, err := LoadConfigIfExists(filepath.Join(t.TempDir(), "none.yaml")); err != nil || len(c.routes(nil)) != 0 {
+		t.Fatalf("missing file: %+v %v", c, err)
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	srv, sent := slackServer(t)
+	store := metrics.NewMetricsStore(100)
+	d := NewDispatcher(store, writeRoutes(t, srv.URL), nil)
+	if changed, err := d.Reload(); err != nil || !changed {
+		t.Fatalf("load: %v %v", changed, err)
+	}
+	create := func(name, severity, msg string, md map[string]string) string {
+		if err := store.CreateAlert(name, severity, msg, md); err != nil {
+			t.Fatal(err)
+		}
+		a := store.GetAlerts()
+		return a[len(a)-1].ID
+	}
+	create("high-cpu", "warning", "cpu 91 on web-1", map[string]string{"host": "web-1"})
+	web2 := create("high-cpu", "warning", "cpu 92 on web-2", map[string]string{"host": "web-2"})
+	create("host-down", "critical", "web-1 is down", map[string]string{"host": "web-1"})
+	acme := create("high-cpu", "critical", "cpu 99 on web-3", map[string]string{"host": "web-3", "tenant": "acme"})
+
+	now := time.Now()
+	dispatch := func(at time.Duration) []posted {
+		t.Helper()
+		if err := d.Dispatch(now.Add(at)); err != nil {
+			t.Fatal(err)
+		}
+		return sent()
+	}
+	if got := dispatch(0); len(got) != 0 {
+		t.Fatalf("sent before group_wait: %+v", got)
+	}
+	got := dispatch(2 * time.Second)
+	if len(got) != 1 || got[0].receiver != "acme" {
+		t.Fatalf("the acme route waits 1s: %+v", got)
+	}
+	if a := got[0].msg.Attachments[0]; a.Text != "cpu 99 on web-3" || a.Color != "#ff0000" {
+		t.Fatalf("acme got %+v", a)
+	}
+
+	// high-cpu is grouped by alertname, and the warning on web-1 is
+	// inhibited by web-1 being down
+	got = dispatch(31 * time.Second)
+	if len(got) != 2 {
+		t.Fatalf("want one notification per group, got %+v", got)
+	}
+	texts := got[0].msg.Attachments[0].Text + "\n" + got[1].msg.Attachments[0].Text
+	if got[0].receiver != "oncall" || got[0].msg.Channel != "#oncall" || strings.Contains(texts, "web-1\n") ||
+		!strings.Contains(texts, "• [warning] high-cpu: cpu 92 on web-2\n• [critical] high-cpu: cpu 99 on web-3") ||
+		!strings.Contains(texts, "web-1 is down") {
+		t.Fatalf("oncall got:\n%s", texts)
+	}
+	if got := dispatch(40 * time.Second); len(got) != 0 {
+		t.Fatalf("repeated too early: %+v", got)
+	}
+
+	// a silenced alert is dropped without a notification and still
+	// inhibits the warning on web-1
+	silences := NewSilenceStore(filepath.Join(t.TempDir(), "silences.json"))
+	if _, err := silences.Add(Silence{Match: []string{"alertname=host-down"}, StartsAt: now, EndsAt: now.Add(4 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	d.Silences = silences
+	if got := dispatch(50 * time.Second); len(got) != 0 {
+		t.Fatalf("silenced: %+v", got)
+	}
+
+	// a resolved alert is reported once the group interval passed
+	if err := store.ResolveAlert(web2); err != nil {
+		t.Fatal(err)
+	}
+	if got := dispatch(time.Minute); len(got) != 0 {
+		t.Fatalf("sent before group_interval: %+v", got)
+	}
+	got = dispatch(31*time.Second + 5*time.Minute)
+	if len(got) != 1 || got[0].receiver != "oncall" || got[0].msg.Attachments[0].Text !=
+		"• [critical] high-cpu: cpu 99 on web-3\nResolved: • [warning] high-cpu: cpu 92 on web-2" {
+		t.Fatalf("after resolving web-2: %+v", got)
+	}
+	// the remaining alert repeats every repeat_interval
+	if got := dispatch(31*time.Second + 5*time.Minute + time.Hour); len(got) != 2 {
+		t.Fatalf("repeat: %+v", got)
+	}
+
+	// resolving the last alert of a group ends it with a resolved notice
+	if err := store.ResolveAlert(acme); err != nil {
+		t.Fatal(err)
+	}
+	got = dispatch(2 * time.Hour)
+	if len(got) != 2 {
+		t.Fatalf("resolved notices: %+v", got)
+	}
+	for _, p := range got {
+		if a := p.msg.Attachments[0]; a.Title != "✅ Alert Resolved" || !strings.Contains(a.Text, "cpu 99 on web-3") {
+			t.Fatalf("%s got %+v", p.receiver, a)
+		}
+	}
+	if got := dispatch(3 * time.Hour); len(got) != 0 {
+		t.Fatalf("ended groups must stay quiet: %+v", got)
+	}
+}
+
+func TestDispatcherSlackTemplates(t *testing.T) {
+	srv, sent := slackServer(t)
+	store := metrics.NewMetricsStore(100)
+	templates := &slack.Templates{}
+	if err := templates.Add("alert.tmpl", `{{.Tenant}}: {{.Name}} {{.Link}}`); err != nil {
+		t.Fatal(err)
+	}
+	d := NewDispatcher(store, writeRoutes(t, srv.URL), nil)
+	d.SlackOptions = []slack.Option{slack.WithTemplates(templates), slack.WithDashboardURL("https://mc.example.com")}
+	if _, err := d.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CreateAlert("high-cpu", "critical", "cpu 99 on web-3", map[string]string{"tenant": "acme"}); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for _, at := range []time.Time{now, now.Add(2 * time.Second)} {
+		if err := d.Dispatch(at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := sent(); len(got) != 1 || got[0].msg.Text != "acme: high-cpu https://mc.example.com/#alerts" {
+		t.Fatalf("templated: %+v", got)
+	}
+}
+
+func TestSilenceStore(t *testing.T) {
+	s := NewSilenceStore(filepath.Join(t.TempDir(), "silences.json"))
+	if list, err := s.List(); err != nil || len(list) != 0 {
+		t.Fatalf("empty store: %+v %v", list, err)
+	}
+	now := time.Now()
+	sil, err := s.Add(Silence{Match: []string{"severity=warning", "host!=db-*"}, EndsAt: now.Add(2 * time.Hour), CreatedBy: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sil.ID == "" || sil.StartsAt.IsZero() {
+		t.Fatalf("added %+v", sil)
+	}
+	if !sil.Matches(map[string]string{"severity": "warning", "host": "web-1"}) || sil.Matches(map[string]string{"severity": "warning", "host": "db-1"}) {
+		t.Fatal("matchers")
+	}
+	if active, err := s.Active(now.Add(3 * time.Hour)); err != nil || len(active) != 0 {
+		t.Fatalf("an ended silence is not active: %+v %v", active, err)
+	}
+	for _, bad := range []Silence{{EndsAt: now.Add(time.Hour)}, {Match: []string{"severity"}, EndsAt: now.Add(time.Hour)}, {Match: []string{"a=b"}, EndsAt: now.Add(-time.Hour)}} {
+		if _, err := s.Add(bad); err == nil {
+			t.Errorf("%+v should be rejected", bad)
+		}
+	}
+	if err := s.Expire(sil.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Expire(sil.ID); err == nil {
+		t.Fatal("expiring twice should fail")
+	}
+	if list, err := s.List(); err != nil || len(list) != 0 {
+		t.Fatalf("after expire: %+v %v", list, err)
+	}
+}
@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultPagerDutyURL is the Events API v2 host.
+const DefaultPagerDutyURL = "https://events.pagerduty.com"
+
+// PagerDuty sends notifications to a PagerDuty Events API v2 integration,
+// or any service speaking the same API. Alerts and unhealthy resources
+// trigger incidents, which their resolved notifications resolve;
+// deployments, operations and metrics summaries are change events.
+type PagerDuty struct {
+	// URL is the API host, DefaultPagerDutyURL by default.
+	URL        string
+	RoutingKey string
+	// Source names the system the events come from, the host name by
+	// default.
+	Source string
+	HTTP
+	formatter
+}
+
+// NewPagerDuty returns a notifier sending to the integration of
+// routingKey.
+func NewPagerDuty(routingKey string, opts ...Option) *PagerDuty {
+	source, _ := os.Hostname()
+	if source == "" {
+		source = "missionctl"
+	}
+	p := &PagerDuty{URL: DefaultPagerDutyURL, RoutingKey: routingKey, Source: source, HTTP: newHTTP(opts)}
+	p.formatter = formatter{p.Send}
+	return p
+}
+
+// pagerDutySeverities maps levels to event severities.
+var pagerDutySeverities = map[string]string{LevelCritical: "critical", LevelWarning: "warning", LevelInfo: "info", LevelOK: "info"}
+
+// DedupKey is the incident key of n: its group when the dispatcher sent
+// it, so a group's resolved notification resolves the incident its first
+// notification opened, else its kind and subject.
+func DedupKey(n Notification) string {
+	if g := n.Field(MetaGroup); g != "" {
+		return "missionctl/" + g
+	}
+	return "missionctl/" + n.Kind + "/" + n.Subject
+}
+
+// Send posts n as an alert or change event.
+func (p *PagerDuty) Send(n Notification) error {
+	base := strings.TrimSuffix(p.URL, "/")
+	if base == "" {
+		base = DefaultPagerDutyURL
+	}
+	details := map[string]string{}
+	for _, f := range n.Fields {
+		details[f.Name] = f.Value
+	}
+	summary := n.Title + ": " + n.Text
+	if len(summary) > 1024 {
+		summary = summary[:1024]
+	}
+	if n.Kind != KindAlert && n.Kind != KindResource {
+		_, err := p.PostJSON(base+"/v2/change/enqueue", map[string]any{
+			"routing_key": p.RoutingKey,
+			"payload": map[string]any{
+				"summary":        summary,
+				"source":         p.Source,
+				"timestamp":      n.Timestamp.Format(time.RFC3339),
+				"custom_details": details,
+			},
+		})
+		return err
+	}
+	event := map[string]any{
+		"routing_key":  p.RoutingKey,
+		"event_action": "trigger",
+		"dedup_key":    DedupKey(n),
+		"client":       "missionctl",
+	}
+	if n.Level == LevelOK {
+		event["event_action"] = "resolve"
+	} else {
+		event["payload"] = map[string]any{
+			"summary":        summary,
+			"source":         p.Source,
+			"severity":       pagerDutySeverities[n.Level],
+			"timestamp":      n.Timestamp.Format(time.RFC3339),
+			"component":      n.Subject,
+			"class":          n.Kind,
+			"custom_details": details,
+		}
+	}
+	_, err := p.PostJSON(base+"/v2/enqueue", event)
+	return err
+}
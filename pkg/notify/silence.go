@@ -0,0 +1,173 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Silence mutes the alerts matching all of Match between StartsAt and
+// EndsAt.
+type Silence struct {
+	ID        string    `json:"id"`
+	Match     []string  `json:"match"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	Comment   string    `json:"comment,omitempty"`
+}
+
+// Active reports whether s is in effect at now.
+func (s Silence) Active(now time.Time) bool {
+	return !now.Before(s.StartsAt) && now.Before(s.EndsAt)
+}
+
+// Matches reports whether s mutes an alert with labels. Matchers that do
+// not parse match nothing.
+func (s Silence) Matches(labels map[string]string) bool {
+	matchers, err := parseMatchers(s.Match)
+	return err == nil && len(matchers) > 0 && matchAll(matchers, labels)
+}
+
+// SilenceStore keeps silences in a JSON file that the CLI writes and the
+// dashboard reads. Writes go through a rename, so readers never see a
+// partial file, and drop the silences that have ended.
+type SilenceStore struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// NewSilenceStore returns a store on the file at path.
+func NewSilenceStore(path string) *SilenceStore {
+	return &SilenceStore{Path: path}
+}
+
+// List returns every silence, ended ones included until the next write.
+func (s *SilenceStore) List() ([]Silence, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read()
+}
+
+// Active returns the silences in effect at now.
+func (s *SilenceStore) Active(now time.Time) ([]Silence, error) {
+	all, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	var out []Silence
+	for _, sil := range all {
+		if sil.Active(now) {
+			out = append(out, sil)
+		}
+	}
+	return out, nil
+}
+
+// Add validates sil, gives it an ID and a start time if it has none, and
+// saves it.
+func (s *SilenceStore) Add(sil Silence) (Silence, error) {
+	if len(sil.Match) == 0 {
+		return sil, fmt.Errorf("silence needs at least one matcher")
+	}
+	if _, err := parseMatchers(sil.Match); err != nil {
+		return sil, err
+	}
+	now := time.Now()
+	if sil.StartsAt.IsZero() {
+		sil.StartsAt = now
+	}
+	if !sil.EndsAt.After(sil.StartsAt) {
+		return sil, fmt.Errorf("silence must end after it starts")
+	}
+	if sil.ID == "" {
+		sil.ID = fmt.Sprintf("silence_%d", now.UnixNano())
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.read()
+	if err != nil {
+		return sil, err
+	}
+	return sil, s.write(append(all, sil), now)
+}
+
+// Expire ends the silence with id and removes it.
+func (s *SilenceStore) Expire(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	all, err := s.read()
+	if err != nil {
+		return err
+	}
+	kept := all[:0]
+	for _, sil := range all {
+		if sil.ID != id {
+			kept = append(kept, sil)
+		}
+	}
+	if len(kept) == len(all) {
+		return fmt.Errorf("silence %s not found", id)
+	}
+	return s.write(kept, time.Now())
+}
+
+func (s *SilenceStore) read() ([]Silence, error) {
+	var all []Silence
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) || err == nil && len(data) == 0 {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, fmt.Errorf("invalid silences file %s: %w", s.Path, err)
+	}
+	return all, nil
+}
+
+// write replaces the file with the silences of all that have not ended.
+func (s *SilenceStore) write(all []Silence, now time.Time) error {
+	kept := []Silence{}
+	for _, sil := range all {
+		if now.Before(sil.EndsAt) {
+			kept = append(kept, sil)
+		}
+	}
+	data, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), "."+filepath.Base(s.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.Path)
+}
+
+// silenced reports whether one of silences mutes an alert with labels.
+func silenced(silences []Silence, labels map[string]string) bool {
+	for _, sil := range silences {
+		if sil.Matches(labels) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package notify
+
+// Teams posts Adaptive Cards to a Microsoft Teams incoming webhook or
+// workflow URL.
+type Teams struct {
+	WebhookURL string
+	HTTP
+	formatter
+}
+
+// NewTeams returns a Teams notifier posting to webhookURL.
+func NewTeams(webhookURL string, opts ...Option) *Teams {
+	t := &Teams{WebhookURL: webhookURL, HTTP: newHTTP(opts)}
+	t.formatter = formatter{t.Send}
+	return t
+}
+
+// teamsColors maps levels to Adaptive Card text colours.
+var teamsColors = map[string]string{LevelCritical: "Attention", LevelWarning: "Warning", LevelOK: "Good", LevelInfo: "Accent"}
+
+// Send posts n as a card: its title, text and fields as facts.
+func (t *Teams) Send(n Notification) error {
+	facts := make([]map[string]string, 0, len(n.Fields))
+	for _, f := range n.Fields {
+		facts = append(facts, map[string]string{"title": f.Name, "value": f.Value})
+	}
+	body := []map[string]any{
+		{"type": "TextBlock", "text": n.Title, "weight": "Bolder", "size": "Medium", "color": teamsColors[n.Level]},
+		{"type": "TextBlock", "text": n.Text, "wrap": true},
+	}
+	if len(facts) > 0 {
+		body = append(body, map[string]any{"type": "FactSet", "facts": facts})
+	}
+	card := map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": map[string]any{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body":    body,
+			},
+		}},
+	}
+	_, err := t.PostJSON(t.WebhookURL, card)
+	return err
+}
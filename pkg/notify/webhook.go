@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+)
+
+// DefaultWebhookTemplate renders a notification as its JSON encoding.
+const DefaultWebhookTemplate = `{{json .}}`
+
+// Webhook posts notifications to any HTTP endpoint as the JSON its
+// Template renders from the Notification. The template has a json
+// function that encodes a value, so
+//
+//	{"text": {{json .Title}}, "summary": {{json .Text}}, "host": {{json (.Field "host")}}}
+//
+// fits an endpoint that wants those three keys.
+type Webhook struct {
+	URL      string
+	Template *template.Template
+	HTTP
+	formatter
+}
+
+// ParseWebhookTemplate parses a webhook body template, DefaultWebhookTemplate
+// when text is empty.
+func ParseWebhookTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		text = DefaultWebhookTemplate
+	}
+	funcs := template.FuncMap{"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	}}
+	t, err := template.New("webhook").Funcs(funcs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("webhook template: %w", err)
+	}
+	return t, nil
+}
+
+// NewWebhook returns a webhook notifier posting to url the bodies tmpl
+// renders (DefaultWebhookTemplate when nil).
+func NewWebhook(url string, tmpl *template.Template, opts ...Option) *Webhook {
+	if tmpl == nil {
+		tmpl, _ = ParseWebhookTemplate("")
+	}
+	w := &Webhook{URL: url, Template: tmpl, HTTP: newHTTP(opts)}
+	w.formatter = formatter{w.Send}
+	return w
+}
+
+// Render returns the body posted for n. It fails unless the template
+// renders valid JSON.
+func (w *Webhook) Render(n Notification) ([]byte, error) {
+	var b bytes.Buffer
+	if err := w.Template.Execute(&b, n); err != nil {
+		return nil, fmt.Errorf("webhook template: %w", err)
+	}
+	if !json.Valid(b.Bytes()) {
+		return nil, fmt.Errorf("webhook template: rendered invalid JSON: %s", b.String())
+	}
+	return b.Bytes(), nil
+}
+
+// Send posts n rendered by the template.
+func (w *Webhook) Send(n Notification) error {
+	body, err := w.Render(n)
+	if err != nil {
+		return err
+	}
+	_, err = w.Post(w.URL, "application/json", body)
+	return err
+}
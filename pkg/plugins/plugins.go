@@ -0,0 +1,313 @@
+// Package plugins discovers and runs external missionctl-<name> executables,
+// the same way kubectl and git find their plugins.
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+	"github.com/yourusername/devops-mission-control/pkg/executor"
+)
+
+// Prefix is the executable name prefix that marks a missionctl plugin.
+const Prefix = "missionctl-"
+
+// ManifestFile lives in the plugins dir and records metadata for installed
+// plugins.
+const ManifestFile = "plugins.json"
+
+// DefaultRole is required to run a plugin that does not declare one. Plugins
+// can do anything, so they are treated like mutating built-in commands.
+const DefaultRole = authpkg.RoleOperator
+
+// Plugin is a discovered plugin executable.
+type Plugin struct {
+	Name        string       `json:"name"`
+	Path        string       `json:"path"`
+	Source      string       `json:"source"` // "plugins-dir" or "path"
+	Role        authpkg.Role `json:"role"`
+	Description string       `json:"description,omitempty"`
+	// Shadowed is set when another plugin with the same name was found first.
+	Shadowed bool `json:"shadowed,omitempty"`
+}
+
+// ManifestEntry is the metadata stored for an installed plugin.
+type ManifestEntry struct {
+	Role        authpkg.Role `json:"role,omitempty"`
+	Description string       `json:"description,omitempty"`
+}
+
+// Manager finds, installs and runs plugins.
+type Manager struct {
+	// Dir is the plugins directory; it is searched before PATH.
+	Dir string
+	// Path is the PATH-style list of directories to search.
+	Path string
+	// Exec runs plugins; defaults to the local OS executor.
+	Exec executor.Executor
+}
+
+// Option configures the Manager.
+type Option func(*Manager)
+
+// WithExecutor sets the executor used to run plugins.
+func WithExecutor(e executor.Executor) Option {
+	return func(m *Manager) { m.Exec = e }
+}
+
+// WithPath overrides the directories searched after the plugins dir
+// (defaults to $PATH).
+func WithPath(path string) Option {
+	return func(m *Manager) { m.Path = path }
+}
+
+// NewManager creates a Manager for the given plugins dir.
+func NewManager(dir string, opts ...Option) *Manager {
+	m := &Manager{Dir: dir, Path: os.Getenv("PATH")}
+	for _, o := range opts {
+		o(m)
+	}
+	if m.Exec == nil {
+		m.Exec = executor.New()
+	}
+	return m
+}
+
+// Discover returns every plugin in the plugins dir and on PATH, sorted by
+// name. When a name appears more than once the first match (plugins dir,
+// then PATH order) wins and later ones are marked Shadowed.
+func (m *Manager) Discover() ([]Plugin, error) {
+	manifest, err := m.manifest()
+	if err != nil {
+		return nil, err
+	}
+	var out []Plugin
+	seen := map[string]bool{}
+	add := func(dir, source string) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		for _, e := range entries {
+			name, ok := pluginName(e.Name())
+			if !ok {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			p := Plugin{Name: name, Path: path, Source: source, Role: DefaultRole, Shadowed: seen[name]}
+			if source == "plugins-dir" {
+				if meta, ok := manifest[name]; ok {
+					p.Description = meta.Description
+					if meta.Role != "" {
+						p.Role = meta.Role
+					}
+				}
+			}
+			seen[name] = true
+			out = append(out, p)
+		}
+	}
+	if m.Dir != "" {
+		add(m.Dir, "plugins-dir")
+	}
+	for _, dir := range filepath.SplitList(m.Path) {
+		if dir == "" || dir == m.Dir {
+			continue
+		}
+		add(dir, "path")
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// Find returns the active plugin called name.
+func (m *Manager) Find(name string) (Plugin, error) {
+	list, err := m.Discover()
+	if err != nil {
+		return Plugin{}, err
+	}
+	for _, p := range list {
+		if p.Name == name && !p.Shadowed {
+			return p, nil
+		}
+	}
+	return Plugin{}, fmt.Errorf("plugin %q not found", name)
+}
+
+// Install copies the executable at src into the plugins dir as
+// missionctl-<name> and records its role and description.
+func (m *Manager) Install(src, name string, meta ManifestEntry) (Plugin, error) {
+	if m.Dir == "" {
+		return Plugin{}, errors.New("no plugins dir configured")
+	}
+	if name == "" {
+		n, ok := pluginName(filepath.Base(src))
+		if !ok {
+			n = strings.TrimSuffix(filepath.Base(src), filepath.Ext(src))
+		}
+		name = n
+	}
+	if err := validName(name); err != nil {
+		return Plugin{}, err
+	}
+	if meta.Role != "" {
+		if _, ok := validRoles[meta.Role]; !ok {
+			return Plugin{}, fmt.Errorf("invalid role %q", meta.Role)
+		}
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return Plugin{}, err
+	}
+	defer in.Close()
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return Plugin{}, err
+	}
+	dst := filepath.Join(m.Dir, Prefix+name)
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o755)
+	if err != nil {
+		return Plugin{}, err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return Plugin{}, err
+	}
+	if err := out.Close(); err != nil {
+		return Plugin{}, err
+	}
+	// OpenFile keeps the mode of an existing file; make sure it is executable.
+	if err := os.Chmod(dst, 0o755); err != nil {
+		return Plugin{}, err
+	}
+
+	manifest, err := m.manifest()
+	if err != nil {
+		return Plugin{}, err
+	}
+	manifest[name] = meta
+	if err := m.saveManifest(manifest); err != nil {
+		return Plugin{}, err
+	}
+	role := meta.Role
+	if role == "" {
+		role = DefaultRole
+	}
+	return Plugin{Name: name, Path: dst, Source: "plugins-dir", Role: role, Description: meta.Description}, nil
+}
+
+// Remove deletes an installed plugin from the plugins dir. Plugins found on
+// PATH are not managed by missionctl and cannot be removed this way.
+func (m *Manager) Remove(name string) error {
+	if err := validName(name); err != nil {
+		return err
+	}
+	path := filepath.Join(m.Dir, Prefix+name)
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("plugin %q is not installed in %s", name, m.Dir)
+		}
+		return err
+	}
+	manifest, err := m.manifest()
+	if err != nil {
+		return err
+	}
+	delete(manifest, name)
+	return m.saveManifest(manifest)
+}
+
+// Run executes the plugin attached to the terminal with env appended to the
+// current environment, and returns its exit code.
+func (m *Manager) Run(ctx context.Context, p Plugin, args, env []string) (int, error) {
+	res, err := m.Exec.Run(ctx, executor.Command{
+		Name:   p.Path,
+		Args:   args,
+		Env:    env,
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	})
+	code := 0
+	if res != nil {
+		code = res.ExitCode
+	}
+	return code, err
+}
+
+func (m *Manager) manifest() (map[string]ManifestEntry, error) {
+	out := map[string]ManifestEntry{}
+	if m.Dir == "" {
+		return out, nil
+	}
+	data, err := os.ReadFile(filepath.Join(m.Dir, ManifestFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return out, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("invalid plugin manifest: %w", err)
+	}
+	return out, nil
+}
+
+func (m *Manager) saveManifest(manifest map[string]ManifestEntry) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(m.Dir, ManifestFile), data, 0o644)
+}
+
+var validRoles = map[authpkg.Role]bool{
+	authpkg.RoleViewer:   true,
+	authpkg.RoleOperator: true,
+	authpkg.RoleAdmin:    true,
+}
+
+// pluginName extracts <name> from a missionctl-<name> file name.
+func pluginName(file string) (string, bool) {
+	if !strings.HasPrefix(file, Prefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(file, Prefix)
+	if ext := filepath.Ext(name); ext == ".exe" {
+		name = strings.TrimSuffix(name, ext)
+	}
+	if validName(name) != nil {
+		return "", false
+	}
+	return name, true
+}
+
+func validName(name string) error {
+	if name == "" {
+		return errors.New("plugin name is empty")
+	}
+	for _, r := range name {
+		if !(r == '-' || r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return fmt.Errorf("invalid plugin name %q", name)
+		}
+	}
+	return nil
+}
+
+func isExecutable(path string) bool {
+	fi, err := os.Stat(path)
+	if err != nil || !fi.Mode().IsRegular() {
+		return false
+	}
+	return fi.Mode().Perm()&0o111 != 0
+}
@@ -0,0 +1,96 @@
+package plugins
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+	"github.com/yourusername/devops-mission-control/pkg/executor"
+)
+
+func writeExec(t *testing.T, dir, name string, mode os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\necho hi\n"), mode); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDiscoverPrefersPluginsDirAndSkipsNonExecutables(t *testing.T) {
+	pluginsDir, binA, binB := t.TempDir(), t.TempDir(), t.TempDir()
+	writeExec(t, pluginsDir, "missionctl-deploy", 0o755)
+	writeExec(t, binA, "missionctl-deploy", 0o755)
+	writeExec(t, binA, "missionctl-backup", 0o755)
+	writeExec(t, binB, "missionctl-notes", 0o644)
+	writeExec(t, binB, "kubectl", 0o755)
+
+	m := NewManager(pluginsDir, WithPath(binA+string(os.PathListSeparator)+binB))
+	list, err := m.Discover()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 3 {
+		t.Fatalf("expected 3 plugins, got %+v", list)
+	}
+	if list[0].Name != "backup" || list[0].Source != "path" || list[0].Role != DefaultRole {
+		t.Fatalf("unexpected first plugin %+v", list[0])
+	}
+	if list[1].Source != "plugins-dir" || list[1].Shadowed || !list[2].Shadowed {
+		t.Fatalf("expected plugins dir to shadow PATH: %+v", list[1:])
+	}
+	p, err := m.Find("deploy")
+	if err != nil || p.Path != filepath.Join(pluginsDir, "missionctl-deploy") {
+		t.Fatalf("unexpected find result %+v %v", p, err)
+	}
+}
+
+func TestInstallAndRemove(t *testing.T) {
+	src := writeExec(t, t.TempDir(), "missionctl-report", 0o644)
+	dir := filepath.Join(t.TempDir(), "plugins")
+	m := NewManager(dir, WithPath(""))
+
+	p, err := m.Install(src, "", ManifestEntry{Role: authpkg.RoleViewer, Description: "weekly report"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "report" || !isExecutable(p.Path) {
+		t.Fatalf("unexpected install result %+v", p)
+	}
+	found, err := m.Find("report")
+	if err != nil || found.Role != authpkg.RoleViewer || found.Description != "weekly report" {
+		t.Fatalf("manifest not applied: %+v %v", found, err)
+	}
+	if _, err := m.Install(src, "../evil", ManifestEntry{}); err == nil {
+		t.Fatal("expected invalid name error")
+	}
+	if _, err := m.Install(src, "x", ManifestEntry{Role: "root"}); err == nil {
+		t.Fatal("expected invalid role error")
+	}
+	if err := m.Remove("report"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Find("report"); err == nil {
+		t.Fatal("expected plugin to be gone")
+	}
+	if err := m.Remove("report"); err == nil {
+		t.Fatal("expected error removing a missing plugin")
+	}
+}
+
+func TestRunPassesArgsAndEnv(t *testing.T) {
+	f := executor.NewFake()
+	f.On("/plugins/missionctl-hello", "world").Fail("boom", 4)
+	m := NewManager("", WithExecutor(f))
+
+	code, err := m.Run(context.Background(), Plugin{Name: "hello", Path: "/plugins/missionctl-hello"}, []string{"world"}, []string{"MISSIONCTL_ACTOR=alice"})
+	if err == nil || code != 4 {
+		t.Fatalf("expected exit code 4, got %d %v", code, err)
+	}
+	calls := f.Calls()
+	if len(calls) != 1 || len(calls[0].Env) != 1 || calls[0].Env[0] != "MISSIONCTL_ACTOR=alice" {
+		t.Fatalf("unexpected calls %+v", calls)
+	}
+}
@@ -0,0 +1,265 @@
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// This file implements the subset of kubectl's JSONPath templates that
+// scripts actually use: {.field}, {['field']}, {[n]}, {[*]}, {.*},
+// {"literal"} and {range <path>}...{end}. Missing keys print nothing, the
+// way kubectl does with --allow-missing-template-keys.
+
+type jpNode struct {
+	text     string // literal text, when path is nil and children is nil
+	path     []jpSegment
+	rangeOf  bool
+	children []*jpNode
+}
+
+type jpSegment struct {
+	field    string
+	index    int
+	wildcard bool
+	isIndex  bool
+	fromRoot bool
+}
+
+type jsonPath struct {
+	nodes []*jpNode
+}
+
+func parseJSONPath(tmpl string) (*jsonPath, error) {
+	root := &jpNode{}
+	stack := []*jpNode{root}
+	push := func(n *jpNode) {
+		top := stack[len(stack)-1]
+		top.children = append(top.children, n)
+	}
+
+	rest := tmpl
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			push(&jpNode{text: rest})
+			break
+		}
+		if open > 0 {
+			push(&jpNode{text: rest[:open]})
+		}
+		end, err := closingBrace(rest, open)
+		if err != nil {
+			return nil, err
+		}
+		expr := strings.TrimSpace(rest[open+1 : end])
+		rest = rest[end+1:]
+
+		switch {
+		case expr == "end":
+			if len(stack) == 1 {
+				return nil, fmt.Errorf("jsonpath: {end} without matching {range}")
+			}
+			stack = stack[:len(stack)-1]
+		case strings.HasPrefix(expr, "range "):
+			path, err := parsePath(strings.TrimSpace(strings.TrimPrefix(expr, "range ")))
+			if err != nil {
+				return nil, err
+			}
+			n := &jpNode{path: path, rangeOf: true}
+			push(n)
+			stack = append(stack, n)
+		case strings.HasPrefix(expr, `"`):
+			lit, err := strconv.Unquote(expr)
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath: invalid literal %s", expr)
+			}
+			push(&jpNode{text: lit})
+		default:
+			path, err := parsePath(expr)
+			if err != nil {
+				return nil, err
+			}
+			push(&jpNode{path: path})
+		}
+	}
+	if len(stack) != 1 {
+		return nil, fmt.Errorf("jsonpath: {range} without matching {end}")
+	}
+	return &jsonPath{nodes: root.children}, nil
+}
+
+// closingBrace finds the '}' that closes the '{' at open, skipping quoted text.
+func closingBrace(s string, open int) (int, error) {
+	var quote byte
+	for i := open + 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+		case quote == 0 && c == '}':
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("jsonpath: unclosed '{' in %q", s)
+}
+
+func parsePath(expr string) ([]jpSegment, error) {
+	segs := []jpSegment{}
+	s := expr
+	if strings.HasPrefix(s, "$") {
+		segs = append(segs, jpSegment{fromRoot: true})
+		s = s[1:]
+	}
+	for s != "" {
+		switch s[0] {
+		case '.':
+			s = s[1:]
+			if s == "" || s[0] == '[' {
+				continue
+			}
+			if s[0] == '.' {
+				return nil, fmt.Errorf("jsonpath: recursive descent is not supported in %q", expr)
+			}
+			n := strings.IndexAny(s, ".[")
+			if n < 0 {
+				n = len(s)
+			}
+			name := s[:n]
+			s = s[n:]
+			if name == "*" {
+				segs = append(segs, jpSegment{wildcard: true})
+			} else {
+				segs = append(segs, jpSegment{field: name})
+			}
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("jsonpath: unclosed '[' in %q", expr)
+			}
+			inner := strings.TrimSpace(s[1:end])
+			s = s[end+1:]
+			switch {
+			case inner == "*":
+				segs = append(segs, jpSegment{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				segs = append(segs, jpSegment{field: inner[1 : len(inner)-1]})
+			default:
+				i, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("jsonpath: unsupported subscript [%s] in %q", inner, expr)
+				}
+				segs = append(segs, jpSegment{index: i, isIndex: true})
+			}
+		default:
+			return nil, fmt.Errorf("jsonpath: unexpected %q in %q", s[:1], expr)
+		}
+	}
+	return segs, nil
+}
+
+func (jp *jsonPath) execute(w io.Writer, data any) error {
+	var sb strings.Builder
+	walkNodes(&sb, jp.nodes, data, data)
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func walkNodes(sb *strings.Builder, nodes []*jpNode, root, cur any) {
+	for _, n := range nodes {
+		switch {
+		case n.rangeOf:
+			items := evalPath(n.path, root, cur)
+			if len(items) == 1 {
+				if list, ok := items[0].([]any); ok {
+					items = list
+				}
+			}
+			for _, item := range items {
+				walkNodes(sb, n.children, root, item)
+			}
+		case n.path != nil:
+			vals := evalPath(n.path, root, cur)
+			for i, v := range vals {
+				if i > 0 {
+					sb.WriteByte(' ')
+				}
+				sb.WriteString(formatValue(v))
+			}
+		default:
+			sb.WriteString(n.text)
+		}
+	}
+}
+
+func evalPath(path []jpSegment, root, cur any) []any {
+	vals := []any{cur}
+	for _, seg := range path {
+		if seg.fromRoot {
+			vals = []any{root}
+			continue
+		}
+		var next []any
+		for _, v := range vals {
+			switch {
+			case seg.wildcard:
+				switch t := v.(type) {
+				case []any:
+					next = append(next, t...)
+				case map[string]any:
+					keys := make([]string, 0, len(t))
+					for k := range t {
+						keys = append(keys, k)
+					}
+					sort.Strings(keys)
+					for _, k := range keys {
+						next = append(next, t[k])
+					}
+				}
+			case seg.isIndex:
+				if list, ok := v.([]any); ok {
+					i := seg.index
+					if i < 0 {
+						i += len(list)
+					}
+					if i >= 0 && i < len(list) {
+						next = append(next, list[i])
+					}
+				}
+			default:
+				if m, ok := v.(map[string]any); ok {
+					if fv, ok := m[seg.field]; ok {
+						next = append(next, fv)
+					}
+				}
+			}
+		}
+		vals = next
+	}
+	return vals
+}
+
+func formatValue(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case json.Number:
+		return t.String()
+	case bool:
+		return strconv.FormatBool(t)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
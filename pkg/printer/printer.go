@@ -0,0 +1,291 @@
+package printer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/yourusername/devops-mission-control/pkg/yamlite"
+)
+
+// Format selects how a Printer renders results.
+type Format string
+
+const (
+	FormatTable      Format = "table"
+	FormatWide       Format = "wide"
+	FormatJSON       Format = "json"
+	FormatYAML       Format = "yaml"
+	FormatCSV        Format = "csv"
+	FormatJSONPath   Format = "jsonpath"
+	FormatGoTemplate Format = "go-template"
+)
+
+// Formats lists the accepted --output values, for help text.
+const Formats = "table|wide|json|yaml|csv|jsonpath=<template>|go-template=<template>"
+
+// ParseFormat splits an --output value such as "json" or
+// "jsonpath={.items[*].name}" into its format and template.
+func ParseFormat(output string) (Format, string, error) {
+	name, tmpl, hasTmpl := strings.Cut(output, "=")
+	f := Format(strings.ToLower(strings.TrimSpace(name)))
+	switch f {
+	case "":
+		return FormatTable, "", nil
+	case FormatTable, FormatWide, FormatJSON, FormatYAML, FormatCSV:
+		if hasTmpl {
+			return "", "", fmt.Errorf("output format %q does not take a template", f)
+		}
+		return f, "", nil
+	case FormatJSONPath, FormatGoTemplate:
+		if strings.TrimSpace(tmpl) == "" {
+			return "", "", fmt.Errorf("output format %s requires a template, e.g. %s='{.name}'", f, f)
+		}
+		return f, tmpl, nil
+	}
+	return "", "", fmt.Errorf("unknown output format %q (expected %s)", output, Formats)
+}
+
+// Table is a header row plus data rows, rendered as aligned columns.
+type Table struct {
+	Headers []string
+	Rows    [][]string
+	// WideFrom is the index of the first column only shown by the wide and
+	// csv formats; 0 means every column is always shown.
+	WideFrom int
+	// Empty, when set, is printed instead of a bare header row by the table
+	// and wide formats when there are no rows.
+	Empty string
+}
+
+// AddRow appends a row to the table.
+func (t *Table) AddRow(cells ...string) {
+	t.Rows = append(t.Rows, cells)
+}
+
+func (t Table) narrow() Table {
+	if t.WideFrom <= 0 {
+		return t
+	}
+	out := Table{Headers: clip(t.Headers, t.WideFrom), Empty: t.Empty}
+	for _, row := range t.Rows {
+		out.Rows = append(out.Rows, clip(row, t.WideFrom))
+	}
+	return out
+}
+
+func clip(cells []string, n int) []string {
+	if len(cells) > n {
+		return cells[:n]
+	}
+	return cells
+}
+
+// Printer renders command results to a writer.
+type Printer struct {
+	Out      io.Writer
+	Format   Format
+	Template string
+}
+
+// New creates a table printer writing to out (stdout when nil).
+func New(out io.Writer) *Printer {
+	if out == nil {
+		out = os.Stdout
+	}
+	return &Printer{Out: out, Format: FormatTable}
+}
+
+// ForOutput creates a printer for an --output value.
+func ForOutput(out io.Writer, output string) (*Printer, error) {
+	f, tmpl, err := ParseFormat(output)
+	if err != nil {
+		return nil, err
+	}
+	p := New(out)
+	p.Format = f
+	p.Template = tmpl
+	return p, nil
+}
+
+// Print renders a result. data is what the machine-readable formats
+// (json, yaml, jsonpath, go-template) serialise; t is its human-readable
+// form used by table, wide and csv.
+func (p *Printer) Print(data any, t Table) error {
+	switch p.Format {
+	case FormatTable, "":
+		return p.PrintTable(t.narrow())
+	case FormatWide:
+		return p.PrintTable(t)
+	case FormatCSV:
+		return p.printCSV(t)
+	}
+	return p.printData(data)
+}
+
+// PrintText renders free-form output from an underlying tool. Table-style
+// formats print it unchanged. The machine-readable formats decode it when it
+// is already JSON and otherwise wrap it as {"output": "..."}.
+func (p *Printer) PrintText(text string) error {
+	switch p.Format {
+	case FormatTable, FormatWide, "":
+		_, err := fmt.Fprintln(p.Out, text)
+		return err
+	case FormatCSV:
+		return p.printCSV(Table{Headers: []string{"OUTPUT"}, Rows: [][]string{{text}}})
+	}
+	var decoded any
+	if err := json.Unmarshal([]byte(text), &decoded); err == nil && text != "" {
+		return p.printData(decoded)
+	}
+	return p.printData(map[string]string{"output": text})
+}
+
+func (p *Printer) printData(data any) error {
+	switch p.Format {
+	case FormatJSON:
+		b, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(p.Out, string(b))
+		return err
+	case FormatYAML:
+		b, err := yamlite.Marshal(data)
+		if err != nil {
+			return err
+		}
+		_, err = p.Out.Write(b)
+		return err
+	case FormatJSONPath:
+		generic, err := genericJSON(data)
+		if err != nil {
+			return err
+		}
+		jp, err := parseJSONPath(p.Template)
+		if err != nil {
+			return err
+		}
+		return jp.execute(p.Out, generic)
+	case FormatGoTemplate:
+		generic, err := genericJSON(data)
+		if err != nil {
+			return err
+		}
+		tmpl, err := template.New("output").Parse(p.Template)
+		if err != nil {
+			return fmt.Errorf("invalid go-template: %w", err)
+		}
+		return tmpl.Execute(p.Out, generic)
+	}
+	return fmt.Errorf("unknown output format %q", p.Format)
+}
+
+func (p *Printer) printCSV(t Table) error {
+	w := csv.NewWriter(p.Out)
+	if len(t.Headers) > 0 {
+		if err := w.Write(t.Headers); err != nil {
+			return err
+		}
+	}
+	if err := w.WriteAll(t.Rows); err != nil {
+		return err
+	}
+	return w.Error()
+}
+
+// genericJSON round-trips data through encoding/json so templates see the
+// same field names as -o json.
+func genericJSON(data any) (any, error) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var out any
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PrintTable writes t as tab-aligned columns.
+func (p *Printer) PrintTable(t Table) error {
+	if len(t.Rows) == 0 && t.Empty != "" {
+		_, err := fmt.Fprintln(p.Out, t.Empty)
+		return err
+	}
+	w := tabwriter.NewWriter(p.Out, 0, 0, 3, ' ', 0)
+	if len(t.Headers) > 0 {
+		fmt.Fprintln(w, strings.Join(t.Headers, "\t"))
+	}
+	for _, row := range t.Rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// Age formats the time since t the way kubectl does (45s, 10m, 3h, 5d).
+// A zero time renders as "<unknown>".
+func Age(t time.Time) string {
+	if t.IsZero() {
+		return "<unknown>"
+	}
+	return Duration(time.Since(t))
+}
+
+// Duration formats d in kubectl's short form.
+func Duration(d time.Duration) string {
+	switch {
+	case d < 0:
+		return "0s"
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
+
+// Join renders a list cell, using "<none>" for an empty list.
+func Join(items []string) string {
+	if len(items) == 0 {
+		return "<none>"
+	}
+	return strings.Join(items, ",")
+}
+
+// Pairs renders a map as sorted key=value pairs (e.g. a label selector),
+// using "<none>" for an empty map.
+func Pairs[V any](m map[string]V) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", k, m[k]))
+	}
+	return Join(parts)
+}
+
+// Cell renders a single value, using "<none>" for an empty string.
+func Cell(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
@@ -0,0 +1,125 @@
+package printer
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPrintTableAlignsColumns(t *testing.T) {
+	var buf bytes.Buffer
+	tbl := Table{Headers: []string{"NAME", "STATUS"}}
+	tbl.AddRow("api-0", "Running")
+	tbl.AddRow("worker-long-name", "Pending")
+	if err := New(&buf).PrintTable(tbl); err != nil {
+		t.Fatal(err)
+	}
+	want := "NAME               STATUS\napi-0              Running\nworker-long-name   Pending\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected table:\n%q\nwant:\n%q", buf.String(), want)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	cases := map[time.Duration]string{
+		30 * time.Second:   "30s",
+		10 * time.Minute:   "10m",
+		5 * time.Hour:      "5h",
+		47 * time.Hour:     "47h",
+		5 * 24 * time.Hour: "5d",
+	}
+	for d, want := range cases {
+		if got := Duration(d); got != want {
+			t.Errorf("Duration(%v) = %q, want %q", d, got, want)
+		}
+	}
+	if Age(time.Time{}) != "<unknown>" {
+		t.Errorf("zero time should render as <unknown>")
+	}
+}
+
+type row struct {
+	Name  string   `json:"name"`
+	Ready int      `json:"ready"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+func sample() ([]row, Table) {
+	rows := []row{{Name: "api", Ready: 2, Tags: []string{"a", "b"}}, {Name: "web", Ready: 1}}
+	t := Table{Headers: []string{"NAME", "READY", "TAGS"}, WideFrom: 2}
+	for _, r := range rows {
+		t.AddRow(r.Name, fmt.Sprint(r.Ready), Join(r.Tags))
+	}
+	return rows, t
+}
+
+func render(t *testing.T, output string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	p, err := ForOutput(&buf, output)
+	if err != nil {
+		t.Fatalf("ForOutput(%q): %v", output, err)
+	}
+	data, tbl := sample()
+	if err := p.Print(data, tbl); err != nil {
+		t.Fatalf("Print(%q): %v", output, err)
+	}
+	return buf.String()
+}
+
+func TestPrintFormats(t *testing.T) {
+	cases := map[string]string{
+		"":                    "NAME   READY\napi    2\nweb    1\n",
+		"wide":                "NAME   READY   TAGS\napi    2       a,b\nweb    1       <none>\n",
+		"csv":                 "NAME,READY,TAGS\napi,2,\"a,b\"\nweb,1,<none>\n",
+		"json":                "[\n  {\n    \"name\": \"api\",\n    \"ready\": 2,\n    \"tags\": [\n      \"a\",\n      \"b\"\n    ]\n  },\n  {\n    \"name\": \"web\",\n    \"ready\": 1\n  }\n]\n",
+		"yaml":                "- name: api\n  ready: 2\n  tags:\n  - a\n  - b\n- name: web\n  ready: 1\n",
+		"jsonpath={[*].name}": "api web",
+		`jsonpath={range [*]}{.name}={.ready}{"\n"}{end}`: "api=2\nweb=1\n",
+		"go-template={{range .}}{{.name}};{{end}}":        "api;web;",
+	}
+	for output, want := range cases {
+		if got := render(t, output); got != want {
+			t.Errorf("-o %s:\n%q\nwant:\n%q", output, got, want)
+		}
+	}
+}
+
+func TestParseFormatRejectsUnknown(t *testing.T) {
+	for _, bad := range []string{"xml", "jsonpath", "json=x"} {
+		if _, _, err := ParseFormat(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestPrintTextWrapsRawOutput(t *testing.T) {
+	var buf bytes.Buffer
+	p, _ := ForOutput(&buf, "json")
+	if err := p.PrintText("Deleted pod api-0"); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "{\n  \"output\": \"Deleted pod api-0\"\n}\n" {
+		t.Fatalf("unexpected wrapped output %q", buf.String())
+	}
+
+	buf.Reset()
+	p, _ = ForOutput(&buf, "jsonpath={.Account}")
+	if err := p.PrintText(`{"Account": "123456789012"}`); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "123456789012" {
+		t.Fatalf("expected JSON text to be decoded, got %q", buf.String())
+	}
+}
+
+func TestEmptyTableMessage(t *testing.T) {
+	var buf bytes.Buffer
+	if err := New(&buf).Print([]row{}, Table{Headers: []string{"NAME"}, Empty: "No rows"}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "No rows\n" {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+}
@@ -0,0 +1,103 @@
+package slack
+
+import "encoding/json"
+
+// Block is a Block Kit layout block. Only the block types missionctl posts
+// are modelled; the helpers below build them.
+type Block struct {
+	Type    string `json:"type"`
+	BlockID string `json:"block_id,omitempty"`
+	Text    *Text  `json:"text,omitempty"`
+	Fields  []Text `json:"fields,omitempty"`
+	// Elements holds Text for context blocks and Button for actions
+	// blocks.
+	Elements []interface{} `json:"elements,omitempty"`
+	// Raw, when set, is sent instead of the fields above: a block from a
+	// message template, of any type.
+	Raw json.RawMessage `json:"-"`
+}
+
+// MarshalJSON sends Raw when it is set.
+func (b Block) MarshalJSON() ([]byte, error) {
+	if b.Raw != nil {
+		return b.Raw, nil
+	}
+	type block Block
+	return json.Marshal(block(b))
+}
+
+// Text is a plain_text or mrkdwn text object.
+type Text struct {
+	Type  string `json:"type"`
+	Text  string `json:"text"`
+	Emoji bool   `json:"emoji,omitempty"`
+}
+
+// Button is an interactive button element of an actions block.
+type Button struct {
+	Type     string `json:"type"`
+	Text     *Text  `json:"text"`
+	ActionID string `json:"action_id"`
+	Value    string `json:"value,omitempty"`
+	// Style is "primary", "danger" or empty.
+	Style string `json:"style,omitempty"`
+}
+
+// Markdown returns a mrkdwn text object.
+func Markdown(text string) *Text {
+	return &Text{Type: "mrkdwn", Text: text}
+}
+
+// PlainText returns a plain_text text object.
+func PlainText(text string) *Text {
+	return &Text{Type: "plain_text", Text: text, Emoji: true}
+}
+
+// Header returns a header block.
+func Header(text string) Block {
+	return Block{Type: "header", Text: PlainText(text)}
+}
+
+// Section returns a section block of mrkdwn text.
+func Section(text string) Block {
+	return Block{Type: "section", Text: Markdown(text)}
+}
+
+// FieldsSection returns a section block laying out name/value pairs in two
+// columns.
+func FieldsSection(fields []Field) Block {
+	b := Block{Type: "section"}
+	for _, f := range fields {
+		b.Fields = append(b.Fields, Text{Type: "mrkdwn", Text: "*" + f.Title + "*\n" + f.Value})
+	}
+	return b
+}
+
+// Context returns a context block of small mrkdwn lines.
+func Context(texts ...string) Block {
+	b := Block{Type: "context"}
+	for _, t := range texts {
+		b.Elements = append(b.Elements, Text{Type: "mrkdwn", Text: t})
+	}
+	return b
+}
+
+// Divider returns a divider block.
+func Divider() Block {
+	return Block{Type: "divider"}
+}
+
+// Actions returns an actions block holding buttons; blockID lets an
+// interaction handler tell which message the buttons belong to.
+func Actions(blockID string, buttons ...Button) Block {
+	b := Block{Type: "actions", BlockID: blockID}
+	for _, btn := range buttons {
+		b.Elements = append(b.Elements, btn)
+	}
+	return b
+}
+
+// NewButton returns a button sending actionID and value when clicked.
+func NewButton(text, actionID, value, style string) Button {
+	return Button{Type: "button", Text: PlainText(text), ActionID: actionID, Value: value, Style: style}
+}
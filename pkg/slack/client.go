@@ -13,9 +13,14 @@ import (
 	"go.uber.org/zap"
 )
 
-// Client handles Slack webhook communications
+// Client handles Slack webhook communications. With a bot Token it uses the
+// Web API instead, which can thread and update messages (see webapi.go).
 type Client struct {
 	WebhookURL string
+	// Token is a bot token (xoxb-...) for the Web API.
+	Token string
+	// APIURL is the Web API base URL, https://slack.com/api by default.
+	APIURL     string
 	Channel    string
 	Username   string
 	IconEmoji  string
@@ -28,6 +33,10 @@ type Client struct {
 	Logger Logger
 	// Sleep is used to wait between retries. Inject for testing.
 	Sleep func(d time.Duration)
+	// Templates replace the built-in alert, deployment and operation
+	// messages; DashboardURL is the base of their dashboard links.
+	Templates    *Templates
+	DashboardURL string
 }
 
 // Logger is the minimal logging interface used by the Slack client.
@@ -76,6 +85,26 @@ func WithLogrLogger(l logr.Logger) Option {
 	return func(c *Client) { c.Logger = logrAdapter{l} }
 }
 
+// WithBotToken makes the client post through the Web API with token.
+func WithBotToken(token string) Option {
+	return func(c *Client) { c.Token = token }
+}
+
+// WithAPIURL sets the Web API base URL (useful for tests).
+func WithAPIURL(url string) Option {
+	return func(c *Client) { c.APIURL = url }
+}
+
+// WithTemplates sets the message templates.
+func WithTemplates(t *Templates) Option {
+	return func(c *Client) { c.Templates = t }
+}
+
+// WithDashboardURL sets the dashboard base URL messages link to.
+func WithDashboardURL(url string) Option {
+	return func(c *Client) { c.DashboardURL = url }
+}
+
 // WithTimeout sets HTTP client timeout conveniently.
 func WithTimeout(d time.Duration) Option {
 	return func(c *Client) {
@@ -171,7 +200,15 @@ type Message struct {
 	Username    string       `json:"username,omitempty"`
 	IconEmoji   string       `json:"icon_emoji,omitempty"`
 	Text        string       `json:"text,omitempty"`
+	Blocks      []Block      `json:"blocks,omitempty"`
 	Attachments []Attachment `json:"attachments,omitempty"`
+	// ThreadTS posts the message as a reply in the thread of that message.
+	ThreadTS string `json:"thread_ts,omitempty"`
+	// TS is the message chat.update changes.
+	TS string `json:"ts,omitempty"`
+	// ResponseType is "ephemeral" for a reply to an interaction only its
+	// user sees.
+	ResponseType string `json:"response_type,omitempty"`
 }
 
 // Attachment represents a Slack message attachment
@@ -204,147 +241,72 @@ func (c *Client) SendMessage(text string) error {
 		Text:      text,
 	}
 
-	return c.sendWebhook(msg)
+	return c.send(msg)
 }
 
-// SendAlert sends an alert notification to Slack
+// SendAlert sends an alert notification to Slack. A severity of "resolved"
+// announces that the alert is over.
 func (c *Client) SendAlert(alertName, severity, message string, metadata map[string]string) error {
-	color := "#36a64f" // green
-	switch severity {
-	case "warning":
-		color = "#ff9900" // orange
-	case "critical":
-		color = "#ff0000" // red
-	}
-
-	fields := []Field{
-		{
-			Title: "Severity",
-			Value: severity,
-			Short: true,
-		},
-		{
-			Title: "Alert",
-			Value: alertName,
-			Short: true,
-		},
-	}
-
-	for key, value := range metadata {
-		fields = append(fields, Field{
-			Title: key,
-			Value: value,
-			Short: true,
-		})
-	}
-
-	attachment := Attachment{
-		Color:  color,
-		Title:  "⚠️ Alert Triggered",
-		Text:   message,
-		Fields: fields,
-	}
-
-	msg := Message{
-		Channel:     c.Channel,
-		Username:    c.Username,
-		IconEmoji:   c.IconEmoji,
-		Attachments: []Attachment{attachment},
-	}
-
-	return c.sendWebhook(msg)
+	return c.sendComposed(alertData(alertName, severity, message, metadata, time.Now()))
 }
 
 // SendDeployment sends a deployment notification to Slack
 func (c *Client) SendDeployment(app, status, version string, metadata map[string]string) error {
-	color := "#36a64f"
-	switch status {
-	case "failed":
-		color = "#ff0000"
-	case "pending":
-		color = "#0099ff"
-	}
-
-	fields := []Field{
-		{
-			Title: "App",
-			Value: app,
-			Short: true,
-		},
-		{
-			Title: "Status",
-			Value: status,
-			Short: true,
-		},
-		{
-			Title: "Version",
-			Value: version,
-			Short: true,
-		},
-	}
+	return c.sendComposed(deploymentData(app, status, version, metadata, time.Now()))
+}
 
-	for key, value := range metadata {
-		fields = append(fields, Field{
-			Title: key,
-			Value: value,
-			Short: true,
-		})
-	}
+// SendOperationStatus sends an operation status update to Slack
+func (c *Client) SendOperationStatus(operation, status string, duration float64, metadata map[string]string) error {
+	return c.sendComposed(operationData(operation, status, duration, metadata, time.Now()))
+}
 
-	emoji := "✅"
-	switch status {
-	case "failed":
-		emoji = "❌"
-	case "pending":
-		emoji = "⏳"
+func (c *Client) sendComposed(d TemplateData) error {
+	msg, err := c.Compose(d)
+	if err != nil {
+		return err
 	}
+	return c.send(msg)
+}
 
-	attachment := Attachment{
-		Color:  color,
-		Title:  emoji + " Deployment Notification",
-		Text:   fmt.Sprintf("%s deployment of %s", status, app),
-		Fields: fields,
+// Compose returns the message sent for d: the client's template of its
+// type, else the built-in attachment.
+func (c *Client) Compose(d TemplateData) (Message, error) {
+	d.DashboardURL = c.DashboardURL
+	msg, ok, err := c.Templates.Render(d)
+	if err != nil {
+		return Message{}, err
 	}
-
-	msg := Message{
-		Channel:     c.Channel,
-		Username:    c.Username,
-		IconEmoji:   c.IconEmoji,
-		Attachments: []Attachment{attachment},
+	if !ok {
+		msg = Message{Attachments: []Attachment{builtinAttachment(d)}}
 	}
-
-	return c.sendWebhook(msg)
+	msg.Channel, msg.Username, msg.IconEmoji = c.Channel, c.Username, c.IconEmoji
+	return msg, nil
 }
 
-// SendOperationStatus sends an operation status update to Slack
-func (c *Client) SendOperationStatus(operation, status string, duration float64, metadata map[string]string) error {
-	color := "#36a64f"
-	switch status {
-	case "failed":
-		color = "#ff0000"
-	case "pending":
-		color = "#0099ff"
-	}
-
-	fields := []Field{
-		{
-			Title: "Operation",
-			Value: operation,
-			Short: true,
-		},
-		{
-			Title: "Status",
-			Value: status,
-			Short: true,
-		},
-		{
-			Title: "Duration",
-			Value: fmt.Sprintf("%.2f seconds", duration),
-			Short: true,
-		},
+// builtinAttachment renders d as missionctl does without a template.
+func builtinAttachment(d TemplateData) Attachment {
+	var fields []Field
+	switch d.Type {
+	case TemplateAlert:
+		fields = []Field{
+			{Title: "Severity", Value: d.Status, Short: true},
+			{Title: "Alert", Value: d.Name, Short: true},
+		}
+	case TemplateDeployment:
+		fields = []Field{
+			{Title: "App", Value: d.Name, Short: true},
+			{Title: "Status", Value: d.Status, Short: true},
+			{Title: "Version", Value: d.Version, Short: true},
+		}
+	case TemplateOperation:
+		fields = []Field{
+			{Title: "Operation", Value: d.Name, Short: true},
+			{Title: "Status", Value: d.Status, Short: true},
+			{Title: "Duration", Value: fmt.Sprintf("%.2f seconds", d.Duration), Short: true},
+		}
 	}
 
-	for key, value := range metadata {
+	for key, value := range d.Metadata {
 		fields = append(fields, Field{
 			Title: key,
 			Value: value,
@@ -352,29 +314,13 @@ func (c *Client) SendOperationStatus(operation, status string, duration float64,
 		})
 	}
 
-	emoji := "✅"
-	switch status {
-	case "failed":
-		emoji = "❌"
-	case "pending":
-		emoji = "⏳"
+	return Attachment{
+		Color:     d.Color,
+		Title:     d.Title,
+		TitleLink: d.Link(),
+		Text:      d.Message,
+		Fields:    fields,
 	}
-
-	attachment := Attachment{
-		Color:  color,
-		Title:  emoji + " Operation Status",
-		Text:   fmt.Sprintf("%s: %s", operation, status),
-		Fields: fields,
-	}
-
-	msg := Message{
-		Channel:     c.Channel,
-		Username:    c.Username,
-		IconEmoji:   c.IconEmoji,
-		Attachments: []Attachment{attachment},
-	}
-
-	return c.sendWebhook(msg)
 }
 
 // SendResourceStatus sends resource status update to Slack
@@ -427,7 +373,7 @@ func (c *Client) SendResourceStatus(resource, resourceType, status string, stats
 		Attachments: []Attachment{attachment},
 	}
 
-	return c.sendWebhook(msg)
+	return c.send(msg)
 }
 
 // SendMetricsSummary sends a metrics summary to Slack
@@ -456,6 +402,30 @@ func (c *Client) SendMetricsSummary(title string, metrics map[string]interface{}
 		Attachments: []Attachment{attachment},
 	}
 
+	return c.send(msg)
+}
+
+// SendBlocks sends a Block Kit message; text is the fallback shown in
+// notifications.
+func (c *Client) SendBlocks(text string, blocks []Block) error {
+	msg := Message{
+		Channel:   c.Channel,
+		Username:  c.Username,
+		IconEmoji: c.IconEmoji,
+		Text:      text,
+		Blocks:    blocks,
+	}
+
+	return c.send(msg)
+}
+
+// send delivers msg through the Web API when the client has a bot token,
+// else through the webhook.
+func (c *Client) send(msg Message) error {
+	if c.Token != "" {
+		_, err := c.PostMessage(msg)
+		return err
+	}
 	return c.sendWebhook(msg)
 }
 
@@ -1,8 +1,15 @@
 package slack
 
 import (
+	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -73,3 +80,173 @@ func (c *capturingLogger) Errorf(format string, v ...interface{}) {
 	}
 	c.end = now
 }
+
+// Test the Web API mode: posting, threading, updating and the errors
+// Slack reports in the body of a 200.
+func TestWebAPI(t *testing.T) {
+	type call struct {
+		method string
+		auth   string
+		body   map[string]interface{}
+	}
+	var calls []call
+	limited := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		var body map[string]interface{}
+		if err := json.Unmarshal(raw, &body); err != nil {
+			t.Errorf("invalid JSON %s", raw)
+		}
+		calls = append(calls, call{r.URL.Path, r.Header.Get("Authorization"), body})
+		if limited {
+			limited = false
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if body["channel"] == "#missing" {
+			_, _ = w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"channel":"C123","ts":"1700000000.000100"}`))
+	}))
+	defer srv.Close()
+
+	var slept []time.Duration
+	c := NewClient("", "#ops", WithBotToken("xoxb-test"), WithAPIURL(srv.URL), WithLogger(&capturingLogger{}),
+		WithSleeper(func(d time.Duration) { slept = append(slept, d) }))
+
+	// the Send methods go through chat.postMessage once a token is set
+	if err := c.SendBlocks("deploy started", []Block{Section("*web* v1.2"), Actions("b1", NewButton("Stop", "stop", "web", "danger"))}); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 2 || len(slept) != 1 || slept[0] != 3*time.Second {
+		t.Fatalf("the 429 should be retried after Retry-After: %d calls, slept %v", len(calls), slept)
+	}
+	post := calls[1]
+	blocks, _ := post.body["blocks"].([]interface{})
+	if post.method != "/chat.postMessage" || post.auth != "Bearer xoxb-test" || post.body["channel"] != "#ops" || len(blocks) != 2 {
+		t.Fatalf("post %+v", post)
+	}
+	button := blocks[1].(map[string]interface{})["elements"].([]interface{})[0].(map[string]interface{})
+	if button["action_id"] != "stop" || button["style"] != "danger" || button["text"].(map[string]interface{})["text"] != "Stop" {
+		t.Fatalf("button %+v", button)
+	}
+
+	ref, err := c.PostMessage(Message{Text: "terraform apply"})
+	if err != nil || ref != (MessageRef{Channel: "C123", TS: "1700000000.000100"}) {
+		t.Fatalf("ref %+v: %v", ref, err)
+	}
+	if _, err := c.Reply(ref, "step 1/2 done"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.UpdateMessage(ref, Message{Text: "terraform apply finished"}); err != nil {
+		t.Fatal(err)
+	}
+	reply, update := calls[3], calls[4]
+	if reply.body["thread_ts"] != ref.TS || reply.body["channel"] != "C123" {
+		t.Fatalf("reply %+v", reply.body)
+	}
+	if update.method != "/chat.update" || update.body["ts"] != ref.TS || update.body["text"] != "terraform apply finished" || update.body["username"] != nil {
+		t.Fatalf("update %+v", update)
+	}
+
+	if _, err := c.PostMessage(Message{Channel: "#missing", Text: "x"}); err == nil || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Fatalf("want channel_not_found, got %v", err)
+	}
+}
+
+func TestVerifyRequest(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte("payload=" + url.QueryEscape(`{"type":"block_actions","user":{"id":"U1","username":"ann"},"actions":[{"action_id":"approve","value":"apr_1"}]}`))
+	header := func(ts time.Time, sig string) http.Header {
+		h := http.Header{}
+		h.Set("X-Slack-Request-Timestamp", strconv.FormatInt(ts.Unix(), 10))
+		h.Set("X-Slack-Signature", sig)
+		return h
+	}
+	good := Sign("s3cret", strconv.FormatInt(now.Unix(), 10), body)
+	if err := VerifyRequest("s3cret", header(now, good), body, now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	for name, err := range map[string]error{
+		"wrong secret":  VerifyRequest("other", header(now, good), body, now),
+		"changed body":  VerifyRequest("s3cret", header(now, good), append(body, 'x'), now),
+		"replayed":      VerifyRequest("s3cret", header(now, good), body, now.Add(10*time.Minute)),
+		"no timestamp":  VerifyRequest("s3cret", http.Header{"X-Slack-Signature": {good}}, body, now),
+		"no secret set": VerifyRequest("", header(now, good), body, now),
+	} {
+		if err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+
+	in, err := ParseInteraction(body)
+	if err != nil || in.User.Username != "ann" || len(in.Actions) != 1 || in.Actions[0].Value != "apr_1" {
+		t.Fatalf("interaction %+v: %v", in, err)
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, text string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("alert.json", `[{"type": "section", "text": {"type": "mrkdwn", "text": {{json (printf "%s *%s* (%s) for %s" .Emoji .Name (upper .Status) .Tenant)}}},
+		"accessory": {"type": "button", "text": {"type": "plain_text", "text": "Open"}, "url": {{json .Link}}}}]`)
+	write("deployment.tmpl", `{{.Emoji}} {{.Name}} {{.Version}} on {{index .Metadata "environment"}}`)
+
+	templates, err := LoadTemplates(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Message
+	var raw []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ = io.ReadAll(r.Body)
+		got = Message{}
+		_ = json.Unmarshal(raw, &got)
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, "#alerts", WithTemplates(templates), WithDashboardURL("https://mc.example.com/"))
+
+	if err := c.SendAlert("disk-full", "critical", "disk 97%", map[string]string{"tenant": "acme"}); err != nil {
+		t.Fatal(err)
+	}
+	if got.Channel != "#alerts" || got.Text != "⚠️ Alert Triggered: disk-full" || !strings.Contains(string(raw), "*disk-full* (CRITICAL) for acme") ||
+		!strings.Contains(string(raw), `"url":"https://mc.example.com/#alerts"`) || len(got.Attachments) != 0 {
+		t.Fatalf("alert %+v %s", got, raw)
+	}
+	if err := c.SendDeployment("web", "failed", "v2", map[string]string{"environment": "prod"}); err != nil {
+		t.Fatal(err)
+	}
+	if got.Text != "❌ web v2 on prod" {
+		t.Fatalf("deployment %+v", got)
+	}
+	// types without a template keep the built-in message, linked to the dashboard
+	if err := c.SendOperationStatus("terraform apply", "success", 3, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Attachments) != 1 || got.Attachments[0].Title != "✅ Operation Status" || got.Attachments[0].TitleLink != "https://mc.example.com/#events" {
+		t.Fatalf("operation %+v", got)
+	}
+
+	// templates that cannot render a message fail to load
+	for name, text := range map[string]string{
+		"alert.json":     `{"blocks": [{{.Name}}]}`,
+		"alert.tmpl":     `{{.Nope}}`,
+		"operation.tmpl": `{{if not .Tenant}}x{{end}}`,
+		"incident.tmpl":  `{{.Name}}`,
+		"alert.txt":      `{{.Name}}`,
+	} {
+		if err := (&Templates{}).Add(name, text); err == nil {
+			t.Errorf("%s %q: loaded", name, text)
+		}
+	}
+	write("alert.tmpl", `{{.Name}}`)
+	if _, err := LoadTemplates(dir); err == nil || !strings.Contains(err.Error(), "both") {
+		t.Fatalf("alert.tmpl and alert.json: %v", err)
+	}
+}
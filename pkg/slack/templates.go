@@ -0,0 +1,232 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Message types that can be templated.
+const (
+	TemplateAlert      = "alert"
+	TemplateDeployment = "deployment"
+	TemplateOperation  = "operation"
+)
+
+// TemplateTypes are the message types, in the order they are listed.
+var TemplateTypes = []string{TemplateAlert, TemplateDeployment, TemplateOperation}
+
+// TemplateData is what a message template renders. Title, Emoji and Color
+// are the ones of the built-in message, so a template can keep them.
+type TemplateData struct {
+	Type  string
+	Title string
+	Emoji string
+	Color string
+	// Name is the alert, app or operation.
+	Name string
+	// Status is the severity of an alert ("resolved" once it is over) and
+	// the status of a deployment or operation.
+	Status   string
+	Message  string
+	Version  string
+	Duration float64 // seconds
+	Metadata map[string]string
+	// Tenant is the tenant of the metadata, if any.
+	Tenant       string
+	DashboardURL string
+	Timestamp    time.Time
+}
+
+// Link is the dashboard section of the message: the alerts of an alert,
+// the events of a deployment or operation. It is "" without a
+// DashboardURL.
+func (d TemplateData) Link() string {
+	if d.DashboardURL == "" {
+		return ""
+	}
+	anchor := "#events"
+	if d.Type == TemplateAlert {
+		anchor = "#alerts"
+	}
+	return strings.TrimRight(d.DashboardURL, "/") + "/" + anchor
+}
+
+// SampleData returns the data `observe slack preview` renders a template
+// of type typ with.
+func SampleData(typ string) (TemplateData, error) {
+	meta := map[string]string{"tenant": "acme", "host": "db-1", "environment": "prod"}
+	at := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	switch typ {
+	case TemplateAlert:
+		return alertData("disk-full", "critical", "disk 97% full on db-1", meta, at), nil
+	case TemplateDeployment:
+		return deploymentData("web", "success", "v1.4.2", meta, at), nil
+	case TemplateOperation:
+		return operationData("terraform apply", "failed", 42.5, meta, at), nil
+	}
+	return TemplateData{}, fmt.Errorf("unknown template %q (valid: %s)", typ, strings.Join(TemplateTypes, ", "))
+}
+
+func alertData(alertName, severity, message string, metadata map[string]string, at time.Time) TemplateData {
+	d := TemplateData{Type: TemplateAlert, Title: "⚠️ Alert Triggered", Emoji: "⚠️", Color: "#36a64f", Name: alertName, Status: severity,
+		Message: message, Metadata: metadata, Tenant: metadata["tenant"], Timestamp: at}
+	switch severity {
+	case "warning":
+		d.Color = "#ff9900"
+	case "critical":
+		d.Color = "#ff0000"
+	case "resolved":
+		d.Title, d.Emoji = "✅ Alert Resolved", "✅"
+	}
+	return d
+}
+
+func deploymentData(app, status, version string, metadata map[string]string, at time.Time) TemplateData {
+	color, emoji := statusStyle(status)
+	return TemplateData{Type: TemplateDeployment, Title: emoji + " Deployment Notification", Emoji: emoji, Color: color, Name: app, Status: status,
+		Message: fmt.Sprintf("%s deployment of %s", status, app), Version: version, Metadata: metadata, Tenant: metadata["tenant"], Timestamp: at}
+}
+
+func operationData(operation, status string, duration float64, metadata map[string]string, at time.Time) TemplateData {
+	color, emoji := statusStyle(status)
+	return TemplateData{Type: TemplateOperation, Title: emoji + " Operation Status", Emoji: emoji, Color: color, Name: operation, Status: status,
+		Message: fmt.Sprintf("%s: %s", operation, status), Duration: duration, Metadata: metadata, Tenant: metadata["tenant"], Timestamp: at}
+}
+
+// statusStyle is the colour and emoji of a deployment or operation status.
+func statusStyle(status string) (color, emoji string) {
+	switch status {
+	case "failed":
+		return "#ff0000", "❌"
+	case "pending":
+		return "#0099ff", "⏳"
+	}
+	return "#36a64f", "✅"
+}
+
+// Templates are user-defined messages, one per message type at most. A
+// <type>.tmpl file is a text/template rendering the message text (mrkdwn);
+// a <type>.json file is a text/template rendering Block Kit JSON: an array
+// of blocks, or an object with text, blocks and attachments. Both have a
+// json function that quotes a value as a JSON string.
+type Templates struct {
+	byType map[string]*template.Template
+}
+
+// templateFuncs are the functions templates can call.
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// LoadTemplates reads the templates in dir; a missing dir has none. Every
+// template is rendered with sample data, so one that cannot render a
+// message fails here rather than when an alert fires.
+func LoadTemplates(dir string) (*Templates, error) {
+	t := &Templates{byType: map[string]*template.Template{}}
+	for _, typ := range TemplateTypes {
+		for _, ext := range []string{".tmpl", ".json"} {
+			path := filepath.Join(dir, typ+ext)
+			data, err := os.ReadFile(path)
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("slack template error: %s", err)
+			}
+			if _, ok := t.byType[typ]; ok {
+				return nil, fmt.Errorf("slack template error: both %s.tmpl and %s.json in %s", typ, typ, dir)
+			}
+			if err := t.Add(typ+ext, string(data)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return t, nil
+}
+
+// Add parses text as the template called name, <type>.tmpl or
+// <type>.json, and checks it renders a message from sample data.
+func (t *Templates) Add(name, text string) error {
+	typ := strings.TrimSuffix(strings.TrimSuffix(name, ".tmpl"), ".json")
+	if typ == name {
+		return fmt.Errorf("slack template error: %s: name must end in .tmpl or .json", name)
+	}
+	sample, err := SampleData(typ)
+	if err != nil {
+		return fmt.Errorf("slack template error: %s: %s", name, err)
+	}
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return fmt.Errorf("slack template error: %s", err)
+	}
+	if t.byType == nil {
+		t.byType = map[string]*template.Template{}
+	}
+	t.byType[typ] = tmpl
+	if _, _, err := t.Render(sample); err != nil {
+		delete(t.byType, typ)
+		return err
+	}
+	return nil
+}
+
+// Has reports whether there is a template for typ.
+func (t *Templates) Has(typ string) bool {
+	return t != nil && t.byType[typ] != nil
+}
+
+// Render renders the template of data.Type and reports whether there is
+// one. The message has no channel or sender; the client fills them in.
+func (t *Templates) Render(data TemplateData) (Message, bool, error) {
+	if !t.Has(data.Type) {
+		return Message{}, false, nil
+	}
+	tmpl := t.byType[data.Type]
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return Message{}, true, fmt.Errorf("slack template error: %s", err)
+	}
+	out := bytes.TrimSpace(b.Bytes())
+	if len(out) == 0 {
+		return Message{}, true, fmt.Errorf("slack template error: %s rendered an empty message", tmpl.Name())
+	}
+	fallback := data.Title + ": " + data.Name
+	if !strings.HasSuffix(tmpl.Name(), ".json") {
+		return Message{Text: string(out)}, true, nil
+	}
+
+	var msg struct {
+		Text        string            `json:"text"`
+		Blocks      []json.RawMessage `json:"blocks"`
+		Attachments []Attachment      `json:"attachments"`
+	}
+	var err error
+	if out[0] == '[' {
+		err = json.Unmarshal(out, &msg.Blocks)
+	} else {
+		err = json.Unmarshal(out, &msg)
+	}
+	if err != nil {
+		return Message{}, true, fmt.Errorf("slack template error: %s did not render Block Kit JSON: %s", tmpl.Name(), err)
+	}
+	m := Message{Text: msg.Text, Attachments: msg.Attachments}
+	for _, raw := range msg.Blocks {
+		m.Blocks = append(m.Blocks, Block{Raw: raw})
+	}
+	if m.Text == "" {
+		m.Text = fallback
+	}
+	return m, true, nil
+}
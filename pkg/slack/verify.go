@@ -0,0 +1,129 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// MaxRequestAge is how far the timestamp of a signed Slack request may be
+// from now before it is refused as a replay.
+const MaxRequestAge = 5 * time.Minute
+
+// Sign returns the X-Slack-Signature of body sent at timestamp ts, signed
+// with the app's signing secret.
+func Sign(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + ts + ":"))
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyRequest checks that a request Slack sent to an interactivity or
+// slash command endpoint was signed with secret and is recent.
+func VerifyRequest(secret string, header http.Header, body []byte, now time.Time) error {
+	if secret == "" {
+		return fmt.Errorf("slack signing secret not configured")
+	}
+	ts := header.Get("X-Slack-Request-Timestamp")
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("slack request error: missing timestamp")
+	}
+	if age := now.Sub(time.Unix(sec, 0)); age > MaxRequestAge || age < -MaxRequestAge {
+		return fmt.Errorf("slack request error: stale timestamp")
+	}
+	if !hmac.Equal([]byte(Sign(secret, ts, body)), []byte(header.Get("X-Slack-Signature"))) {
+		return fmt.Errorf("slack request error: invalid signature")
+	}
+	return nil
+}
+
+// User is the Slack user behind an interaction.
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	TeamID   string `json:"team_id"`
+}
+
+// Action is a clicked interactive element.
+type Action struct {
+	ActionID string `json:"action_id"`
+	BlockID  string `json:"block_id"`
+	Value    string `json:"value"`
+}
+
+// Interaction is the block_actions payload Slack posts when a button is
+// clicked.
+type Interaction struct {
+	Type    string `json:"type"`
+	User    User   `json:"user"`
+	Channel struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"channel"`
+	Message struct {
+		TS string `json:"ts"`
+	} `json:"message"`
+	ResponseURL string   `json:"response_url"`
+	Actions     []Action `json:"actions"`
+}
+
+// ParseInteraction decodes the payload field of an interaction request's
+// form body.
+func ParseInteraction(body []byte) (Interaction, error) {
+	var in Interaction
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return in, fmt.Errorf("slack interaction error: %s", err)
+	}
+	payload := form.Get("payload")
+	if payload == "" {
+		return in, fmt.Errorf("slack interaction error: no payload")
+	}
+	if err := json.Unmarshal([]byte(payload), &in); err != nil {
+		return in, fmt.Errorf("slack interaction error: %s", err)
+	}
+	return in, nil
+}
+
+// SlashCommand is the form Slack posts when a slash command is invoked.
+type SlashCommand struct {
+	Command     string
+	Text        string
+	UserID      string
+	UserName    string
+	ChannelID   string
+	ChannelName string
+	TeamID      string
+	ResponseURL string
+}
+
+// ParseSlashCommand decodes the form body of a slash command request.
+func ParseSlashCommand(body []byte) (SlashCommand, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return SlashCommand{}, fmt.Errorf("slack command error: %s", err)
+	}
+	sc := SlashCommand{
+		Command:     form.Get("command"),
+		Text:        form.Get("text"),
+		UserID:      form.Get("user_id"),
+		UserName:    form.Get("user_name"),
+		ChannelID:   form.Get("channel_id"),
+		ChannelName: form.Get("channel_name"),
+		TeamID:      form.Get("team_id"),
+		ResponseURL: form.Get("response_url"),
+	}
+	if sc.UserID == "" {
+		return sc, fmt.Errorf("slack command error: no user_id")
+	}
+	return sc, nil
+}
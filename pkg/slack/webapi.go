@@ -0,0 +1,154 @@
+package slack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAPIURL is the Slack Web API base URL.
+const DefaultAPIURL = "https://slack.com/api"
+
+// MessageRef identifies a message posted through the Web API, for
+// threading replies under it and updating it.
+type MessageRef struct {
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+}
+
+// PostMessage posts msg with chat.postMessage (in the client's channel
+// unless msg names one) and returns where it landed. Set msg.ThreadTS to
+// reply in a thread.
+func (c *Client) PostMessage(msg Message) (MessageRef, error) {
+	if msg.Channel == "" {
+		msg.Channel = c.Channel
+	}
+	if msg.Channel == "" {
+		return MessageRef{}, fmt.Errorf("slack chat.postMessage error: no channel")
+	}
+	var ref MessageRef
+	err := c.callAPI("chat.postMessage", msg, &ref)
+	return ref, err
+}
+
+// UpdateMessage replaces the text, blocks and attachments of the message
+// at ref with those of msg.
+func (c *Client) UpdateMessage(ref MessageRef, msg Message) error {
+	msg.Channel, msg.TS, msg.ThreadTS = ref.Channel, ref.TS, ""
+	// chat.update rejects the posting identity fields
+	msg.Username, msg.IconEmoji = "", ""
+	return c.callAPI("chat.update", msg, nil)
+}
+
+// Reply posts text in the thread of the message at ref.
+func (c *Client) Reply(ref MessageRef, text string) (MessageRef, error) {
+	return c.PostMessage(Message{Channel: ref.Channel, ThreadTS: ref.TS, Text: text, Username: c.Username, IconEmoji: c.IconEmoji})
+}
+
+// Respond posts msg to the response_url of an interaction or slash
+// command, which needs no token. Set msg.ResponseType to "ephemeral" to
+// show it to the invoking user only.
+func (c *Client) Respond(responseURL string, msg Message) error {
+	r := *c
+	r.WebhookURL = responseURL
+	return r.sendWebhook(msg)
+}
+
+// apiResponse is the envelope of every Web API answer.
+type apiResponse struct {
+	OK      bool   `json:"ok"`
+	Error   string `json:"error"`
+	Channel string `json:"channel"`
+	TS      string `json:"ts"`
+}
+
+// callAPI posts payload as JSON to the Web API method with the bot token,
+// retrying network errors, rate limiting and server errors like
+// sendWebhook does. out, when not nil, receives the channel and ts of the
+// answer.
+func (c *Client) callAPI(method string, payload interface{}, out *MessageRef) error {
+	if c.Token == "" {
+		return fmt.Errorf("slack bot token not configured")
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	if c.HTTPClient == nil {
+		c.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if c.Logger == nil {
+		c.Logger = stdLogger{l: log.Default()}
+	}
+	if c.Backoff <= 0 {
+		c.Backoff = 500 * time.Millisecond
+	}
+	base := strings.TrimSuffix(c.APIURL, "/")
+	if base == "" {
+		base = DefaultAPIURL
+	}
+	sleep := func(d time.Duration) {
+		if c.Sleep != nil {
+			c.Sleep(d)
+		} else {
+			time.Sleep(d)
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= c.MaxRetries; attempt++ {
+		wait := c.Backoff * (1 << attempt)
+		req, err := http.NewRequest(http.MethodPost, base+"/"+method, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("slack %s error: %w", method, err)
+		}
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to call slack %s: %w", method, err)
+			c.Logger.Errorf("slack %s attempt %d/%d failed: %v", method, attempt+1, c.MaxRetries+1, err)
+			if attempt < c.MaxRetries {
+				sleep(wait)
+			}
+			continue
+		}
+		data, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			lastErr = fmt.Errorf("slack %s returned status %d: %s", method, resp.StatusCode, string(data))
+			if attempt < c.MaxRetries {
+				// honour the rate limit window Slack asks for
+				if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && s > 0 {
+					wait = time.Duration(s) * time.Second
+				}
+				c.Logger.Infof("retrying slack %s due to status %d", method, resp.StatusCode)
+				sleep(wait)
+			}
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("slack %s returned status %d: %s", method, resp.StatusCode, string(data))
+		}
+
+		var r apiResponse
+		if err := json.Unmarshal(data, &r); err != nil {
+			return fmt.Errorf("slack %s error: invalid response %s", method, string(data))
+		}
+		if !r.OK {
+			return fmt.Errorf("slack %s error: %s", method, r.Error)
+		}
+		if out != nil {
+			*out = MessageRef{Channel: r.Channel, TS: r.TS}
+		}
+		return nil
+	}
+	return lastErr
+}
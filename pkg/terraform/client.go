@@ -1,39 +1,52 @@
 package terraform
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"os/exec"
 	"strings"
+
+	"github.com/yourusername/devops-mission-control/pkg/executor"
 )
 
 // Client wraps Terraform operations
 type Client struct {
 	WorkDir string
+	// Exec runs terraform; defaults to the local OS executor.
+	Exec executor.Executor
+}
+
+// Option configures the Terraform client.
+type Option func(*Client)
+
+// WithExecutor sets the executor used to run terraform.
+func WithExecutor(e executor.Executor) Option {
+	return func(c *Client) { c.Exec = e }
 }
 
 // NewClient creates a new Terraform client
-func NewClient(workDir string) *Client {
+func NewClient(workDir string, opts ...Option) *Client {
 	if workDir == "" {
 		workDir = "."
 	}
-	return &Client{
+	c := &Client{
 		WorkDir: workDir,
 	}
+	for _, o := range opts {
+		o(c)
+	}
+	if c.Exec == nil {
+		c.Exec = executor.New()
+	}
+	return c
 }
 
 // execTerraform runs a terraform command in the working directory
 func (c *Client) execTerraform(args ...string) (string, error) {
-	cmd := exec.Command("terraform", args...)
-	cmd.Dir = c.WorkDir
-	var out, errOut bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &errOut
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("terraform error: %s", errOut.String())
+	res, err := c.Exec.Run(context.Background(), executor.Command{Name: "terraform", Args: args, Dir: c.WorkDir})
+	if err != nil {
+		return "", fmt.Errorf("terraform error: %s", executor.ErrorText(res, err))
 	}
-	return strings.TrimSpace(out.String()), nil
+	return strings.TrimSpace(string(res.Stdout)), nil
 }
 
 // Version
@@ -95,8 +108,12 @@ func (c *Client) Destroy(varFile string) (string, error) {
 }
 
 // State operations
-func (c *Client) StateList() (string, error) {
-	return c.execTerraform("state", "list")
+func (c *Client) StateList() ([]string, error) {
+	out, err := c.execTerraform("state", "list")
+	if err != nil {
+		return nil, err
+	}
+	return parseLines(out), nil
 }
 
 func (c *Client) StateShow(resource string) (string, error) {
@@ -116,8 +133,12 @@ func (c *Client) Output(name string) (string, error) {
 	return c.execTerraform("output", name)
 }
 
-func (c *Client) OutputAll() (string, error) {
-	return c.execTerraform("output", "-json")
+func (c *Client) OutputAll() (map[string]Output, error) {
+	out, err := c.execTerraform("output", "-json")
+	if err != nil {
+		return nil, err
+	}
+	return parseOutputs(out)
 }
 
 // Modules
@@ -146,8 +167,12 @@ func (c *Client) Untaint(address string) (string, error) {
 }
 
 // Workspace operations
-func (c *Client) WorkspaceList() (string, error) {
-	return c.execTerraform("workspace", "list")
+func (c *Client) WorkspaceList() ([]Workspace, error) {
+	out, err := c.execTerraform("workspace", "list")
+	if err != nil {
+		return nil, err
+	}
+	return parseWorkspaces(out), nil
 }
 
 func (c *Client) WorkspaceNew(name string) (string, error) {
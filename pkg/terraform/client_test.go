@@ -2,6 +2,8 @@ package terraform
 
 import (
 	"testing"
+
+	"github.com/yourusername/devops-mission-control/pkg/executor"
 )
 
 func TestNewClient(t *testing.T) {
@@ -65,3 +67,35 @@ func TestOutputAll(t *testing.T) {
 		t.Logf("terraform not available: %v", err)
 	}
 }
+
+func TestWorkspaceListMarksCurrent(t *testing.T) {
+	fake := executor.NewFake()
+	fake.On("terraform", "workspace", "list").Return("  default\n* staging\n  prod\n")
+
+	workspaces, err := NewClient(t.TempDir(), WithExecutor(fake)).WorkspaceList()
+	if err != nil {
+		t.Fatalf("WorkspaceList: %v", err)
+	}
+	want := []Workspace{{Name: "default"}, {Name: "staging", Current: true}, {Name: "prod"}}
+	if len(workspaces) != len(want) {
+		t.Fatalf("unexpected workspaces %+v", workspaces)
+	}
+	for i := range want {
+		if workspaces[i] != want[i] {
+			t.Fatalf("workspace %d: got %+v, want %+v", i, workspaces[i], want[i])
+		}
+	}
+}
+
+func TestOutputAllParsesJSON(t *testing.T) {
+	fake := executor.NewFake()
+	fake.On("terraform", "output", "-json").Return(`{"url":{"sensitive":false,"type":"string","value":"https://example.com"},"password":{"sensitive":true,"type":"string","value":"hunter2"}}`)
+
+	outputs, err := NewClient(t.TempDir(), WithExecutor(fake)).OutputAll()
+	if err != nil {
+		t.Fatalf("OutputAll: %v", err)
+	}
+	if outputs["url"].Value != "https://example.com" || outputs["url"].Name != "url" || !outputs["password"].Sensitive {
+		t.Fatalf("unexpected outputs %+v", outputs)
+	}
+}
@@ -0,0 +1,67 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Output is one entry of `terraform output -json`.
+type Output struct {
+	Name      string `json:"name"`
+	Value     any    `json:"value"`
+	Type      any    `json:"type"`
+	Sensitive bool   `json:"sensitive"`
+}
+
+// Workspace is a Terraform workspace.
+type Workspace struct {
+	Name    string `json:"name"`
+	Current bool   `json:"current"`
+}
+
+// parseOutputs converts `terraform output -json`, which is an object keyed by
+// output name.
+func parseOutputs(data string) (map[string]Output, error) {
+	out := map[string]Output{}
+	if data == "" {
+		return out, nil
+	}
+	if err := json.Unmarshal([]byte(data), &out); err != nil {
+		return nil, fmt.Errorf("failed to parse outputs: %w", err)
+	}
+	for name, o := range out {
+		o.Name = name
+		out[name] = o
+	}
+	return out, nil
+}
+
+// parseWorkspaces converts `terraform workspace list`, which marks the
+// selected workspace with a leading "*".
+func parseWorkspaces(data string) []Workspace {
+	out := []Workspace{}
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		ws := Workspace{Name: line}
+		if name, ok := strings.CutPrefix(line, "*"); ok {
+			ws = Workspace{Name: strings.TrimSpace(name), Current: true}
+		}
+		out = append(out, ws)
+	}
+	return out
+}
+
+// parseLines splits line-oriented output such as `terraform state list`.
+func parseLines(data string) []string {
+	out := []string{}
+	for _, line := range strings.Split(data, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
@@ -0,0 +1,354 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// App runs a Model on the terminal.
+type App struct {
+	Title string
+	Views []*View
+	// Refresh is how often the current view reloads; 0 disables it.
+	Refresh time.Duration
+	// Authorize is called before every action and must return an error to
+	// deny it (RBAC).
+	Authorize func(v *View, a *Action, row Row) error
+	// Audit is called after every attempted action with its outcome.
+	Audit func(v *View, a *Action, row Row, input string, err error)
+
+	In  *os.File
+	Out io.Writer
+
+	model   *Model
+	readMu  sync.Mutex
+	keys    chan Key
+	loads   chan loadResult
+	results chan actionResult
+	sigs    chan os.Signal
+	gen     int
+}
+
+type loadResult struct {
+	gen  int
+	rows []Row
+	err  error
+}
+
+type actionResult struct {
+	view   *View
+	action *Action
+	row    Row
+	input  string
+	output string
+	err    error
+}
+
+// Run takes over the terminal until the user quits.
+func (a *App) Run() error {
+	if len(a.Views) == 0 {
+		return errors.New("no views configured")
+	}
+	if a.In == nil {
+		a.In = os.Stdin
+	}
+	if a.Out == nil {
+		a.Out = os.Stdout
+	}
+	saved, err := a.stty("-g")
+	if err != nil {
+		return fmt.Errorf("ui needs an interactive terminal: %w", err)
+	}
+	saved = strings.TrimSpace(saved)
+	if err := a.enterScreen(); err != nil {
+		return err
+	}
+	defer a.leaveScreen(saved)
+
+	a.model = NewModel(a.Title, a.Views)
+	a.resize()
+	a.keys = make(chan Key, 16)
+	a.loads = make(chan loadResult, 4)
+	a.results = make(chan actionResult, 4)
+	done := make(chan struct{})
+	defer close(done)
+	go a.readKeys(done)
+
+	// Ctrl-C arrives as a key while in raw mode; this catches signals sent
+	// from elsewhere so the terminal is always restored, and keeps Ctrl-C in
+	// an interactive action from killing missionctl along with the child.
+	a.sigs = make(chan os.Signal, 1)
+	signal.Notify(a.sigs, os.Interrupt)
+	defer signal.Stop(a.sigs)
+
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+	lastLoad := time.Now()
+	a.reload()
+	a.draw()
+	for {
+		select {
+		case k := <-a.keys:
+			eff := a.model.HandleKey(k)
+			switch eff.Kind {
+			case EffectQuit:
+				return nil
+			case EffectReload:
+				a.reload()
+				lastLoad = time.Now()
+			case EffectRun:
+				a.runAction(eff, saved)
+			}
+		case r := <-a.loads:
+			if r.gen == a.gen {
+				a.model.SetRows(r.rows, r.err)
+			}
+		case r := <-a.results:
+			a.finishAction(r)
+		case <-tick.C:
+			a.resize()
+			if a.Refresh > 0 && time.Since(lastLoad) >= a.Refresh && a.model.mode == modeList {
+				a.reload()
+				lastLoad = time.Now()
+			}
+		case <-a.sigs:
+			return nil
+		}
+		a.draw()
+	}
+}
+
+func (a *App) reload() {
+	a.gen++
+	gen, view := a.gen, a.model.View()
+	a.model.SetLoading()
+	go func() {
+		rows, err := view.Load()
+		a.loads <- loadResult{gen: gen, rows: rows, err: err}
+	}()
+}
+
+func (a *App) runAction(eff Effect, saved string) {
+	view, act, row := a.model.View(), eff.Action, eff.Row
+	if a.Authorize != nil {
+		if err := a.Authorize(view, act, row); err != nil {
+			a.audit(view, act, row, eff.Input, err)
+			a.model.SetStatus(fmt.Sprintf("%s %s: %v", act.Name, row.ID, err), true)
+			return
+		}
+	}
+	if !act.Interactive {
+		a.model.SetStatus(fmt.Sprintf("%s %s…", act.Name, row.ID), false)
+		go func() {
+			out, err := act.Run(row, eff.Input)
+			a.results <- actionResult{view: view, action: act, row: row, input: eff.Input, output: out, err: err}
+		}()
+		return
+	}
+
+	// Hand the terminal to the command: stop reading keys and restore the
+	// original tty settings while it runs.
+	a.readMu.Lock()
+	a.leaveScreen(saved)
+	fmt.Fprintf(a.Out, "── %s %s (Ctrl-C to return) ──\n", act.Name, row.ID)
+	out, err := act.Run(row, eff.Input)
+	if out != "" {
+		fmt.Fprintln(a.Out, out)
+	}
+	if err != nil {
+		fmt.Fprintf(a.Out, "\n%s failed: %v\n", act.Name, err)
+	}
+	fmt.Fprint(a.Out, "\nPress Enter to return to missionctl ui")
+	_, _ = a.stty(saved)
+	buf := make([]byte, 64)
+	_, _ = a.In.Read(buf)
+	_ = a.enterScreen()
+	a.readMu.Unlock()
+	// The child's Ctrl-C was ours too; it must not close the UI.
+	select {
+	case <-a.sigs:
+	default:
+	}
+
+	a.finishAction(actionResult{view: view, action: act, row: row, input: eff.Input, err: err})
+}
+
+func (a *App) finishAction(r actionResult) {
+	a.audit(r.view, r.action, r.row, r.input, r.err)
+	if r.err != nil {
+		a.model.SetStatus(fmt.Sprintf("%s %s: %v", r.action.Name, r.row.ID, r.err), true)
+		return
+	}
+	if r.action.ShowOutput && r.output != "" {
+		a.model.ShowText(fmt.Sprintf("%s %s", r.action.Name, r.row.ID), r.output)
+		return
+	}
+	msg := fmt.Sprintf("%s %s: done", r.action.Name, r.row.ID)
+	if line := firstLine(r.output); line != "" {
+		msg = line
+	}
+	a.model.SetStatus(msg, false)
+	if !r.action.Interactive {
+		a.reload()
+	}
+}
+
+func (a *App) audit(v *View, act *Action, row Row, input string, err error) {
+	if a.Audit != nil {
+		a.Audit(v, act, row, input, err)
+	}
+}
+
+func (a *App) draw() {
+	fmt.Fprint(a.Out, "\x1b[H"+a.model.Render())
+}
+
+func (a *App) resize() {
+	out, err := a.stty("size")
+	if err != nil {
+		return
+	}
+	var rows, cols int
+	if _, err := fmt.Sscan(out, &rows, &cols); err == nil {
+		a.model.Resize(cols, rows)
+	}
+}
+
+// readKeys turns terminal input into keys. The tty is put in raw mode with
+// a 100ms read timeout, so holding readMu pauses input within one timeout.
+func (a *App) readKeys(done <-chan struct{}) {
+	buf := make([]byte, 64)
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		a.readMu.Lock()
+		n, err := a.In.Read(buf)
+		a.readMu.Unlock()
+		if n == 0 || (err != nil && !errors.Is(err, io.EOF)) {
+			time.Sleep(10 * time.Millisecond)
+			continue
+		}
+		for _, k := range ParseKeys(buf[:n]) {
+			select {
+			case a.keys <- k:
+			case <-done:
+				return
+			}
+		}
+	}
+}
+
+func (a *App) enterScreen() error {
+	if _, err := a.stty("raw", "-echo", "min", "0", "time", "1"); err != nil {
+		return err
+	}
+	// alternate screen, hide cursor, clear
+	fmt.Fprint(a.Out, "\x1b[?1049h\x1b[?25l\x1b[2J")
+	return nil
+}
+
+// leaveScreen restores the normal screen and the saved tty settings.
+func (a *App) leaveScreen(saved string) {
+	fmt.Fprint(a.Out, "\x1b[?25h\x1b[?1049l")
+	_, _ = a.stty(saved)
+}
+
+// stty runs stty against the UI's terminal.
+func (a *App) stty(args ...string) (string, error) {
+	c := exec.Command("stty", args...)
+	c.Stdin = a.In
+	out, err := c.Output()
+	return string(out), err
+}
+
+// ParseKeys decodes a chunk of raw terminal input.
+func ParseKeys(b []byte) []Key {
+	var keys []Key
+	for len(b) > 0 {
+		if b[0] == 0x1b {
+			if len(b) == 1 {
+				keys = append(keys, Key{Name: "esc"})
+				break
+			}
+			if k, n := escapeKey(b); n > 0 {
+				if k.Name != "" {
+					keys = append(keys, k)
+				}
+				b = b[n:]
+				continue
+			}
+			keys = append(keys, Key{Name: "esc"})
+			b = b[1:]
+			continue
+		}
+		switch b[0] {
+		case '\r', '\n':
+			keys = append(keys, Key{Name: "enter"})
+		case '\t':
+			keys = append(keys, Key{Name: "tab"})
+		case 0x7f, 0x08:
+			keys = append(keys, Key{Name: "backspace"})
+		case 0x03:
+			keys = append(keys, Key{Name: "ctrl-c"})
+		case 0x04:
+			keys = append(keys, Key{Name: "ctrl-d"})
+		default:
+			if b[0] < 0x20 {
+				keys = append(keys, Key{Name: "ctrl-" + string(rune('a'+b[0]-1))})
+				break
+			}
+			r := []rune(string(b))[0]
+			keys = append(keys, Key{Rune: r})
+			b = b[len(string(r)):]
+			continue
+		}
+		b = b[1:]
+	}
+	return keys
+}
+
+// escapeKey decodes a CSI or SS3 sequence at the start of b and returns the
+// key and the number of bytes consumed (0 if b is not a sequence).
+func escapeKey(b []byte) (Key, int) {
+	if len(b) < 3 || (b[1] != '[' && b[1] != 'O') {
+		return Key{}, 0
+	}
+	i := 2
+	for i < len(b) && (b[i] >= '0' && b[i] <= '9' || b[i] == ';') {
+		i++
+	}
+	if i >= len(b) {
+		return Key{}, 0
+	}
+	params, final := string(b[2:i]), b[i]
+	names := map[byte]string{'A': "up", 'B': "down", 'C': "right", 'D': "left", 'H': "home", 'F': "end", 'Z': "backtab"}
+	if name, ok := names[final]; ok {
+		return Key{Name: name}, i + 1
+	}
+	if final == '~' {
+		n, _ := strconv.Atoi(strings.Split(params, ";")[0])
+		tilde := map[int]string{1: "home", 3: "delete", 4: "end", 5: "pgup", 6: "pgdn", 7: "home", 8: "end"}
+		return Key{Name: tilde[n]}, i + 1
+	}
+	// Unknown sequence: swallow it.
+	return Key{}, i + 1
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
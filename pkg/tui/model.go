@@ -0,0 +1,421 @@
+// Package tui implements the full-screen terminal UI behind `missionctl ui`:
+// a set of resource views (pods, containers, VMs, ...) with filtering, live
+// refresh and per-view actions bound to keys.
+//
+// The package only knows about rows and actions. The views themselves, and
+// the RBAC and audit hooks, are supplied by the caller.
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+)
+
+// Key is a single key press: either a printable rune or a named special key
+// such as "up", "enter", "esc" or "ctrl-d".
+type Key struct {
+	Rune rune
+	Name string
+}
+
+// R is shorthand for the key that types r.
+func R(r rune) Key { return Key{Rune: r} }
+
+// String renders the key for the help bar.
+func (k Key) String() string {
+	if k.Name != "" {
+		return k.Name
+	}
+	return string(k.Rune)
+}
+
+// Row is one resource in a view.
+type Row struct {
+	// ID is what actions operate on (pod name, container ID, ...).
+	ID    string
+	Cells []string
+	// Object is the typed resource, used by actions that need more than ID.
+	Object any
+}
+
+// Action is something the user can do to the selected row.
+type Action struct {
+	Key  Key
+	Name string
+	// Role is the minimum role needed to run the action.
+	Role authpkg.Role
+	// Confirm asks "are you sure" before running.
+	Confirm bool
+	// Prompt, when set, asks for a value (e.g. "replicas") passed as input.
+	Prompt string
+	// Interactive actions (logs -f, exec, port-forward) take over the
+	// terminal, so the UI is suspended while they run.
+	Interactive bool
+	// ShowOutput displays the returned text full-screen (describe);
+	// otherwise its first line goes to the status bar.
+	ShowOutput bool
+	Run        func(row Row, input string) (string, error)
+}
+
+// View is a list of resources of one kind.
+type View struct {
+	Name    string
+	Headers []string
+	Load    func() ([]Row, error)
+	Actions []*Action
+}
+
+type mode int
+
+const (
+	modeList mode = iota
+	modeFilter
+	modeCommand
+	modePrompt
+	modeConfirm
+	modeText
+)
+
+// EffectKind says what the App must do after a key press.
+type EffectKind int
+
+const (
+	EffectNone EffectKind = iota
+	EffectQuit
+	EffectReload
+	EffectRun
+)
+
+// Effect is returned by HandleKey.
+type Effect struct {
+	Kind   EffectKind
+	Action *Action
+	Row    Row
+	Input  string
+}
+
+// Model is the UI state. It has no I/O so it can be driven from tests.
+type Model struct {
+	Title string
+	Views []*View
+
+	cur      int
+	rows     []Row
+	visible  []Row
+	sel      int
+	offset   int
+	filter   string
+	mode     mode
+	input    string
+	pending  *Action
+	pendRow  Row
+	text     []string
+	textName string
+	textOff  int
+	status   string
+	isErr    bool
+	loading  bool
+	updated  time.Time
+	width    int
+	height   int
+}
+
+// NewModel creates a model showing the first view.
+func NewModel(title string, views []*View) *Model {
+	return &Model{Title: title, Views: views, width: 80, height: 24}
+}
+
+// Resize sets the screen size.
+func (m *Model) Resize(width, height int) {
+	if width > 0 && height > 0 {
+		m.width, m.height = width, height
+	}
+	m.clamp()
+}
+
+// View returns the current view.
+func (m *Model) View() *View { return m.Views[m.cur] }
+
+// Selected returns the selected row, if any.
+func (m *Model) Selected() (Row, bool) {
+	if m.sel < 0 || m.sel >= len(m.visible) {
+		return Row{}, false
+	}
+	return m.visible[m.sel], true
+}
+
+// SetLoading marks the current view as being (re)loaded.
+func (m *Model) SetLoading() { m.loading = true }
+
+// SetRows replaces the rows of the current view, keeping the selection on
+// the same ID when it is still there.
+func (m *Model) SetRows(rows []Row, err error) {
+	m.loading = false
+	if err != nil {
+		m.SetStatus(err.Error(), true)
+		return
+	}
+	prev, hadSel := m.Selected()
+	m.rows = rows
+	m.updated = time.Now()
+	m.applyFilter()
+	if hadSel {
+		for i, r := range m.visible {
+			if r.ID == prev.ID {
+				m.sel = i
+				break
+			}
+		}
+	}
+	m.clamp()
+}
+
+// SetStatus shows a message in the status bar.
+func (m *Model) SetStatus(msg string, isErr bool) {
+	m.status, m.isErr = msg, isErr
+}
+
+// ShowText switches to the full-screen text view.
+func (m *Model) ShowText(name, text string) {
+	m.mode = modeText
+	m.textName = name
+	m.text = strings.Split(strings.TrimRight(text, "\n"), "\n")
+	m.textOff = 0
+}
+
+func (m *Model) applyFilter() {
+	if m.filter == "" {
+		m.visible = m.rows
+		return
+	}
+	needle := strings.ToLower(m.filter)
+	m.visible = nil
+	for _, r := range m.rows {
+		if strings.Contains(strings.ToLower(strings.Join(r.Cells, " ")), needle) {
+			m.visible = append(m.visible, r)
+		}
+	}
+}
+
+func (m *Model) switchView(i int) Effect {
+	if i < 0 || i >= len(m.Views) {
+		return Effect{}
+	}
+	m.cur = i
+	m.rows, m.visible = nil, nil
+	m.sel, m.offset = 0, 0
+	m.filter = ""
+	m.status = ""
+	return Effect{Kind: EffectReload}
+}
+
+// listHeight is the number of table rows that fit on screen: the screen
+// minus title, tabs, header, status and help lines.
+func (m *Model) listHeight() int {
+	if h := m.height - 5; h > 1 {
+		return h
+	}
+	return 1
+}
+
+func (m *Model) clamp() {
+	if m.sel >= len(m.visible) {
+		m.sel = len(m.visible) - 1
+	}
+	if m.sel < 0 {
+		m.sel = 0
+	}
+	h := m.listHeight()
+	if m.sel < m.offset {
+		m.offset = m.sel
+	}
+	if m.sel >= m.offset+h {
+		m.offset = m.sel - h + 1
+	}
+	if m.offset < 0 {
+		m.offset = 0
+	}
+}
+
+// HandleKey updates the model for one key press and says what the App
+// should do next.
+func (m *Model) HandleKey(k Key) Effect {
+	if k.Name == "ctrl-c" {
+		return Effect{Kind: EffectQuit}
+	}
+	switch m.mode {
+	case modeFilter, modeCommand, modePrompt:
+		return m.handleInput(k)
+	case modeConfirm:
+		m.mode = modeList
+		if k.Rune == 'y' || k.Rune == 'Y' {
+			return Effect{Kind: EffectRun, Action: m.pending, Row: m.pendRow}
+		}
+		m.SetStatus("cancelled", false)
+		return Effect{}
+	case modeText:
+		return m.handleText(k)
+	}
+
+	switch {
+	case k.Rune == 'q':
+		return Effect{Kind: EffectQuit}
+	case k.Name == "down" || k.Rune == 'j':
+		m.sel++
+	case k.Name == "up" || k.Rune == 'k':
+		m.sel--
+	case k.Name == "pgdn":
+		m.sel += m.listHeight()
+	case k.Name == "pgup":
+		m.sel -= m.listHeight()
+	case k.Name == "home" || k.Rune == 'g':
+		m.sel = 0
+	case k.Name == "end" || k.Rune == 'G':
+		m.sel = len(m.visible) - 1
+	case k.Name == "tab":
+		return m.switchView((m.cur + 1) % len(m.Views))
+	case k.Name == "backtab":
+		return m.switchView((m.cur + len(m.Views) - 1) % len(m.Views))
+	case k.Rune >= '1' && k.Rune <= '9':
+		return m.switchView(int(k.Rune - '1'))
+	case k.Rune == '/':
+		m.mode, m.input = modeFilter, m.filter
+	case k.Rune == ':':
+		m.mode, m.input = modeCommand, ""
+	case k.Rune == 'r':
+		return Effect{Kind: EffectReload}
+	case k.Rune == '?':
+		m.ShowText("help", m.helpText())
+	case k.Name == "esc":
+		if m.filter != "" {
+			m.filter = ""
+			m.applyFilter()
+		}
+	default:
+		return m.handleAction(k)
+	}
+	m.clamp()
+	return Effect{}
+}
+
+func (m *Model) handleAction(k Key) Effect {
+	for _, a := range m.View().Actions {
+		if a.Key != k {
+			continue
+		}
+		row, ok := m.Selected()
+		if !ok {
+			m.SetStatus("nothing selected", true)
+			return Effect{}
+		}
+		switch {
+		case a.Prompt != "":
+			m.mode, m.input, m.pending, m.pendRow = modePrompt, "", a, row
+		case a.Confirm:
+			m.mode, m.pending, m.pendRow = modeConfirm, a, row
+		default:
+			return Effect{Kind: EffectRun, Action: a, Row: row}
+		}
+		return Effect{}
+	}
+	return Effect{}
+}
+
+func (m *Model) handleInput(k Key) Effect {
+	switch k.Name {
+	case "esc":
+		if m.mode == modeFilter {
+			m.filter = ""
+			m.applyFilter()
+		}
+		m.mode = modeList
+		return Effect{}
+	case "backspace":
+		if r := []rune(m.input); len(r) > 0 {
+			m.input = string(r[:len(r)-1])
+		}
+	case "enter":
+		mode := m.mode
+		m.mode = modeList
+		switch mode {
+		case modeCommand:
+			return m.runCommand(strings.TrimSpace(m.input))
+		case modePrompt:
+			return Effect{Kind: EffectRun, Action: m.pending, Row: m.pendRow, Input: strings.TrimSpace(m.input)}
+		}
+		m.clamp()
+		return Effect{}
+	case "":
+		m.input += string(k.Rune)
+	}
+	if m.mode == modeFilter {
+		m.filter = m.input
+		m.applyFilter()
+		m.clamp()
+	}
+	return Effect{}
+}
+
+// runCommand handles ":<view>" and ":q".
+func (m *Model) runCommand(c string) Effect {
+	switch c {
+	case "":
+		return Effect{}
+	case "q", "quit":
+		return Effect{Kind: EffectQuit}
+	}
+	for i, v := range m.Views {
+		if v.Name == c || strings.TrimSuffix(v.Name, "s") == c {
+			return m.switchView(i)
+		}
+	}
+	m.SetStatus(fmt.Sprintf("unknown view %q", c), true)
+	return Effect{}
+}
+
+func (m *Model) handleText(k Key) Effect {
+	page := m.height - 2
+	switch {
+	case k.Name == "esc" || k.Rune == 'q':
+		m.mode = modeList
+		return Effect{}
+	case k.Name == "down" || k.Rune == 'j':
+		m.textOff++
+	case k.Name == "up" || k.Rune == 'k':
+		m.textOff--
+	case k.Name == "pgdn" || k.Rune == ' ':
+		m.textOff += page
+	case k.Name == "pgup":
+		m.textOff -= page
+	case k.Rune == 'g':
+		m.textOff = 0
+	case k.Rune == 'G':
+		m.textOff = len(m.text)
+	}
+	if last := len(m.text) - page; m.textOff > last {
+		m.textOff = last
+	}
+	if m.textOff < 0 {
+		m.textOff = 0
+	}
+	return Effect{}
+}
+
+func (m *Model) helpText() string {
+	var b strings.Builder
+	b.WriteString("Navigation\n")
+	b.WriteString("  j/k, ↑/↓      move           g/G      top/bottom\n")
+	b.WriteString("  1-9, tab      switch view    :<view>  switch view by name\n")
+	b.WriteString("  /             filter         esc      clear filter / back\n")
+	b.WriteString("  r             refresh        q        quit\n\n")
+	for _, v := range m.Views {
+		fmt.Fprintf(&b, "%s\n", v.Name)
+		for _, a := range v.Actions {
+			fmt.Fprintf(&b, "  %-13s %s (%s)\n", a.Key.String(), a.Name, a.Role)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,212 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI sequences used by the renderer.
+const (
+	reverse  = "\x1b[7m"
+	bold     = "\x1b[1m"
+	red      = "\x1b[31m"
+	dim      = "\x1b[2m"
+	reset    = "\x1b[0m"
+	clearEOL = "\x1b[K"
+)
+
+// Render draws the whole screen as height lines of at most width columns.
+func (m *Model) Render() string {
+	var lines []string
+	if m.mode == modeText {
+		lines = m.renderText()
+	} else {
+		lines = m.renderList()
+	}
+	for len(lines) < m.height {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines[:m.height], clearEOL+"\r\n") + clearEOL
+}
+
+func (m *Model) renderList() []string {
+	v := m.View()
+	var lines []string
+
+	info := fmt.Sprintf(" %s │ %s (%d/%d)", m.Title, v.Name, len(m.visible), len(m.rows))
+	if m.filter != "" {
+		info += fmt.Sprintf(" │ filter: %s", m.filter)
+	}
+	switch {
+	case m.loading:
+		info += " │ loading…"
+	case !m.updated.IsZero():
+		info += " │ updated " + m.updated.Format("15:04:05")
+	}
+	lines = append(lines, bold+fit(info, m.width)+reset)
+
+	var tabs []string
+	for i, view := range m.Views {
+		label := fmt.Sprintf(" %d:%s ", i+1, view.Name)
+		if i == m.cur {
+			label = reverse + label + reset
+		}
+		tabs = append(tabs, label)
+	}
+	lines = append(lines, fitANSI(strings.Join(tabs, ""), m.width))
+
+	widths := columnWidths(v.Headers, m.visible, m.width)
+	lines = append(lines, bold+fit(formatRow(v.Headers, widths), m.width)+reset)
+
+	h := m.listHeight()
+	for i := m.offset; i < len(m.visible) && i < m.offset+h; i++ {
+		line := fit(formatRow(m.visible[i].Cells, widths), m.width)
+		if i == m.sel {
+			line = reverse + pad(line, m.width) + reset
+		}
+		lines = append(lines, line)
+	}
+	if len(m.visible) == 0 && !m.loading {
+		lines = append(lines, dim+fit(" no resources", m.width)+reset)
+	}
+	for len(lines) < h+3 {
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, m.renderStatus())
+	lines = append(lines, dim+fit(m.hints(), m.width)+reset)
+	return lines
+}
+
+func (m *Model) renderStatus() string {
+	switch m.mode {
+	case modeFilter:
+		return fit("/"+m.input+"█", m.width)
+	case modeCommand:
+		return fit(":"+m.input+"█", m.width)
+	case modePrompt:
+		return fit(fmt.Sprintf("%s %s %s: %s█", m.pending.Name, m.pendRow.ID, m.pending.Prompt, m.input), m.width)
+	case modeConfirm:
+		return bold + fit(fmt.Sprintf("%s %s? (y/n)", m.pending.Name, m.pendRow.ID), m.width) + reset
+	}
+	if m.isErr {
+		return red + fit(m.status, m.width) + reset
+	}
+	return fit(m.status, m.width)
+}
+
+func (m *Model) hints() string {
+	parts := []string{"?:help", "/:filter", "r:refresh", "q:quit"}
+	for _, a := range m.View().Actions {
+		parts = append(parts, a.Key.String()+":"+a.Name)
+	}
+	return " " + strings.Join(parts, "  ")
+}
+
+func (m *Model) renderText() []string {
+	lines := []string{bold + fit(fmt.Sprintf(" %s │ %s", m.Title, m.textName), m.width) + reset}
+	page := m.height - 2
+	for i := m.textOff; i < len(m.text) && i < m.textOff+page; i++ {
+		lines = append(lines, fit(strings.ReplaceAll(m.text[i], "\t", "    "), m.width))
+	}
+	for len(lines) < page+1 {
+		lines = append(lines, "")
+	}
+	return append(lines, dim+fit(" esc:back  j/k:scroll  space:page", m.width)+reset)
+}
+
+// columnWidths sizes each column to its widest cell, shrinking the widest
+// columns first when the table does not fit.
+func columnWidths(headers []string, rows []Row, width int) []int {
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = utf8.RuneCountInString(h)
+	}
+	for _, r := range rows {
+		for i, c := range r.Cells {
+			if i < len(widths) {
+				if n := utf8.RuneCountInString(c); n > widths[i] {
+					widths[i] = n
+				}
+			}
+		}
+	}
+	const gap = 2
+	total := func() int {
+		t := 1
+		for _, w := range widths {
+			t += w + gap
+		}
+		return t
+	}
+	for total() > width {
+		widest := 0
+		for i := range widths {
+			if widths[i] > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= 8 {
+			break
+		}
+		widths[widest]--
+	}
+	return widths
+}
+
+func formatRow(cells []string, widths []int) string {
+	var b strings.Builder
+	b.WriteByte(' ')
+	for i, w := range widths {
+		c := ""
+		if i < len(cells) {
+			c = cells[i]
+		}
+		b.WriteString(pad(fit(c, w), w+2))
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// fit truncates s to width runes, marking the cut with an ellipsis.
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:width-1]) + "…"
+}
+
+// fitANSI truncates s to width visible runes, skipping escape sequences.
+func fitANSI(s string, width int) string {
+	var b strings.Builder
+	visible := 0
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			end := strings.IndexByte(s[i:], 'm')
+			if end < 0 {
+				break
+			}
+			b.WriteString(s[i : i+end+1])
+			i += end + 1
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if visible < width {
+			b.WriteRune(r)
+			visible++
+		}
+		i += size
+	}
+	return b.String() + reset
+}
+
+func pad(s string, width int) string {
+	if n := utf8.RuneCountInString(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	authpkg "github.com/yourusername/devops-mission-control/pkg/auth"
+)
+
+var ansi = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+func testViews() []*View {
+	del := &Action{Key: Key{Name: "ctrl-d"}, Name: "delete", Role: authpkg.RoleOperator, Confirm: true}
+	scale := &Action{Key: R('S'), Name: "scale", Role: authpkg.RoleOperator, Prompt: "replicas"}
+	return []*View{
+		{Name: "pods", Headers: []string{"NAME", "STATUS"}, Actions: []*Action{del}},
+		{Name: "deployments", Headers: []string{"NAME", "READY"}, Actions: []*Action{scale}},
+	}
+}
+
+func typeString(m *Model, s string) Effect {
+	var eff Effect
+	for _, r := range s {
+		eff = m.HandleKey(R(r))
+	}
+	return eff
+}
+
+func TestModelFilterAndSelection(t *testing.T) {
+	m := NewModel("prod", testViews())
+	m.SetRows([]Row{
+		{ID: "api-1", Cells: []string{"api-1", "Running"}},
+		{ID: "web-1", Cells: []string{"web-1", "Pending"}},
+		{ID: "web-2", Cells: []string{"web-2", "Running"}},
+	}, nil)
+
+	m.HandleKey(R('/'))
+	typeString(m, "web")
+	m.HandleKey(Key{Name: "enter"})
+	if len(m.visible) != 2 {
+		t.Fatalf("expected 2 filtered rows, got %d", len(m.visible))
+	}
+	m.HandleKey(R('j'))
+	if row, _ := m.Selected(); row.ID != "web-2" {
+		t.Fatalf("expected web-2 selected, got %q", row.ID)
+	}
+	// A refresh keeps the selection on the same resource.
+	m.SetRows([]Row{{ID: "web-0", Cells: []string{"web-0", "Running"}}, {ID: "web-1", Cells: []string{"web-1", "Running"}}, {ID: "web-2", Cells: []string{"web-2", "Running"}}}, nil)
+	if row, _ := m.Selected(); row.ID != "web-2" {
+		t.Fatalf("selection lost after refresh, got %q", row.ID)
+	}
+
+	screen := ansi.ReplaceAllString(m.Render(), "")
+	if !strings.Contains(screen, "filter: web") || !strings.Contains(screen, "web-2  Running") || strings.Contains(screen, "api-1") {
+		t.Fatalf("unexpected screen:\n%s", screen)
+	}
+	m.HandleKey(Key{Name: "esc"})
+	if len(m.visible) != 3 {
+		t.Fatalf("esc should clear the filter")
+	}
+}
+
+func TestModelConfirmPromptAndViewSwitch(t *testing.T) {
+	m := NewModel("prod", testViews())
+	m.SetRows([]Row{{ID: "api-1", Cells: []string{"api-1", "Running"}}}, nil)
+
+	if eff := m.HandleKey(Key{Name: "ctrl-d"}); eff.Kind != EffectNone {
+		t.Fatalf("delete must ask for confirmation first, got %+v", eff)
+	}
+	if eff := m.HandleKey(R('n')); eff.Kind != EffectNone {
+		t.Fatalf("expected cancel, got %+v", eff)
+	}
+	m.HandleKey(Key{Name: "ctrl-d"})
+	eff := m.HandleKey(R('y'))
+	if eff.Kind != EffectRun || eff.Action.Name != "delete" || eff.Row.ID != "api-1" {
+		t.Fatalf("expected delete effect, got %+v", eff)
+	}
+
+	if eff := m.HandleKey(R('2')); eff.Kind != EffectReload || m.View().Name != "deployments" {
+		t.Fatalf("expected switch to deployments, got %+v", eff)
+	}
+	m.SetRows([]Row{{ID: "api", Cells: []string{"api", "1/1"}}}, nil)
+	m.HandleKey(R('S'))
+	typeString(m, "3")
+	eff = m.HandleKey(Key{Name: "enter"})
+	if eff.Kind != EffectRun || eff.Action.Name != "scale" || eff.Input != "3" {
+		t.Fatalf("expected scale effect with input, got %+v", eff)
+	}
+
+	m.HandleKey(R(':'))
+	typeString(m, "pod")
+	if eff := m.HandleKey(Key{Name: "enter"}); eff.Kind != EffectReload || m.View().Name != "pods" {
+		t.Fatalf("expected :pod to switch views, got %+v", eff)
+	}
+	if eff := m.HandleKey(R('q')); eff.Kind != EffectQuit {
+		t.Fatalf("expected quit, got %+v", eff)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	got := ParseKeys([]byte("j\x1b[A\x1b[6~\r\x04\x1b[Zé\x1b"))
+	want := []Key{R('j'), {Name: "up"}, {Name: "pgdn"}, {Name: "enter"}, {Name: "ctrl-d"}, {Name: "backtab"}, R('é'), {Name: "esc"}}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("key %d: expected %v, got %v", i, want[i], got[i])
+		}
+	}
+}
@@ -0,0 +1,469 @@
+package yamlite
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Unmarshal decodes block-style YAML into v. The document is first parsed into
+// maps, slices and scalars and then converted through encoding/json, so struct
+// json tags decide which keys map to which fields.
+//
+// Supported: block mappings and sequences, "- key: value" list items, plain,
+// single- and double-quoted scalars, literal (|) and folded (>) blocks, empty
+// and single-line flow collections ([a, b], {a: b}) and # comments. Anchors,
+// tags and multi-document streams are not.
+func Unmarshal(data []byte, v any) error {
+	generic, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	if generic == nil {
+		return nil
+	}
+	raw, err := json.Marshal(generic)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// Parse decodes a YAML document into map[string]any, []any, string, bool,
+// json.Number or nil values.
+func Parse(data []byte) (any, error) {
+	p := &parser{lines: strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")}
+	for i, l := range p.lines {
+		trimmed := strings.TrimSpace(l)
+		if trimmed == "---" || trimmed == "..." {
+			p.lines[i] = ""
+		}
+	}
+	p.skip()
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+	ind, _, err := p.current()
+	if err != nil {
+		return nil, err
+	}
+	out, err := p.node(ind)
+	if err != nil {
+		return nil, err
+	}
+	p.skip()
+	if p.pos < len(p.lines) {
+		return nil, p.errorf("unexpected content")
+	}
+	return out, nil
+}
+
+type parser struct {
+	lines []string
+	pos   int
+	// override replaces the current line's indent and text while the
+	// remainder of a "- key: value" item is parsed as a mapping.
+	override *overrideLine
+}
+
+type overrideLine struct {
+	pos    int
+	indent int
+	text   string
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("yaml: line %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+// skip advances past blank and comment-only lines.
+func (p *parser) skip() {
+	for p.pos < len(p.lines) {
+		t := strings.TrimSpace(p.lines[p.pos])
+		if t != "" && !strings.HasPrefix(t, "#") {
+			return
+		}
+		p.pos++
+	}
+}
+
+// current returns the indent and comment-stripped text of the current line.
+func (p *parser) current() (int, string, error) {
+	if o := p.override; o != nil && o.pos == p.pos {
+		return o.indent, o.text, nil
+	}
+	l := p.lines[p.pos]
+	ind := len(l) - len(strings.TrimLeft(l, " "))
+	if strings.HasPrefix(l[ind:], "\t") {
+		return 0, "", p.errorf("tabs are not allowed for indentation")
+	}
+	return ind, strings.TrimSpace(stripComment(l[ind:])), nil
+}
+
+func (p *parser) advance() {
+	p.pos++
+	p.override = nil
+	p.skip()
+}
+
+// node parses whatever block starts at the current line, which must be
+// indented exactly ind.
+func (p *parser) node(ind int) (any, error) {
+	_, text, err := p.current()
+	if err != nil {
+		return nil, err
+	}
+	if isSeqItem(text) {
+		return p.sequence(ind)
+	}
+	if _, _, ok := splitKey(text); ok {
+		return p.mapping(ind)
+	}
+	if strings.HasPrefix(text, "|") || strings.HasPrefix(text, ">") {
+		return p.block(text, ind-1)
+	}
+	p.advance()
+	return parseScalar(text)
+}
+
+func (p *parser) mapping(ind int) (map[string]any, error) {
+	out := map[string]any{}
+	for p.pos < len(p.lines) {
+		cur, text, err := p.current()
+		if err != nil {
+			return nil, err
+		}
+		if cur < ind {
+			break
+		}
+		if cur > ind {
+			return nil, p.errorf("unexpected indentation")
+		}
+		if isSeqItem(text) {
+			break
+		}
+		key, rest, ok := splitKey(text)
+		if !ok {
+			return nil, p.errorf("expected \"key: value\", got %q", text)
+		}
+		if _, dup := out[key]; dup {
+			return nil, p.errorf("duplicate key %q", key)
+		}
+		val, err := p.value(rest, ind, true)
+		if err != nil {
+			return nil, err
+		}
+		out[key] = val
+	}
+	return out, nil
+}
+
+func (p *parser) sequence(ind int) ([]any, error) {
+	out := []any{}
+	for p.pos < len(p.lines) {
+		cur, text, err := p.current()
+		if err != nil {
+			return nil, err
+		}
+		if cur != ind || !isSeqItem(text) {
+			if cur > ind {
+				return nil, p.errorf("unexpected indentation")
+			}
+			break
+		}
+		rest := strings.TrimLeft(text[1:], " ")
+		if rest != "" && !strings.HasPrefix(rest, "|") && !strings.HasPrefix(rest, ">") {
+			if _, _, ok := splitKey(rest); ok || isSeqItem(rest) {
+				// "- key: value" opens a mapping whose keys line up with key.
+				inner := ind + len(text) - len(rest)
+				p.override = &overrideLine{pos: p.pos, indent: inner, text: rest}
+				val, err := p.node(inner)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, val)
+				continue
+			}
+		}
+		val, err := p.value(rest, ind, false)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, val)
+	}
+	return out, nil
+}
+
+// value parses what follows "key:" or "-" on the current line, consuming any
+// nested block below it. In a mapping a sequence may sit at the key's own
+// indent, as kubectl writes it.
+func (p *parser) value(rest string, ind int, inMap bool) (any, error) {
+	if strings.HasPrefix(rest, "|") || strings.HasPrefix(rest, ">") {
+		return p.block(rest, ind)
+	}
+	p.advance()
+	if rest != "" {
+		return parseScalar(rest)
+	}
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+	next, text, err := p.current()
+	if err != nil {
+		return nil, err
+	}
+	if next > ind || (inMap && next == ind && isSeqItem(text)) {
+		return p.node(next)
+	}
+	return nil, nil
+}
+
+// block reads a literal (|) or folded (>) scalar whose lines are indented
+// deeper than parent.
+func (p *parser) block(header string, parent int) (string, error) {
+	style, chomp := header[0], header[1:]
+	if chomp != "" && chomp != "-" && chomp != "+" {
+		return "", p.errorf("unsupported block header %q", header)
+	}
+	p.pos++
+	p.override = nil
+	var lines []string
+	contentIndent := -1
+	for p.pos < len(p.lines) {
+		l := p.lines[p.pos]
+		if strings.TrimSpace(l) == "" {
+			lines = append(lines, "")
+			p.pos++
+			continue
+		}
+		ind := len(l) - len(strings.TrimLeft(l, " "))
+		if contentIndent < 0 {
+			if ind <= parent {
+				break
+			}
+			contentIndent = ind
+		}
+		if ind < contentIndent {
+			break
+		}
+		lines = append(lines, l[contentIndent:])
+		p.pos++
+	}
+	// Trailing blank lines belong to whatever follows the block.
+	trailing := 0
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+	p.skip()
+
+	var s string
+	if style == '>' {
+		s = fold(lines)
+	} else {
+		s = strings.Join(lines, "\n")
+	}
+	switch {
+	case len(lines) == 0:
+		return "", nil
+	case chomp == "-":
+	case chomp == "+":
+		s += strings.Repeat("\n", trailing+1)
+	default:
+		s += "\n"
+	}
+	return s, nil
+}
+
+func fold(lines []string) string {
+	var b strings.Builder
+	for i, l := range lines {
+		switch {
+		case i == 0:
+		case l == "" || lines[i-1] == "":
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(' ')
+		}
+		b.WriteString(l)
+	}
+	return b.String()
+}
+
+func isSeqItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitKey splits "key: value" at the first colon that is followed by a space
+// or ends the line and is not inside quotes.
+func splitKey(text string) (key, rest string, ok bool) {
+	if text == "" || text[0] == '[' || text[0] == '{' {
+		return "", "", false
+	}
+	start := 0
+	if text[0] == '"' || text[0] == '\'' {
+		end := closingQuote(text)
+		if end < 0 {
+			return "", "", false
+		}
+		start = end + 1
+	}
+	for i := start; i < len(text); i++ {
+		if text[i] != ':' || (i+1 < len(text) && text[i+1] != ' ') {
+			continue
+		}
+		k, err := parseScalar(strings.TrimSpace(text[:i]))
+		if err != nil {
+			return "", "", false
+		}
+		if k == nil {
+			k = "null"
+		}
+		return fmt.Sprint(k), strings.TrimSpace(text[i+1:]), true
+	}
+	return "", "", false
+}
+
+// closingQuote returns the index of the quote that closes the quoted scalar at
+// the start of s, or -1.
+func closingQuote(s string) int {
+	q := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case q == '"' && s[i] == '\\':
+			i++
+		case q == '\'' && s[i] == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+		case s[i] == q:
+			return i
+		}
+	}
+	return -1
+}
+
+// stripComment removes a trailing "# comment" that is not inside quotes.
+func stripComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || s[i-1] == ' ' || s[i-1] == ':' || s[i-1] == '-' || s[i-1] == '[' || s[i-1] == '{' || s[i-1] == ',' {
+				quote = c
+			}
+		case c == '#' && (i == 0 || s[i-1] == ' '):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+var numberRE = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+
+func parseScalar(s string) (any, error) {
+	if s == "" {
+		return nil, nil
+	}
+	switch s[0] {
+	case '"':
+		if closingQuote(s) != len(s)-1 {
+			return nil, fmt.Errorf("yaml: malformed quoted scalar %s", s)
+		}
+		out, err := strconv.Unquote(s)
+		if err != nil {
+			return nil, fmt.Errorf("yaml: malformed quoted scalar %s", s)
+		}
+		return out, nil
+	case '\'':
+		if closingQuote(s) != len(s)-1 {
+			return nil, fmt.Errorf("yaml: malformed quoted scalar %s", s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	case '[':
+		return parseFlowList(s)
+	case '{':
+		return parseFlowMap(s)
+	}
+	switch s {
+	case "~", "null", "Null", "NULL":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	if numberRE.MatchString(s) {
+		return json.Number(s), nil
+	}
+	return s, nil
+}
+
+func parseFlowList(s string) (any, error) {
+	if !strings.HasSuffix(s, "]") {
+		return nil, fmt.Errorf("yaml: unterminated flow sequence %s", s)
+	}
+	out := []any{}
+	for _, item := range splitFlow(s[1 : len(s)-1]) {
+		v, err := parseScalar(item)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func parseFlowMap(s string) (any, error) {
+	if !strings.HasSuffix(s, "}") {
+		return nil, fmt.Errorf("yaml: unterminated flow mapping %s", s)
+	}
+	out := map[string]any{}
+	for _, item := range splitFlow(s[1 : len(s)-1]) {
+		key, rest, ok := splitKey(item)
+		if !ok {
+			return nil, fmt.Errorf("yaml: expected \"key: value\" in flow mapping, got %q", item)
+		}
+		v, err := parseScalar(rest)
+		if err != nil {
+			return nil, err
+		}
+		out[key] = v
+	}
+	return out, nil
+}
+
+// splitFlow splits the inside of a single-level flow collection on commas
+// that are not inside quotes.
+func splitFlow(s string) []string {
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			items = append(items, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" || len(items) > 0 {
+		items = append(items, last)
+	}
+	return items
+}
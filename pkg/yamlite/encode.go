@@ -0,0 +1,182 @@
+// Package yamlite reads and writes the small subset of YAML missionctl needs
+// (block mappings, block sequences and scalars) without pulling in a full
+// YAML library.
+package yamlite
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Marshal encodes v as block-style YAML. v is first converted through
+// encoding/json, so struct json tags decide the key names; mapping keys are
+// emitted in sorted order.
+func Marshal(v any) ([]byte, error) {
+	generic, err := toGeneric(v)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	switch val := generic.(type) {
+	case map[string]any:
+		if len(val) == 0 {
+			buf.WriteString("{}\n")
+		} else {
+			writeMap(&buf, val, 0)
+		}
+	case []any:
+		if len(val) == 0 {
+			buf.WriteString("[]\n")
+		} else {
+			writeList(&buf, val, 0)
+		}
+	default:
+		buf.WriteString(scalar(val, 0))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+func toGeneric(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var out any
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func writeMap(buf *bytes.Buffer, m map[string]any, indent int) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pad := strings.Repeat(" ", indent)
+	for _, k := range keys {
+		buf.WriteString(pad)
+		buf.WriteString(quoteIfNeeded(k))
+		buf.WriteByte(':')
+		writeValue(buf, m[k], indent, indent+2)
+	}
+}
+
+func writeList(buf *bytes.Buffer, l []any, indent int) {
+	pad := strings.Repeat(" ", indent)
+	for _, item := range l {
+		buf.WriteString(pad)
+		buf.WriteByte('-')
+		if m, ok := item.(map[string]any); ok && len(m) > 0 {
+			// The first key sits on the dash line, the rest line up under it.
+			var inner bytes.Buffer
+			writeMap(&inner, m, indent+2)
+			buf.WriteByte(' ')
+			buf.Write(inner.Bytes()[indent+2:])
+			continue
+		}
+		writeValue(buf, item, indent, indent+2)
+	}
+}
+
+// writeValue writes the remainder of a line that already holds "key:" or "-".
+// Sequences nested under a mapping key stay at the key's indent, matching
+// kubectl's output.
+func writeValue(buf *bytes.Buffer, v any, indent, child int) {
+	switch val := v.(type) {
+	case map[string]any:
+		if len(val) == 0 {
+			buf.WriteString(" {}\n")
+			return
+		}
+		buf.WriteByte('\n')
+		writeMap(buf, val, child)
+	case []any:
+		if len(val) == 0 {
+			buf.WriteString(" []\n")
+			return
+		}
+		buf.WriteByte('\n')
+		writeList(buf, val, indent)
+	default:
+		buf.WriteByte(' ')
+		buf.WriteString(scalar(val, child))
+		buf.WriteByte('\n')
+	}
+}
+
+func scalar(v any, indent int) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(val)
+	case json.Number:
+		return val.String()
+	case string:
+		if strings.Contains(val, "\n") && !strings.ContainsAny(val, "\r\t") {
+			return blockLiteral(val, indent)
+		}
+		return quoteIfNeeded(val)
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+func blockLiteral(s string, indent int) string {
+	header := "|-"
+	if strings.HasSuffix(s, "\n") {
+		header = "|"
+		s = strings.TrimSuffix(s, "\n")
+	}
+	pad := strings.Repeat(" ", indent)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+	return header + "\n" + strings.Join(lines, "\n")
+}
+
+// quoteIfNeeded returns s as a plain scalar when that round-trips as the same
+// string, and as a double-quoted scalar otherwise.
+func quoteIfNeeded(s string) string {
+	if needsQuotes(s) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func needsQuotes(s string) bool {
+	if s == "" || s != strings.TrimSpace(s) {
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~":
+		return true
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return true
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return true
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return true
+	}
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
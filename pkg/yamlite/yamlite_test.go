@@ -0,0 +1,128 @@
+package yamlite
+
+import "testing"
+
+func TestMarshalQuotesAmbiguousScalars(t *testing.T) {
+	in := map[string]any{
+		"name":    "api",
+		"version": "1.20",
+		"enabled": "true",
+		"empty":   "",
+		"note":    "key: value",
+		"nested":  map[string]any{"list": []any{}, "obj": map[string]any{}},
+		"script":  "line one\nline two",
+	}
+	got, err := Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `empty: ""
+enabled: "true"
+name: api
+nested:
+  list: []
+  obj: {}
+note: "key: value"
+script: |-
+  line one
+  line two
+version: "1.20"
+`
+	if string(got) != want {
+		t.Fatalf("unexpected yaml:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestMarshalListOfMaps(t *testing.T) {
+	got, err := Marshal([]map[string]any{{"a": 1, "b": []string{"x"}}, {"a": 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "- a: 1\n  b:\n  - x\n- a: 2\n"
+	if string(got) != want {
+		t.Fatalf("unexpected yaml:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestUnmarshalRoundTripsMarshal(t *testing.T) {
+	in := map[string]any{
+		"name":    "api",
+		"version": "1.20",
+		"enabled": "true",
+		"empty":   "",
+		"note":    "key: value",
+		"nested":  map[string]any{"list": []any{}, "obj": map[string]any{}},
+		"script":  "line one\nline two\n",
+		"items":   []any{map[string]any{"a": "x", "b": []any{"y", "z"}}, "plain"},
+	}
+	data, err := Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out map[string]any
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatalf("unmarshal failed: %v\n%s", err, data)
+	}
+	want, _ := Marshal(out)
+	if string(want) != string(data) {
+		t.Fatalf("round trip mismatch:\n%s\nwant:\n%s", want, data)
+	}
+}
+
+func TestUnmarshalHandWrittenDocument(t *testing.T) {
+	doc := `# missionctl settings
+current_profile: prod-eu   # active
+profiles:
+  prod-eu:
+    region: 'eu-west-1'
+    replicas: 3
+    tags: [web, "blue green"]
+  staging: {region: us-east-1}
+hosts:
+  - name: a
+    port: 22
+  -   name: b
+flags:
+- true
+- ~
+`
+	var out struct {
+		CurrentProfile string `json:"current_profile"`
+		Profiles       map[string]struct {
+			Region   string   `json:"region"`
+			Replicas int      `json:"replicas"`
+			Tags     []string `json:"tags"`
+		} `json:"profiles"`
+		Hosts []struct {
+			Name string `json:"name"`
+			Port int    `json:"port"`
+		} `json:"hosts"`
+		Flags []*bool `json:"flags"`
+	}
+	if err := Unmarshal([]byte(doc), &out); err != nil {
+		t.Fatal(err)
+	}
+	prod := out.Profiles["prod-eu"]
+	if out.CurrentProfile != "prod-eu" || prod.Region != "eu-west-1" || prod.Replicas != 3 {
+		t.Fatalf("unexpected result: %+v", out)
+	}
+	if len(prod.Tags) != 2 || prod.Tags[1] != "blue green" || out.Profiles["staging"].Region != "us-east-1" {
+		t.Fatalf("unexpected flow values: %+v", out.Profiles)
+	}
+	if len(out.Hosts) != 2 || out.Hosts[0].Port != 22 || out.Hosts[1].Name != "b" {
+		t.Fatalf("unexpected hosts: %+v", out.Hosts)
+	}
+	if len(out.Flags) != 2 || out.Flags[0] == nil || !*out.Flags[0] || out.Flags[1] != nil {
+		t.Fatalf("unexpected flags: %+v", out.Flags)
+	}
+}
+
+func TestUnmarshalRejectsBadIndentation(t *testing.T) {
+	var out map[string]any
+	if err := Unmarshal([]byte("a: 1\n   b: 2\n"), &out); err == nil {
+		t.Fatal("expected indentation error")
+	}
+	if err := Unmarshal([]byte("a: 1\na: 2\n"), &out); err == nil {
+		t.Fatal("expected duplicate key error")
+	}
+}